@@ -26,20 +26,40 @@ import (
 )
 
 type API struct {
-	engine   *gin.Engine
-	webCtrl  *controllers.WebController
-	userCtrl *controllers.UsersController
-	listCtrl *controllers.ListsController
-	wishCtrl *controllers.WishesController
+	engine       *gin.Engine
+	webCtrl      *controllers.WebController
+	userCtrl     *controllers.UsersController
+	listCtrl     *controllers.ListsController
+	wishCtrl     *controllers.WishesController
+	guestCtrl    *controllers.GuestReservationsController
+	memberCtrl   *controllers.MembersController
+	followCtrl   *controllers.FollowsController
+	feedCtrl     *controllers.FeedController
+	occasionCtrl *controllers.OccasionsController
+	groupCtrl    *controllers.GroupsController
+	contribCtrl  *controllers.ContributionsController
+	exportCtrl   *controllers.ExportsController
+	trashCtrl    *controllers.TrashController
+	filesCtrl    *controllers.FilesController // Only with the filesystem storage driver
 }
 
-func NewAPI(e *gin.Engine, web *controllers.WebController, uc *controllers.UsersController, lc *controllers.ListsController, wc *controllers.WishesController) *API {
+func NewAPI(e *gin.Engine, web *controllers.WebController, uc *controllers.UsersController, lc *controllers.ListsController, wc *controllers.WishesController, grc *controllers.GuestReservationsController, mc *controllers.MembersController, fwc *controllers.FollowsController, fdc *controllers.FeedController, oc *controllers.OccasionsController, gc *controllers.GroupsController, cc *controllers.ContributionsController, ec *controllers.ExportsController, tc *controllers.TrashController, fc *controllers.FilesController) *API {
 	return &API{
-		engine:   e,
-		webCtrl:  web,
-		userCtrl: uc,
-		listCtrl: lc,
-		wishCtrl: wc,
+		engine:       e,
+		webCtrl:      web,
+		userCtrl:     uc,
+		listCtrl:     lc,
+		wishCtrl:     wc,
+		guestCtrl:    grc,
+		memberCtrl:   mc,
+		followCtrl:   fwc,
+		feedCtrl:     fdc,
+		occasionCtrl: oc,
+		groupCtrl:    gc,
+		contribCtrl:  cc,
+		exportCtrl:   ec,
+		trashCtrl:    tc,
+		filesCtrl:    fc,
 	}
 }
 
@@ -72,6 +92,20 @@ func (api *API) RegisterRoutes() {
 	api.userCtrl.RegisterRoutes()
 	api.listCtrl.RegisterRoutes()
 	api.wishCtrl.RegisterRoutes()
+	api.guestCtrl.RegisterRoutes()
+	api.memberCtrl.RegisterRoutes()
+	api.followCtrl.RegisterRoutes()
+	api.feedCtrl.RegisterRoutes()
+	api.occasionCtrl.RegisterRoutes()
+	api.groupCtrl.RegisterRoutes()
+	api.contribCtrl.RegisterRoutes()
+	api.exportCtrl.RegisterRoutes()
+	api.trashCtrl.RegisterRoutes()
+
+	// Files
+	if api.filesCtrl != nil {
+		api.filesCtrl.RegisterRoutes()
+	}
 
 	// Swagger
 	docs.SwaggerInfo.Host = fmt.Sprintf("%s", viper.GetString(config.WebAppDomain))
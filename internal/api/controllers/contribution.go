@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+)
+
+type WishContributionService interface {
+	Contribute(ctx context.Context, listID, wishID, userID uuid.UUID, req models.ContributeRequest) (models.WishContribution, error)
+	WithdrawContribution(ctx context.Context, listID, wishID, userID uuid.UUID) error
+}
+
+type ContributionsController struct {
+	router              *gin.Engine
+	mw                  *middlewares.Middlewares
+	contributionService WishContributionService
+}
+
+func NewContributionsController(e *gin.Engine, mw *middlewares.Middlewares, cs WishContributionService) *ContributionsController {
+	return &ContributionsController{router: e, mw: mw, contributionService: cs}
+}
+
+func (ctrl *ContributionsController) RegisterRoutes() {
+	basePath := ctrl.router.Group(viper.GetString(config.ApiBasePath))
+	listRoutes := basePath.Group("/lists")
+	{
+		authedListRoutes := listRoutes.Group("").Use(ctrl.mw.AuthMiddleware())
+		{
+			authedListRoutes.POST("/:list_id/wishes/:wish_id/contributions", ctrl.Contribute)
+			authedListRoutes.DELETE("/:list_id/wishes/:wish_id/contributions", ctrl.WithdrawContribution)
+		}
+	}
+}
+
+// Contribute GoDoc
+// @Summary Chip in for wish
+// @Description Pledge an amount towards the wish price, pledging again replaces the previous amount
+// @Tags contributions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Param request body models.ContributeRequest true "Contribution"
+// @Success 201 {object} models.WishContributionResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/{wish_id}/contributions [post]
+// noinspection DuplicatedCode
+func (ctrl *ContributionsController) Contribute(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	wishID, err := uuid.Parse(ctx.Param("wish_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid wish ID")
+		return
+	}
+
+	var req models.ContributeRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	contribution, err := ctrl.contributionService.Contribute(ctx, listID, wishID, userID, req)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, contribution.ToResponse())
+}
+
+// WithdrawContribution GoDoc
+// @Summary Withdraw contribution
+// @Description Withdraw contribution of current user from the wish
+// @Tags contributions
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/{wish_id}/contributions [delete]
+// noinspection DuplicatedCode
+func (ctrl *ContributionsController) WithdrawContribution(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	wishID, err := uuid.Parse(ctx.Param("wish_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid wish ID")
+		return
+	}
+
+	if err = ctrl.contributionService.WithdrawContribution(ctx, listID, wishID, userID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
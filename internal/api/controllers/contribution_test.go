@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type contributionControllerServiceMock struct {
+	contributeFn           func(ctx context.Context, listID, wishID, userID uuid.UUID, req models.ContributeRequest) (models.WishContribution, error)
+	withdrawContributionFn func(ctx context.Context, listID, wishID, userID uuid.UUID) error
+}
+
+func (m *contributionControllerServiceMock) Contribute(ctx context.Context, listID, wishID, userID uuid.UUID, req models.ContributeRequest) (models.WishContribution, error) {
+	if m.contributeFn != nil {
+		return m.contributeFn(ctx, listID, wishID, userID, req)
+	}
+	return models.WishContribution{}, nil
+}
+
+func (m *contributionControllerServiceMock) WithdrawContribution(ctx context.Context, listID, wishID, userID uuid.UUID) error {
+	if m.withdrawContributionFn != nil {
+		return m.withdrawContributionFn(ctx, listID, wishID, userID)
+	}
+	return nil
+}
+
+func setupContributionControllerForTest(as *wishControllerAuthMock, cs *contributionControllerServiceMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	viper.Set(config.ApiBasePath, "/api/v1")
+	router := gin.New()
+	mw := middlewares.NewMiddlewares(as)
+	ctrl := NewContributionsController(router, mw, cs)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestContributionsController_Contribute(t *testing.T) {
+	currentUserID := uuid.New()
+	listID := uuid.New()
+	wishID := uuid.New()
+	path := "/api/v1/lists/" + listID.String() + "/wishes/" + wishID.String() + "/contributions"
+	as := &wishControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("non positive amount", func(t *testing.T) {
+		router := setupContributionControllerForTest(as, &contributionControllerServiceMock{})
+		w := wishJSONRequest(router, http.MethodPost, path, `{"amount":-5}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("overfunded", func(t *testing.T) {
+		cs := &contributionControllerServiceMock{contributeFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, req models.ContributeRequest) (models.WishContribution, error) {
+			return models.WishContribution{}, svcErr.ValidationError{Message: "contribution exceeds the remaining amount of 10"}
+		}}
+		router := setupContributionControllerForTest(as, cs)
+		w := wishJSONRequest(router, http.MethodPost, path, `{"amount":500}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		cs := &contributionControllerServiceMock{contributeFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, req models.ContributeRequest) (models.WishContribution, error) {
+			if gotListID != listID || gotWishID != wishID || gotUserID != currentUserID || req.Amount != 500 {
+				t.Fatalf("unexpected contribute args")
+			}
+			return models.WishContribution{WishID: gotWishID, UserID: gotUserID, Amount: req.Amount}, nil
+		}}
+		router := setupContributionControllerForTest(as, cs)
+		w := wishJSONRequest(router, http.MethodPost, path, `{"amount":500}`, "ok")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+		}
+	})
+}
+
+func TestContributionsController_WithdrawContribution(t *testing.T) {
+	currentUserID := uuid.New()
+	path := "/api/v1/lists/" + uuid.NewString() + "/wishes/" + uuid.NewString() + "/contributions"
+	as := &wishControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("not found", func(t *testing.T) {
+		cs := &contributionControllerServiceMock{withdrawContributionFn: func(ctx context.Context, listID, wishID, userID uuid.UUID) error {
+			return svcErr.NotFoundError{Entity: "contribution", Field: "wish_id", Value: wishID.String()}
+		}}
+		router := setupContributionControllerForTest(as, cs)
+		w := wishJSONRequest(router, http.MethodDelete, path, "", "ok")
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		router := setupContributionControllerForTest(as, &contributionControllerServiceMock{})
+		w := wishJSONRequest(router, http.MethodDelete, path, "", "ok")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+)
+
+type DataExportService interface {
+	ExportUserData(ctx context.Context, userID uuid.UUID) (models.DataExport, error)
+	DownloadDataExport(ctx context.Context, token string) (io.ReadCloser, models.BucketObject, error)
+}
+
+type ExportsController struct {
+	router        *gin.Engine
+	mw            *middlewares.Middlewares
+	exportService DataExportService
+}
+
+func NewExportsController(e *gin.Engine, mw *middlewares.Middlewares, es DataExportService) *ExportsController {
+	return &ExportsController{router: e, mw: mw, exportService: es}
+}
+
+func (ctrl *ExportsController) RegisterRoutes() {
+	basePath := ctrl.router.Group(viper.GetString(config.ApiBasePath))
+	basePath.GET("/users/me/export", ctrl.mw.AuthMiddleware(), ctrl.ExportCurrentUser)
+	basePath.GET("/exports/:token", ctrl.DownloadExport) // The token is the credential, the link is opened from an email
+}
+
+// ExportCurrentUser GoDoc
+// @Summary Export my data
+// @Description Download a ZIP archive with the profile, all lists with their wishes, held reservations and the image files.
+// @Description Large accounts get 202 instead: the archive is built in the background and a download link is emailed
+// @Tags users
+// @Produce application/zip
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {file} file "ZIP archive"
+// @Success 202 {object} models.DataExportResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 409 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/me/export [get]
+func (ctrl *ExportsController) ExportCurrentUser(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	export, err := ctrl.exportService.ExportUserData(ctx, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	if export.Pending {
+		ctx.JSON(http.StatusAccepted, models.DataExportResponse{
+			Message: "your export is being prepared, a download link will be sent by email",
+			Email:   export.Email,
+		})
+		return
+	}
+
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.FileName}))
+	ctx.Data(http.StatusOK, "application/zip", export.Data)
+}
+
+// DownloadExport GoDoc
+// @Summary Download data export
+// @Description Download an archive prepared in the background, the link comes by email and expires
+// @Tags users
+// @Produce application/zip
+// @Param token path string true "Download token from the email"
+// @Success 200 {file} file "ZIP archive"
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /exports/{token} [get]
+func (ctrl *ExportsController) DownloadExport(ctx *gin.Context) {
+	archive, info, err := ctrl.exportService.DownloadDataExport(ctx, ctx.Param("token"))
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+	defer func() { _ = archive.Close() }()
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.DataFromReader(http.StatusOK, info.Size, "application/zip", archive, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(info.Key)}),
+	})
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type exportControllerServiceMock struct {
+	exportUserDataFn     func(ctx context.Context, userID uuid.UUID) (models.DataExport, error)
+	downloadDataExportFn func(ctx context.Context, token string) (io.ReadCloser, models.BucketObject, error)
+}
+
+func (m *exportControllerServiceMock) ExportUserData(ctx context.Context, userID uuid.UUID) (models.DataExport, error) {
+	if m.exportUserDataFn != nil {
+		return m.exportUserDataFn(ctx, userID)
+	}
+	return models.DataExport{}, nil
+}
+
+func (m *exportControllerServiceMock) DownloadDataExport(ctx context.Context, token string) (io.ReadCloser, models.BucketObject, error) {
+	if m.downloadDataExportFn != nil {
+		return m.downloadDataExportFn(ctx, token)
+	}
+	return nil, models.BucketObject{}, svcErr.NotFoundError{Entity: "data export", Field: "token", Value: token}
+}
+
+// setupExportControllerForTest registers the users routes as well, /users/me/export lives next to /users/:user_id
+func setupExportControllerForTest(as *userControllerAuthMock, es *exportControllerServiceMock) *gin.Engine {
+	router := setupUserControllerForTest(as, &userControllerServiceMock{})
+	ctrl := NewExportsController(router, middlewares.NewMiddlewares(as), es)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestExportsController_ExportCurrentUser(t *testing.T) {
+	currentUserID := uuid.New()
+	as := &userControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		router := setupExportControllerForTest(&userControllerAuthMock{}, &exportControllerServiceMock{})
+		w := userJSONRequest(router, http.MethodGet, "/api/v1/users/me/export", "", "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		es := &exportControllerServiceMock{exportUserDataFn: func(ctx context.Context, userID uuid.UUID) (models.DataExport, error) {
+			if userID != currentUserID {
+				t.Fatalf("userID = %s, want %s", userID, currentUserID)
+			}
+			return models.DataExport{FileName: "wishlist-export-alice-2030-03-08.zip", Data: []byte("PK")}, nil
+		}}
+		router := setupExportControllerForTest(as, es)
+		w := userJSONRequest(router, http.MethodGet, "/api/v1/users/me/export", "", "ok")
+		if w.Code != http.StatusOK || w.Body.String() != "PK" {
+			t.Fatalf("status = %d, body = %q", w.Code, w.Body.String())
+		}
+		if w.Header().Get("Content-Type") != "application/zip" || w.Header().Get("Content-Disposition") != `attachment; filename=wishlist-export-alice-2030-03-08.zip` {
+			t.Fatalf("headers = %v", w.Header())
+		}
+	})
+
+	t.Run("pending", func(t *testing.T) {
+		es := &exportControllerServiceMock{exportUserDataFn: func(ctx context.Context, userID uuid.UUID) (models.DataExport, error) {
+			return models.DataExport{Pending: true, Email: "alice@example.com"}, nil
+		}}
+		router := setupExportControllerForTest(as, es)
+		w := userJSONRequest(router, http.MethodGet, "/api/v1/users/me/export", "", "ok")
+		if w.Code != http.StatusAccepted || !strings.Contains(w.Body.String(), "alice@example.com") {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("already running", func(t *testing.T) {
+		es := &exportControllerServiceMock{exportUserDataFn: func(ctx context.Context, userID uuid.UUID) (models.DataExport, error) {
+			return models.DataExport{}, svcErr.ConflictError{Message: "your data export is already being prepared"}
+		}}
+		router := setupExportControllerForTest(as, es)
+		w := userJSONRequest(router, http.MethodGet, "/api/v1/users/me/export", "", "ok")
+		if w.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusConflict)
+		}
+	})
+}
+
+func TestExportsController_DownloadExport(t *testing.T) {
+	viper.Set(config.ApiBasePath, "/api/v1")
+	es := &exportControllerServiceMock{downloadDataExportFn: func(ctx context.Context, token string) (io.ReadCloser, models.BucketObject, error) {
+		if token != "valid" {
+			return nil, models.BucketObject{}, svcErr.NotFoundError{Entity: "data export", Field: "token", Value: token}
+		}
+		return io.NopCloser(strings.NewReader("PK")), models.BucketObject{Key: "exports/u/1/wishlist-export.zip", Size: 2}, nil
+	}}
+	router := setupExportControllerForTest(&userControllerAuthMock{}, es)
+
+	w := userJSONRequest(router, http.MethodGet, "/api/v1/exports/valid", "", "")
+	if w.Code != http.StatusOK || w.Body.String() != "PK" || w.Header().Get("Content-Disposition") != "attachment; filename=wishlist-export.zip" {
+		t.Fatalf("status = %d, body = %q, headers = %v", w.Code, w.Body.String(), w.Header())
+	}
+
+	w = userJSONRequest(router, http.MethodGet, "/api/v1/exports/expired", "", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+)
+
+type FeedService interface {
+	GetFeed(ctx context.Context, userID uuid.UUID, cursor string, limit int) (models.Feed, error)
+}
+
+type FeedController struct {
+	router      *gin.Engine
+	mw          *middlewares.Middlewares
+	feedService FeedService
+}
+
+func NewFeedController(e *gin.Engine, mw *middlewares.Middlewares, fs FeedService) *FeedController {
+	return &FeedController{router: e, mw: mw, feedService: fs}
+}
+
+func (ctrl *FeedController) RegisterRoutes() {
+	basePath := ctrl.router.Group(viper.GetString(config.ApiBasePath))
+	basePath.GET("/feed", ctrl.mw.AuthMiddleware(), ctrl.GetFeed)
+}
+
+// GetFeed GoDoc
+// @Summary Get activity feed
+// @Description Get new and changed wishes and lists of followed users and shared wishlists, newest first. Pass next_cursor of a page to get the following one
+// @Tags feed
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "Cursor of the previous page"
+// @Param limit query int false "Events per page, 20 by default and 100 at most"
+// @Success 200 {object} models.FeedResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /feed [get]
+func (ctrl *FeedController) GetFeed(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var limit int
+	if raw := ctx.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+			apiModels.Error(ctx, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	feed, err := ctrl.feedService.GetFeed(ctx, userID, ctx.Query("cursor"), limit)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, feed.ToResponse())
+}
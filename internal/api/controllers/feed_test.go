@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type feedControllerServiceMock struct {
+	getFeedFn func(ctx context.Context, userID uuid.UUID, cursor string, limit int) (models.Feed, error)
+}
+
+func (m *feedControllerServiceMock) GetFeed(ctx context.Context, userID uuid.UUID, cursor string, limit int) (models.Feed, error) {
+	if m.getFeedFn != nil {
+		return m.getFeedFn(ctx, userID, cursor, limit)
+	}
+	return models.Feed{}, nil
+}
+
+func setupFeedControllerForTest(as *listControllerAuthMock, fs *feedControllerServiceMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	viper.Set(config.ApiBasePath, "/api/v1")
+	router := gin.New()
+	mw := middlewares.NewMiddlewares(as)
+	ctrl := NewFeedController(router, mw, fs)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestFeedController_GetFeed(t *testing.T) {
+	currentUserID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		router := setupFeedControllerForTest(as, &feedControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/feed", "", "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		router := setupFeedControllerForTest(as, &feedControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/feed?limit=many", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		fs := &feedControllerServiceMock{getFeedFn: func(ctx context.Context, userID uuid.UUID, cursor string, limit int) (models.Feed, error) {
+			return models.Feed{}, svcErr.ValidationError{Message: "invalid cursor"}
+		}}
+		router := setupFeedControllerForTest(as, fs)
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/feed?cursor=bad", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		next := models.FeedCursor{CreatedAt: time.Now(), ID: uuid.New()}
+		fs := &feedControllerServiceMock{getFeedFn: func(ctx context.Context, userID uuid.UUID, cursor string, limit int) (models.Feed, error) {
+			if userID != currentUserID || cursor != "abc" || limit != 5 {
+				t.Fatalf("unexpected feed args: %s %q %d", userID, cursor, limit)
+			}
+			return models.Feed{
+				Events:     []models.ActivityEvent{{ID: next.ID, ActorID: uuid.New(), Kind: models.ActivityWishReserved}},
+				NextCursor: &next,
+			}, nil
+		}}
+		router := setupFeedControllerForTest(as, fs)
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/feed?cursor=abc&limit=5", "", "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+
+		var got models.FeedResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if len(got.Events) != 1 || got.Events[0].ActorID != nil || got.NextCursor == nil || *got.NextCursor != next.Encode() {
+			t.Fatalf("response = %s", w.Body.String())
+		}
+	})
+}
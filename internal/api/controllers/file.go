@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/models"
+)
+
+type FileStorage interface {
+	GetBaseURL() string
+	StatObject(ctx context.Context, objectName string) (models.BucketObject, error)
+	GetObject(ctx context.Context, objectName string) (io.ReadCloser, error)
+	UploadObject(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) error
+	VerifyDownload(objectName string, query url.Values) error
+	VerifyUpload(objectName, contentType string, size int64, query url.Values) error
+}
+
+// FilesController serves the objects of the filesystem storage driver, it stands in for the bucket: GET is what
+// image URLs point to and PUT is where presigned uploads go, both authorized by the signature in the URL, not a token
+type FilesController struct {
+	router *gin.Engine
+	files  FileStorage
+}
+
+func NewFilesController(e *gin.Engine, fs FileStorage) *FilesController {
+	return &FilesController{router: e, files: fs}
+}
+
+func (ctrl *FilesController) RegisterRoutes() {
+	route := "/files"
+	if base, err := url.Parse(ctrl.files.GetBaseURL()); err == nil && base.Path != "" {
+		route = strings.TrimSuffix(base.Path, "/")
+	}
+
+	fileRoutes := ctrl.router.Group(route)
+	{
+		fileRoutes.GET("/*key", ctrl.GetFile)
+		fileRoutes.PUT("/*key", ctrl.PutFile)
+	}
+}
+
+// GetFile GoDoc
+// @Summary Get stored file
+// @Description Download an avatar or wish image kept by the filesystem storage driver, private files need the signature of their URL
+// @Tags files
+// @Produce octet-stream
+// @Param key path string true "Object key"
+// @Param expires query int false "Expiry of the signed URL (Unix time)"
+// @Param signature query string false "Signature of the URL"
+// @Success 200 {file} binary
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /files/{key} [get]
+func (ctrl *FilesController) GetFile(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	if err := ctrl.files.VerifyDownload(key, ctx.Request.URL.Query()); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	info, err := ctrl.files.StatObject(ctx, key)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	object, err := ctrl.files.GetObject(ctx, key)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+	defer func() { _ = object.Close() }()
+
+	contentType := info.ContentType
+	if !strings.HasPrefix(contentType, "image/") { // Never let an uploaded page run on our origin
+		contentType = "application/octet-stream"
+	}
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("X-Content-Type-Options", "nosniff")
+
+	if seeker, ok := object.(io.ReadSeeker); ok { // Files are, ServeContent adds ranges and If-Modified-Since
+		http.ServeContent(ctx.Writer, ctx.Request, "", info.LastModified, seeker)
+		return
+	}
+	ctx.DataFromReader(http.StatusOK, info.Size, contentType, object, nil)
+}
+
+// PutFile GoDoc
+// @Summary Upload file with signed URL
+// @Description Target of the upload URLs the filesystem storage driver hands out, Content-Type and Content-Length must match the signed ones
+// @Tags files
+// @Accept octet-stream
+// @Param key path string true "Object key"
+// @Param expires query int true "Expiry of the signed URL (Unix time)"
+// @Param signature query string true "Signature of the URL"
+// @Success 200
+// @Failure 403 {object} apiModels.APIError
+// @Failure 411 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /files/{key} [put]
+func (ctrl *FilesController) PutFile(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	size := ctx.Request.ContentLength
+	if size < 0 {
+		apiModels.Error(ctx, http.StatusLengthRequired, "Content-Length is required")
+		return
+	}
+
+	contentType := ctx.GetHeader("Content-Type")
+	if err := ctrl.files.VerifyUpload(key, contentType, size, ctx.Request.URL.Query()); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, size)
+	if err := ctrl.files.UploadObject(ctx, key, body, size, contentType); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type fileStorageMock struct {
+	objects          map[string]string
+	verifyDownloadFn func(objectName string, query url.Values) error
+	verifyUploadFn   func(objectName, contentType string, size int64, query url.Values) error
+}
+
+func (m *fileStorageMock) GetBaseURL() string {
+	return "https://wishlist.example/files"
+}
+
+func (m *fileStorageMock) StatObject(_ context.Context, objectName string) (models.BucketObject, error) {
+	data, ok := m.objects[objectName]
+	if !ok {
+		return models.BucketObject{}, svcErr.NotFoundError{Entity: "Upload", Field: "key", Value: objectName}
+	}
+	return models.BucketObject{Key: objectName, Size: int64(len(data)), ContentType: http.DetectContentType([]byte(data)), LastModified: time.Now()}, nil
+}
+
+func (m *fileStorageMock) GetObject(_ context.Context, objectName string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(m.objects[objectName])), nil
+}
+
+func (m *fileStorageMock) UploadObject(_ context.Context, objectName string, reader io.Reader, _ int64, _ string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	m.objects[objectName] = string(data)
+	return nil
+}
+
+func (m *fileStorageMock) VerifyDownload(objectName string, query url.Values) error {
+	if m.verifyDownloadFn != nil {
+		return m.verifyDownloadFn(objectName, query)
+	}
+	return nil
+}
+
+func (m *fileStorageMock) VerifyUpload(objectName, contentType string, size int64, query url.Values) error {
+	if m.verifyUploadFn != nil {
+		return m.verifyUploadFn(objectName, contentType, size, query)
+	}
+	return nil
+}
+
+func setupFilesControllerForTest(fs *fileStorageMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	ctrl := NewFilesController(router, fs)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestFilesController_GetFile(t *testing.T) {
+	const png = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+	t.Run("serves image", func(t *testing.T) {
+		router := setupFilesControllerForTest(&fileStorageMock{objects: map[string]string{"avatars/u/1/original": png}})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/avatars/u/1/original", nil))
+		if w.Code != http.StatusOK || w.Body.String() != png {
+			t.Fatalf("status = %d, body = %q", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("Content-Type"); got != "image/png" {
+			t.Fatalf("Content-Type = %s, want image/png", got)
+		}
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		fs := &fileStorageMock{
+			objects: map[string]string{"avatars/u/1/original": png},
+			verifyDownloadFn: func(objectName string, query url.Values) error {
+				if query.Get("signature") != "good" {
+					return svcErr.ForbiddenError{Message: "missing or invalid signature"}
+				}
+				return nil
+			},
+		}
+		router := setupFilesControllerForTest(fs)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/avatars/u/1/original?signature=bad", nil))
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		router := setupFilesControllerForTest(&fileStorageMock{objects: map[string]string{}})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/avatars/u/1/original", nil))
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("html is not served as a page", func(t *testing.T) {
+		router := setupFilesControllerForTest(&fileStorageMock{objects: map[string]string{"uploads/u/1": "<html><script>alert(1)</script>"}})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/uploads/u/1", nil))
+		if got := w.Header().Get("Content-Type"); got != "application/octet-stream" {
+			t.Fatalf("Content-Type = %s, want application/octet-stream", got)
+		}
+	})
+}
+
+func TestFilesController_PutFile(t *testing.T) {
+	t.Run("stores signed upload", func(t *testing.T) {
+		fs := &fileStorageMock{
+			objects: map[string]string{},
+			verifyUploadFn: func(objectName, contentType string, size int64, query url.Values) error {
+				if objectName != "uploads/u/1" || contentType != "image/png" || size != 4 {
+					t.Fatalf("VerifyUpload(%s, %s, %d)", objectName, contentType, size)
+				}
+				return nil
+			},
+		}
+		router := setupFilesControllerForTest(fs)
+		req := httptest.NewRequest(http.MethodPut, "/files/uploads/u/1?expires=1&signature=abc", strings.NewReader("data"))
+		req.Header.Set("Content-Type", "image/png")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || fs.objects["uploads/u/1"] != "data" {
+			t.Fatalf("status = %d, stored = %q", w.Code, fs.objects["uploads/u/1"])
+		}
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		fs := &fileStorageMock{
+			objects: map[string]string{},
+			verifyUploadFn: func(string, string, int64, url.Values) error {
+				return svcErr.ForbiddenError{Message: "missing or invalid signature"}
+			},
+		}
+		router := setupFilesControllerForTest(fs)
+		req := httptest.NewRequest(http.MethodPut, "/files/uploads/u/1", strings.NewReader("data"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden || len(fs.objects) != 0 {
+			t.Fatalf("status = %d, objects = %v", w.Code, fs.objects)
+		}
+	})
+
+	t.Run("unknown length", func(t *testing.T) {
+		router := setupFilesControllerForTest(&fileStorageMock{objects: map[string]string{}})
+		req := httptest.NewRequest(http.MethodPut, "/files/uploads/u/1", strings.NewReader("data"))
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusLengthRequired {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusLengthRequired)
+		}
+	})
+}
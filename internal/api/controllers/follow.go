@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+)
+
+type FollowService interface {
+	Follow(ctx context.Context, followerID, followeeID uuid.UUID) (models.Follow, error)
+	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	GetFollowState(ctx context.Context, userID, otherUserID uuid.UUID) (models.FollowState, error)
+	GetFollowers(ctx context.Context, userID uuid.UUID) ([]models.Follow, error)
+	GetFollowing(ctx context.Context, userID uuid.UUID) ([]models.Follow, error)
+	AcceptFollower(ctx context.Context, userID, followerID uuid.UUID) error
+	RemoveFollower(ctx context.Context, userID, followerID uuid.UUID) error
+}
+
+type FollowsController struct {
+	router        *gin.Engine
+	mw            *middlewares.Middlewares
+	followService FollowService
+}
+
+func NewFollowsController(e *gin.Engine, mw *middlewares.Middlewares, fs FollowService) *FollowsController {
+	return &FollowsController{router: e, mw: mw, followService: fs}
+}
+
+func (ctrl *FollowsController) RegisterRoutes() {
+	basePath := ctrl.router.Group(viper.GetString(config.ApiBasePath))
+	userRoutes := basePath.Group("/users")
+	{
+		authedUserRoutes := userRoutes.Group("").Use(ctrl.mw.AuthMiddleware())
+		{
+			authedUserRoutes.GET("/me/followers", ctrl.GetFollowers)
+			authedUserRoutes.POST("/me/followers/:user_id/accept", ctrl.AcceptFollower)
+			authedUserRoutes.DELETE("/me/followers/:user_id", ctrl.RemoveFollower)
+			authedUserRoutes.GET("/me/following", ctrl.GetFollowing)
+
+			authedUserRoutes.GET("/:user_id/follow", ctrl.GetFollowState)
+			authedUserRoutes.POST("/:user_id/follow", ctrl.Follow)
+			authedUserRoutes.DELETE("/:user_id/follow", ctrl.Unfollow)
+		}
+	}
+}
+
+// GetFollowState GoDoc
+// @Summary Get follow state
+// @Description Get whether current user follows the user and the other way round
+// @Tags follows
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path string true "User ID (UUID)"
+// @Success 200 {object} models.FollowState
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/{user_id}/follow [get]
+func (ctrl *FollowsController) GetFollowState(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	otherUserID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	state, err := ctrl.followService.GetFollowState(ctx, userID, otherUserID)
+	if err != nil {
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, state)
+}
+
+// Follow GoDoc
+// @Summary Follow user
+// @Description Send a follow request, followers-only wishlists open up once the user accepts it
+// @Tags follows
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path string true "User ID (UUID)"
+// @Success 201 {object} models.FollowResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 409 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/{user_id}/follow [post]
+func (ctrl *FollowsController) Follow(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	followeeID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	follow, err := ctrl.followService.Follow(ctx, userID, followeeID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, follow.ToResponse())
+}
+
+// Unfollow GoDoc
+// @Summary Unfollow user
+// @Description Stop following the user or withdraw the follow request
+// @Tags follows
+// @Security BearerAuth
+// @Param user_id path string true "User ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/{user_id}/follow [delete]
+func (ctrl *FollowsController) Unfollow(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	followeeID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err = ctrl.followService.Unfollow(ctx, userID, followeeID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetFollowers GoDoc
+// @Summary Get followers
+// @Description Get followers of current user together with the follow requests waiting for an answer
+// @Tags follows
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.FollowResponse
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/me/followers [get]
+func (ctrl *FollowsController) GetFollowers(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	followers, err := ctrl.followService.GetFollowers(ctx, userID)
+	if err != nil {
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := make([]models.FollowResponse, len(followers))
+	for i, follow := range followers {
+		response[i] = follow.ToResponse()
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetFollowing GoDoc
+// @Summary Get followed users
+// @Description Get users current user follows or asked to follow
+// @Tags follows
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.FollowResponse
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/me/following [get]
+func (ctrl *FollowsController) GetFollowing(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	following, err := ctrl.followService.GetFollowing(ctx, userID)
+	if err != nil {
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := make([]models.FollowResponse, len(following))
+	for i, follow := range following {
+		response[i] = follow.ToResponse()
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// AcceptFollower GoDoc
+// @Summary Accept follow request
+// @Description Accept the follow request the user sent to current user
+// @Tags follows
+// @Security BearerAuth
+// @Param user_id path string true "Follower ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/me/followers/{user_id}/accept [post]
+func (ctrl *FollowsController) AcceptFollower(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	followerID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err = ctrl.followService.AcceptFollower(ctx, userID, followerID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveFollower GoDoc
+// @Summary Remove follower
+// @Description Decline the follow request or remove the follower of current user
+// @Tags follows
+// @Security BearerAuth
+// @Param user_id path string true "Follower ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/me/followers/{user_id} [delete]
+func (ctrl *FollowsController) RemoveFollower(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	followerID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err = ctrl.followService.RemoveFollower(ctx, userID, followerID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type followControllerServiceMock struct {
+	followFn         func(ctx context.Context, followerID, followeeID uuid.UUID) (models.Follow, error)
+	unfollowFn       func(ctx context.Context, followerID, followeeID uuid.UUID) error
+	getFollowStateFn func(ctx context.Context, userID, otherUserID uuid.UUID) (models.FollowState, error)
+	getFollowersFn   func(ctx context.Context, userID uuid.UUID) ([]models.Follow, error)
+	getFollowingFn   func(ctx context.Context, userID uuid.UUID) ([]models.Follow, error)
+	acceptFollowerFn func(ctx context.Context, userID, followerID uuid.UUID) error
+	removeFollowerFn func(ctx context.Context, userID, followerID uuid.UUID) error
+}
+
+func (m *followControllerServiceMock) Follow(ctx context.Context, followerID, followeeID uuid.UUID) (models.Follow, error) {
+	if m.followFn != nil {
+		return m.followFn(ctx, followerID, followeeID)
+	}
+	return models.Follow{}, nil
+}
+
+func (m *followControllerServiceMock) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	if m.unfollowFn != nil {
+		return m.unfollowFn(ctx, followerID, followeeID)
+	}
+	return nil
+}
+
+func (m *followControllerServiceMock) GetFollowState(ctx context.Context, userID, otherUserID uuid.UUID) (models.FollowState, error) {
+	if m.getFollowStateFn != nil {
+		return m.getFollowStateFn(ctx, userID, otherUserID)
+	}
+	return models.FollowState{}, nil
+}
+
+func (m *followControllerServiceMock) GetFollowers(ctx context.Context, userID uuid.UUID) ([]models.Follow, error) {
+	if m.getFollowersFn != nil {
+		return m.getFollowersFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *followControllerServiceMock) GetFollowing(ctx context.Context, userID uuid.UUID) ([]models.Follow, error) {
+	if m.getFollowingFn != nil {
+		return m.getFollowingFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *followControllerServiceMock) AcceptFollower(ctx context.Context, userID, followerID uuid.UUID) error {
+	if m.acceptFollowerFn != nil {
+		return m.acceptFollowerFn(ctx, userID, followerID)
+	}
+	return nil
+}
+
+func (m *followControllerServiceMock) RemoveFollower(ctx context.Context, userID, followerID uuid.UUID) error {
+	if m.removeFollowerFn != nil {
+		return m.removeFollowerFn(ctx, userID, followerID)
+	}
+	return nil
+}
+
+func setupFollowControllerForTest(as *listControllerAuthMock, fs *followControllerServiceMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	viper.Set(config.ApiBasePath, "/api/v1")
+	router := gin.New()
+	mw := middlewares.NewMiddlewares(as)
+	NewUsersController(router, mw, nil, nil).RegisterRoutes() // Both controllers share the /users prefix
+	ctrl := NewFollowsController(router, mw, fs)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestFollowsController_Follow(t *testing.T) {
+	currentUserID := uuid.New()
+	followeeID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("invalid user ID", func(t *testing.T) {
+		router := setupFollowControllerForTest(as, &followControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/users/not-uuid/follow", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("already following", func(t *testing.T) {
+		fs := &followControllerServiceMock{followFn: func(ctx context.Context, followerID, gotFolloweeID uuid.UUID) (models.Follow, error) {
+			return models.Follow{}, svcErr.ConflictError{Message: "already following"}
+		}}
+		router := setupFollowControllerForTest(as, fs)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/users/"+followeeID.String()+"/follow", "", "ok")
+		if w.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		fs := &followControllerServiceMock{followFn: func(ctx context.Context, followerID, gotFolloweeID uuid.UUID) (models.Follow, error) {
+			if followerID != currentUserID || gotFolloweeID != followeeID {
+				t.Fatalf("unexpected follow args")
+			}
+			return models.Follow{FollowerID: followerID, FolloweeID: gotFolloweeID}, nil
+		}}
+		router := setupFollowControllerForTest(as, fs)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/users/"+followeeID.String()+"/follow", "", "ok")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+		}
+		var got models.FollowResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || got.Status != models.FollowStatusRequested {
+			t.Fatalf("response = %s, want a pending request", w.Body.String())
+		}
+	})
+}
+
+func TestFollowsController_GetFollowState(t *testing.T) {
+	currentUserID := uuid.New()
+	otherUserID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+	fs := &followControllerServiceMock{getFollowStateFn: func(ctx context.Context, userID, gotOtherUserID uuid.UUID) (models.FollowState, error) {
+		if userID != currentUserID || gotOtherUserID != otherUserID {
+			t.Fatalf("unexpected follow state args")
+		}
+		return models.FollowState{Following: models.FollowStatusAccepted, FollowedBy: models.FollowStatusNone}, nil
+	}}
+	router := setupFollowControllerForTest(as, fs)
+
+	w := listJSONRequest(router, http.MethodGet, "/api/v1/users/"+otherUserID.String()+"/follow", "", "ok")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var got models.FollowState
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || got.Following != models.FollowStatusAccepted {
+		t.Fatalf("response = %s", w.Body.String())
+	}
+}
+
+func TestFollowsController_Followers(t *testing.T) {
+	currentUserID := uuid.New()
+	followerID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("list", func(t *testing.T) {
+		fs := &followControllerServiceMock{getFollowersFn: func(ctx context.Context, userID uuid.UUID) ([]models.Follow, error) {
+			return []models.Follow{{FollowerID: followerID, FolloweeID: userID}}, nil
+		}}
+		router := setupFollowControllerForTest(as, fs)
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/users/me/followers", "", "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+		var got []models.FollowResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || len(got) != 1 || got[0].FollowerID != followerID {
+			t.Fatalf("response = %s", w.Body.String())
+		}
+	})
+
+	t.Run("accept not found", func(t *testing.T) {
+		fs := &followControllerServiceMock{acceptFollowerFn: func(ctx context.Context, userID, gotFollowerID uuid.UUID) error {
+			return svcErr.NotFoundError{Entity: "follow request", Field: "user_id", Value: gotFollowerID.String()}
+		}}
+		router := setupFollowControllerForTest(as, fs)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/users/me/followers/"+followerID.String()+"/accept", "", "ok")
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		var removed uuid.UUID
+		fs := &followControllerServiceMock{removeFollowerFn: func(ctx context.Context, userID, gotFollowerID uuid.UUID) error {
+			removed = gotFollowerID
+			return nil
+		}}
+		router := setupFollowControllerForTest(as, fs)
+		w := listJSONRequest(router, http.MethodDelete, "/api/v1/users/me/followers/"+followerID.String(), "", "ok")
+		if w.Code != http.StatusNoContent || removed != followerID {
+			t.Fatalf("status = %d removed = %s, want %d and %s", w.Code, removed, http.StatusNoContent, followerID)
+		}
+	})
+}
@@ -0,0 +1,727 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type GroupService interface {
+	CreateGroup(ctx context.Context, userID uuid.UUID, req models.CreateGroupRequest) (models.Group, error)
+	GetUserGroups(ctx context.Context, userID uuid.UUID) ([]models.Group, error)
+	GetGroupInvitations(ctx context.Context, userID uuid.UUID) ([]models.Group, error)
+	GetGroup(ctx context.Context, groupID, userID uuid.UUID) (models.Group, []models.GroupMember, error)
+	DeleteGroup(ctx context.Context, groupID, userID uuid.UUID) error
+	InviteMember(ctx context.Context, groupID, userID uuid.UUID, req models.InviteGroupMemberRequest) (models.GroupMember, error)
+	AcceptInvitation(ctx context.Context, groupID, userID uuid.UUID) error
+	RemoveMember(ctx context.Context, groupID, memberID, userID uuid.UUID) error
+	SetMemberList(ctx context.Context, groupID, userID uuid.UUID, req models.SetGroupListRequest) error
+	AddExclusion(ctx context.Context, groupID, userID uuid.UUID, req models.CreateGroupExclusionRequest) ([]models.GroupExclusion, error)
+	GetExclusions(ctx context.Context, groupID, userID uuid.UUID) ([]models.GroupExclusion, error)
+	DeleteExclusion(ctx context.Context, groupID, giverID, receiverID, userID uuid.UUID) error
+	Draw(ctx context.Context, groupID, userID uuid.UUID) (models.GroupDraw, error)
+	GetDraws(ctx context.Context, groupID, userID uuid.UUID) ([]models.GroupDraw, error)
+	GetAssignment(ctx context.Context, groupID, userID uuid.UUID) (models.GroupAssignee, error)
+}
+
+// GroupListService opens the list of the drawn receiver the same way as any other list the giver looks at
+type GroupListService interface {
+	GetListWithWishes(ctx context.Context, listID, requestedByUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error)
+}
+
+type GroupsController struct {
+	router       *gin.Engine
+	mw           *middlewares.Middlewares
+	groupService GroupService
+	listService  GroupListService
+}
+
+func NewGroupsController(e *gin.Engine, mw *middlewares.Middlewares, gs GroupService, ls GroupListService) *GroupsController {
+	return &GroupsController{router: e, mw: mw, groupService: gs, listService: ls}
+}
+
+func (ctrl *GroupsController) RegisterRoutes() {
+	basePath := ctrl.router.Group(viper.GetString(config.ApiBasePath))
+	groupRoutes := basePath.Group("/groups")
+	{
+		authedGroupRoutes := groupRoutes.Group("").Use(ctrl.mw.AuthMiddleware())
+		{
+			authedGroupRoutes.POST("", ctrl.CreateGroup)
+			authedGroupRoutes.GET("", ctrl.GetUserGroups)
+			authedGroupRoutes.GET("/invitations", ctrl.GetGroupInvitations)
+			authedGroupRoutes.GET("/:group_id", ctrl.GetGroup)
+			authedGroupRoutes.DELETE("/:group_id", ctrl.DeleteGroup)
+			authedGroupRoutes.POST("/:group_id/members", ctrl.InviteMember)
+			authedGroupRoutes.POST("/:group_id/members/accept", ctrl.AcceptInvitation)
+			authedGroupRoutes.DELETE("/:group_id/members/:user_id", ctrl.RemoveMember)
+			authedGroupRoutes.PUT("/:group_id/list", ctrl.SetMemberList)
+			authedGroupRoutes.GET("/:group_id/exclusions", ctrl.GetExclusions)
+			authedGroupRoutes.POST("/:group_id/exclusions", ctrl.AddExclusion)
+			authedGroupRoutes.DELETE("/:group_id/exclusions/:giver_id/:receiver_id", ctrl.DeleteExclusion)
+			authedGroupRoutes.GET("/:group_id/draws", ctrl.GetDraws)
+			authedGroupRoutes.POST("/:group_id/draws", ctrl.Draw)
+			authedGroupRoutes.GET("/:group_id/assignment", ctrl.GetAssignment)
+		}
+	}
+}
+
+// CreateGroup GoDoc
+// @Summary Create gift exchange group
+// @Description Create a group, e.g. a Secret Santa, organized by current user
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateGroupRequest true "Group"
+// @Success 201 {object} models.GroupResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups [post]
+func (ctrl *GroupsController) CreateGroup(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.CreateGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	group, err := ctrl.groupService.CreateGroup(ctx, userID, req)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, group.ToResponse())
+}
+
+// GetUserGroups GoDoc
+// @Summary Get current user groups
+// @Description Get gift exchange groups current user organizes or joined
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.GroupResponse
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups [get]
+func (ctrl *GroupsController) GetUserGroups(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groups, err := ctrl.groupService.GetUserGroups(ctx, userID)
+	if err != nil {
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, groupResponses(groups))
+}
+
+// GetGroupInvitations GoDoc
+// @Summary Get group invitations
+// @Description Get gift exchange groups current user is invited to and did not accept yet
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.GroupResponse
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/invitations [get]
+func (ctrl *GroupsController) GetGroupInvitations(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groups, err := ctrl.groupService.GetGroupInvitations(ctx, userID)
+	if err != nil {
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, groupResponses(groups))
+}
+
+// GetGroup GoDoc
+// @Summary Get group
+// @Description Get gift exchange group with its members and invitations
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Success 200 {object} models.GroupDetailsResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id} [get]
+func (ctrl *GroupsController) GetGroup(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	group, members, err := ctrl.groupService.GetGroup(ctx, groupID, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := models.GroupDetailsResponse{GroupResponse: group.ToResponse(), Members: make([]models.GroupMemberResponse, len(members))}
+	for i, member := range members {
+		response.Members[i] = member.ToResponse()
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteGroup GoDoc
+// @Summary Delete group
+// @Description Delete gift exchange group with its draws, only the organizer can
+// @Tags groups
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id} [delete]
+func (ctrl *GroupsController) DeleteGroup(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	if err = ctrl.groupService.DeleteGroup(ctx, groupID, userID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// InviteMember GoDoc
+// @Summary Invite group member
+// @Description Invite a user to the gift exchange, only the organizer can
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Param request body models.InviteGroupMemberRequest true "Invitation"
+// @Success 201 {object} models.GroupMemberResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 409 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/members [post]
+func (ctrl *GroupsController) InviteMember(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	var req models.InviteGroupMemberRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	member, err := ctrl.groupService.InviteMember(ctx, groupID, userID, req)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, member.ToResponse())
+}
+
+// AcceptInvitation GoDoc
+// @Summary Accept group invitation
+// @Description Accept invitation of current user to the gift exchange
+// @Tags groups
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/members/accept [post]
+func (ctrl *GroupsController) AcceptInvitation(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	if err = ctrl.groupService.AcceptInvitation(ctx, groupID, userID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveMember GoDoc
+// @Summary Remove group member
+// @Description Remove member or invitation from the gift exchange; members may also leave on their own
+// @Tags groups
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Param user_id path string true "User ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/members/{user_id} [delete]
+func (ctrl *GroupsController) RemoveMember(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	memberID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err = ctrl.groupService.RemoveMember(ctx, groupID, memberID, userID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// SetMemberList GoDoc
+// @Summary Choose list for the group
+// @Description Choose the public wishlist current user wants gifts from, null clears it
+// @Tags groups
+// @Accept json
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Param request body models.SetGroupListRequest true "List"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/list [put]
+func (ctrl *GroupsController) SetMemberList(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	var req models.SetGroupListRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	if err = ctrl.groupService.SetMemberList(ctx, groupID, userID, req); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetExclusions GoDoc
+// @Summary Get group exclusions
+// @Description Get who must not draw whom, only the organizer can
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Success 200 {array} models.GroupExclusionResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/exclusions [get]
+func (ctrl *GroupsController) GetExclusions(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	exclusions, err := ctrl.groupService.GetExclusions(ctx, groupID, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, groupExclusionResponses(exclusions))
+}
+
+// AddExclusion GoDoc
+// @Summary Add group exclusion
+// @Description Keep a member from drawing another one, e.g. their partner; mutual adds the reverse rule too
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Param request body models.CreateGroupExclusionRequest true "Exclusion"
+// @Success 201 {array} models.GroupExclusionResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/exclusions [post]
+func (ctrl *GroupsController) AddExclusion(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	var req models.CreateGroupExclusionRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	exclusions, err := ctrl.groupService.AddExclusion(ctx, groupID, userID, req)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, groupExclusionResponses(exclusions))
+}
+
+// DeleteExclusion GoDoc
+// @Summary Delete group exclusion
+// @Description Let the giver draw the receiver again, only the organizer can
+// @Tags groups
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Param giver_id path string true "Giver ID (UUID)"
+// @Param receiver_id path string true "Receiver ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/exclusions/{giver_id}/{receiver_id} [delete]
+func (ctrl *GroupsController) DeleteExclusion(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	giverID, err := uuid.Parse(ctx.Param("giver_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid giver ID")
+		return
+	}
+
+	receiverID, err := uuid.Parse(ctx.Param("receiver_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid receiver ID")
+		return
+	}
+
+	if err = ctrl.groupService.DeleteExclusion(ctx, groupID, giverID, receiverID, userID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetDraws GoDoc
+// @Summary Get group draws
+// @Description Get the audit trail of the draws, newest first; it never shows who drew whom
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Success 200 {array} models.GroupDrawResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/draws [get]
+func (ctrl *GroupsController) GetDraws(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	draws, err := ctrl.groupService.GetDraws(ctx, groupID, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := make([]models.GroupDrawResponse, len(draws))
+	for i, draw := range draws {
+		response[i] = draw.ToResponse()
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Draw GoDoc
+// @Summary Draw names
+// @Description Assign every accepted member someone to give to and email them; drawing again replaces the assignments
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Success 201 {object} models.GroupDrawResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/draws [post]
+func (ctrl *GroupsController) Draw(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	draw, err := ctrl.groupService.Draw(ctx, groupID, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, draw.ToResponse())
+}
+
+// GetAssignment GoDoc
+// @Summary Get own assignment
+// @Description Get who current user gives to in the latest draw, with the wishlist they chose; 409 when a member left after it
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Group ID (UUID)"
+// @Success 200 {object} models.GroupAssignmentResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 409 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /groups/{group_id}/assignment [get]
+func (ctrl *GroupsController) GetAssignment(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupID, err := uuid.Parse(ctx.Param("group_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid group ID")
+		return
+	}
+
+	assignee, err := ctrl.groupService.GetAssignment(ctx, groupID, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := models.GroupAssignmentResponse{
+		GroupID:  assignee.GroupID,
+		DrawID:   assignee.DrawID,
+		UserID:   assignee.UserID,
+		Username: assignee.Username,
+		Name:     assignee.Name,
+		DrawnAt:  assignee.DrawnAt,
+	}
+
+	if assignee.ListID != nil {
+		list, wishes, err := ctrl.listService.GetListWithWishes(ctx, *assignee.ListID, userID, models.WishFilter{})
+		if err == nil {
+			listResponse := list.ToViewerResponse()
+			listResponse.Wishes = make([]models.WishResponse, len(wishes))
+			for i, wish := range wishes {
+				listResponse.Wishes[i] = wish.ToViewerResponse(&userID)
+			}
+			response.List = &listResponse
+		} else if !isHiddenListError(err) {
+			apiModels.InternalError(ctx, err.Error())
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// isHiddenListError tells that the receiver's list was deleted or is no longer public, the assignment is shown without it
+func isHiddenListError(err error) bool {
+	_, forbidden := errors.AsType[svcErr.ForbiddenError](err)
+	_, notFound := errors.AsType[svcErr.NotFoundError](err)
+	return forbidden || notFound
+}
+
+func groupResponses(groups []models.Group) []models.GroupResponse {
+	response := make([]models.GroupResponse, len(groups))
+	for i, group := range groups {
+		response[i] = group.ToResponse()
+	}
+	return response
+}
+
+func groupExclusionResponses(exclusions []models.GroupExclusion) []models.GroupExclusionResponse {
+	response := make([]models.GroupExclusionResponse, len(exclusions))
+	for i, exclusion := range exclusions {
+		response[i] = exclusion.ToResponse()
+	}
+	return response
+}
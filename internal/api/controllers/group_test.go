@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type groupControllerServiceMock struct {
+	getGroupFn      func(ctx context.Context, groupID, userID uuid.UUID) (models.Group, []models.GroupMember, error)
+	drawFn          func(ctx context.Context, groupID, userID uuid.UUID) (models.GroupDraw, error)
+	getAssignmentFn func(ctx context.Context, groupID, userID uuid.UUID) (models.GroupAssignee, error)
+}
+
+func (m *groupControllerServiceMock) CreateGroup(ctx context.Context, userID uuid.UUID, req models.CreateGroupRequest) (models.Group, error) {
+	return models.Group{}, nil
+}
+
+func (m *groupControllerServiceMock) GetUserGroups(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	return nil, nil
+}
+
+func (m *groupControllerServiceMock) GetGroupInvitations(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	return nil, nil
+}
+
+func (m *groupControllerServiceMock) GetGroup(ctx context.Context, groupID, userID uuid.UUID) (models.Group, []models.GroupMember, error) {
+	if m.getGroupFn != nil {
+		return m.getGroupFn(ctx, groupID, userID)
+	}
+	return models.Group{}, nil, nil
+}
+
+func (m *groupControllerServiceMock) DeleteGroup(ctx context.Context, groupID, userID uuid.UUID) error {
+	return nil
+}
+
+func (m *groupControllerServiceMock) InviteMember(ctx context.Context, groupID, userID uuid.UUID, req models.InviteGroupMemberRequest) (models.GroupMember, error) {
+	return models.GroupMember{}, nil
+}
+
+func (m *groupControllerServiceMock) AcceptInvitation(ctx context.Context, groupID, userID uuid.UUID) error {
+	return nil
+}
+
+func (m *groupControllerServiceMock) RemoveMember(ctx context.Context, groupID, memberID, userID uuid.UUID) error {
+	return nil
+}
+
+func (m *groupControllerServiceMock) SetMemberList(ctx context.Context, groupID, userID uuid.UUID, req models.SetGroupListRequest) error {
+	return nil
+}
+
+func (m *groupControllerServiceMock) AddExclusion(ctx context.Context, groupID, userID uuid.UUID, req models.CreateGroupExclusionRequest) ([]models.GroupExclusion, error) {
+	return nil, nil
+}
+
+func (m *groupControllerServiceMock) GetExclusions(ctx context.Context, groupID, userID uuid.UUID) ([]models.GroupExclusion, error) {
+	return nil, nil
+}
+
+func (m *groupControllerServiceMock) DeleteExclusion(ctx context.Context, groupID, giverID, receiverID, userID uuid.UUID) error {
+	return nil
+}
+
+func (m *groupControllerServiceMock) Draw(ctx context.Context, groupID, userID uuid.UUID) (models.GroupDraw, error) {
+	if m.drawFn != nil {
+		return m.drawFn(ctx, groupID, userID)
+	}
+	return models.GroupDraw{}, nil
+}
+
+func (m *groupControllerServiceMock) GetDraws(ctx context.Context, groupID, userID uuid.UUID) ([]models.GroupDraw, error) {
+	return nil, nil
+}
+
+func (m *groupControllerServiceMock) GetAssignment(ctx context.Context, groupID, userID uuid.UUID) (models.GroupAssignee, error) {
+	if m.getAssignmentFn != nil {
+		return m.getAssignmentFn(ctx, groupID, userID)
+	}
+	return models.GroupAssignee{}, nil
+}
+
+type groupControllerListServiceMock struct {
+	getListWithWishesFn func(ctx context.Context, listID, requestedByUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error)
+}
+
+func (m *groupControllerListServiceMock) GetListWithWishes(ctx context.Context, listID, requestedByUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+	if m.getListWithWishesFn != nil {
+		return m.getListWithWishesFn(ctx, listID, requestedByUserID, filter)
+	}
+	return models.List{}, nil, nil
+}
+
+func setupGroupControllerForTest(as *listControllerAuthMock, gs *groupControllerServiceMock, ls *groupControllerListServiceMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	viper.Set(config.ApiBasePath, "/api/v1")
+	router := gin.New()
+	mw := middlewares.NewMiddlewares(as)
+	ctrl := NewGroupsController(router, mw, gs, ls)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestGroupsController_GetGroup(t *testing.T) {
+	currentUserID := uuid.New()
+	groupID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	gs := &groupControllerServiceMock{getGroupFn: func(ctx context.Context, gID, userID uuid.UUID) (models.Group, []models.GroupMember, error) {
+		members := []models.GroupMember{
+			{UserID: currentUserID, Username: "alice", Email: new("alice@example.com"), AcceptedAt: new(time.Now())},
+			{UserID: uuid.New(), Username: "bob"},
+		}
+		return models.Group{ID: gID, OrganizerID: currentUserID, Title: "Office Secret Santa", MembersCount: 1}, members, nil
+	}}
+	router := setupGroupControllerForTest(as, gs, &groupControllerListServiceMock{})
+
+	w := listJSONRequest(router, http.MethodGet, "/api/v1/groups/"+groupID.String(), "", "ok")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var response models.GroupDetailsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if response.ID != groupID || len(response.Members) != 2 || response.Members[0].Status != "accepted" || response.Members[1].Status != "invited" {
+		t.Fatalf("response = %+v, want the group with an accepted and an invited member", response)
+	}
+	if strings.Contains(w.Body.String(), "alice@example.com") {
+		t.Fatalf("body = %s, want no member emails", w.Body.String())
+	}
+}
+
+func TestGroupsController_Draw(t *testing.T) {
+	currentUserID := uuid.New()
+	groupID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	gs := &groupControllerServiceMock{drawFn: func(ctx context.Context, gID, userID uuid.UUID) (models.GroupDraw, error) {
+		return models.GroupDraw{ID: uuid.New(), GroupID: gID, DrawnBy: userID, Seed: 42, Participants: 3}, nil
+	}}
+	router := setupGroupControllerForTest(as, gs, &groupControllerListServiceMock{})
+
+	t.Run("keeps the seed to itself", func(t *testing.T) {
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/groups/"+groupID.String()+"/draws", "", "ok")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+		}
+		var response models.GroupDrawResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if strings.Contains(w.Body.String(), "seed") || len(response.Commitment) != 64 {
+			t.Fatalf("body = %s, want a commitment and no seed", w.Body.String())
+		}
+	})
+
+	t.Run("invalid group ID", func(t *testing.T) {
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/groups/nope/draws", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestGroupsController_GetAssignment(t *testing.T) {
+	currentUserID := uuid.New()
+	groupID := uuid.New()
+	listID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+	assignee := models.GroupAssignee{GroupID: groupID, DrawID: uuid.New(), UserID: uuid.New(), Username: "bob", Name: "Bob", ListID: &listID, DrawnAt: time.Now()}
+	gs := &groupControllerServiceMock{getAssignmentFn: func(ctx context.Context, gID, userID uuid.UUID) (models.GroupAssignee, error) {
+		return assignee, nil
+	}}
+
+	t.Run("with the receiver's list", func(t *testing.T) {
+		ls := &groupControllerListServiceMock{getListWithWishesFn: func(ctx context.Context, lID, requestedByUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+			if lID != listID || requestedByUserID != currentUserID {
+				t.Fatalf("unexpected args: %s %s", lID, requestedByUserID)
+			}
+			return models.List{ID: listID, UserID: assignee.UserID, Title: "Christmas", Slug: "secret"}, []models.Wish{{ID: uuid.New(), ListID: listID, Title: "Scarf"}}, nil
+		}}
+		router := setupGroupControllerForTest(as, gs, ls)
+
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/groups/"+groupID.String()+"/assignment", "", "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+		}
+
+		var response models.GroupAssignmentResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if response.UserID != assignee.UserID || response.List == nil || len(response.List.Wishes) != 1 || response.List.Slug != "" {
+			t.Fatalf("response = %+v, want the receiver with the viewer's view of their list", response)
+		}
+	})
+
+	t.Run("list no longer public", func(t *testing.T) {
+		ls := &groupControllerListServiceMock{getListWithWishesFn: func(ctx context.Context, lID, requestedByUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+			return models.List{}, nil, svcErr.ForbiddenError{Message: "you do not have access to this wishlist"}
+		}}
+		router := setupGroupControllerForTest(as, gs, ls)
+
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/groups/"+groupID.String()+"/assignment", "", "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+		}
+		var response models.GroupAssignmentResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if response.List != nil || response.Name != "Bob" {
+			t.Fatalf("response = %+v, want the receiver without the list", response)
+		}
+	})
+
+	t.Run("not drawn yet", func(t *testing.T) {
+		notDrawn := &groupControllerServiceMock{getAssignmentFn: func(ctx context.Context, gID, userID uuid.UUID) (models.GroupAssignee, error) {
+			return models.GroupAssignee{}, svcErr.NotFoundError{Entity: "assignment", Field: "group_id", Value: gID.String()}
+		}}
+		router := setupGroupControllerForTest(as, notDrawn, &groupControllerListServiceMock{})
+
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/groups/"+groupID.String()+"/assignment", "", "ok")
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+}
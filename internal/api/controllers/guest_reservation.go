@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+)
+
+type GuestReservationService interface {
+	ReserveWish(ctx context.Context, slug string, wishID uuid.UUID, clientIP string, req models.GuestReserveWishRequest) (models.GuestReservationDetails, error)
+	GetReservation(ctx context.Context, token string) (models.GuestReservationDetails, error)
+	ConfirmReservation(ctx context.Context, token string) (models.GuestReservationDetails, error)
+	ReleaseReservation(ctx context.Context, token string) error
+}
+
+// GuestReservationsController serves people without an account, the shared link and the emailed token are all they have
+type GuestReservationsController struct {
+	router                  *gin.Engine
+	mw                      *middlewares.Middlewares
+	guestReservationService GuestReservationService
+}
+
+func NewGuestReservationsController(e *gin.Engine, mw *middlewares.Middlewares, gs GuestReservationService) *GuestReservationsController {
+	return &GuestReservationsController{router: e, mw: mw, guestReservationService: gs}
+}
+
+func (ctrl *GuestReservationsController) RegisterRoutes() {
+	basePath := ctrl.router.Group(viper.GetString(config.ApiBasePath))
+	basePath.POST("/lists/shared/:slug/wishes/:wish_id/guest-reserve", ctrl.ReserveWish)
+	guestRoutes := basePath.Group("/guest-reservations")
+	{
+		guestRoutes.GET("/:token", ctrl.GetReservation)
+		guestRoutes.POST("/:token/confirm", ctrl.ConfirmReservation)
+		guestRoutes.DELETE("/:token", ctrl.ReleaseReservation)
+	}
+}
+
+// ReserveWish GoDoc
+// @Summary Reserve wish as guest
+// @Description Reserve units of a wish from a list opened by shared link without an account. The reservation is pending until confirmed with the link emailed to the guest, it is released if that doesn't happen in time. Limited per client address per hour and in how many unconfirmed reservations a list and an email address may have
+// @Tags guest-reservations
+// @Accept json
+// @Produce json
+// @Param slug path string true "Shared link slug"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Param request body models.GuestReserveWishRequest true "Guest name, email and units to reserve"
+// @Success 202 {object} models.GuestReservationResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 429 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/shared/{slug}/wishes/{wish_id}/guest-reserve [post]
+func (ctrl *GuestReservationsController) ReserveWish(ctx *gin.Context) {
+	wishID, err := uuid.Parse(ctx.Param("wish_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid wish ID")
+		return
+	}
+
+	var req models.GuestReserveWishRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	reservation, err := ctrl.guestReservationService.ReserveWish(ctx, ctx.Param("slug"), wishID, ctx.ClientIP(), req)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, reservation.ToResponse())
+}
+
+// GetReservation GoDoc
+// @Summary Get guest reservation
+// @Description Get the reservation the emailed token belongs to
+// @Tags guest-reservations
+// @Produce json
+// @Param token path string true "Token from the email"
+// @Success 200 {object} models.GuestReservationResponse
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /guest-reservations/{token} [get]
+func (ctrl *GuestReservationsController) GetReservation(ctx *gin.Context) {
+	reservation, err := ctrl.guestReservationService.GetReservation(ctx, ctx.Param("token"))
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation.ToResponse())
+}
+
+// ConfirmReservation GoDoc
+// @Summary Confirm guest reservation
+// @Description Keep the reservation for good, it is no longer released automatically
+// @Tags guest-reservations
+// @Produce json
+// @Param token path string true "Token from the email"
+// @Success 200 {object} models.GuestReservationResponse
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /guest-reservations/{token}/confirm [post]
+func (ctrl *GuestReservationsController) ConfirmReservation(ctx *gin.Context) {
+	reservation, err := ctrl.guestReservationService.ConfirmReservation(ctx, ctx.Param("token"))
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation.ToResponse())
+}
+
+// ReleaseReservation GoDoc
+// @Summary Release guest reservation
+// @Description Release the reservation the emailed token belongs to, the wish can be reserved by others again
+// @Tags guest-reservations
+// @Param token path string true "Token from the email"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /guest-reservations/{token} [delete]
+func (ctrl *GuestReservationsController) ReleaseReservation(ctx *gin.Context) {
+	if err := ctrl.guestReservationService.ReleaseReservation(ctx, ctx.Param("token")); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type guestReservationControllerServiceMock struct {
+	reserveFn func(ctx context.Context, slug string, wishID uuid.UUID, clientIP string, req models.GuestReserveWishRequest) (models.GuestReservationDetails, error)
+	getFn     func(ctx context.Context, token string) (models.GuestReservationDetails, error)
+	releaseFn func(ctx context.Context, token string) error
+}
+
+func (m *guestReservationControllerServiceMock) ReserveWish(ctx context.Context, slug string, wishID uuid.UUID, clientIP string, req models.GuestReserveWishRequest) (models.GuestReservationDetails, error) {
+	if m.reserveFn != nil {
+		return m.reserveFn(ctx, slug, wishID, clientIP, req)
+	}
+	return models.GuestReservationDetails{}, nil
+}
+
+func (m *guestReservationControllerServiceMock) GetReservation(ctx context.Context, token string) (models.GuestReservationDetails, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, token)
+	}
+	return models.GuestReservationDetails{}, nil
+}
+
+func (m *guestReservationControllerServiceMock) ConfirmReservation(ctx context.Context, token string) (models.GuestReservationDetails, error) {
+	return models.GuestReservationDetails{GuestReservation: models.GuestReservation{ConfirmedAt: new(time.Now())}}, nil
+}
+
+func (m *guestReservationControllerServiceMock) ReleaseReservation(ctx context.Context, token string) error {
+	if m.releaseFn != nil {
+		return m.releaseFn(ctx, token)
+	}
+	return nil
+}
+
+func setupGuestReservationControllerForTest(gs *guestReservationControllerServiceMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	viper.Set(config.ApiBasePath, "/api/v1")
+	router := gin.New()
+	mw := middlewares.NewMiddlewares(&listControllerAuthMock{})
+	ctrl := NewGuestReservationsController(router, mw, gs)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestGuestReservationsController_ReserveWish(t *testing.T) {
+	wishID := uuid.New()
+
+	t.Run("without an account", func(t *testing.T) {
+		gs := &guestReservationControllerServiceMock{reserveFn: func(ctx context.Context, slug string, gotWishID uuid.UUID, clientIP string, req models.GuestReserveWishRequest) (models.GuestReservationDetails, error) {
+			if slug != "secret" || gotWishID != wishID || clientIP == "" || req.Name != "Aunt Mary" || req.Email != "mary@example.com" {
+				t.Fatalf("unexpected args: %s %s %q %+v", slug, gotWishID, clientIP, req)
+			}
+			reservation := models.GuestReservation{WishID: wishID, Name: req.Name, Email: req.Email, Token: "guest-token", Units: 1, ReservedUntil: new(time.Now().Add(time.Hour))}
+			return models.GuestReservationDetails{GuestReservation: reservation, WishTitle: "Bike", ListTitle: "Birthday", ListSlug: slug}, nil
+		}}
+		router := setupGuestReservationControllerForTest(gs)
+
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/shared/secret/wishes/"+wishID.String()+"/guest-reserve", `{"name":"Aunt Mary","email":"mary@example.com"}`, "")
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusAccepted, w.Body.String())
+		}
+		if strings.Contains(w.Body.String(), "guest-token") || strings.Contains(w.Body.String(), "mary@example.com") {
+			t.Fatalf("body = %s, want neither the token nor the email, only the mailbox gets them", w.Body.String())
+		}
+		var response models.GuestReservationResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if response.WishID != wishID || response.Confirmed || response.ReservedUntil == nil {
+			t.Fatalf("response = %+v, want a pending reservation", response)
+		}
+	})
+
+	t.Run("missing email", func(t *testing.T) {
+		router := setupGuestReservationControllerForTest(&guestReservationControllerServiceMock{})
+
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/shared/secret/wishes/"+wishID.String()+"/guest-reserve", `{"name":"Aunt Mary"}`, "")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("invalid wish ID", func(t *testing.T) {
+		router := setupGuestReservationControllerForTest(&guestReservationControllerServiceMock{})
+
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/shared/secret/wishes/nope/guest-reserve", `{"name":"Aunt Mary","email":"mary@example.com"}`, "")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("too many requests", func(t *testing.T) {
+		gs := &guestReservationControllerServiceMock{reserveFn: func(ctx context.Context, slug string, gotWishID uuid.UUID, clientIP string, req models.GuestReserveWishRequest) (models.GuestReservationDetails, error) {
+			return models.GuestReservationDetails{}, svcErr.TooManyRequestsError{Message: "too many reservations from your address, try again later"}
+		}}
+		router := setupGuestReservationControllerForTest(gs)
+
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/shared/secret/wishes/"+wishID.String()+"/guest-reserve", `{"name":"Aunt Mary","email":"mary@example.com"}`, "")
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+		}
+	})
+
+	t.Run("private list", func(t *testing.T) {
+		gs := &guestReservationControllerServiceMock{reserveFn: func(ctx context.Context, slug string, gotWishID uuid.UUID, clientIP string, req models.GuestReserveWishRequest) (models.GuestReservationDetails, error) {
+			return models.GuestReservationDetails{}, svcErr.ForbiddenError{Message: "this wishlist is private"}
+		}}
+		router := setupGuestReservationControllerForTest(gs)
+
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/shared/secret/wishes/"+wishID.String()+"/guest-reserve", `{"name":"Aunt Mary","email":"mary@example.com"}`, "")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+}
+
+func TestGuestReservationsController_ManageByToken(t *testing.T) {
+	gs := &guestReservationControllerServiceMock{
+		getFn: func(ctx context.Context, token string) (models.GuestReservationDetails, error) {
+			if token != "guest-token" {
+				return models.GuestReservationDetails{}, svcErr.NotFoundError{Entity: "reservation", Field: "token", Value: token}
+			}
+			return models.GuestReservationDetails{GuestReservation: models.GuestReservation{Name: "Aunt Mary", Units: 1}, WishTitle: "Bike"}, nil
+		},
+		releaseFn: func(ctx context.Context, token string) error {
+			if token != "guest-token" {
+				t.Fatalf("released %q, want the token from the path", token)
+			}
+			return nil
+		},
+	}
+	router := setupGuestReservationControllerForTest(gs)
+
+	t.Run("get", func(t *testing.T) {
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/guest-reservations/guest-token", "", "")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"wish_title":"Bike"`) {
+			t.Fatalf("status = %d, body = %s, want the reservation", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/guest-reservations/other", "", "")
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/guest-reservations/guest-token/confirm", "", "")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"confirmed":true`) {
+			t.Fatalf("status = %d, body = %s, want the confirmed reservation", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		w := listJSONRequest(router, http.MethodDelete, "/api/v1/guest-reservations/guest-token", "", "")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -17,14 +18,16 @@ import (
 type ListService interface {
 	CreateList(ctx context.Context, userID uuid.UUID, req models.CreateListRequest) (models.List, error)
 	GetListByID(ctx context.Context, listID, requestedByUserID uuid.UUID) (models.List, error)
-	GetListBySharedLink(ctx context.Context, token string) (models.List, error)
-	GetListWithWishes(ctx context.Context, listID, requestedByUserID uuid.UUID) (models.List, []models.Wish, error)
-	GetListWithWishesBySharedLink(ctx context.Context, token string) (models.List, []models.Wish, error)
+	GetListBySharedLink(ctx context.Context, token string, requestedByUserID *uuid.UUID) (models.List, error)
+	GetListWithWishes(ctx context.Context, listID, requestedByUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error)
+	GetListWithWishesBySharedLink(ctx context.Context, token string, requestedByUserID *uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error)
 	GetCurrentUserLists(ctx context.Context, userID uuid.UUID) ([]models.List, error)
-	GetPublicListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error)
+	GetPublicListsByUserID(ctx context.Context, userID, requestedByUserID uuid.UUID) ([]models.List, error)
 	UpdateList(ctx context.Context, listID, userID uuid.UUID, req models.UpdateListRequest) error
 	RotateSharedLink(ctx context.Context, listID, userID uuid.UUID) (string, error)
 	DeleteList(ctx context.Context, listID, userID uuid.UUID) error
+	DuplicateList(ctx context.Context, listID, userID uuid.UUID, req models.DuplicateListRequest) (models.List, error)
+	CopyWish(ctx context.Context, listID, wishID, targetListID, userID uuid.UUID) (models.Wish, error)
 }
 
 type ListsController struct {
@@ -48,6 +51,8 @@ func (ctrl *ListsController) RegisterRoutes() {
 			authedListRoutes.GET("/:list_id", ctrl.GetListByID)
 			authedListRoutes.PATCH("/:list_id", ctrl.UpdateList)
 			authedListRoutes.POST("/:list_id/rotate-share-link", ctrl.RotateSharedLink)
+			authedListRoutes.POST("/:list_id/duplicate", ctrl.DuplicateList)
+			authedListRoutes.POST("/:list_id/wishes/:wish_id/copy-to/:target_list_id", ctrl.CopyWish)
 			authedListRoutes.DELETE("/:list_id", ctrl.DeleteList)
 		}
 		listRoutes.GET("/shared/:slug", ctrl.mw.OptionalAuthMiddleware(), ctrl.GetListBySharedLink)
@@ -106,6 +111,9 @@ func (ctrl *ListsController) CreateList(ctx *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param list_id path string true "List ID (UUID)"
+// @Param status query string false "Only wishes in this status" Enums(open, reserved, purchased, received, archived)
+// @Param sort query string false "Order of the wishes, position by default" Enums(position, price, priority, created_at)
+// @Param order query string false "Sort direction, asc by default" Enums(asc, desc)
 // @Success 200 {object} models.ListResponse
 // @Failure 400 {object} apiModels.APIError
 // @Failure 401 {object} apiModels.APIError
@@ -125,7 +133,13 @@ func (ctrl *ListsController) GetListByID(ctx *gin.Context) {
 		return
 	}
 
-	list, wishes, err := ctrl.listService.GetListWithWishes(ctx, listID, userID)
+	filter, err := wishFilterFromQuery(ctx)
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list, wishes, err := ctrl.listService.GetListWithWishes(ctx, listID, userID, filter)
 	if err != nil {
 		if apiModels.RespondWithServiceError(ctx, err) {
 			return
@@ -135,7 +149,7 @@ func (ctrl *ListsController) GetListByID(ctx *gin.Context) {
 	}
 
 	var response models.ListResponse
-	if list.UserID == userID {
+	if list.Role.CanEdit() {
 		response = list.ToOwnerResponse()
 		wishResponses := make([]models.WishResponse, len(wishes))
 		for i, wish := range wishes {
@@ -160,8 +174,12 @@ func (ctrl *ListsController) GetListByID(ctx *gin.Context) {
 // @Tags lists
 // @Produce json
 // @Param slug path string true "Shared slug (32 chars)"
+// @Param status query string false "Only wishes in this status" Enums(open, reserved, purchased, received, archived)
+// @Param sort query string false "Order of the wishes, position by default" Enums(position, price, priority, created_at)
+// @Param order query string false "Sort direction, asc by default" Enums(asc, desc)
 // @Success 200 {object} models.ListResponse
 // @Failure 400 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
 // @Failure 404 {object} apiModels.APIError
 // @Failure 500 {object} apiModels.APIError
 // @Router /lists/shared/{slug} [get]
@@ -172,7 +190,18 @@ func (ctrl *ListsController) GetListBySharedLink(ctx *gin.Context) {
 		return
 	}
 
-	list, wishes, err := ctrl.listService.GetListWithWishesBySharedLink(ctx, slug)
+	var userID *uuid.UUID
+	if uid, ok := middlewares.GetUserID(ctx); ok {
+		userID = &uid
+	}
+
+	filter, err := wishFilterFromQuery(ctx)
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list, wishes, err := ctrl.listService.GetListWithWishesBySharedLink(ctx, slug, userID, filter)
 	if err != nil {
 		if apiModels.RespondWithServiceError(ctx, err) {
 			return
@@ -181,13 +210,8 @@ func (ctrl *ListsController) GetListBySharedLink(ctx *gin.Context) {
 		return
 	}
 
-	var userID *uuid.UUID
-	if uid, ok := middlewares.GetUserID(ctx); ok {
-		userID = &uid
-	}
-
 	var response models.ListResponse
-	if userID != nil && list.UserID == *userID {
+	if list.Role.CanEdit() {
 		response = list.ToOwnerResponse()
 		wishResponses := make([]models.WishResponse, len(wishes))
 		for i, wish := range wishes {
@@ -208,7 +232,7 @@ func (ctrl *ListsController) GetListBySharedLink(ctx *gin.Context) {
 
 // GetCurrentUserLists GoDoc
 // @Summary Get current user wishlists
-// @Description Get all wishlists of current user
+// @Description Get all wishlists of current user, including the ones shared with them as a member
 // @Tags lists
 // @Produce json
 // @Security BearerAuth
@@ -231,7 +255,11 @@ func (ctrl *ListsController) GetCurrentUserLists(ctx *gin.Context) {
 
 	response := make([]models.ListResponse, len(lists))
 	for i, list := range lists {
-		response[i] = list.ToOwnerResponse()
+		if list.Role.CanEdit() {
+			response[i] = list.ToOwnerResponse()
+		} else {
+			response[i] = list.ToViewerResponse()
+		}
 	}
 
 	ctx.JSON(http.StatusOK, response)
@@ -239,7 +267,7 @@ func (ctrl *ListsController) GetCurrentUserLists(ctx *gin.Context) {
 
 // GetPublicListsByUserID GoDoc
 // @Summary Get public wishlists by user ID
-// @Description Get public wishlists of selected user
+// @Description Get public wishlists of selected user, accepted followers also get the followers-only ones
 // @Tags lists
 // @Produce json
 // @Security BearerAuth
@@ -262,7 +290,7 @@ func (ctrl *ListsController) GetPublicListsByUserID(ctx *gin.Context) {
 		return
 	}
 
-	lists, err := ctrl.listService.GetPublicListsByUserID(ctx, userID)
+	lists, err := ctrl.listService.GetPublicListsByUserID(ctx, userID, currentUserID)
 	if err != nil {
 		apiModels.InternalError(ctx, err.Error())
 		return
@@ -363,6 +391,110 @@ func (ctrl *ListsController) RotateSharedLink(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"slug": token})
 }
 
+// DuplicateList GoDoc
+// @Summary Duplicate wishlist
+// @Description Copy a wishlist the user can read, with its wishes and their images, into a new list of the user.
+// @Description Archived wishes are left out, reservations and contributions are never copied
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param request body models.DuplicateListRequest false "Title and visibility of the copy, private by default"
+// @Success 201 {object} models.ListResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/duplicate [post]
+func (ctrl *ListsController) DuplicateList(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	var req models.DuplicateListRequest
+	if ctx.Request.ContentLength > 0 { // Body is optional
+		if err = ctx.ShouldBindJSON(&req); err != nil {
+			apiModels.RespondWithBindError(ctx, err)
+			return
+		}
+	}
+
+	list, err := ctrl.listService.DuplicateList(ctx, listID, userID, req)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, list.ToOwnerResponse())
+}
+
+// CopyWish GoDoc
+// @Summary Copy wish to another list
+// @Description Copy a wish of a list the user can read to the end of a list they can edit, the image is copied too.
+// @Description Reservations and contributions are never copied
+// @Tags wishes
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Param target_list_id path string true "Target list ID (UUID)"
+// @Success 201 {object} models.WishResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/{wish_id}/copy-to/{target_list_id} [post]
+func (ctrl *ListsController) CopyWish(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	wishID, err := uuid.Parse(ctx.Param("wish_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid wish ID")
+		return
+	}
+
+	targetListID, err := uuid.Parse(ctx.Param("target_list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid target list ID")
+		return
+	}
+
+	wish, err := ctrl.listService.CopyWish(ctx, listID, wishID, targetListID, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, wish.ToOwnerResponse())
+}
+
 // DeleteList GoDoc
 // @Summary Delete wishlist
 // @Description Delete wishlist by ID
@@ -398,3 +530,28 @@ func (ctrl *ListsController) DeleteList(ctx *gin.Context) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// wishFilterFromQuery reads the optional status, sort and order query parameters
+func wishFilterFromQuery(ctx *gin.Context) (models.WishFilter, error) {
+	var filter models.WishFilter
+	if status := models.WishStatus(ctx.Query("status")); status != "" {
+		if !status.IsValid() {
+			return models.WishFilter{}, errors.New("invalid wish status")
+		}
+		filter.Status = &status
+	}
+	if sort := models.WishSort(ctx.Query("sort")); sort != "" {
+		if !sort.IsValid() {
+			return models.WishFilter{}, errors.New("invalid wish sort")
+		}
+		filter.Sort = sort
+	}
+	switch ctx.Query("order") {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return models.WishFilter{}, errors.New("invalid sort order")
+	}
+	return filter, nil
+}
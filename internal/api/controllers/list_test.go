@@ -34,14 +34,16 @@ func (m *listControllerAuthMock) ValidateAccessToken(ctx context.Context, token
 type listControllerServiceMock struct {
 	createListFn                func(ctx context.Context, userID uuid.UUID, req models.CreateListRequest) (models.List, error)
 	getListByIDFn               func(ctx context.Context, listID, requestedByUserID uuid.UUID) (models.List, error)
-	getListBySharedLinkFn       func(ctx context.Context, token string) (models.List, error)
-	getListWithWishesFn         func(ctx context.Context, listID, requestedByUserID uuid.UUID) (models.List, []models.Wish, error)
-	getListWithWishesBySharedFn func(ctx context.Context, token string) (models.List, []models.Wish, error)
+	getListBySharedLinkFn       func(ctx context.Context, token string, requestedByUserID *uuid.UUID) (models.List, error)
+	getListWithWishesFn         func(ctx context.Context, listID, requestedByUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error)
+	getListWithWishesBySharedFn func(ctx context.Context, token string, requestedByUserID *uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error)
 	getCurrentUserListsFn       func(ctx context.Context, userID uuid.UUID) ([]models.List, error)
-	getPublicListsByUserIDFn    func(ctx context.Context, userID uuid.UUID) ([]models.List, error)
+	getPublicListsByUserIDFn    func(ctx context.Context, userID, requestedByUserID uuid.UUID) ([]models.List, error)
 	updateListFn                func(ctx context.Context, listID, userID uuid.UUID, req models.UpdateListRequest) error
 	rotateSharedLinkFn          func(ctx context.Context, listID, userID uuid.UUID) (string, error)
 	deleteListFn                func(ctx context.Context, listID, userID uuid.UUID) error
+	duplicateListFn             func(ctx context.Context, listID, userID uuid.UUID, req models.DuplicateListRequest) (models.List, error)
+	copyWishFn                  func(ctx context.Context, listID, wishID, targetListID, userID uuid.UUID) (models.Wish, error)
 }
 
 func (m *listControllerServiceMock) CreateList(ctx context.Context, userID uuid.UUID, req models.CreateListRequest) (models.List, error) {
@@ -58,23 +60,23 @@ func (m *listControllerServiceMock) GetListByID(ctx context.Context, listID, req
 	return models.List{}, nil
 }
 
-func (m *listControllerServiceMock) GetListBySharedLink(ctx context.Context, token string) (models.List, error) {
+func (m *listControllerServiceMock) GetListBySharedLink(ctx context.Context, token string, requestedByUserID *uuid.UUID) (models.List, error) {
 	if m.getListBySharedLinkFn != nil {
-		return m.getListBySharedLinkFn(ctx, token)
+		return m.getListBySharedLinkFn(ctx, token, requestedByUserID)
 	}
 	return models.List{}, nil
 }
 
-func (m *listControllerServiceMock) GetListWithWishes(ctx context.Context, listID, requestedByUserID uuid.UUID) (models.List, []models.Wish, error) {
+func (m *listControllerServiceMock) GetListWithWishes(ctx context.Context, listID, requestedByUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
 	if m.getListWithWishesFn != nil {
-		return m.getListWithWishesFn(ctx, listID, requestedByUserID)
+		return m.getListWithWishesFn(ctx, listID, requestedByUserID, filter)
 	}
 	return models.List{}, nil, nil
 }
 
-func (m *listControllerServiceMock) GetListWithWishesBySharedLink(ctx context.Context, token string) (models.List, []models.Wish, error) {
+func (m *listControllerServiceMock) GetListWithWishesBySharedLink(ctx context.Context, token string, requestedByUserID *uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
 	if m.getListWithWishesBySharedFn != nil {
-		return m.getListWithWishesBySharedFn(ctx, token)
+		return m.getListWithWishesBySharedFn(ctx, token, requestedByUserID, filter)
 	}
 	return models.List{}, nil, nil
 }
@@ -86,9 +88,9 @@ func (m *listControllerServiceMock) GetCurrentUserLists(ctx context.Context, use
 	return nil, nil
 }
 
-func (m *listControllerServiceMock) GetPublicListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
+func (m *listControllerServiceMock) GetPublicListsByUserID(ctx context.Context, userID, requestedByUserID uuid.UUID) ([]models.List, error) {
 	if m.getPublicListsByUserIDFn != nil {
-		return m.getPublicListsByUserIDFn(ctx, userID)
+		return m.getPublicListsByUserIDFn(ctx, userID, requestedByUserID)
 	}
 	return nil, nil
 }
@@ -114,6 +116,20 @@ func (m *listControllerServiceMock) DeleteList(ctx context.Context, listID, user
 	return nil
 }
 
+func (m *listControllerServiceMock) DuplicateList(ctx context.Context, listID, userID uuid.UUID, req models.DuplicateListRequest) (models.List, error) {
+	if m.duplicateListFn != nil {
+		return m.duplicateListFn(ctx, listID, userID, req)
+	}
+	return models.List{}, nil
+}
+
+func (m *listControllerServiceMock) CopyWish(ctx context.Context, listID, wishID, targetListID, userID uuid.UUID) (models.Wish, error) {
+	if m.copyWishFn != nil {
+		return m.copyWishFn(ctx, listID, wishID, targetListID, userID)
+	}
+	return models.Wish{}, nil
+}
+
 func setupListControllerForTest(as *listControllerAuthMock, ls *listControllerServiceMock) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	viper.Set(config.ApiBasePath, "/api/v1")
@@ -166,13 +182,13 @@ func TestListsController_CreateList(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		now := time.Now().UTC()
 		expected := models.List{
-			ID:        uuid.New(),
-			UserID:    currentUserID,
-			Title:     "Birthday",
-			IsPublic:  true,
-			Slug:      "12345678901234567890123456789012",
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:         uuid.New(),
+			UserID:     currentUserID,
+			Title:      "Birthday",
+			Visibility: models.ListVisibilityPublic,
+			Slug:       "12345678901234567890123456789012",
+			CreatedAt:  now,
+			UpdatedAt:  now,
 		}
 		ls := &listControllerServiceMock{createListFn: func(ctx context.Context, userID uuid.UUID, req models.CreateListRequest) (models.List, error) {
 			if userID != currentUserID || req.Title != "Birthday" {
@@ -213,7 +229,7 @@ func TestListsController_GetCurrentUserLists(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		ls := &listControllerServiceMock{getCurrentUserListsFn: func(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
-			return []models.List{{ID: uuid.New(), UserID: userID, Title: "Mine", IsPublic: true}}, nil
+			return []models.List{{ID: uuid.New(), UserID: userID, Title: "Mine", Visibility: models.ListVisibilityPublic}}, nil
 		}}
 		router := setupListControllerForTest(as, ls)
 		w := listJSONRequest(router, http.MethodGet, "/api/v1/lists", "", "ok")
@@ -237,7 +253,7 @@ func TestListsController_GetListByID(t *testing.T) {
 	})
 
 	t.Run("forbidden", func(t *testing.T) {
-		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID) (models.List, []models.Wish, error) {
+		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
 			return models.List{}, nil, svcErr.ForbiddenError{Message: "private"}
 		}}
 		router := setupListControllerForTest(as, ls)
@@ -248,7 +264,7 @@ func TestListsController_GetListByID(t *testing.T) {
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID) (models.List, []models.Wish, error) {
+		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
 			return models.List{}, nil, svcErr.NotFoundError{Entity: "list", Field: "id", Value: gotListID.String()}
 		}}
 		router := setupListControllerForTest(as, ls)
@@ -259,7 +275,7 @@ func TestListsController_GetListByID(t *testing.T) {
 	})
 
 	t.Run("internal error", func(t *testing.T) {
-		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID) (models.List, []models.Wish, error) {
+		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
 			return models.List{}, nil, errors.New("db")
 		}}
 		router := setupListControllerForTest(as, ls)
@@ -270,8 +286,8 @@ func TestListsController_GetListByID(t *testing.T) {
 	})
 
 	t.Run("success owner", func(t *testing.T) {
-		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID) (models.List, []models.Wish, error) {
-			return models.List{ID: listID, UserID: currentUserID, Title: "Mine", IsPublic: true, Slug: "token"}, []models.Wish{{ID: uuid.New(), ListID: listID, Title: "Gift"}}, nil
+		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+			return models.List{ID: listID, UserID: currentUserID, Title: "Mine", Visibility: models.ListVisibilityPublic, Slug: "token"}, []models.Wish{{ID: uuid.New(), ListID: listID, Title: "Gift"}}, nil
 		}}
 		router := setupListControllerForTest(as, ls)
 		w := listJSONRequest(router, http.MethodGet, "/api/v1/lists/"+listID.String(), "", "ok")
@@ -279,6 +295,55 @@ func TestListsController_GetListByID(t *testing.T) {
 			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
 		}
 	})
+
+	t.Run("invalid status filter", func(t *testing.T) {
+		router := setupListControllerForTest(as, &listControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/lists/"+listID.String()+"?status=lost", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("status filter", func(t *testing.T) {
+		var gotFilter models.WishFilter
+		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+			gotFilter = filter
+			return models.List{ID: listID, UserID: currentUserID, Role: models.ListRoleOwner}, nil, nil
+		}}
+		router := setupListControllerForTest(as, ls)
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/lists/"+listID.String()+"?status=archived", "", "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+		if gotFilter.Status == nil || *gotFilter.Status != models.WishStatusArchived {
+			t.Fatalf("filter status = %v, want archived", gotFilter.Status)
+		}
+	})
+	for _, query := range []string{"?sort=random", "?sort=price&order=up"} {
+		t.Run("invalid sort "+query, func(t *testing.T) {
+			router := setupListControllerForTest(as, &listControllerServiceMock{})
+			w := listJSONRequest(router, http.MethodGet, "/api/v1/lists/"+listID.String()+query, "", "ok")
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+
+	t.Run("sort", func(t *testing.T) {
+		var gotFilter models.WishFilter
+		ls := &listControllerServiceMock{getListWithWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+			gotFilter = filter
+			return models.List{ID: listID, UserID: currentUserID, Role: models.ListRoleOwner}, nil, nil
+		}}
+		router := setupListControllerForTest(as, ls)
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/lists/"+listID.String()+"?sort=price&order=desc", "", "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+		if gotFilter.Sort != models.WishSortPrice || !gotFilter.Desc {
+			t.Fatalf("filter = %+v, want price descending", gotFilter)
+		}
+	})
 }
 
 func TestListsController_UpdateList(t *testing.T) {
@@ -438,6 +503,107 @@ func TestListsController_DeleteList(t *testing.T) {
 	})
 }
 
+func TestListsController_DuplicateList(t *testing.T) {
+	currentUserID := uuid.New()
+	listID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("private list", func(t *testing.T) {
+		ls := &listControllerServiceMock{duplicateListFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, req models.DuplicateListRequest) (models.List, error) {
+			return models.List{}, svcErr.ForbiddenError{Message: "this wishlist is private"}
+		}}
+		router := setupListControllerForTest(as, ls)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/duplicate", "", "ok")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("without body", func(t *testing.T) {
+		ls := &listControllerServiceMock{duplicateListFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, req models.DuplicateListRequest) (models.List, error) {
+			if gotListID != listID || gotUserID != currentUserID || req.Title != nil {
+				t.Fatalf("DuplicateList(%s, %s, %+v)", gotListID, gotUserID, req)
+			}
+			return models.List{ID: uuid.New(), UserID: gotUserID, Title: "Birthday", Visibility: models.ListVisibilityPrivate, WishesCount: 3}, nil
+		}}
+		router := setupListControllerForTest(as, ls)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/duplicate", "", "ok")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+		}
+		var resp models.ListResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.WishesCount != 3 || resp.UserID != currentUserID {
+			t.Fatalf("response = %+v, %v", resp, err)
+		}
+	})
+
+	t.Run("with title", func(t *testing.T) {
+		ls := &listControllerServiceMock{duplicateListFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, req models.DuplicateListRequest) (models.List, error) {
+			if req.Title == nil || *req.Title != "Birthday 2031" {
+				t.Fatalf("DuplicateList() req = %+v", req)
+			}
+			return models.List{ID: uuid.New(), Title: *req.Title}, nil
+		}}
+		router := setupListControllerForTest(as, ls)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/duplicate", `{"title":"Birthday 2031"}`, "ok")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+		}
+	})
+
+	t.Run("invalid visibility", func(t *testing.T) {
+		router := setupListControllerForTest(as, &listControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/duplicate", `{"visibility":"everyone"}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestListsController_CopyWish(t *testing.T) {
+	currentUserID := uuid.New()
+	listID, wishID, targetListID := uuid.New(), uuid.New(), uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+	path := "/api/v1/lists/" + listID.String() + "/wishes/" + wishID.String() + "/copy-to/"
+
+	t.Run("invalid target list ID", func(t *testing.T) {
+		router := setupListControllerForTest(as, &listControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodPost, path+"not-uuid", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("target not editable", func(t *testing.T) {
+		ls := &listControllerServiceMock{copyWishFn: func(ctx context.Context, gotListID, gotWishID, gotTargetID, gotUserID uuid.UUID) (models.Wish, error) {
+			return models.Wish{}, svcErr.ForbiddenError{Message: "you are not allowed to edit this wishlist"}
+		}}
+		router := setupListControllerForTest(as, ls)
+		w := listJSONRequest(router, http.MethodPost, path+targetListID.String(), "", "ok")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ls := &listControllerServiceMock{copyWishFn: func(ctx context.Context, gotListID, gotWishID, gotTargetID, gotUserID uuid.UUID) (models.Wish, error) {
+			if gotListID != listID || gotWishID != wishID || gotTargetID != targetListID || gotUserID != currentUserID {
+				t.Fatalf("CopyWish(%s, %s, %s, %s)", gotListID, gotWishID, gotTargetID, gotUserID)
+			}
+			return models.Wish{ID: uuid.New(), ListID: gotTargetID, Title: "Kettle", Quantity: 1, Status: models.WishStatusOpen}, nil
+		}}
+		router := setupListControllerForTest(as, ls)
+		w := listJSONRequest(router, http.MethodPost, path+targetListID.String(), "", "ok")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+		}
+		var resp models.WishResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.ListID != targetListID {
+			t.Fatalf("response = %+v, %v", resp, err)
+		}
+	})
+}
+
 func TestListsController_GetListBySharedLink(t *testing.T) {
 	slug := "12345678901234567890123456789012"
 	ownerID := uuid.New()
@@ -452,7 +618,7 @@ func TestListsController_GetListBySharedLink(t *testing.T) {
 	})
 
 	t.Run("internal error", func(t *testing.T) {
-		ls := &listControllerServiceMock{getListWithWishesBySharedFn: func(ctx context.Context, token string) (models.List, []models.Wish, error) {
+		ls := &listControllerServiceMock{getListWithWishesBySharedFn: func(ctx context.Context, token string, requestedByUserID *uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
 			return models.List{}, nil, errors.New("db")
 		}}
 		router := setupListControllerForTest(&listControllerAuthMock{}, ls)
@@ -463,8 +629,8 @@ func TestListsController_GetListBySharedLink(t *testing.T) {
 	})
 
 	t.Run("success guest", func(t *testing.T) {
-		ls := &listControllerServiceMock{getListWithWishesBySharedFn: func(ctx context.Context, token string) (models.List, []models.Wish, error) {
-			return models.List{ID: listID, UserID: ownerID, Title: "Shared", IsPublic: true}, []models.Wish{{ID: uuid.New(), ListID: listID, Title: "Gift"}}, nil
+		ls := &listControllerServiceMock{getListWithWishesBySharedFn: func(ctx context.Context, token string, requestedByUserID *uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+			return models.List{ID: listID, UserID: ownerID, Title: "Shared", Visibility: models.ListVisibilityPublic}, []models.Wish{{ID: uuid.New(), ListID: listID, Title: "Gift"}}, nil
 		}}
 		router := setupListControllerForTest(&listControllerAuthMock{}, ls)
 		w := listJSONRequest(router, http.MethodGet, "/api/v1/lists/shared/"+slug, "", "")
@@ -475,8 +641,11 @@ func TestListsController_GetListBySharedLink(t *testing.T) {
 
 	t.Run("success owner", func(t *testing.T) {
 		as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return ownerID, nil }}
-		ls := &listControllerServiceMock{getListWithWishesBySharedFn: func(ctx context.Context, token string) (models.List, []models.Wish, error) {
-			return models.List{ID: listID, UserID: ownerID, Title: "Shared", IsPublic: true, Slug: slug}, []models.Wish{{ID: uuid.New(), ListID: listID, Title: "Gift"}}, nil
+		ls := &listControllerServiceMock{getListWithWishesBySharedFn: func(ctx context.Context, token string, requestedByUserID *uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+			if requestedByUserID == nil || *requestedByUserID != ownerID {
+				t.Fatalf("unexpected requester")
+			}
+			return models.List{ID: listID, UserID: ownerID, Title: "Shared", Visibility: models.ListVisibilityPublic, Slug: slug, Role: models.ListRoleOwner}, []models.Wish{{ID: uuid.New(), ListID: listID, Title: "Gift"}}, nil
 		}}
 		router := setupListControllerForTest(as, ls)
 		w := listJSONRequest(router, http.MethodGet, "/api/v1/lists/shared/"+slug, "", "ok")
@@ -484,6 +653,33 @@ func TestListsController_GetListBySharedLink(t *testing.T) {
 			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
 		}
 	})
+
+	t.Run("guest reservation stays anonymous", func(t *testing.T) {
+		for name, requesterID := range map[string]uuid.UUID{"owner": ownerID, "viewer": uuid.New()} {
+			as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return requesterID, nil }}
+			ls := &listControllerServiceMock{getListWithWishesBySharedFn: func(ctx context.Context, token string, requestedByUserID *uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+				list := models.List{ID: listID, UserID: ownerID, Title: "Shared", Visibility: models.ListVisibilityLinkOnly, Slug: slug}
+				if *requestedByUserID == ownerID {
+					list.Role = models.ListRoleOwner
+				}
+				wish := models.Wish{ID: uuid.New(), ListID: listID, Title: "Gift", Quantity: 1, Status: models.WishStatusReserved, Reservations: []models.WishReservation{{Guest: true, Units: 1}}}
+				return list, []models.Wish{wish}, nil
+			}}
+			router := setupListControllerForTest(as, ls)
+
+			w := listJSONRequest(router, http.MethodGet, "/api/v1/lists/shared/"+slug, "", "ok")
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s: status = %d, want %d", name, w.Code, http.StatusOK)
+			}
+			var response models.ListResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%s: unmarshal: %v", name, err)
+			}
+			if wish := response.Wishes[0]; !wish.Reserved || wish.ReservedUnits != 1 || wish.ReservedBy != nil || wish.ReservedByMe != 0 {
+				t.Fatalf("%s: wish = %+v, want it reserved without telling by whom", name, wish)
+			}
+		}
+	})
 }
 
 func TestListsController_GetPublicListsByUserID(t *testing.T) {
@@ -500,7 +696,7 @@ func TestListsController_GetPublicListsByUserID(t *testing.T) {
 	})
 
 	t.Run("internal error", func(t *testing.T) {
-		ls := &listControllerServiceMock{getPublicListsByUserIDFn: func(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
+		ls := &listControllerServiceMock{getPublicListsByUserIDFn: func(ctx context.Context, userID, requestedByUserID uuid.UUID) ([]models.List, error) {
 			return nil, errors.New("db")
 		}}
 		router := setupListControllerForTest(as, ls)
@@ -511,10 +707,10 @@ func TestListsController_GetPublicListsByUserID(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
-		ls := &listControllerServiceMock{getPublicListsByUserIDFn: func(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
+		ls := &listControllerServiceMock{getPublicListsByUserIDFn: func(ctx context.Context, userID, requestedByUserID uuid.UUID) ([]models.List, error) {
 			return []models.List{
-				{ID: uuid.New(), UserID: targetUserID, Title: "Public", IsPublic: true},
-				{ID: uuid.New(), UserID: currentUserID, Title: "Mine", IsPublic: true, Slug: "token"},
+				{ID: uuid.New(), UserID: targetUserID, Title: "Public", Visibility: models.ListVisibilityPublic},
+				{ID: uuid.New(), UserID: currentUserID, Title: "Mine", Visibility: models.ListVisibilityPublic, Slug: "token"},
 			}, nil
 		}}
 		router := setupListControllerForTest(as, ls)
@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+)
+
+type ListMemberService interface {
+	GetMembers(ctx context.Context, listID, userID uuid.UUID) ([]models.ListMember, error)
+	GetPendingInvitations(ctx context.Context, userID uuid.UUID) ([]models.ListMember, error)
+	InviteMember(ctx context.Context, listID, userID uuid.UUID, req models.InviteListMemberRequest) (models.ListMember, error)
+	AcceptInvitation(ctx context.Context, listID, userID uuid.UUID) error
+	RemoveMember(ctx context.Context, listID, memberID, userID uuid.UUID) error
+}
+
+type MembersController struct {
+	router        *gin.Engine
+	mw            *middlewares.Middlewares
+	memberService ListMemberService
+}
+
+func NewMembersController(e *gin.Engine, mw *middlewares.Middlewares, ms ListMemberService) *MembersController {
+	return &MembersController{router: e, mw: mw, memberService: ms}
+}
+
+func (ctrl *MembersController) RegisterRoutes() {
+	basePath := ctrl.router.Group(viper.GetString(config.ApiBasePath))
+	listRoutes := basePath.Group("/lists")
+	{
+		authedListRoutes := listRoutes.Group("").Use(ctrl.mw.AuthMiddleware())
+		{
+			authedListRoutes.GET("/invitations", ctrl.GetPendingInvitations)
+			authedListRoutes.GET("/:list_id/members", ctrl.GetMembers)
+			authedListRoutes.POST("/:list_id/members", ctrl.InviteMember)
+			authedListRoutes.POST("/:list_id/members/accept", ctrl.AcceptInvitation)
+			authedListRoutes.DELETE("/:list_id/members/:user_id", ctrl.RemoveMember)
+		}
+	}
+}
+
+// GetMembers GoDoc
+// @Summary Get wishlist members
+// @Description Get members and pending invitations of a co-owned wishlist
+// @Tags members
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Success 200 {array} models.ListMemberResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/members [get]
+func (ctrl *MembersController) GetMembers(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	members, err := ctrl.memberService.GetMembers(ctx, listID, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := make([]models.ListMemberResponse, len(members))
+	for i, member := range members {
+		response[i] = member.ToResponse()
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetPendingInvitations GoDoc
+// @Summary Get pending invitations
+// @Description Get wishlist invitations of current user that were not accepted yet
+// @Tags members
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.ListMemberResponse
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/invitations [get]
+func (ctrl *MembersController) GetPendingInvitations(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	invitations, err := ctrl.memberService.GetPendingInvitations(ctx, userID)
+	if err != nil {
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := make([]models.ListMemberResponse, len(invitations))
+	for i, invitation := range invitations {
+		response[i] = invitation.ToResponse()
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// InviteMember GoDoc
+// @Summary Invite wishlist member
+// @Description Invite a user to co-own, edit or view the wishlist
+// @Tags members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param request body models.InviteListMemberRequest true "Invitation"
+// @Success 201 {object} models.ListMemberResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 409 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/members [post]
+func (ctrl *MembersController) InviteMember(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	var req models.InviteListMemberRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	member, err := ctrl.memberService.InviteMember(ctx, listID, userID, req)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, member.ToResponse())
+}
+
+// AcceptInvitation GoDoc
+// @Summary Accept wishlist invitation
+// @Description Accept invitation of current user to the wishlist
+// @Tags members
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/members/accept [post]
+func (ctrl *MembersController) AcceptInvitation(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	if err = ctrl.memberService.AcceptInvitation(ctx, listID, userID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveMember GoDoc
+// @Summary Remove wishlist member
+// @Description Remove member or invitation from the wishlist; members may also remove themselves
+// @Tags members
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param user_id path string true "User ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/members/{user_id} [delete]
+func (ctrl *MembersController) RemoveMember(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	memberID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err = ctrl.memberService.RemoveMember(ctx, listID, memberID, userID); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type memberControllerServiceMock struct {
+	getMembersFn            func(ctx context.Context, listID, userID uuid.UUID) ([]models.ListMember, error)
+	getPendingInvitationsFn func(ctx context.Context, userID uuid.UUID) ([]models.ListMember, error)
+	inviteMemberFn          func(ctx context.Context, listID, userID uuid.UUID, req models.InviteListMemberRequest) (models.ListMember, error)
+	acceptInvitationFn      func(ctx context.Context, listID, userID uuid.UUID) error
+	removeMemberFn          func(ctx context.Context, listID, memberID, userID uuid.UUID) error
+}
+
+func (m *memberControllerServiceMock) GetMembers(ctx context.Context, listID, userID uuid.UUID) ([]models.ListMember, error) {
+	if m.getMembersFn != nil {
+		return m.getMembersFn(ctx, listID, userID)
+	}
+	return nil, nil
+}
+
+func (m *memberControllerServiceMock) GetPendingInvitations(ctx context.Context, userID uuid.UUID) ([]models.ListMember, error) {
+	if m.getPendingInvitationsFn != nil {
+		return m.getPendingInvitationsFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *memberControllerServiceMock) InviteMember(ctx context.Context, listID, userID uuid.UUID, req models.InviteListMemberRequest) (models.ListMember, error) {
+	if m.inviteMemberFn != nil {
+		return m.inviteMemberFn(ctx, listID, userID, req)
+	}
+	return models.ListMember{}, nil
+}
+
+func (m *memberControllerServiceMock) AcceptInvitation(ctx context.Context, listID, userID uuid.UUID) error {
+	if m.acceptInvitationFn != nil {
+		return m.acceptInvitationFn(ctx, listID, userID)
+	}
+	return nil
+}
+
+func (m *memberControllerServiceMock) RemoveMember(ctx context.Context, listID, memberID, userID uuid.UUID) error {
+	if m.removeMemberFn != nil {
+		return m.removeMemberFn(ctx, listID, memberID, userID)
+	}
+	return nil
+}
+
+func setupMemberControllerForTest(as *listControllerAuthMock, ms *memberControllerServiceMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	viper.Set(config.ApiBasePath, "/api/v1")
+	router := gin.New()
+	mw := middlewares.NewMiddlewares(as)
+	ctrl := NewMembersController(router, mw, ms)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestMembersController_InviteMember(t *testing.T) {
+	currentUserID := uuid.New()
+	listID := uuid.New()
+	invitedID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("invalid role", func(t *testing.T) {
+		router := setupMemberControllerForTest(as, &memberControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/members", `{"user_id":"`+invitedID.String()+`","role":"admin"}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		ms := &memberControllerServiceMock{inviteMemberFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, req models.InviteListMemberRequest) (models.ListMember, error) {
+			return models.ListMember{}, svcErr.ForbiddenError{Message: "not owner"}
+		}}
+		router := setupMemberControllerForTest(as, ms)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/members", `{"user_id":"`+invitedID.String()+`","role":"editor"}`, "ok")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ms := &memberControllerServiceMock{inviteMemberFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, req models.InviteListMemberRequest) (models.ListMember, error) {
+			if gotListID != listID || gotUserID != currentUserID || req.UserID != invitedID || req.Role != models.ListRoleEditor {
+				t.Fatalf("unexpected invite args")
+			}
+			return models.ListMember{ListID: gotListID, UserID: req.UserID, Role: req.Role, InvitedBy: gotUserID}, nil
+		}}
+		router := setupMemberControllerForTest(as, ms)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/members", `{"user_id":"`+invitedID.String()+`","role":"editor"}`, "ok")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+		}
+	})
+}
+
+func TestMembersController_AcceptAndRemove(t *testing.T) {
+	currentUserID := uuid.New()
+	listID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("accept not found", func(t *testing.T) {
+		ms := &memberControllerServiceMock{acceptInvitationFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID) error {
+			return svcErr.NotFoundError{Entity: "invitation", Field: "list_id", Value: gotListID.String()}
+		}}
+		router := setupMemberControllerForTest(as, ms)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/members/accept", "", "ok")
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("remove invalid user ID", func(t *testing.T) {
+		router := setupMemberControllerForTest(as, &memberControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodDelete, "/api/v1/lists/"+listID.String()+"/members/not-uuid", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("remove success", func(t *testing.T) {
+		router := setupMemberControllerForTest(as, &memberControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodDelete, "/api/v1/lists/"+listID.String()+"/members/"+uuid.NewString(), "", "ok")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+	})
+}
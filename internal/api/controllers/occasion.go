@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+)
+
+type OccasionService interface {
+	GetUpcomingOccasions(ctx context.Context, userID uuid.UUID, days int) ([]models.List, error)
+}
+
+type OccasionsController struct {
+	router          *gin.Engine
+	mw              *middlewares.Middlewares
+	occasionService OccasionService
+}
+
+func NewOccasionsController(e *gin.Engine, mw *middlewares.Middlewares, ocs OccasionService) *OccasionsController {
+	return &OccasionsController{router: e, mw: mw, occasionService: ocs}
+}
+
+func (ctrl *OccasionsController) RegisterRoutes() {
+	basePath := ctrl.router.Group(viper.GetString(config.ApiBasePath))
+	basePath.GET("/occasions/upcoming", ctrl.mw.AuthMiddleware(), ctrl.GetUpcomingOccasions)
+}
+
+// GetUpcomingOccasions GoDoc
+// @Summary Get upcoming occasions
+// @Description Get the wishlists the user can read whose occasion comes within the next days, soonest first. Own and shared wishlists are included, and those of followed users unless they are private or link-only
+// @Tags occasions
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "How many days ahead to look, 30 by default and 366 at most"
+// @Success 200 {array} models.ListResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /occasions/upcoming [get]
+func (ctrl *OccasionsController) GetUpcomingOccasions(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var days int
+	if raw := ctx.Query("days"); raw != "" {
+		var err error
+		if days, err = strconv.Atoi(raw); err != nil || days <= 0 {
+			apiModels.Error(ctx, http.StatusBadRequest, "invalid days")
+			return
+		}
+	}
+
+	lists, err := ctrl.occasionService.GetUpcomingOccasions(ctx, userID, days)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := make([]models.ListResponse, len(lists))
+	for i, list := range lists {
+		if list.Role.CanEdit() {
+			response[i] = list.ToOwnerResponse()
+		} else {
+			response[i] = list.ToViewerResponse()
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
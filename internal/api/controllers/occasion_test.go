@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+)
+
+type occasionControllerServiceMock struct {
+	getUpcomingFn func(ctx context.Context, userID uuid.UUID, days int) ([]models.List, error)
+}
+
+func (m *occasionControllerServiceMock) GetUpcomingOccasions(ctx context.Context, userID uuid.UUID, days int) ([]models.List, error) {
+	if m.getUpcomingFn != nil {
+		return m.getUpcomingFn(ctx, userID, days)
+	}
+	return nil, nil
+}
+
+func setupOccasionControllerForTest(as *listControllerAuthMock, os *occasionControllerServiceMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	viper.Set(config.ApiBasePath, "/api/v1")
+	router := gin.New()
+	mw := middlewares.NewMiddlewares(as)
+	ctrl := NewOccasionsController(router, mw, os)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestOccasionsController_GetUpcomingOccasions(t *testing.T) {
+	currentUserID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		router := setupOccasionControllerForTest(as, &occasionControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/occasions/upcoming", "", "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("invalid days", func(t *testing.T) {
+		router := setupOccasionControllerForTest(as, &occasionControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/occasions/upcoming?days=-3", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		own := models.List{ID: uuid.New(), UserID: currentUserID, Title: "Birthday", Slug: "secret", Role: models.ListRoleOwner, OccasionDate: new(today.AddDate(0, 0, 4))}
+		friends := models.List{ID: uuid.New(), UserID: uuid.New(), Title: "Wedding", Slug: "theirs", OccasionDate: new(today.AddDate(-1, 0, 9)), OccasionRecursYearly: true}
+		os := &occasionControllerServiceMock{getUpcomingFn: func(ctx context.Context, userID uuid.UUID, days int) ([]models.List, error) {
+			if userID != currentUserID || days != 14 {
+				t.Fatalf("unexpected args: %s %d", userID, days)
+			}
+			return []models.List{own, friends}, nil
+		}}
+		router := setupOccasionControllerForTest(as, os)
+
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/occasions/upcoming?days=14", "", "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+		}
+
+		var response []models.ListResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if len(response) != 2 || *response[0].DaysUntilOccasion != 4 || response[0].Slug != "secret" {
+			t.Fatalf("response[0] = %+v, want the own list with its slug and a countdown", response[0])
+		}
+		if response[1].Slug != "" || !response[1].OccasionRecursYearly || *response[1].NextOccasion != today.AddDate(0, 0, 9).Format(models.OccasionDateLayout) {
+			t.Fatalf("response[1] = %+v, want the friend's list without its slug and this year's date", response[1])
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/errors"
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+)
+
+type TrashService interface {
+	GetTrash(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error)
+	RestoreItem(ctx context.Context, id, userID uuid.UUID) (models.TrashItem, error)
+}
+
+type TrashController struct {
+	router       *gin.Engine
+	mw           *middlewares.Middlewares
+	trashService TrashService
+}
+
+func NewTrashController(e *gin.Engine, mw *middlewares.Middlewares, ts TrashService) *TrashController {
+	return &TrashController{router: e, mw: mw, trashService: ts}
+}
+
+func (ctrl *TrashController) RegisterRoutes() {
+	basePath := ctrl.router.Group(viper.GetString(config.ApiBasePath))
+	trashRoutes := basePath.Group("/trash").Use(ctrl.mw.AuthMiddleware())
+	{
+		trashRoutes.GET("", ctrl.GetTrash)
+		trashRoutes.POST("/:id/restore", ctrl.RestoreItem)
+	}
+}
+
+// GetTrash GoDoc
+// @Summary Get trash
+// @Description Get deleted wishlists the current user owns and deleted wishes of wishlists the user edits, most recent first.
+// @Description Items are removed for good at purge_at
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TrashItemResponse
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /trash [get]
+func (ctrl *TrashController) GetTrash(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	items, err := ctrl.trashService.GetTrash(ctx, userID)
+	if err != nil {
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := make([]models.TrashItemResponse, len(items))
+	for i, item := range items {
+		response[i] = item.ToResponse()
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RestoreItem GoDoc
+// @Summary Restore from trash
+// @Description Restore a deleted wishlist together with its wishes, or a deleted wish to the end of its wishlist
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "List or wish ID (UUID)"
+// @Success 200 {object} models.TrashItemResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /trash/{id}/restore [post]
+func (ctrl *TrashController) RestoreItem(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid item ID")
+		return
+	}
+
+	item, err := ctrl.trashService.RestoreItem(ctx, id, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item.ToResponse())
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/api/middlewares"
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type trashControllerServiceMock struct {
+	getTrashFn    func(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error)
+	restoreItemFn func(ctx context.Context, id, userID uuid.UUID) (models.TrashItem, error)
+}
+
+func (m *trashControllerServiceMock) GetTrash(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error) {
+	if m.getTrashFn != nil {
+		return m.getTrashFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *trashControllerServiceMock) RestoreItem(ctx context.Context, id, userID uuid.UUID) (models.TrashItem, error) {
+	if m.restoreItemFn != nil {
+		return m.restoreItemFn(ctx, id, userID)
+	}
+	return models.TrashItem{}, nil
+}
+
+func setupTrashControllerForTest(as *listControllerAuthMock, ts *trashControllerServiceMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	viper.Set(config.ApiBasePath, "/api/v1")
+	router := gin.New()
+	ctrl := NewTrashController(router, middlewares.NewMiddlewares(as), ts)
+	ctrl.RegisterRoutes()
+	return router
+}
+
+func TestTrashController_GetTrash(t *testing.T) {
+	currentUserID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		router := setupTrashControllerForTest(&listControllerAuthMock{}, &trashControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/trash", "", "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ts := &trashControllerServiceMock{getTrashFn: func(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error) {
+			if userID != currentUserID {
+				t.Fatalf("userID = %s, want %s", userID, currentUserID)
+			}
+			return []models.TrashItem{{Type: models.TrashItemList, ID: uuid.New(), Title: "Birthday"}}, nil
+		}}
+		router := setupTrashControllerForTest(as, ts)
+		w := listJSONRequest(router, http.MethodGet, "/api/v1/trash", "", "ok")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"type":"list"`) || !strings.Contains(w.Body.String(), `"purge_at"`) {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestTrashController_RestoreItem(t *testing.T) {
+	currentUserID := uuid.New()
+	itemID := uuid.New()
+	as := &listControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return currentUserID, nil }}
+
+	t.Run("invalid id", func(t *testing.T) {
+		router := setupTrashControllerForTest(as, &trashControllerServiceMock{})
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/trash/nope/restore", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("not in trash", func(t *testing.T) {
+		ts := &trashControllerServiceMock{restoreItemFn: func(ctx context.Context, id, userID uuid.UUID) (models.TrashItem, error) {
+			return models.TrashItem{}, svcErr.NotFoundError{Entity: "trash item", Field: "id", Value: id.String()}
+		}}
+		router := setupTrashControllerForTest(as, ts)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/trash/"+itemID.String()+"/restore", "", "ok")
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ts := &trashControllerServiceMock{restoreItemFn: func(ctx context.Context, id, userID uuid.UUID) (models.TrashItem, error) {
+			if id != itemID || userID != currentUserID {
+				t.Fatalf("unexpected restore args")
+			}
+			return models.TrashItem{Type: models.TrashItemWish, ID: id, Title: "Kettle"}, nil
+		}}
+		router := setupTrashControllerForTest(as, ts)
+		w := listJSONRequest(router, http.MethodPost, "/api/v1/trash/"+itemID.String()+"/restore", "", "ok")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), itemID.String()) {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+	})
+}
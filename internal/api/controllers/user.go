@@ -2,9 +2,11 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -31,13 +33,16 @@ type UserService interface {
 	GetUserByUsername(ctx context.Context, username string) (models.User, error)
 	SearchUsersByUsername(ctx context.Context, query string, limit int) ([]models.User, error)
 	UpdateUserByID(ctx context.Context, id uuid.UUID, req models.UpdateUserRequest) error
-	UpdateAvatar(ctx context.Context, id uuid.UUID, reader io.Reader, size int64, contentType string) error
+	UpdateAvatar(ctx context.Context, id uuid.UUID, reader io.Reader) error
+	CreateAvatarUploadURL(ctx context.Context, id uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error)
+	ConfirmAvatarUpload(ctx context.Context, id uuid.UUID, objectKey string, maxSize int64) error
 	DeleteAvatar(ctx context.Context, id uuid.UUID) error
 	VerifyPassword(ctx context.Context, id uuid.UUID, password string) error
 	ChangePassword(ctx context.Context, id uuid.UUID, req models.ChangePasswordRequest) error
 	RequestPasswordReset(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, token, newPassword string) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) (time.Time, error)
+	CancelDeletion(ctx context.Context, token string) error
 }
 
 type UsersController struct {
@@ -63,6 +68,7 @@ func (ctrl *UsersController) RegisterRoutes() {
 
 		authRoutes.POST("/forgot-password", ctrl.ForgotPassword)
 		authRoutes.POST("/set-new-password", ctrl.SetNewPassword)
+		authRoutes.POST("/cancel-deletion", ctrl.CancelDeletion)
 	}
 	userRoutes := basePath.Group("/users")
 	{
@@ -71,6 +77,8 @@ func (ctrl *UsersController) RegisterRoutes() {
 			authedUserRoutes.GET("/me", ctrl.GetCurrentUser)
 			authedUserRoutes.PATCH("/me", ctrl.UpdateCurrentUser)
 			authedUserRoutes.PUT("/me/avatar", ctrl.UpdateAvatar)
+			authedUserRoutes.POST("/me/avatar/upload-url", ctrl.CreateAvatarUploadURL)
+			authedUserRoutes.POST("/me/avatar/confirm", ctrl.ConfirmAvatarUpload)
 			authedUserRoutes.DELETE("/me/avatar", ctrl.DeleteAvatar)
 			authedUserRoutes.PATCH("/me/update-password", ctrl.UpdateCurrentPassword)
 			authedUserRoutes.DELETE("/me", ctrl.DeleteCurrentUser)
@@ -159,10 +167,12 @@ func (ctrl *UsersController) VerifyEmail(ctx *gin.Context) {
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Description An account scheduled for deletion gets 403 with the deletion date, log in again with reactivate to keep it
 // @Param request body models.LogInUserRequest true "Credentials"
 // @Success 200 {object} models.AuthResponse
 // @Failure 400 {object} apiModels.APIError
 // @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} models.AccountDeletionResponse
 // @Failure 500 {object} apiModels.APIError
 // @Router /auth/login [post]
 func (ctrl *UsersController) LogIn(ctx *gin.Context) {
@@ -177,6 +187,13 @@ func (ctrl *UsersController) LogIn(ctx *gin.Context) {
 		apiModels.Error(ctx, http.StatusUnauthorized, "invalid credentials")
 		return
 	}
+	if user.IsDeactivated() {
+		ctx.JSON(http.StatusForbidden, models.AccountDeletionResponse{
+			Message:     "account is scheduled for deletion, log in with reactivate to keep it",
+			DeleteAfter: *user.DeleteAfter,
+		})
+		return
+	}
 
 	accessToken, refreshToken, err := ctrl.authService.GenerateTokens(user.ID)
 	if err != nil {
@@ -217,6 +234,10 @@ func (ctrl *UsersController) RefreshTokens(ctx *gin.Context) {
 		apiModels.Error(ctx, http.StatusUnauthorized, "invalid or expired refresh token")
 		return
 	}
+	if _, err = ctrl.userService.GetUserByID(ctx, userID); err != nil {
+		apiModels.Error(ctx, http.StatusUnauthorized, "invalid or expired refresh token") // Deleted or deactivated, sessions end with the account
+		return
+	}
 
 	accessToken, refreshToken, err := ctrl.authService.GenerateTokens(userID)
 	if err != nil {
@@ -473,7 +494,100 @@ func (ctrl *UsersController) UpdateAvatar(ctx *gin.Context) {
 	}
 	defer func() { _ = file.Close() }()
 
-	if err = ctrl.userService.UpdateAvatar(ctx, userID, file, fileHeader.Size, contentType); err != nil {
+	if err = ctrl.userService.UpdateAvatar(ctx, userID, file); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	user, err := ctrl.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user.ToPrivateResponse())
+}
+
+// CreateAvatarUploadURL GoDoc
+// @Summary Get avatar upload URL
+// @Description Presign a URL to PUT the avatar straight to S3, send the returned headers with it and confirm the upload afterwards
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UploadURLRequest true "Type and size of the file"
+// @Success 200 {object} models.UploadURLResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/me/avatar/upload-url [post]
+func (ctrl *UsersController) CreateAvatarUploadURL(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.UploadURLRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	maxSize := int64(viper.GetInt(config.MinioMaxFileSize)) << 20
+	if req.Size > maxSize {
+		apiModels.Error(ctx, http.StatusBadRequest, fmt.Sprintf("avatar file too large (max %d MB)", maxSize>>20))
+		return
+	}
+
+	upload, err := ctrl.userService.CreateAvatarUploadURL(ctx, userID, req)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, upload)
+}
+
+// ConfirmAvatarUpload GoDoc
+// @Summary Confirm avatar upload
+// @Description Check the file uploaded to S3 and make it the avatar of the current user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ConfirmUploadRequest true "Key returned with the upload URL"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /users/me/avatar/confirm [post]
+func (ctrl *UsersController) ConfirmAvatarUpload(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.ConfirmUploadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	maxSize := int64(viper.GetInt(config.MinioMaxFileSize)) << 20
+	if err := ctrl.userService.ConfirmAvatarUpload(ctx, userID, req.ObjectKey, maxSize); err != nil {
 		if apiModels.RespondWithServiceError(ctx, err) {
 			return
 		}
@@ -611,15 +725,45 @@ func (ctrl *UsersController) SetNewPassword(ctx *gin.Context) {
 	ctx.JSON(200, apiModels.APIResponse{Message: "password has been reset"})
 }
 
+// CancelDeletion GoDoc
+// @Summary Cancel account deletion
+// @Description Reactivate an account scheduled for deletion with the token from the email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.CancelDeletionRequest true "Cancellation token"
+// @Success 200 {object} apiModels.APIResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /auth/cancel-deletion [post]
+func (ctrl *UsersController) CancelDeletion(ctx *gin.Context) {
+	var req models.CancelDeletionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	if err := ctrl.userService.CancelDeletion(ctx, req.Token); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(200, apiModels.APIResponse{Message: "account deletion cancelled"})
+}
+
 // DeleteCurrentUser GoDoc
 // @Summary Delete current user
-// @Description Delete current user account
+// @Description Deactivate current user account and delete it after the grace period. An email with a link to keep
+// @Description the account is sent, logging in again also offers to keep it
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body models.DeleteAccountRequest true "Current password"
-// @Success 200 {object} apiModels.APIResponse
+// @Success 200 {object} models.AccountDeletionResponse
 // @Failure 400 {object} apiModels.APIError
 // @Failure 401 {object} apiModels.APIError
 // @Failure 500 {object} apiModels.APIError
@@ -645,10 +789,11 @@ func (ctrl *UsersController) DeleteCurrentUser(ctx *gin.Context) {
 		return
 	}
 
-	if err := ctrl.userService.Delete(ctx, userID); err != nil {
+	deleteAfter, err := ctrl.userService.Delete(ctx, userID)
+	if err != nil {
 		apiModels.InternalError(ctx, err.Error())
 		return
 	}
 
-	ctx.JSON(200, apiModels.APIResponse{Message: "account deleted"})
+	ctx.JSON(200, models.AccountDeletionResponse{Message: "account is scheduled for deletion", DeleteAfter: deleteAfter})
 }
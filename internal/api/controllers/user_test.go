@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/textproto"
+	"strings"
 	"testing"
 	"time"
 
@@ -67,13 +68,16 @@ type userControllerServiceMock struct {
 	getUserByUsernameFn     func(ctx context.Context, username string) (models.User, error)
 	searchUsersByUsernameFn func(ctx context.Context, query string, limit int) ([]models.User, error)
 	updateUserByIDFn        func(ctx context.Context, id uuid.UUID, req models.UpdateUserRequest) error
-	updateAvatarFn          func(ctx context.Context, id uuid.UUID, reader io.Reader, size int64, contentType string) error
+	updateAvatarFn          func(ctx context.Context, id uuid.UUID, reader io.Reader) error
 	deleteAvatarFn          func(ctx context.Context, id uuid.UUID) error
+	avatarUploadURLFn       func(ctx context.Context, id uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error)
+	confirmAvatarUploadFn   func(ctx context.Context, id uuid.UUID, objectKey string, maxSize int64) error
 	verifyPasswordFn        func(ctx context.Context, id uuid.UUID, password string) error
 	changePasswordFn        func(ctx context.Context, id uuid.UUID, req models.ChangePasswordRequest) error
 	requestPasswordResetFn  func(ctx context.Context, email string) error
 	resetPasswordFn         func(ctx context.Context, token, newPassword string) error
-	deleteFn                func(ctx context.Context, id uuid.UUID) error
+	deleteFn                func(ctx context.Context, id uuid.UUID) (time.Time, error)
+	cancelDeletionFn        func(ctx context.Context, token string) error
 }
 
 func (m *userControllerServiceMock) Register(ctx context.Context, req models.RegisterUserRequest) (models.User, error) {
@@ -125,9 +129,23 @@ func (m *userControllerServiceMock) UpdateUserByID(ctx context.Context, id uuid.
 	return nil
 }
 
-func (m *userControllerServiceMock) UpdateAvatar(ctx context.Context, id uuid.UUID, reader io.Reader, size int64, contentType string) error {
+func (m *userControllerServiceMock) UpdateAvatar(ctx context.Context, id uuid.UUID, reader io.Reader) error {
 	if m.updateAvatarFn != nil {
-		return m.updateAvatarFn(ctx, id, reader, size, contentType)
+		return m.updateAvatarFn(ctx, id, reader)
+	}
+	return nil
+}
+
+func (m *userControllerServiceMock) CreateAvatarUploadURL(ctx context.Context, id uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error) {
+	if m.avatarUploadURLFn != nil {
+		return m.avatarUploadURLFn(ctx, id, req)
+	}
+	return models.UploadURLResponse{}, nil
+}
+
+func (m *userControllerServiceMock) ConfirmAvatarUpload(ctx context.Context, id uuid.UUID, objectKey string, maxSize int64) error {
+	if m.confirmAvatarUploadFn != nil {
+		return m.confirmAvatarUploadFn(ctx, id, objectKey, maxSize)
 	}
 	return nil
 }
@@ -167,10 +185,17 @@ func (m *userControllerServiceMock) ResetPassword(ctx context.Context, token, ne
 	return nil
 }
 
-func (m *userControllerServiceMock) Delete(ctx context.Context, id uuid.UUID) error {
+func (m *userControllerServiceMock) Delete(ctx context.Context, id uuid.UUID) (time.Time, error) {
 	if m.deleteFn != nil {
 		return m.deleteFn(ctx, id)
 	}
+	return time.Time{}, nil
+}
+
+func (m *userControllerServiceMock) CancelDeletion(ctx context.Context, token string) error {
+	if m.cancelDeletionFn != nil {
+		return m.cancelDeletionFn(ctx, token)
+	}
 	return nil
 }
 
@@ -382,6 +407,23 @@ func TestUsersController_LogIn(t *testing.T) {
 			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
 		}
 	})
+
+	t.Run("scheduled for deletion", func(t *testing.T) {
+		deactivated := user
+		deactivated.DeleteAfter = new(time.Date(2030, 3, 22, 10, 0, 0, 0, time.UTC))
+		as := &userControllerAuthMock{generateTokensFn: func(userID uuid.UUID) (string, string, error) {
+			t.Fatal("tokens issued to a deactivated account")
+			return "", "", nil
+		}}
+		us := &userControllerServiceMock{logInFn: func(ctx context.Context, req models.LogInUserRequest) (models.User, error) {
+			return deactivated, nil
+		}}
+		router := setupUserControllerForTest(as, us)
+		w := userJSONRequest(router, http.MethodPost, "/api/v1/auth/login", `{"username":"john","password":"password123"}`, "")
+		if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), `"delete_after":"2030-03-22T10:00:00Z"`) {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+	})
 }
 
 func TestUsersController_RefreshTokens(t *testing.T) {
@@ -406,6 +448,20 @@ func TestUsersController_RefreshTokens(t *testing.T) {
 		}
 	})
 
+	t.Run("deleted account", func(t *testing.T) {
+		as := &userControllerAuthMock{validateRefreshFn: func(ctx context.Context, token string) (uuid.UUID, error) {
+			return userID, nil
+		}}
+		us := &userControllerServiceMock{getUserByIDFn: func(ctx context.Context, id uuid.UUID) (models.User, error) {
+			return models.User{}, svcErr.NotFoundError{Entity: "user", Field: "id", Value: id.String()}
+		}}
+		router := setupUserControllerForTest(as, us)
+		w := userJSONRequest(router, http.MethodPost, "/api/v1/auth/refresh", `{"refresh_token":"ok"}`, "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
 	t.Run("internal token error", func(t *testing.T) {
 		as := &userControllerAuthMock{
 			validateRefreshFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil },
@@ -554,6 +610,26 @@ func TestUsersController_SetNewPassword(t *testing.T) {
 	})
 }
 
+func TestUsersController_CancelDeletion(t *testing.T) {
+	us := &userControllerServiceMock{cancelDeletionFn: func(ctx context.Context, token string) error {
+		if token != "valid" {
+			return svcErr.ValidationError{Message: "invalid or expired cancellation link"}
+		}
+		return nil
+	}}
+	router := setupUserControllerForTest(&userControllerAuthMock{}, us)
+
+	w := userJSONRequest(router, http.MethodPost, "/api/v1/auth/cancel-deletion", `{"token":"valid"}`, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	w = userJSONRequest(router, http.MethodPost, "/api/v1/auth/cancel-deletion", `{"token":"expired"}`, "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
 func TestUsersController_GetCurrentUser(t *testing.T) {
 	userID := uuid.New()
 	as := &userControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
@@ -647,6 +723,66 @@ func TestUsersController_UpdateCurrentUser(t *testing.T) {
 	})
 }
 
+func TestUsersController_AvatarDirectUpload(t *testing.T) {
+	userID := uuid.New()
+	as := &userControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		router := setupUserControllerForTest(as, &userControllerServiceMock{})
+		w := userJSONRequest(router, http.MethodPost, "/api/v1/users/me/avatar/upload-url", `{"content_type":"text/plain","size":10}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		router := setupUserControllerForTest(as, &userControllerServiceMock{})
+		w := userJSONRequest(router, http.MethodPost, "/api/v1/users/me/avatar/upload-url", `{"content_type":"image/png","size":20971520}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("upload url", func(t *testing.T) {
+		us := &userControllerServiceMock{avatarUploadURLFn: func(ctx context.Context, id uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error) {
+			if id != userID || req.ContentType != "image/png" || req.Size != 1024 {
+				t.Fatalf("unexpected params %s %+v", id, req)
+			}
+			return models.UploadURLResponse{URL: "http://minio/upload", ObjectKey: "uploads/key"}, nil
+		}}
+		router := setupUserControllerForTest(as, us)
+		w := userJSONRequest(router, http.MethodPost, "/api/v1/users/me/avatar/upload-url", `{"content_type":"image/png","size":1024}`, "ok")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"object_key":"uploads/key"`) {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("confirm missing upload", func(t *testing.T) {
+		us := &userControllerServiceMock{confirmAvatarUploadFn: func(ctx context.Context, id uuid.UUID, objectKey string, maxSize int64) error {
+			return svcErr.NotFoundError{Entity: "Upload", Field: "key", Value: objectKey}
+		}}
+		router := setupUserControllerForTest(as, us)
+		w := userJSONRequest(router, http.MethodPost, "/api/v1/users/me/avatar/confirm", `{"object_key":"uploads/key"}`, "ok")
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		var gotKey string
+		var gotMax int64
+		us := &userControllerServiceMock{confirmAvatarUploadFn: func(ctx context.Context, id uuid.UUID, objectKey string, maxSize int64) error {
+			gotKey, gotMax = objectKey, maxSize
+			return nil
+		}}
+		router := setupUserControllerForTest(as, us)
+		w := userJSONRequest(router, http.MethodPost, "/api/v1/users/me/avatar/confirm", `{"object_key":"uploads/key"}`, "ok")
+		if w.Code != http.StatusOK || gotKey != "uploads/key" || gotMax != 10<<20 {
+			t.Fatalf("status = %d, key = %s, max = %d", w.Code, gotKey, gotMax)
+		}
+	})
+}
+
 func TestUsersController_UpdateAvatar(t *testing.T) {
 	userID := uuid.New()
 	as := &userControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
@@ -676,7 +812,7 @@ func TestUsersController_UpdateAvatar(t *testing.T) {
 	})
 
 	t.Run("internal update avatar", func(t *testing.T) {
-		us := &userControllerServiceMock{updateAvatarFn: func(ctx context.Context, id uuid.UUID, reader io.Reader, size int64, contentType string) error {
+		us := &userControllerServiceMock{updateAvatarFn: func(ctx context.Context, id uuid.UUID, reader io.Reader) error {
 			return errors.New("s3")
 		}}
 		router := setupUserControllerForTest(as, us)
@@ -693,7 +829,7 @@ func TestUsersController_UpdateAvatar(t *testing.T) {
 
 	t.Run("internal get user", func(t *testing.T) {
 		us := &userControllerServiceMock{
-			updateAvatarFn: func(ctx context.Context, id uuid.UUID, reader io.Reader, size int64, contentType string) error {
+			updateAvatarFn: func(ctx context.Context, id uuid.UUID, reader io.Reader) error {
 				return nil
 			},
 			getUserByIDFn: func(ctx context.Context, id uuid.UUID) (models.User, error) { return models.User{}, errors.New("db") },
@@ -712,8 +848,8 @@ func TestUsersController_UpdateAvatar(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		us := &userControllerServiceMock{
-			updateAvatarFn: func(ctx context.Context, id uuid.UUID, reader io.Reader, size int64, contentType string) error {
-				if id != userID || contentType != "image/png" {
+			updateAvatarFn: func(ctx context.Context, id uuid.UUID, reader io.Reader) error {
+				if id != userID || reader == nil {
 					t.Fatalf("unexpected update avatar args")
 				}
 				return nil
@@ -814,6 +950,7 @@ func TestUsersController_UpdateCurrentPassword(t *testing.T) {
 
 func TestUsersController_DeleteCurrentUser(t *testing.T) {
 	userID := uuid.New()
+	deleteAfter := time.Date(2030, 3, 22, 10, 0, 0, 0, time.UTC)
 	as := &userControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
 
 	t.Run("bad request", func(t *testing.T) {
@@ -838,7 +975,7 @@ func TestUsersController_DeleteCurrentUser(t *testing.T) {
 	t.Run("internal delete error", func(t *testing.T) {
 		us := &userControllerServiceMock{
 			verifyPasswordFn: func(ctx context.Context, id uuid.UUID, password string) error { return nil },
-			deleteFn:         func(ctx context.Context, id uuid.UUID) error { return errors.New("db") },
+			deleteFn:         func(ctx context.Context, id uuid.UUID) (time.Time, error) { return time.Time{}, errors.New("db") },
 		}
 		router := setupUserControllerForTest(as, us)
 		w := userJSONRequest(router, http.MethodDelete, "/api/v1/users/me", `{"password":"password123"}`, "ok")
@@ -850,12 +987,12 @@ func TestUsersController_DeleteCurrentUser(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		us := &userControllerServiceMock{
 			verifyPasswordFn: func(ctx context.Context, id uuid.UUID, password string) error { return nil },
-			deleteFn:         func(ctx context.Context, id uuid.UUID) error { return nil },
+			deleteFn:         func(ctx context.Context, id uuid.UUID) (time.Time, error) { return deleteAfter, nil },
 		}
 		router := setupUserControllerForTest(as, us)
 		w := userJSONRequest(router, http.MethodDelete, "/api/v1/users/me", `{"password":"password123"}`, "ok")
-		if w.Code != http.StatusOK {
-			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"delete_after":"2030-03-22T10:00:00Z"`) {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
 		}
 	})
 }
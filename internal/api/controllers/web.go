@@ -27,6 +27,8 @@ func (ctrl *WebController) RegisterRoutes() {
 	ctrl.router.GET("/shared/:slug", ctrl.WishlistBySharedLink)
 	ctrl.router.GET("/verify-email", ctrl.VerifyEmail)
 	ctrl.router.GET("/reset-password", ctrl.ResetPassword)
+	ctrl.router.GET("/cancel-deletion", ctrl.CancelDeletion)
+	ctrl.router.GET("/guest-reservation", ctrl.GuestReservation)
 	ctrl.router.NoRoute(ctrl.NotFound)
 }
 
@@ -69,6 +71,17 @@ func (ctrl *WebController) ResetPassword(ctx *gin.Context) {
 	ctx.HTML(http.StatusOK, "reset-password", gin.H{})
 }
 
+// CancelDeletion is where the emailed link takes the user to keep their account, it is only kept once they choose so
+// that mail scanners opening the link don't undo the deletion
+func (ctrl *WebController) CancelDeletion(ctx *gin.Context) {
+	ctx.HTML(http.StatusOK, "cancel-deletion", gin.H{})
+}
+
+// GuestReservation is where the emailed link takes a guest to confirm or release their reservation, nothing changes until they choose
+func (ctrl *WebController) GuestReservation(ctx *gin.Context) {
+	ctx.HTML(http.StatusOK, "guest-reservation", gin.H{})
+}
+
 func (ctrl *WebController) NotFound(ctx *gin.Context) {
 	if strings.HasPrefix(ctx.Request.URL.Path, viper.GetString(config.ApiBasePath)) {
 		ctx.JSON(http.StatusNotFound, gin.H{"message": "route not found"})
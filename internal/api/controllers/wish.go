@@ -2,10 +2,12 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"github.com/spf13/viper"
 
@@ -13,18 +15,28 @@ import (
 	"wishlist/internal/api/middlewares"
 	"wishlist/internal/config"
 	"wishlist/internal/models"
+	"wishlist/internal/wishimport"
 )
 
 type WishService interface {
 	CreateWish(ctx context.Context, listID, userID uuid.UUID, req models.CreateWishRequest) (models.Wish, error)
+	ImportWishes(ctx context.Context, listID, userID uuid.UUID, rows []models.WishImportRow) (models.WishImportReport, error)
 	GetWishByID(ctx context.Context, wishID uuid.UUID) (models.Wish, error)
+	PreviewLink(ctx context.Context, link string) (models.LinkPreview, error)
 	UpdateWish(ctx context.Context, listID, wishID, userID uuid.UUID, req models.UpdateWishRequest) error
-	UpdateWishImage(ctx context.Context, listID, wishID, userID uuid.UUID, reader io.Reader, size int64, contentType string) error
-	ReserveWish(ctx context.Context, listID, wishID, userID uuid.UUID) error
-	ReleaseWish(ctx context.Context, listID, wishID, userID uuid.UUID) error
+	UpdateWishImage(ctx context.Context, listID, wishID, userID uuid.UUID, reader io.Reader) error
+	CreateWishImageUploadURL(ctx context.Context, listID, wishID, userID uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error)
+	ConfirmWishImageUpload(ctx context.Context, listID, wishID, userID uuid.UUID, objectKey string, maxSize int64) error
+	ReserveWish(ctx context.Context, listID, wishID, userID uuid.UUID, req models.ReserveWishRequest) error
+	ReleaseWish(ctx context.Context, listID, wishID, userID uuid.UUID, units int) error
+	ChangeWishStatus(ctx context.Context, listID, wishID, userID uuid.UUID, status models.WishStatus) error
+	ReorderWishes(ctx context.Context, listID, userID uuid.UUID, req models.ReorderWishesRequest) error
+	GetPriceHistory(ctx context.Context, listID, wishID, userID uuid.UUID) ([]models.WishPrice, error)
 	DeleteWish(ctx context.Context, listID, wishID, userID uuid.UUID) error
 }
 
+const maxImportSize = 2 << 20 // 2 MB, far more than the rows one import takes
+
 type WishesController struct {
 	router      *gin.Engine
 	mw          *middlewares.Middlewares
@@ -42,18 +54,33 @@ func (ctrl *WishesController) RegisterRoutes() {
 		authedListRoutes := listRoutes.Group("").Use(ctrl.mw.AuthMiddleware())
 		{
 			authedListRoutes.POST("/:list_id/wishes", ctrl.CreateWish)
+			authedListRoutes.POST("/:list_id/import", ctrl.ImportWishes)
+			authedListRoutes.PUT("/:list_id/wishes/order", ctrl.ReorderWishes)
 			authedListRoutes.PATCH("/:list_id/wishes/:wish_id", ctrl.UpdateWish)
+			authedListRoutes.GET("/:list_id/wishes/:wish_id/price-history", ctrl.GetPriceHistory)
 			authedListRoutes.PUT("/:list_id/wishes/:wish_id/image", ctrl.UpdateWishImage)
+			authedListRoutes.POST("/:list_id/wishes/:wish_id/image/upload-url", ctrl.CreateWishImageUploadURL)
+			authedListRoutes.POST("/:list_id/wishes/:wish_id/image/confirm", ctrl.ConfirmWishImageUpload)
 			authedListRoutes.POST("/:list_id/wishes/:wish_id/reserve", ctrl.ReserveWish)
 			authedListRoutes.DELETE("/:list_id/wishes/:wish_id/reserve", ctrl.ReleaseWish)
+			authedListRoutes.POST("/:list_id/wishes/:wish_id/purchase", ctrl.MarkWishPurchased)
+			authedListRoutes.POST("/:list_id/wishes/:wish_id/receive", ctrl.MarkWishReceived)
+			authedListRoutes.POST("/:list_id/wishes/:wish_id/archive", ctrl.ArchiveWish)
 			authedListRoutes.DELETE("/:list_id/wishes/:wish_id", ctrl.DeleteWish)
 		}
 	}
+	wishRoutes := basePath.Group("/wishes")
+	{
+		authedWishRoutes := wishRoutes.Group("").Use(ctrl.mw.AuthMiddleware())
+		{
+			authedWishRoutes.POST("/preview", ctrl.PreviewLink)
+		}
+	}
 }
 
 // CreateWish GoDoc
 // @Summary Create wish
-// @Description Create wish in wishlist
+// @Description Create wish in wishlist; with autofill set, title, price and image missing from the request are taken from the link
 // @Tags wishes
 // @Accept json
 // @Produce json
@@ -98,6 +125,171 @@ func (ctrl *WishesController) CreateWish(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, wish.ToOwnerResponse())
 }
 
+// ImportWishes GoDoc
+// @Summary Import wishes
+// @Description Add wishes from a CSV file (header row, prices like 19.99), a JSON list in the format of the API or browser bookmarks (HTML); valid rows are created together, the report has every row
+// @Tags wishes
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param file formData file true "File to import"
+// @Param format formData string false "csv, json or html, taken from the file when not set"
+// @Success 200 {object} models.WishImportReport
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/import [post]
+// noinspection DuplicatedCode
+func (ctrl *WishesController) ImportWishes(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "import file is required")
+		return
+	}
+	if fileHeader.Size > maxImportSize {
+		apiModels.Error(ctx, http.StatusBadRequest, fmt.Sprintf("import file too large (max %d MB)", maxImportSize>>20))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(io.LimitReader(file, maxImportSize))
+	if err != nil {
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	format, err := wishimport.DetectFormat(ctx.PostForm("format"), fileHeader.Filename, data)
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	rows, err := wishimport.Parse(format, data)
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	for i := range rows { // Same rules as a wish sent to CreateWish
+		if rows[i].Error == "" {
+			if err = binding.Validator.ValidateStruct(&rows[i].Request); err != nil {
+				rows[i].Error = apiModels.BindErrorMessage(err)
+			}
+		}
+	}
+
+	report, err := ctrl.wishService.ImportWishes(ctx, listID, userID, rows)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// PreviewLink GoDoc
+// @Summary Preview link
+// @Description Read title, price and image of a product page to pre-fill a new wish, the image is copied to our storage
+// @Tags wishes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.PreviewLinkRequest true "Product page link"
+// @Success 200 {object} models.LinkPreviewResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /wishes/preview [post]
+func (ctrl *WishesController) PreviewLink(ctx *gin.Context) {
+	if _, ok := middlewares.GetUserID(ctx); !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.PreviewLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	preview, err := ctrl.wishService.PreviewLink(ctx, req.Link)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preview.ToResponse())
+}
+
+// ReorderWishes GoDoc
+// @Summary Reorder wishes
+// @Description Set the order of all wishes in the wishlist at once
+// @Tags wishes
+// @Accept json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param request body models.ReorderWishesRequest true "Every wish ID of the list in the new order"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 409 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/order [put]
+func (ctrl *WishesController) ReorderWishes(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	var req models.ReorderWishesRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	if err = ctrl.wishService.ReorderWishes(ctx, listID, userID, req); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 // UpdateWish GoDoc
 // @Summary Update wish
 // @Description Update wish fields
@@ -192,7 +384,6 @@ func (ctrl *WishesController) UpdateWishImage(ctx *gin.Context) {
 		return
 	}
 
-	contentType := fileHeader.Header.Get("Content-Type")
 	file, err := fileHeader.Open()
 	if err != nil {
 		apiModels.InternalError(ctx, err.Error())
@@ -200,7 +391,7 @@ func (ctrl *WishesController) UpdateWishImage(ctx *gin.Context) {
 	}
 	defer func() { _ = file.Close() }()
 
-	if err = ctrl.wishService.UpdateWishImage(ctx, listID, wishID, userID, file, fileHeader.Size, contentType); err != nil {
+	if err = ctrl.wishService.UpdateWishImage(ctx, listID, wishID, userID, file); err != nil {
 		if apiModels.RespondWithServiceError(ctx, err) {
 			return
 		}
@@ -222,11 +413,13 @@ func (ctrl *WishesController) UpdateWishImage(ctx *gin.Context) {
 
 // ReserveWish GoDoc
 // @Summary Reserve wish
-// @Description Reserve wish for current user
+// @Description Reserve units of wish for current user, one unit if no body is sent; with reserved_until the reservation is released automatically after that moment
 // @Tags wishes
+// @Accept json
 // @Security BearerAuth
 // @Param list_id path string true "List ID (UUID)"
 // @Param wish_id path string true "Wish ID (UUID)"
+// @Param request body models.ReserveWishRequest false "Units to reserve"
 // @Success 204 {string} string "No Content"
 // @Failure 400 {object} apiModels.APIError
 // @Failure 401 {object} apiModels.APIError
@@ -252,7 +445,15 @@ func (ctrl *WishesController) ReserveWish(ctx *gin.Context) {
 		return
 	}
 
-	if err = ctrl.wishService.ReserveWish(ctx, listID, wishID, userID); err != nil {
+	var req models.ReserveWishRequest
+	if ctx.Request.ContentLength > 0 { // Body is optional
+		if err = ctx.ShouldBindJSON(&req); err != nil {
+			apiModels.RespondWithBindError(ctx, err)
+			return
+		}
+	}
+
+	if err = ctrl.wishService.ReserveWish(ctx, listID, wishID, userID, req); err != nil {
 		if apiModels.RespondWithServiceError(ctx, err) {
 			return
 		}
@@ -265,11 +466,13 @@ func (ctrl *WishesController) ReserveWish(ctx *gin.Context) {
 
 // ReleaseWish GoDoc
 // @Summary Release wish reservation
-// @Description Release wish reservation by current user
+// @Description Release units reserved by current user, all of them if no body is sent
 // @Tags wishes
+// @Accept json
 // @Security BearerAuth
 // @Param list_id path string true "List ID (UUID)"
 // @Param wish_id path string true "Wish ID (UUID)"
+// @Param request body models.ReleaseWishRequest false "Units to release"
 // @Success 204 {string} string "No Content"
 // @Failure 400 {object} apiModels.APIError
 // @Failure 401 {object} apiModels.APIError
@@ -295,7 +498,15 @@ func (ctrl *WishesController) ReleaseWish(ctx *gin.Context) {
 		return
 	}
 
-	if err = ctrl.wishService.ReleaseWish(ctx, listID, wishID, userID); err != nil {
+	var req models.ReleaseWishRequest
+	if ctx.Request.ContentLength > 0 { // Body is optional
+		if err = ctx.ShouldBindJSON(&req); err != nil {
+			apiModels.RespondWithBindError(ctx, err)
+			return
+		}
+	}
+
+	if err = ctrl.wishService.ReleaseWish(ctx, listID, wishID, userID, req.Units); err != nil {
 		if apiModels.RespondWithServiceError(ctx, err) {
 			return
 		}
@@ -306,6 +517,143 @@ func (ctrl *WishesController) ReleaseWish(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// MarkWishPurchased GoDoc
+// @Summary Mark wish as purchased
+// @Description Mark reserved wish as bought, only the user who reserved all of its units can do this
+// @Tags wishes
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 409 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/{wish_id}/purchase [post]
+func (ctrl *WishesController) MarkWishPurchased(ctx *gin.Context) {
+	ctrl.changeWishStatus(ctx, models.WishStatusPurchased)
+}
+
+// MarkWishReceived GoDoc
+// @Summary Mark wish as received
+// @Description Mark reserved or purchased wish as received by the owner
+// @Tags wishes
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 409 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/{wish_id}/receive [post]
+func (ctrl *WishesController) MarkWishReceived(ctx *gin.Context) {
+	ctrl.changeWishStatus(ctx, models.WishStatusReceived)
+}
+
+// ArchiveWish GoDoc
+// @Summary Archive wish
+// @Description Archive wish to keep it for history, archived wishes are not counted in the list
+// @Tags wishes
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 409 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/{wish_id}/archive [post]
+func (ctrl *WishesController) ArchiveWish(ctx *gin.Context) {
+	ctrl.changeWishStatus(ctx, models.WishStatusArchived)
+}
+
+// noinspection DuplicatedCode
+func (ctrl *WishesController) changeWishStatus(ctx *gin.Context, status models.WishStatus) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	wishID, err := uuid.Parse(ctx.Param("wish_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid wish ID")
+		return
+	}
+
+	if err = ctrl.wishService.ChangeWishStatus(ctx, listID, wishID, userID, status); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetPriceHistory GoDoc
+// @Summary Get wish price history
+// @Description Get prices read from the wish link by the price tracking job, oldest first
+// @Tags wishes
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Success 200 {array} models.WishPriceResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/{wish_id}/price-history [get]
+// noinspection DuplicatedCode
+func (ctrl *WishesController) GetPriceHistory(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	wishID, err := uuid.Parse(ctx.Param("wish_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid wish ID")
+		return
+	}
+
+	prices, err := ctrl.wishService.GetPriceHistory(ctx, listID, wishID, userID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	response := make([]models.WishPriceResponse, len(prices))
+	for i, price := range prices {
+		response[i] = price.ToResponse()
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
 // DeleteWish GoDoc
 // @Summary Delete wish
 // @Description Delete wish from wishlist
@@ -349,3 +697,126 @@ func (ctrl *WishesController) DeleteWish(ctx *gin.Context) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// CreateWishImageUploadURL GoDoc
+// @Summary Get wish image upload URL
+// @Description Presign a URL to PUT the wish image straight to S3, send the returned headers with it and confirm the upload afterwards
+// @Tags wishes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Param request body models.UploadURLRequest true "Type and size of the file"
+// @Success 200 {object} models.UploadURLResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/{wish_id}/image/upload-url [post]
+func (ctrl *WishesController) CreateWishImageUploadURL(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	wishID, err := uuid.Parse(ctx.Param("wish_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid wish ID")
+		return
+	}
+
+	var req models.UploadURLRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	maxSize := int64(viper.GetInt(config.MinioMaxFileSize)) << 20
+	if req.Size > maxSize {
+		apiModels.Error(ctx, http.StatusBadRequest, fmt.Sprintf("image file too large (max %d MB)", maxSize>>20))
+		return
+	}
+
+	upload, err := ctrl.wishService.CreateWishImageUploadURL(ctx, listID, wishID, userID, req)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, upload)
+}
+
+// ConfirmWishImageUpload GoDoc
+// @Summary Confirm wish image upload
+// @Description Check the file uploaded to S3 and make it the image of the wish
+// @Tags wishes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "List ID (UUID)"
+// @Param wish_id path string true "Wish ID (UUID)"
+// @Param request body models.ConfirmUploadRequest true "Key returned with the upload URL"
+// @Success 200 {object} models.WishResponse
+// @Failure 400 {object} apiModels.APIError
+// @Failure 401 {object} apiModels.APIError
+// @Failure 403 {object} apiModels.APIError
+// @Failure 404 {object} apiModels.APIError
+// @Failure 500 {object} apiModels.APIError
+// @Router /lists/{list_id}/wishes/{wish_id}/image/confirm [post]
+func (ctrl *WishesController) ConfirmWishImageUpload(ctx *gin.Context) {
+	userID, ok := middlewares.GetUserID(ctx)
+	if !ok {
+		apiModels.Error(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	listID, err := uuid.Parse(ctx.Param("list_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid list ID")
+		return
+	}
+
+	wishID, err := uuid.Parse(ctx.Param("wish_id"))
+	if err != nil {
+		apiModels.Error(ctx, http.StatusBadRequest, "invalid wish ID")
+		return
+	}
+
+	var req models.ConfirmUploadRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		apiModels.RespondWithBindError(ctx, err)
+		return
+	}
+
+	maxSize := int64(viper.GetInt(config.MinioMaxFileSize)) << 20
+	if err = ctrl.wishService.ConfirmWishImageUpload(ctx, listID, wishID, userID, req.ObjectKey, maxSize); err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	wish, err := ctrl.wishService.GetWishByID(ctx, wishID)
+	if err != nil {
+		if apiModels.RespondWithServiceError(ctx, err) {
+			return
+		}
+		apiModels.InternalError(ctx, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, wish.ToOwnerResponse())
+}
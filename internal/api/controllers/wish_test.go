@@ -5,8 +5,10 @@ import (
 	"context"
 	"errors"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -33,11 +35,39 @@ func (m *wishControllerAuthMock) ValidateAccessToken(ctx context.Context, token
 type wishControllerServiceMock struct {
 	createWishFn      func(ctx context.Context, listID, userID uuid.UUID, req models.CreateWishRequest) (models.Wish, error)
 	getWishByIDFn     func(ctx context.Context, wishID uuid.UUID) (models.Wish, error)
+	previewLinkFn     func(ctx context.Context, link string) (models.LinkPreview, error)
 	updateWishFn      func(ctx context.Context, listID, wishID, userID uuid.UUID, req models.UpdateWishRequest) error
-	updateWishImageFn func(ctx context.Context, listID, wishID, userID uuid.UUID, reader io.Reader, size int64, contentType string) error
-	reserveWishFn     func(ctx context.Context, listID, wishID, userID uuid.UUID) error
-	releaseWishFn     func(ctx context.Context, listID, wishID, userID uuid.UUID) error
+	updateWishImageFn func(ctx context.Context, listID, wishID, userID uuid.UUID, reader io.Reader) error
+	reserveWishFn     func(ctx context.Context, listID, wishID, userID uuid.UUID, req models.ReserveWishRequest) error
+	releaseWishFn     func(ctx context.Context, listID, wishID, userID uuid.UUID, units int) error
+	changeStatusFn    func(ctx context.Context, listID, wishID, userID uuid.UUID, status models.WishStatus) error
+	reorderWishesFn   func(ctx context.Context, listID, userID uuid.UUID, req models.ReorderWishesRequest) error
+	priceHistoryFn    func(ctx context.Context, listID, wishID, userID uuid.UUID) ([]models.WishPrice, error)
 	deleteWishFn      func(ctx context.Context, listID, wishID, userID uuid.UUID) error
+	imageUploadURLFn  func(ctx context.Context, listID, wishID, userID uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error)
+	confirmImageFn    func(ctx context.Context, listID, wishID, userID uuid.UUID, objectKey string, maxSize int64) error
+	importWishesFn    func(ctx context.Context, listID, userID uuid.UUID, rows []models.WishImportRow) (models.WishImportReport, error)
+}
+
+func (m *wishControllerServiceMock) ImportWishes(ctx context.Context, listID, userID uuid.UUID, rows []models.WishImportRow) (models.WishImportReport, error) {
+	if m.importWishesFn != nil {
+		return m.importWishesFn(ctx, listID, userID, rows)
+	}
+	return models.WishImportReport{}, nil
+}
+
+func (m *wishControllerServiceMock) CreateWishImageUploadURL(ctx context.Context, listID, wishID, userID uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error) {
+	if m.imageUploadURLFn != nil {
+		return m.imageUploadURLFn(ctx, listID, wishID, userID, req)
+	}
+	return models.UploadURLResponse{}, nil
+}
+
+func (m *wishControllerServiceMock) ConfirmWishImageUpload(ctx context.Context, listID, wishID, userID uuid.UUID, objectKey string, maxSize int64) error {
+	if m.confirmImageFn != nil {
+		return m.confirmImageFn(ctx, listID, wishID, userID, objectKey, maxSize)
+	}
+	return nil
 }
 
 func (m *wishControllerServiceMock) CreateWish(ctx context.Context, listID, userID uuid.UUID, req models.CreateWishRequest) (models.Wish, error) {
@@ -54,6 +84,13 @@ func (m *wishControllerServiceMock) GetWishByID(ctx context.Context, wishID uuid
 	return models.Wish{}, nil
 }
 
+func (m *wishControllerServiceMock) PreviewLink(ctx context.Context, link string) (models.LinkPreview, error) {
+	if m.previewLinkFn != nil {
+		return m.previewLinkFn(ctx, link)
+	}
+	return models.LinkPreview{}, nil
+}
+
 func (m *wishControllerServiceMock) UpdateWish(ctx context.Context, listID, wishID, userID uuid.UUID, req models.UpdateWishRequest) error {
 	if m.updateWishFn != nil {
 		return m.updateWishFn(ctx, listID, wishID, userID, req)
@@ -61,27 +98,48 @@ func (m *wishControllerServiceMock) UpdateWish(ctx context.Context, listID, wish
 	return nil
 }
 
-func (m *wishControllerServiceMock) UpdateWishImage(ctx context.Context, listID, wishID, userID uuid.UUID, reader io.Reader, size int64, contentType string) error {
+func (m *wishControllerServiceMock) UpdateWishImage(ctx context.Context, listID, wishID, userID uuid.UUID, reader io.Reader) error {
 	if m.updateWishImageFn != nil {
-		return m.updateWishImageFn(ctx, listID, wishID, userID, reader, size, contentType)
+		return m.updateWishImageFn(ctx, listID, wishID, userID, reader)
 	}
 	return nil
 }
 
-func (m *wishControllerServiceMock) ReserveWish(ctx context.Context, listID, wishID, userID uuid.UUID) error {
+func (m *wishControllerServiceMock) ReserveWish(ctx context.Context, listID, wishID, userID uuid.UUID, req models.ReserveWishRequest) error {
 	if m.reserveWishFn != nil {
-		return m.reserveWishFn(ctx, listID, wishID, userID)
+		return m.reserveWishFn(ctx, listID, wishID, userID, req)
 	}
 	return nil
 }
 
-func (m *wishControllerServiceMock) ReleaseWish(ctx context.Context, listID, wishID, userID uuid.UUID) error {
+func (m *wishControllerServiceMock) ReleaseWish(ctx context.Context, listID, wishID, userID uuid.UUID, units int) error {
 	if m.releaseWishFn != nil {
-		return m.releaseWishFn(ctx, listID, wishID, userID)
+		return m.releaseWishFn(ctx, listID, wishID, userID, units)
 	}
 	return nil
 }
 
+func (m *wishControllerServiceMock) ChangeWishStatus(ctx context.Context, listID, wishID, userID uuid.UUID, status models.WishStatus) error {
+	if m.changeStatusFn != nil {
+		return m.changeStatusFn(ctx, listID, wishID, userID, status)
+	}
+	return nil
+}
+
+func (m *wishControllerServiceMock) ReorderWishes(ctx context.Context, listID, userID uuid.UUID, req models.ReorderWishesRequest) error {
+	if m.reorderWishesFn != nil {
+		return m.reorderWishesFn(ctx, listID, userID, req)
+	}
+	return nil
+}
+
+func (m *wishControllerServiceMock) GetPriceHistory(ctx context.Context, listID, wishID, userID uuid.UUID) ([]models.WishPrice, error) {
+	if m.priceHistoryFn != nil {
+		return m.priceHistoryFn(ctx, listID, wishID, userID)
+	}
+	return nil, nil
+}
+
 func (m *wishControllerServiceMock) DeleteWish(ctx context.Context, listID, wishID, userID uuid.UUID) error {
 	if m.deleteWishFn != nil {
 		return m.deleteWishFn(ctx, listID, wishID, userID)
@@ -174,6 +232,52 @@ func TestWishesController_CreateWish(t *testing.T) {
 	})
 }
 
+func TestWishesController_PreviewLink(t *testing.T) {
+	userID := uuid.New()
+	as := &wishControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		router := setupWishControllerForTest(as, &wishControllerServiceMock{})
+		w := wishJSONRequest(router, http.MethodPost, "/api/v1/wishes/preview", `{"link":"https://shop.example.com/p/1"}`, "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("invalid link", func(t *testing.T) {
+		router := setupWishControllerForTest(as, &wishControllerServiceMock{})
+		w := wishJSONRequest(router, http.MethodPost, "/api/v1/wishes/preview", `{"link":"not a link"}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("unreachable page", func(t *testing.T) {
+		ws := &wishControllerServiceMock{previewLinkFn: func(ctx context.Context, link string) (models.LinkPreview, error) {
+			return models.LinkPreview{}, svcErr.ValidationError{Message: "could not load a preview for this link"}
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodPost, "/api/v1/wishes/preview", `{"link":"https://shop.example.com/p/1"}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ws := &wishControllerServiceMock{previewLinkFn: func(ctx context.Context, link string) (models.LinkPreview, error) {
+			return models.LinkPreview{Link: link, Title: new("Kettle"), Price: new(int64(20))}, nil
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodPost, "/api/v1/wishes/preview", `{"link":"https://shop.example.com/p/1"}`, "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+		if !bytes.Contains(w.Body.Bytes(), []byte(`"title":"Kettle"`)) {
+			t.Fatalf("body = %s, want preview title", w.Body.String())
+		}
+	})
+}
+
 func TestWishesController_UpdateWish(t *testing.T) {
 	userID := uuid.New()
 	listID := uuid.New()
@@ -264,7 +368,7 @@ func TestWishesController_ReserveWish(t *testing.T) {
 	})
 
 	t.Run("validation", func(t *testing.T) {
-		ws := &wishControllerServiceMock{reserveWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID) error {
+		ws := &wishControllerServiceMock{reserveWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, req models.ReserveWishRequest) error {
 			return svcErr.ValidationError{Message: "already reserved"}
 		}}
 		router := setupWishControllerForTest(as, ws)
@@ -275,7 +379,7 @@ func TestWishesController_ReserveWish(t *testing.T) {
 	})
 
 	t.Run("internal", func(t *testing.T) {
-		ws := &wishControllerServiceMock{reserveWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID) error {
+		ws := &wishControllerServiceMock{reserveWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, req models.ReserveWishRequest) error {
 			return errors.New("db")
 		}}
 		router := setupWishControllerForTest(as, ws)
@@ -285,9 +389,31 @@ func TestWishesController_ReserveWish(t *testing.T) {
 		}
 	})
 
+	t.Run("invalid units", func(t *testing.T) {
+		router := setupWishControllerForTest(as, &wishControllerServiceMock{})
+		w := wishJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/wishes/"+wishID.String()+"/reserve", `{"units":-1}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("units", func(t *testing.T) {
+		ws := &wishControllerServiceMock{reserveWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, req models.ReserveWishRequest) error {
+			if req.Units != 3 || req.ReservedUntil == nil {
+				t.Fatalf("unexpected reserve request: %+v", req)
+			}
+			return nil
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/wishes/"+wishID.String()+"/reserve", `{"units":3,"reserved_until":"2030-01-02T15:04:05Z"}`, "ok")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+	})
+
 	t.Run("success", func(t *testing.T) {
-		ws := &wishControllerServiceMock{reserveWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID) error {
-			if gotListID != listID || gotWishID != wishID || gotUserID != userID {
+		ws := &wishControllerServiceMock{reserveWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, req models.ReserveWishRequest) error {
+			if gotListID != listID || gotWishID != wishID || gotUserID != userID || req.Units != 0 {
 				t.Fatalf("unexpected reserve args")
 			}
 			return nil
@@ -323,7 +449,7 @@ func TestWishesController_ReleaseWish(t *testing.T) {
 	})
 
 	t.Run("internal", func(t *testing.T) {
-		ws := &wishControllerServiceMock{releaseWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID) error {
+		ws := &wishControllerServiceMock{releaseWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, gotUnits int) error {
 			return errors.New("db")
 		}}
 		router := setupWishControllerForTest(as, ws)
@@ -334,7 +460,7 @@ func TestWishesController_ReleaseWish(t *testing.T) {
 	})
 
 	t.Run("validation", func(t *testing.T) {
-		ws := &wishControllerServiceMock{releaseWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID) error {
+		ws := &wishControllerServiceMock{releaseWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, gotUnits int) error {
 			return svcErr.ValidationError{Message: "wish is not reserved by you"}
 		}}
 		router := setupWishControllerForTest(as, ws)
@@ -346,7 +472,7 @@ func TestWishesController_ReleaseWish(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		called := false
-		ws := &wishControllerServiceMock{releaseWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID) error {
+		ws := &wishControllerServiceMock{releaseWishFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, gotUnits int) error {
 			called = true
 			if gotListID != listID || gotWishID != wishID || gotUserID != userID {
 				t.Fatalf("unexpected release args")
@@ -364,6 +490,256 @@ func TestWishesController_ReleaseWish(t *testing.T) {
 	})
 }
 
+func TestWishesController_ChangeWishStatus(t *testing.T) {
+	userID := uuid.New()
+	listID := uuid.New()
+	wishID := uuid.New()
+	as := &wishControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
+	basePath := "/api/v1/lists/" + listID.String() + "/wishes/" + wishID.String()
+
+	t.Run("invalid wish ID", func(t *testing.T) {
+		router := setupWishControllerForTest(as, &wishControllerServiceMock{})
+		w := wishJSONRequest(router, http.MethodPost, "/api/v1/lists/"+listID.String()+"/wishes/not-uuid/purchase", "", "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		ws := &wishControllerServiceMock{changeStatusFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, status models.WishStatus) error {
+			return svcErr.ForbiddenError{Message: "only the one who reserved this wish can mark it as purchased"}
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodPost, basePath+"/purchase", "", "ok")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		ws := &wishControllerServiceMock{changeStatusFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, status models.WishStatus) error {
+			return svcErr.ConflictError{Message: "wish status has changed, reload and try again"}
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodPost, basePath+"/archive", "", "ok")
+		if w.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusConflict)
+		}
+	})
+
+	for route, want := range map[string]models.WishStatus{
+		"purchase": models.WishStatusPurchased,
+		"receive":  models.WishStatusReceived,
+		"archive":  models.WishStatusArchived,
+	} {
+		t.Run(route, func(t *testing.T) {
+			var got models.WishStatus
+			ws := &wishControllerServiceMock{changeStatusFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, status models.WishStatus) error {
+				if gotListID != listID || gotWishID != wishID || gotUserID != userID {
+					t.Fatalf("unexpected params")
+				}
+				got = status
+				return nil
+			}}
+			router := setupWishControllerForTest(as, ws)
+			w := wishJSONRequest(router, http.MethodPost, basePath+"/"+route, "", "ok")
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+			if got != want {
+				t.Fatalf("status passed = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestWishesController_ImageDirectUpload(t *testing.T) {
+	userID := uuid.New()
+	listID := uuid.New()
+	wishID := uuid.New()
+	as := &wishControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
+	path := "/api/v1/lists/" + listID.String() + "/wishes/" + wishID.String() + "/image"
+	viper.Set(config.MinioMaxFileSize, 5)
+
+	t.Run("not an editor", func(t *testing.T) {
+		ws := &wishControllerServiceMock{imageUploadURLFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error) {
+			return models.UploadURLResponse{}, svcErr.ForbiddenError{Message: "you are not allowed to edit this wish"}
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodPost, path+"/upload-url", `{"content_type":"image/jpeg","size":100}`, "ok")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		ws := &wishControllerServiceMock{
+			confirmImageFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID, objectKey string, maxSize int64) error {
+				if gotListID != listID || gotWishID != wishID || gotUserID != userID || objectKey != "uploads/key" || maxSize != 5<<20 {
+					t.Fatalf("unexpected params")
+				}
+				return nil
+			},
+			getWishByIDFn: func(ctx context.Context, id uuid.UUID) (models.Wish, error) {
+				return models.Wish{ID: wishID, ListID: listID, Title: "Kettle"}, nil
+			},
+		}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodPost, path+"/confirm", `{"object_key":"uploads/key"}`, "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+	})
+}
+
+func importRequest(router *gin.Engine, path, filename, content string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", filename)
+	_, _ = part.Write([]byte(content))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer ok")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestWishesController_ImportWishes(t *testing.T) {
+	userID := uuid.New()
+	listID := uuid.New()
+	as := &wishControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
+	path := "/api/v1/lists/" + listID.String() + "/import"
+
+	t.Run("rows are validated like new wishes", func(t *testing.T) {
+		ws := &wishControllerServiceMock{importWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, rows []models.WishImportRow) (models.WishImportReport, error) {
+			if gotListID != listID || gotUserID != userID || len(rows) != 3 {
+				t.Fatalf("ImportWishes(%s, %s, %d rows)", gotListID, gotUserID, len(rows))
+			}
+			if rows[0].Error != "" || rows[1].Error != "link is invalid" || rows[2].Error != "quantity must be greater than 0" {
+				t.Fatalf("row errors = %q, %q, %q", rows[0].Error, rows[1].Error, rows[2].Error)
+			}
+			return models.WishImportReport{Created: 1, Rejected: 2}, nil
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := importRequest(router, path, "wishes.csv", "title,link,quantity\nKettle,https://shop.example/kettle,1\nSocks,not a link,\nMug,,0\n")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"created":1`) {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		router := setupWishControllerForTest(as, &wishControllerServiceMock{})
+		w := importRequest(router, path, "wishes.json", `{"wishes": [`)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("not an editor", func(t *testing.T) {
+		ws := &wishControllerServiceMock{importWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, rows []models.WishImportRow) (models.WishImportReport, error) {
+			return models.WishImportReport{}, svcErr.ForbiddenError{Message: "you are not allowed to edit this wishlist"}
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := importRequest(router, path, "bookmarks.html", `<DT><A HREF="https://shop.example/kettle">Kettle</A>`)
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+}
+
+func TestWishesController_ReorderWishes(t *testing.T) {
+	userID := uuid.New()
+	listID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	as := &wishControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
+	path := "/api/v1/lists/" + listID.String() + "/wishes/order"
+
+	t.Run("empty order", func(t *testing.T) {
+		router := setupWishControllerForTest(as, &wishControllerServiceMock{})
+		w := wishJSONRequest(router, http.MethodPut, path, `{"wish_ids":[]}`, "ok")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("list changed", func(t *testing.T) {
+		ws := &wishControllerServiceMock{reorderWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, req models.ReorderWishesRequest) error {
+			return svcErr.ConflictError{Message: "wishes of the list have changed, reload and try again"}
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodPut, path, `{"wish_ids":["`+first.String()+`"]}`, "ok")
+		if w.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var got []uuid.UUID
+		ws := &wishControllerServiceMock{reorderWishesFn: func(ctx context.Context, gotListID, gotUserID uuid.UUID, req models.ReorderWishesRequest) error {
+			if gotListID != listID || gotUserID != userID {
+				t.Fatalf("unexpected params")
+			}
+			got = req.WishIDs
+			return nil
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodPut, path, `{"wish_ids":["`+second.String()+`","`+first.String()+`"]}`, "ok")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+		if len(got) != 2 || got[0] != second || got[1] != first {
+			t.Fatalf("order passed = %v, want [%s %s]", got, second, first)
+		}
+	})
+}
+
+func TestWishesController_GetPriceHistory(t *testing.T) {
+	userID := uuid.New()
+	listID := uuid.New()
+	wishID := uuid.New()
+	as := &wishControllerAuthMock{validateAccessTokenFn: func(ctx context.Context, token string) (uuid.UUID, error) { return userID, nil }}
+	path := "/api/v1/lists/" + listID.String() + "/wishes/" + wishID.String() + "/price-history"
+
+	t.Run("unauthorized", func(t *testing.T) {
+		router := setupWishControllerForTest(as, &wishControllerServiceMock{})
+		w := wishJSONRequest(router, http.MethodGet, path, "", "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("private list", func(t *testing.T) {
+		ws := &wishControllerServiceMock{priceHistoryFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID) ([]models.WishPrice, error) {
+			return nil, svcErr.ForbiddenError{Message: "this wishlist is private"}
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodGet, path, "", "ok")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ws := &wishControllerServiceMock{priceHistoryFn: func(ctx context.Context, gotListID, gotWishID, gotUserID uuid.UUID) ([]models.WishPrice, error) {
+			if gotListID != listID || gotWishID != wishID || gotUserID != userID {
+				t.Fatalf("unexpected params")
+			}
+			return []models.WishPrice{{WishID: wishID, Price: 120}, {WishID: wishID, Price: 99}}, nil
+		}}
+		router := setupWishControllerForTest(as, ws)
+		w := wishJSONRequest(router, http.MethodGet, path, "", "ok")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+		if !bytes.Contains(w.Body.Bytes(), []byte(`"price":99`)) {
+			t.Fatalf("body = %s, want prices", w.Body.String())
+		}
+	})
+}
+
 func TestWishesController_DeleteWish(t *testing.T) {
 	userID := uuid.New()
 	listID := uuid.New()
@@ -74,12 +74,25 @@ func fieldLabel(field string) string {
 		return "price"
 	case "Currency":
 		return "currency"
+	case "UserID":
+		return "user ID"
+	case "Role":
+		return "role"
+	case "Amount":
+		return "amount"
+	case "PriceAlert":
+		return "price alert"
 	default:
 		return strings.ToLower(field)
 	}
 }
 
 func RespondWithBindError(ctx *gin.Context, err error) {
+	Error(ctx, http.StatusBadRequest, BindErrorMessage(err))
+}
+
+// BindErrorMessage describes what is wrong with a request that failed binding or validation, for the client to show
+func BindErrorMessage(err error) string {
 	var errStr string
 
 	var validationErrs validator.ValidationErrors
@@ -91,7 +104,7 @@ func RespondWithBindError(ctx *gin.Context, err error) {
 		fieldErr := validationErrs[0]
 		field := fieldLabel(fieldErr.Field())
 		switch fieldErr.Tag() {
-		case "required":
+		case "required", "required_without":
 			errStr = fmt.Sprintf("%s is required", field)
 		case "email":
 			errStr = fmt.Sprintf("%s must be a valid email address", field)
@@ -101,6 +114,8 @@ func RespondWithBindError(ctx *gin.Context, err error) {
 			errStr = fmt.Sprintf("%s must be at most %s characters", field, fieldErr.Param())
 		case "oneof":
 			errStr = fmt.Sprintf("%s has invalid value", field)
+		case "gt":
+			errStr = fmt.Sprintf("%s must be greater than %s", field, fieldErr.Param())
 		default:
 			errStr = fmt.Sprintf("%s is invalid", field)
 		}
@@ -112,7 +127,7 @@ func RespondWithBindError(ctx *gin.Context, err error) {
 		errStr = "invalid request payload"
 	}
 
-	Error(ctx, http.StatusBadRequest, errStr)
+	return errStr
 }
 
 func RespondWithServiceError(ctx *gin.Context, err error) bool {
@@ -120,6 +135,7 @@ func RespondWithServiceError(ctx *gin.Context, err error) bool {
 	var conflictErr svcErr.ConflictError
 	var forbiddenErr svcErr.ForbiddenError
 	var notFoundErr svcErr.NotFoundError
+	var tooManyErr svcErr.TooManyRequestsError
 
 	switch {
 	case errors.As(err, &validationErr):
@@ -134,6 +150,9 @@ func RespondWithServiceError(ctx *gin.Context, err error) bool {
 	case errors.As(err, &notFoundErr):
 		Error(ctx, http.StatusNotFound, notFoundErr.Error())
 		return true
+	case errors.As(err, &tooManyErr):
+		Error(ctx, http.StatusTooManyRequests, tooManyErr.Error())
+		return true
 	default:
 		return false
 	}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"time"
 
+	"github.com/spf13/viper"
+
 	"wishlist/internal/api"
 	"wishlist/internal/api/controllers"
 	"wishlist/internal/api/middlewares"
 	"wishlist/internal/config"
+	"wishlist/internal/currency"
 	"wishlist/internal/events"
 	"wishlist/internal/logger"
+	"wishlist/internal/models"
+	"wishlist/internal/scraper"
 	"wishlist/internal/services"
 	"wishlist/internal/storage"
 	"wishlist/pkg/minio"
@@ -17,9 +22,22 @@ import (
 	"wishlist/pkg/redis"
 )
 
+// ObjectStorage is what the storage drivers have in common: MinioServiceImpl and FilesystemStorageImpl
+type ObjectStorage interface {
+	services.AvatarStorage
+	services.BucketStorage
+	MediaURL(objectName string) string
+}
+
 type App struct {
-	API       *api.API
-	publisher *events.Publisher
+	API            *api.API
+	publisher      *events.Publisher
+	reservationJob *services.ReservationExpiryJob
+	priceJob       *services.PriceTrackingJob
+	objectJob      *services.ObjectSweepJob
+	trashJob       *services.TrashPurgeJob
+	accountJob     *services.AccountDeletionJob
+	occasionJob    *services.OccasionReminderJob
 }
 
 func Load() *App {
@@ -38,9 +56,20 @@ func Load() *App {
 	if err != nil {
 		logger.Fatal(err)
 	}
-	s3, err := minio.NewClient(ctx, config.MinioConfig())
-	if err != nil {
-		logger.Fatal(err)
+	var objects ObjectStorage
+	var filesystem *storage.FilesystemStorageImpl
+	switch config.CurrentStorageDriver() {
+	case "filesystem":
+		if filesystem, err = storage.NewFilesystemStorage(); err != nil {
+			logger.Fatal(err)
+		}
+		objects = filesystem
+	default:
+		s3, err := minio.NewClient(ctx, config.MinioConfig())
+		if err != nil {
+			logger.Fatal(err)
+		}
+		objects = storage.NewMinioService(s3)
 	}
 
 	// Events publisher
@@ -49,25 +78,81 @@ func Load() *App {
 		logger.Fatal(err)
 	}
 
+	// Exchange rates
+	exchangeRates, err := config.StaticExchangeRates()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	ratesProvider, err := currency.NewStaticRates(viper.GetString(config.CurrencyBase), exchangeRates)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
 	// Storages
 	userStore := storage.NewUserStorage(db)
 	wishStore := storage.NewWishStorage(db)
 	listStore := storage.NewListStorage(db)
+	memberStore := storage.NewListMemberStorage(db)
+	followStore := storage.NewFollowStorage(db)
+	activityStore := storage.NewActivityStorage(db)
+	occasionStore := storage.NewOccasionStorage(db)
+	guestStore := storage.NewGuestReservationStorage(db)
+	groupStore := storage.NewGroupStorage(db)
+	contribStore := storage.NewWishContributionStorage(db)
+	priceStore := storage.NewWishPriceStorage(db)
+	objectStore := storage.NewObjectStorage(db)
+	trashStore := storage.NewTrashStorage(db)
 	tokenStore := storage.NewTokenStorage(rc)
+	rateLimitStore := storage.NewRateLimitStorage(rc)
 
 	// Services
 	authSvc := services.NewAuthService(tokenStore)
 	emailSvc := services.NewEmailService()
 	var emailSender services.EmailSender
+	var reminderSender services.ReservationReminderSender
+	var priceDropSender services.PriceDropSender
+	var exportSender services.DataExportSender
+	var occasionSender services.OccasionReminderSender
 	if publisher == nil {
-		emailSender = services.NewSMTPEmailSender(emailSvc)
+		smtpSender := services.NewSMTPEmailSender(emailSvc)
+		emailSender, reminderSender, priceDropSender, exportSender, occasionSender = smtpSender, smtpSender, smtpSender, smtpSender, smtpSender
 	} else {
-		emailSender = events.NewEmailSender(publisher)
+		eventSender := events.NewEmailSender(publisher)
+		emailSender, reminderSender, priceDropSender, exportSender, occasionSender = eventSender, eventSender, eventSender, eventSender, eventSender
+	}
+	models.SetMediaURLResolver(objects.MediaURL)
+	if converted, err := services.MigrateStoredURLs(context.Background(), objectStore, objects.GetBaseURL()); err != nil {
+		logger.Fatal(err)
+	} else if converted > 0 {
+		logger.Info("Converted %d stored image URLs to object keys", converted)
 	}
-	minioSvc := storage.NewMinioService(s3)
-	userSvc := services.NewUserService(emailSender, userStore, tokenStore, minioSvc, logger.GlobalLogger{})
-	listSvc := services.NewListService(listStore, wishStore)
-	wishSvc := services.NewWishService(wishStore, listStore, minioSvc)
+	objectTracker := services.NewObjectTracker(objectStore, objects, logger.GlobalLogger{})
+	activityRecorder := services.NewActivityRecorder(activityStore, logger.GlobalLogger{})
+	userSvc := services.NewUserService(emailSender, userStore, tokenStore, objects, logger.GlobalLogger{}, objectTracker, viper.GetDuration(config.AccountDeletionGracePeriod))
+	listSvc := services.NewListService(listStore, wishStore, memberStore, followStore, objects, services.NewPriceConverter(userStore, ratesProvider), objectTracker, activityRecorder)
+	linkScraper := scraper.NewScraper(viper.GetDuration(config.LinkPreviewTimeout), int64(viper.GetInt(config.MinioMaxFileSize))<<20)
+	wishSvc := services.NewWishService(wishStore, listStore, memberStore, followStore, objects, linkScraper, priceStore, objectTracker, activityRecorder)
+	guestSvc := services.NewGuestReservationService(guestStore, listStore, wishStore, emailSender, logger.GlobalLogger{}, rateLimitStore, viper.GetDuration(config.GuestReservationConfirmTTL), models.GuestReservationLimits{
+		PerIPHourly: viper.GetInt(config.GuestReservationHourlyLimit),
+		PerList:     viper.GetInt(config.GuestReservationPendingPerList),
+		PerEmail:    viper.GetInt(config.GuestReservationPendingPerEmail),
+	})
+	memberSvc := services.NewListMemberService(memberStore, listStore)
+	followSvc := services.NewFollowService(followStore)
+	feedSvc := services.NewFeedService(activityStore)
+	occasionSvc := services.NewOccasionService(occasionStore)
+	groupSvc := services.NewGroupService(groupStore, listStore, emailSender, logger.GlobalLogger{})
+	contribSvc := services.NewWishContributionService(contribStore, wishStore, listStore, memberStore, followStore)
+	trashSvc := services.NewTrashService(trashStore, viper.GetDuration(config.TrashRetention))
+	exportSvc := services.NewDataExportService(userStore, listStore, wishStore, tokenStore, objects, exportSender, logger.GlobalLogger{}, int64(viper.GetInt(config.DataExportSyncLimit))<<20, viper.GetDuration(config.DataExportLinkTTL))
+
+	// Background jobs
+	reservationJob := services.NewReservationExpiryJob(wishStore, reminderSender, logger.GlobalLogger{}, viper.GetDuration(config.ReservationJobInterval), viper.GetDuration(config.ReservationReminderLeadTime))
+	priceJob := services.NewPriceTrackingJob(priceStore, linkScraper, priceDropSender, logger.GlobalLogger{}, viper.GetDuration(config.PriceTrackingInterval), viper.GetInt(config.PriceDropPercent))
+	objectJob := services.NewObjectSweepJob(objectStore, objects, logger.GlobalLogger{}, viper.GetDuration(config.ObjectSweepInterval), viper.GetDuration(config.ObjectSweepGracePeriod), viper.GetDuration(config.DataExportLinkTTL), viper.GetBool(config.ObjectSweepDryRun))
+	accountJob := services.NewAccountDeletionJob(userStore, objectTracker, logger.GlobalLogger{}, viper.GetDuration(config.AccountDeletionInterval))
+	occasionJob := services.NewOccasionReminderJob(occasionStore, occasionSender, logger.GlobalLogger{}, viper.GetDuration(config.OccasionReminderInterval), viper.GetInt(config.OccasionReminderDaysBefore))
+	trashJob := services.NewTrashPurgeJob(trashStore, objectTracker, logger.GlobalLogger{}, viper.GetDuration(config.TrashPurgeInterval), viper.GetDuration(config.TrashRetention))
 
 	// API
 	e := api.NewEngine()
@@ -78,15 +163,44 @@ func Load() *App {
 	userCtrl := controllers.NewUsersController(e, mw, authSvc, userSvc)
 	listCtrl := controllers.NewListsController(e, mw, listSvc)
 	wishCtrl := controllers.NewWishesController(e, mw, wishSvc)
+	guestCtrl := controllers.NewGuestReservationsController(e, mw, guestSvc)
+	memberCtrl := controllers.NewMembersController(e, mw, memberSvc)
+	followCtrl := controllers.NewFollowsController(e, mw, followSvc)
+	feedCtrl := controllers.NewFeedController(e, mw, feedSvc)
+	occasionCtrl := controllers.NewOccasionsController(e, mw, occasionSvc)
+	groupCtrl := controllers.NewGroupsController(e, mw, groupSvc, listSvc)
+	contribCtrl := controllers.NewContributionsController(e, mw, contribSvc)
+	exportCtrl := controllers.NewExportsController(e, mw, exportSvc)
+	trashCtrl := controllers.NewTrashController(e, mw, trashSvc)
+	var filesCtrl *controllers.FilesController
+	if filesystem != nil {
+		filesCtrl = controllers.NewFilesController(e, filesystem)
+	}
 
 	return &App{
-		API:       api.NewAPI(e, webCtrl, userCtrl, listCtrl, wishCtrl),
-		publisher: publisher,
+		API:            api.NewAPI(e, webCtrl, userCtrl, listCtrl, wishCtrl, guestCtrl, memberCtrl, followCtrl, feedCtrl, occasionCtrl, groupCtrl, contribCtrl, exportCtrl, trashCtrl, filesCtrl),
+		publisher:      publisher,
+		reservationJob: reservationJob,
+		priceJob:       priceJob,
+		objectJob:      objectJob,
+		trashJob:       trashJob,
+		accountJob:     accountJob,
+		occasionJob:    occasionJob,
 	}
 }
 
 func (a *App) Run() {
 	defer a.closeEventPublisher()
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go a.reservationJob.Run(jobsCtx)
+	go a.priceJob.Run(jobsCtx)
+	go a.objectJob.Run(jobsCtx)
+	go a.trashJob.Run(jobsCtx)
+	go a.accountJob.Run(jobsCtx)
+	go a.occasionJob.Run(jobsCtx)
+
 	a.API.RegisterMiddlewares()
 	a.API.RegisterRoutes()
 	a.API.Run()
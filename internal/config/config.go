@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -57,6 +58,15 @@ const (
 	MinioUseSSL          = "app.minio.use_ssl"
 	MinioBucketName      = "app.minio.bucket_name"
 	MinioMaxFileSize     = "app.minio.max_upload_size_mb"
+	MinioPrivateBucket   = "app.minio.private_bucket" // bool, images are served with presigned URLs instead of a world-readable bucket
+	MinioURLTTL          = "app.minio.url_ttl"        // duration, how long a presigned image URL stays valid
+
+	StorageDriver                  = "app.storage.driver"                    // string ("minio" or "filesystem")
+	StorageFilesystemRoot          = "app.storage.filesystem.root"           // string (path), directory the files are written to
+	StorageFilesystemPublicURL     = "app.storage.filesystem.public_url"     // string, address clients reach the API at, files are served under /files
+	StorageFilesystemPrivate       = "app.storage.filesystem.private"        // bool, files are only served with signed URLs that expire
+	StorageFilesystemURLTTL        = "app.storage.filesystem.url_ttl"        // duration, how long a signed file URL stays valid
+	StorageFilesystemSigningSecret = "app.storage.filesystem.signing_secret" // string, key of the URL signatures, derived from the access token secret when empty
 
 	EmailHost     = "app.email.host"
 	EmailPort     = "app.email.port"
@@ -74,6 +84,34 @@ const (
 	KafkaAuthPassword  = "app.broker.kafka.auth.password"
 	KafkaAuthTLS       = "app.broker.kafka.auth.tls"
 	RabbitMQURL        = "app.broker.rabbitmq.url"
+
+	LinkPreviewTimeout = "app.link_preview.timeout" // duration, how long fetching a product page or its image may take
+
+	DataExportSyncLimit = "app.data_export.sync_limit_mb" // int, accounts with more image data are exported in the background and the link is emailed
+	DataExportLinkTTL   = "app.data_export.link_ttl"      // duration, how long the emailed download link and its archive are kept
+
+	GuestReservationConfirmTTL      = "app.guest_reservations.confirm_within"        // duration, how long a guest has to confirm a reservation made from a shared link before it is released
+	GuestReservationHourlyLimit     = "app.guest_reservations.hourly_limit"          // int, reservations one client IP address may start per hour, 0 for no limit
+	GuestReservationPendingPerList  = "app.guest_reservations.max_pending_per_list"  // int, unconfirmed guest reservations a list may have at a time, 0 for no limit
+	GuestReservationPendingPerEmail = "app.guest_reservations.max_pending_per_email" // int, unconfirmed reservations one email address may have on a list, 0 for no limit
+
+	CurrencyRatesProvider = "app.currency.rates_provider" // string ("static")
+	CurrencyBase          = "app.currency.base"           // string, ISO 4217 code rates are quoted in and shown to viewers without a preferred currency
+	CurrencyRates         = "app.currency.rates"          // map of ISO 4217 code to how much of it one base unit buys
+
+	ReservationJobInterval      = "app.jobs.reservation_expiry.interval"      // duration, how often expiring reservations are checked
+	ReservationReminderLeadTime = "app.jobs.reservation_expiry.remind_before" // duration, how long before the deadline reservers are reminded
+	PriceTrackingInterval       = "app.jobs.price_tracking.interval"          // duration, how often the price of each wish with a link is re-read
+	PriceDropPercent            = "app.jobs.price_tracking.drop_percent"      // int, a drop this big is reported when the owner set no alert price, 0 disables it
+	ObjectSweepInterval         = "app.jobs.object_sweep.interval"            // duration, how often the bucket is compared with the database
+	ObjectSweepGracePeriod      = "app.jobs.object_sweep.grace_period"        // duration, unused objects younger than this are kept, e.g. a link preview not saved yet
+	ObjectSweepDryRun           = "app.jobs.object_sweep.dry_run"             // bool, only report unused objects instead of removing them
+	TrashPurgeInterval          = "app.jobs.trash_purge.interval"             // duration, how often lists and wishes past the retention period are deleted for good
+	TrashRetention              = "app.jobs.trash_purge.retention"            // duration, how long deleted lists and wishes can be restored
+	AccountDeletionInterval     = "app.jobs.account_deletion.interval"        // duration, how often accounts past their grace period are deleted
+	AccountDeletionGracePeriod  = "app.jobs.account_deletion.grace_period"    // duration, how long a deleted account stays deactivated and can be reactivated
+	OccasionReminderInterval    = "app.jobs.occasion_reminder.interval"       // duration, how often lists are checked for a coming occasion
+	OccasionReminderDaysBefore  = "app.jobs.occasion_reminder.days_before"    // int, how many days before the occasion of a list its owner, members and reservers are reminded
 )
 
 func LoadConfig() {
@@ -100,18 +138,19 @@ func ValidateConfigFields() error {
 	var required = []string{ // Must be present and non-empty
 		DatabaseHost, DatabasePort, DatabaseUser, DatabasePassword,
 		ApiPort, AccessTokenSecret, RefreshTokenSecret, JwtIssuer, JwtAudience,
-		MinioEndpoint, MinioAccessKeyID, MinioAccessKeySecret,
 	}
 	var dependent = map[string][]string{ // If A=true => must be non-empty B (, C...)
 		LogToFile: {LogFilePath},
 		EmailHost: {EmailUser, EmailPassword, EmailFrom},
 	}
 	var possibleValues = map[string][]string{ // If present, must be one of these values
-		LogLevel:           {"DEBUG", "INFO", "WARN", "ERROR"},
-		LogFormat:          {"text", "json"},
-		LogFileMode:        {"append", "overwrite", "rotate"},
-		BrokerType:         {"none", "kafka", "rabbitmq"},
-		KafkaAuthMechanism: {"plain"},
+		LogLevel:              {"DEBUG", "INFO", "WARN", "ERROR"},
+		LogFormat:             {"text", "json"},
+		LogFileMode:           {"append", "overwrite", "rotate"},
+		BrokerType:            {"none", "kafka", "rabbitmq"},
+		StorageDriver:         {"minio", "filesystem"},
+		KafkaAuthMechanism:    {"plain"},
+		CurrencyRatesProvider: {"static"},
 	}
 	var defaults = map[string]any{ // Will be set if not present, overwrites above required/dependent
 		/* Log */ LogLevel: "INFO", LogFormat: "text", LogToConsole: true, LogToFile: true, LogFilePath: "application.log", LogFileMode: "append",
@@ -120,8 +159,18 @@ func ValidateConfigFields() error {
 		/* API */ ApiBasePath: "/api/v1", ApiShutdownTimeout: "5s",
 		/* JWT */ AccessTokenTTL: "24h", RefreshTokenTTL: "168h" /* 7 days */, PwdResetTokenTTL: "1h", JwtIssuer: "wishlist", JwtAudience: "Wishlist API",
 		/* Email */ EmailPort: "587" /* Default port */, EmailVerifyTokenTTL: "24h",
-		/* Minio */ MinioBucketName: "wishlist", MinioMaxFileSize: 5,
+		/* Minio */ MinioBucketName: "wishlist", MinioMaxFileSize: 5, MinioPrivateBucket: true, MinioURLTTL: "6h",
+		/* Storage */ StorageDriver: "minio", StorageFilesystemRoot: "./data/files", StorageFilesystemPrivate: true, StorageFilesystemURLTTL: "6h",
 		/* Broker */ BrokerType: "none",
+		/* Link preview */ LinkPreviewTimeout: "10s",
+		/* Data export */ DataExportSyncLimit: 20, DataExportLinkTTL: "72h",
+		/* Currency */ CurrencyRatesProvider: "static", CurrencyBase: "EUR",
+		/* Guest reservations */ GuestReservationConfirmTTL: "48h", GuestReservationHourlyLimit: 10, GuestReservationPendingPerList: 10, GuestReservationPendingPerEmail: 2,
+		/* Jobs */ ReservationJobInterval: "10m", ReservationReminderLeadTime: "24h", PriceTrackingInterval: "24h", PriceDropPercent: 10,
+		/* Object sweep */ ObjectSweepInterval: "24h", ObjectSweepGracePeriod: "24h", ObjectSweepDryRun: false,
+		/* Trash purge */ TrashPurgeInterval: "1h", TrashRetention: "720h",
+		/* Account deletion */ AccountDeletionInterval: "1h", AccountDeletionGracePeriod: "336h",
+		/* Occasion reminder */ OccasionReminderInterval: "1h", OccasionReminderDaysBefore: 7,
 	}
 
 	for k, v := range defaults {
@@ -141,6 +190,18 @@ func ValidateConfigFields() error {
 			missing = append(missing, fmt.Sprintf("%s (required when %s=rotate)", LogFilesFolder, LogFileMode))
 		}
 	}
+	switch CurrentStorageDriver() {
+	case "minio":
+		for _, key := range []string{MinioEndpoint, MinioAccessKeyID, MinioAccessKeySecret} {
+			if isEmptyValue(key) {
+				missing = append(missing, fmt.Sprintf("%s (required when %s=minio)", key, StorageDriver))
+			}
+		}
+	case "filesystem":
+		if isEmptyValue(StorageFilesystemPublicURL) {
+			missing = append(missing, fmt.Sprintf("%s (required when %s=filesystem)", StorageFilesystemPublicURL, StorageDriver))
+		}
+	}
 	switch CurrentBrokerType() {
 	case "kafka":
 		if isEmptyValue(KafkaBrokers) {
@@ -196,11 +257,22 @@ func ValidateConfigFields() error {
 			invalid = append(invalid, fmt.Sprintf("'%s' for '%s' (must be one of [%s])", val, key, strings.Join(allowed, ", ")))
 		}
 	}
-	for _, key := range []string{ApiShutdownTimeout, AccessTokenTTL, RefreshTokenTTL, PwdResetTokenTTL, EmailVerifyTokenTTL} {
+	for _, key := range []string{ApiShutdownTimeout, AccessTokenTTL, RefreshTokenTTL, PwdResetTokenTTL, EmailVerifyTokenTTL, MinioURLTTL, StorageFilesystemURLTTL, LinkPreviewTimeout, DataExportLinkTTL, GuestReservationConfirmTTL, ReservationJobInterval, ReservationReminderLeadTime, PriceTrackingInterval, ObjectSweepInterval, ObjectSweepGracePeriod, TrashPurgeInterval, TrashRetention, AccountDeletionInterval, AccountDeletionGracePeriod, OccasionReminderInterval} {
 		if viper.GetDuration(key) <= 0 {
 			invalid = append(invalid, fmt.Sprintf("%s (duration must be >0, got '%s')", key, viper.GetString(key)))
 		}
 	}
+	for _, key := range []string{DataExportSyncLimit, GuestReservationHourlyLimit, GuestReservationPendingPerList, GuestReservationPendingPerEmail} {
+		if limit := viper.GetInt(key); limit < 0 {
+			invalid = append(invalid, fmt.Sprintf("%s (must be >=0, got %d)", key, limit))
+		}
+	}
+	if percent := viper.GetInt(PriceDropPercent); percent < 0 || percent > 100 {
+		invalid = append(invalid, fmt.Sprintf("%s (must be between 0 and 100, got %d)", PriceDropPercent, percent))
+	}
+	if days := viper.GetInt(OccasionReminderDaysBefore); days < 0 || days > 365 {
+		invalid = append(invalid, fmt.Sprintf("%s (must be between 0 and 365, got %d)", OccasionReminderDaysBefore, days))
+	}
 	if len(invalid) > 0 {
 		return fmt.Errorf("invalid config values: %s", strings.Join(invalid, ", "))
 	}
@@ -253,7 +325,25 @@ func MinioConfig() minio.Config {
 		SecretAccessKey: viper.GetString(MinioAccessKeySecret),
 		UseSSL:          viper.GetBool(MinioUseSSL),
 		BucketName:      viper.GetString(MinioBucketName),
+		Private:         viper.GetBool(MinioPrivateBucket),
+	}
+}
+
+// StaticExchangeRates reads CurrencyRates, keys come back lower-cased from viper and are left for the caller to normalize
+func StaticExchangeRates() (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for code, raw := range viper.GetStringMapString(CurrencyRates) {
+		rate, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate '%s' for '%s' in %s", raw, code, CurrencyRates)
+		}
+		rates[code] = rate
 	}
+	return rates, nil
+}
+
+func CurrentStorageDriver() string {
+	return strings.ToLower(strings.TrimSpace(viper.GetString(StorageDriver)))
 }
 
 func CurrentBrokerType() string {
@@ -0,0 +1,131 @@
+package currency
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const DefaultMinorUnits = 2 // Scale of prices whose currency is unknown
+
+// minorUnits maps active ISO 4217 codes to the number of digits after the decimal separator
+var minorUnits = func() map[string]int {
+	byScale := map[int]string{
+		0: "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF",
+		2: "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BOV BRL BSD BTN BWP BYN BZD " +
+			"CAD CDF CHE CHF CHW CNY COP COU CRC CUP CVE CZK DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GTQ GYD " +
+			"HKD HNL HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL MAD MDL MGA MKD MMK MNT MOP MRU " +
+			"MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN PGK PHP PKR PLN QAR RON RSD RUB SAR SBD SCR SDG SEK " +
+			"SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TOP TRY TTD TWD TZS UAH USD USN UYU UZS VED VES WST XCD XCG " +
+			"YER ZAR ZMW ZWG",
+		3: "BHD IQD JOD KWD LYD OMR TND",
+		4: "CLF UYW",
+	}
+
+	units := make(map[string]int)
+	for scale, codes := range byScale {
+		for _, code := range strings.Fields(codes) {
+			units[code] = scale
+		}
+	}
+	return units
+}()
+
+// Normalize trims and upper-cases a currency code, it does not check that the code exists
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsValid reports whether code is an active ISO 4217 currency, codes are expected to be normalized
+func IsValid(code string) bool {
+	_, ok := minorUnits[code]
+	return ok
+}
+
+// MinorUnits returns how many minor units make up one major unit as a power of ten, e.g. 2 for cents
+func MinorUnits(code string) int {
+	if units, ok := minorUnits[code]; ok {
+		return units
+	}
+	return DefaultMinorUnits
+}
+
+// FromMajor turns an amount like 19.99 into minor units of the currency, rounding what does not fit
+func FromMajor(amount float64, code string) int64 {
+	return int64(math.Round(amount * math.Pow10(MinorUnits(code))))
+}
+
+// ParseMajor reads amounts of the currency written by people like "1299", "1 299,00", "1.299,00" or "19.99", false
+// when it is not a number >= 0. The last separator is the decimal one when no more digits follow it than the currency
+// has, at least two, and the other separator groups thousands. Anything that does not read as exactly one of the two,
+// like "1.2,50" or "1,2345", is refused rather than guessed
+func ParseMajor(raw, code string) (float64, bool) {
+	raw = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\u00a0' || r == '\u202f' { // Thousands are often grouped with (non-breaking) spaces
+			return -1
+		}
+		return r
+	}, raw)
+
+	if i := strings.LastIndexAny(raw, ".,"); i >= 0 {
+		separator, other := raw[i:i+1], ","
+		if separator == "," {
+			other = "."
+		}
+
+		if digits := len(raw) - i - 1; digits >= 1 && digits <= max(MinorUnits(code), 2) {
+			integer, ok := ungroup(raw[:i], other)
+			if !ok || strings.Contains(integer, separator) {
+				return 0, false
+			}
+			raw = integer + "." + raw[i+1:]
+		} else {
+			if strings.Contains(raw, other) { // Two kinds of grouping
+				return 0, false
+			}
+			var ok bool
+			if raw, ok = ungroup(raw, separator); !ok {
+				return 0, false
+			}
+		}
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, false
+	}
+	return value, true
+}
+
+// ungroup drops the separator from digits grouped in thousands like "1.299.000", false when the groups are not of three
+func ungroup(raw, separator string) (string, bool) {
+	groups := strings.Split(raw, separator)
+	if len(groups) == 1 {
+		return raw, true
+	}
+
+	for i, group := range groups {
+		if strings.Trim(group, "0123456789") != "" {
+			return "", false
+		}
+		if i == 0 && (group == "" || len(group) > 3 || group[0] == '0') || i > 0 && len(group) != 3 {
+			return "", false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
+
+// Convert moves an amount in minor units of from into minor units of to, rate is the price of one from in to
+func Convert(amount int64, from, to string, rate float64) int64 {
+	return FromMajor(float64(amount)/math.Pow10(MinorUnits(from))*rate, to)
+}
+
+// Format renders an amount in minor units as "1299.00 EUR", the code is left out when it is empty
+func Format(amount int64, code string) string {
+	units := MinorUnits(code)
+	value := strconv.FormatFloat(float64(amount)/math.Pow10(units), 'f', units, 64)
+	if code == "" {
+		return value
+	}
+	return value + " " + code
+}
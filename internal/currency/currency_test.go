@@ -0,0 +1,111 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMinorUnits(t *testing.T) {
+	for code, want := range map[string]int{"EUR": 2, "JPY": 0, "KWD": 3, "CLF": 4, "XYZ": DefaultMinorUnits} {
+		if got := MinorUnits(code); got != want {
+			t.Fatalf("MinorUnits(%s) = %d, want %d", code, got, want)
+		}
+	}
+	if !IsValid(Normalize(" usd ")) || IsValid("US") || IsValid("руб") {
+		t.Fatal("IsValid() accepts only normalized ISO 4217 codes")
+	}
+}
+
+func TestParseMajor(t *testing.T) {
+	tests := []struct {
+		raw, code string
+		want      float64
+	}{
+		{raw: "1299", want: 1299},
+		{raw: "1 299", want: 1299},
+		{raw: "1 299,00", code: "EUR", want: 1299},
+		{raw: "1.299,00", code: "EUR", want: 1299},
+		{raw: "1,299.00", code: "USD", want: 1299},
+		{raw: "1,299.50", want: 1299.5},
+		{raw: "1.234.567,89", want: 1234567.89},
+		{raw: "1,299", code: "USD", want: 1299},
+		{raw: "1.500", code: "JPY", want: 1500},
+		{raw: "1.299", code: "KWD", want: 1.299},
+		{raw: "19.99", want: 19.99},
+		{raw: "12,5", code: "EUR", want: 12.5},
+		{raw: "0,99", want: 0.99},
+	}
+	for _, tt := range tests {
+		if got, ok := ParseMajor(tt.raw, tt.code); !ok || got != tt.want {
+			t.Fatalf("ParseMajor(%q, %q) = %v, %v, want %v", tt.raw, tt.code, got, ok, tt.want)
+		}
+	}
+
+	for _, raw := range []string{"", "free", "-5", "12,", "1.2,50", "12,34,56", "1,29.99", "1.299.00", "0.299,00", "1,2345", "1.299,000", "1,299.000"} {
+		if got, ok := ParseMajor(raw, "EUR"); ok {
+			t.Fatalf("ParseMajor(%q) = %v, want it refused", raw, got)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		from, to string
+		rate     float64
+		want     int64
+	}{
+		{name: "same scale", amount: 1999, from: "EUR", to: "USD", rate: 1.1, want: 2199},
+		{name: "to zero decimals", amount: 1000, from: "USD", to: "JPY", rate: 150, want: 1500},
+		{name: "from zero decimals", amount: 1500, from: "JPY", to: "USD", rate: 1.0 / 150, want: 1000},
+		{name: "to three decimals", amount: 100, from: "EUR", to: "KWD", rate: 0.33, want: 330},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Convert(tt.amount, tt.from, tt.to, tt.rate); got != tt.want {
+				t.Fatalf("Convert() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for want, got := range map[string]string{
+		"1299.00 EUR": Format(129900, "EUR"),
+		"500 JPY":     Format(500, "JPY"),
+		"1.250 KWD":   Format(1250, "KWD"),
+		"0.30":        Format(30, ""),
+	} {
+		if got != want {
+			t.Fatalf("Format() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestStaticRates(t *testing.T) {
+	rates, err := NewStaticRates("eur", map[string]float64{"usd": 1.25, "JPY": 160})
+	if err != nil {
+		t.Fatalf("NewStaticRates() error = %v", err)
+	}
+	if rates.Base() != "EUR" {
+		t.Fatalf("Base() = %s, want EUR", rates.Base())
+	}
+
+	rate, err := rates.Rate(context.Background(), "USD", "JPY")
+	if err != nil || rate != 128 {
+		t.Fatalf("Rate(USD, JPY) = %v, %v, want 128", rate, err)
+	}
+	if _, err = rates.Rate(context.Background(), "EUR", "GBP"); !errors.Is(err, ErrNoRate) {
+		t.Fatalf("Rate(EUR, GBP) error = %v, want ErrNoRate", err)
+	}
+
+	if _, err = NewStaticRates("EUR", map[string]float64{"ABC": 1}); err == nil {
+		t.Fatal("NewStaticRates() accepted an unknown currency")
+	}
+	if _, err = NewStaticRates("EUR", map[string]float64{"USD": 0}); err == nil {
+		t.Fatal("NewStaticRates() accepted a zero rate")
+	}
+}
@@ -0,0 +1,54 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrNoRate = errors.New("no exchange rate")
+
+// StaticRates keeps exchange rates from the config, each rate is how much of the currency one unit of base buys
+type StaticRates struct {
+	base  string
+	rates map[string]float64
+}
+
+func NewStaticRates(base string, rates map[string]float64) (*StaticRates, error) {
+	base = Normalize(base)
+	if !IsValid(base) {
+		return nil, fmt.Errorf("unknown base currency '%s'", base)
+	}
+
+	normalized := map[string]float64{base: 1}
+	for code, rate := range rates {
+		code = Normalize(code)
+		if !IsValid(code) {
+			return nil, fmt.Errorf("unknown currency '%s' in exchange rates", code)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("exchange rate of '%s' must be >0, got %v", code, rate)
+		}
+		normalized[code] = rate
+	}
+
+	return &StaticRates{base: base, rates: normalized}, nil
+}
+
+// Base is the currency rates are quoted in, prices are shown in it to viewers who did not pick a currency
+func (r *StaticRates) Base() string {
+	return r.base
+}
+
+func (r *StaticRates) Rate(_ context.Context, from, to string) (float64, error) {
+	fromRate, ok := r.rates[from]
+	if !ok {
+		return 0, fmt.Errorf("%w for '%s'", ErrNoRate, from)
+	}
+	toRate, ok := r.rates[to]
+	if !ok {
+		return 0, fmt.Errorf("%w for '%s'", ErrNoRate, to)
+	}
+
+	return toRate / fromRate, nil
+}
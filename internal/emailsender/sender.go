@@ -111,6 +111,62 @@ func (s *Sender) handleEmailEvent(ctx context.Context, msg []byte) error {
 
 		return s.emailSvc.SendPasswordResetLetter(ctx, payload.Email, payload.Token)
 
+	case events.TypeReservationReminder:
+		var payload events.ReservationReminderPayload
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal reservation reminder payload: %w", err)
+		}
+
+		return s.emailSvc.SendReservationReminderLetter(ctx, payload.Email, payload.WishTitle, payload.ListID, payload.ReservedUntil)
+
+	case events.TypePriceDropped:
+		var payload events.PriceDroppedPayload
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal price dropped payload: %w", err)
+		}
+
+		return s.emailSvc.SendPriceDropLetter(ctx, payload.Email, payload.WishTitle, payload.ListID, payload.OldPrice, payload.NewPrice, payload.Currency, payload.ToReserver)
+
+	case events.TypeDataExportReady:
+		var payload events.DataExportReadyPayload
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal data export payload: %w", err)
+		}
+
+		return s.emailSvc.SendDataExportLetter(ctx, payload.Email, payload.Token, payload.ExpiresAt)
+
+	case events.TypeAccountDeletion:
+		var payload events.AccountDeletionPayload
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal account deletion payload: %w", err)
+		}
+
+		return s.emailSvc.SendAccountDeletionLetter(ctx, payload.Email, payload.Token, payload.DeleteAfter)
+
+	case events.TypeOccasionReminder:
+		var payload events.OccasionReminderPayload
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal occasion reminder payload: %w", err)
+		}
+
+		return s.emailSvc.SendOccasionReminderLetter(ctx, payload.Email, payload.ListTitle, payload.ListID, payload.OccasionDate, payload.ToReserver)
+
+	case events.TypeGroupAssignment:
+		var payload events.GroupAssignmentPayload
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal group assignment payload: %w", err)
+		}
+
+		return s.emailSvc.SendGroupAssignmentLetter(ctx, payload.Email, payload.GroupTitle, payload.ReceiverName, payload.ListID)
+
+	case events.TypeGuestReservation:
+		var payload events.GuestReservationPayload
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal guest reservation payload: %w", err)
+		}
+
+		return s.emailSvc.SendGuestReservationLetter(ctx, payload.Email, payload.Name, payload.WishTitle, payload.ListTitle, payload.Token, payload.ConfirmBefore)
+
 	default:
 		return fmt.Errorf("unsupported event type: %s", env.Type)
 	}
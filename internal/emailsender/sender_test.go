@@ -12,8 +12,23 @@ import (
 type emailServiceMock struct {
 	verificationCalls int
 	resetCalls        int
+	reminderCalls     int
+	priceDropCalls    int
+	exportCalls       int
+	deletionCalls     int
+	occasionCalls     int
+	groupCalls        int
+	guestCalls        int
+	lastToReserver    bool
 	lastTo            string
 	lastToken         string
+	lastWishTitle     string
+	lastListTitle     string
+	lastListID        string
+	lastGroupTitle    string
+	lastReceiverName  string
+	lastName          string
+	lastDate          time.Time
 }
 
 func (m *emailServiceMock) SendPasswordResetLetter(_ context.Context, to, token string) error {
@@ -30,6 +45,64 @@ func (m *emailServiceMock) SendEmailVerificationLetter(_ context.Context, to, to
 	return nil
 }
 
+func (m *emailServiceMock) SendReservationReminderLetter(_ context.Context, to, wishTitle, _ string, _ time.Time) error {
+	m.reminderCalls++
+	m.lastTo = to
+	m.lastWishTitle = wishTitle
+	return nil
+}
+
+func (m *emailServiceMock) SendPriceDropLetter(_ context.Context, to, wishTitle, _ string, _, _ int64, _ string, toReserver bool) error {
+	m.priceDropCalls++
+	m.lastTo = to
+	m.lastWishTitle = wishTitle
+	m.lastToReserver = toReserver
+	return nil
+}
+
+func (m *emailServiceMock) SendDataExportLetter(_ context.Context, to, token string, _ time.Time) error {
+	m.exportCalls++
+	m.lastTo = to
+	m.lastToken = token
+	return nil
+}
+
+func (m *emailServiceMock) SendAccountDeletionLetter(_ context.Context, to, token string, _ time.Time) error {
+	m.deletionCalls++
+	m.lastTo = to
+	m.lastToken = token
+	return nil
+}
+
+func (m *emailServiceMock) SendOccasionReminderLetter(_ context.Context, to, listTitle, _ string, date time.Time, toReserver bool) error {
+	m.occasionCalls++
+	m.lastTo = to
+	m.lastListTitle = listTitle
+	m.lastDate = date
+	m.lastToReserver = toReserver
+	return nil
+}
+
+func (m *emailServiceMock) SendGroupAssignmentLetter(_ context.Context, to, groupTitle, receiverName, listID string) error {
+	m.groupCalls++
+	m.lastTo = to
+	m.lastGroupTitle = groupTitle
+	m.lastReceiverName = receiverName
+	m.lastListID = listID
+	return nil
+}
+
+func (m *emailServiceMock) SendGuestReservationLetter(_ context.Context, to, name, wishTitle, listTitle, token string, confirmBefore time.Time) error {
+	m.guestCalls++
+	m.lastTo = to
+	m.lastName = name
+	m.lastWishTitle = wishTitle
+	m.lastListTitle = listTitle
+	m.lastToken = token
+	m.lastDate = confirmBefore
+	return nil
+}
+
 func TestSender_HandleEmailEvent_Verification(t *testing.T) {
 	emailSvc := &emailServiceMock{}
 	sender := &Sender{emailSvc: emailSvc}
@@ -84,6 +157,173 @@ func TestSender_HandleEmailEvent_PasswordReset(t *testing.T) {
 	}
 }
 
+func TestSender_HandleEmailEvent_ReservationReminder(t *testing.T) {
+	emailSvc := &emailServiceMock{}
+	sender := &Sender{emailSvc: emailSvc}
+
+	msg := mustMarshalEvent(t, events.TypeReservationReminder, events.ReservationReminderPayload{
+		UserID:        "user-3",
+		Email:         "carol@example.com",
+		WishTitle:     "Bike",
+		ListID:        "list-1",
+		ReservedUntil: time.Now().Add(24 * time.Hour),
+	})
+
+	if err := sender.handleEmailEvent(context.Background(), msg); err != nil {
+		t.Fatalf("handleEmailEvent() error = %v", err)
+	}
+	if emailSvc.reminderCalls != 1 {
+		t.Fatalf("reminderCalls = %d, want 1", emailSvc.reminderCalls)
+	}
+	if emailSvc.lastTo != "carol@example.com" || emailSvc.lastWishTitle != "Bike" {
+		t.Fatalf("lastTo = %q, lastWishTitle = %q", emailSvc.lastTo, emailSvc.lastWishTitle)
+	}
+}
+
+func TestSender_HandleEmailEvent_PriceDropped(t *testing.T) {
+	emailSvc := &emailServiceMock{}
+	sender := &Sender{emailSvc: emailSvc}
+
+	msg := mustMarshalEvent(t, events.TypePriceDropped, events.PriceDroppedPayload{
+		UserID:     "user-4",
+		Email:      "dave@example.com",
+		WishTitle:  "Kettle",
+		ListID:     "list-2",
+		OldPrice:   40,
+		NewPrice:   30,
+		Currency:   "EUR",
+		ToReserver: true,
+	})
+
+	if err := sender.handleEmailEvent(context.Background(), msg); err != nil {
+		t.Fatalf("handleEmailEvent() error = %v", err)
+	}
+	if emailSvc.priceDropCalls != 1 {
+		t.Fatalf("priceDropCalls = %d, want 1", emailSvc.priceDropCalls)
+	}
+	if emailSvc.lastTo != "dave@example.com" || emailSvc.lastWishTitle != "Kettle" || !emailSvc.lastToReserver {
+		t.Fatalf("lastTo = %q, lastWishTitle = %q, lastToReserver = %v", emailSvc.lastTo, emailSvc.lastWishTitle, emailSvc.lastToReserver)
+	}
+}
+
+func TestSender_HandleEmailEvent_DataExportReady(t *testing.T) {
+	emailSvc := &emailServiceMock{}
+	sender := &Sender{emailSvc: emailSvc}
+
+	msg := mustMarshalEvent(t, events.TypeDataExportReady, events.DataExportReadyPayload{
+		UserID:    "user-5",
+		Email:     "erin@example.com",
+		Token:     "export-token",
+		ExpiresAt: time.Now().Add(72 * time.Hour),
+	})
+
+	if err := sender.handleEmailEvent(context.Background(), msg); err != nil {
+		t.Fatalf("handleEmailEvent() error = %v", err)
+	}
+	if emailSvc.exportCalls != 1 {
+		t.Fatalf("exportCalls = %d, want 1", emailSvc.exportCalls)
+	}
+	if emailSvc.lastTo != "erin@example.com" || emailSvc.lastToken != "export-token" {
+		t.Fatalf("lastTo = %q, lastToken = %q", emailSvc.lastTo, emailSvc.lastToken)
+	}
+}
+
+func TestSender_HandleEmailEvent_AccountDeletion(t *testing.T) {
+	emailSvc := &emailServiceMock{}
+	sender := &Sender{emailSvc: emailSvc}
+
+	msg := mustMarshalEvent(t, events.TypeAccountDeletion, events.AccountDeletionPayload{
+		UserID:      "user-6",
+		Email:       "frank@example.com",
+		Token:       "cancel-token",
+		DeleteAfter: time.Now().Add(14 * 24 * time.Hour),
+	})
+
+	if err := sender.handleEmailEvent(context.Background(), msg); err != nil {
+		t.Fatalf("handleEmailEvent() error = %v", err)
+	}
+	if emailSvc.deletionCalls != 1 {
+		t.Fatalf("deletionCalls = %d, want 1", emailSvc.deletionCalls)
+	}
+	if emailSvc.lastTo != "frank@example.com" || emailSvc.lastToken != "cancel-token" {
+		t.Fatalf("lastTo = %q, lastToken = %q", emailSvc.lastTo, emailSvc.lastToken)
+	}
+}
+
+func TestSender_HandleEmailEvent_OccasionReminder(t *testing.T) {
+	emailSvc := &emailServiceMock{}
+	sender := &Sender{emailSvc: emailSvc}
+
+	date := time.Date(2026, time.December, 24, 0, 0, 0, 0, time.UTC)
+	msg := mustMarshalEvent(t, events.TypeOccasionReminder, events.OccasionReminderPayload{
+		UserID:       "user-7",
+		Email:        "grace@example.com",
+		ListTitle:    "Christmas",
+		ListID:       "list-3",
+		OccasionDate: date,
+		ToReserver:   true,
+	})
+
+	if err := sender.handleEmailEvent(context.Background(), msg); err != nil {
+		t.Fatalf("handleEmailEvent() error = %v", err)
+	}
+	if emailSvc.occasionCalls != 1 {
+		t.Fatalf("occasionCalls = %d, want 1", emailSvc.occasionCalls)
+	}
+	if emailSvc.lastTo != "grace@example.com" || emailSvc.lastListTitle != "Christmas" || !emailSvc.lastDate.Equal(date) || !emailSvc.lastToReserver {
+		t.Fatalf("lastTo = %q, lastListTitle = %q, lastDate = %v, lastToReserver = %v", emailSvc.lastTo, emailSvc.lastListTitle, emailSvc.lastDate, emailSvc.lastToReserver)
+	}
+}
+
+func TestSender_HandleEmailEvent_GroupAssignment(t *testing.T) {
+	emailSvc := &emailServiceMock{}
+	sender := &Sender{emailSvc: emailSvc}
+
+	msg := mustMarshalEvent(t, events.TypeGroupAssignment, events.GroupAssignmentPayload{
+		UserID:       "user-8",
+		Email:        "heidi@example.com",
+		GroupID:      "group-1",
+		GroupTitle:   "Office Secret Santa",
+		ReceiverName: "Ivan",
+		ListID:       "list-4",
+	})
+
+	if err := sender.handleEmailEvent(context.Background(), msg); err != nil {
+		t.Fatalf("handleEmailEvent() error = %v", err)
+	}
+	if emailSvc.groupCalls != 1 {
+		t.Fatalf("groupCalls = %d, want 1", emailSvc.groupCalls)
+	}
+	if emailSvc.lastTo != "heidi@example.com" || emailSvc.lastGroupTitle != "Office Secret Santa" || emailSvc.lastReceiverName != "Ivan" || emailSvc.lastListID != "list-4" {
+		t.Fatalf("lastTo = %q, lastGroupTitle = %q, lastReceiverName = %q, lastListID = %q", emailSvc.lastTo, emailSvc.lastGroupTitle, emailSvc.lastReceiverName, emailSvc.lastListID)
+	}
+}
+
+func TestSender_HandleEmailEvent_GuestReservation(t *testing.T) {
+	emailSvc := &emailServiceMock{}
+	sender := &Sender{emailSvc: emailSvc}
+	confirmBefore := time.Date(2026, time.December, 20, 18, 0, 0, 0, time.UTC)
+
+	msg := mustMarshalEvent(t, events.TypeGuestReservation, events.GuestReservationPayload{
+		Email:         "mary@example.com",
+		Name:          "Aunt Mary",
+		WishTitle:     "Bike",
+		ListTitle:     "Birthday",
+		Token:         "guest-token",
+		ConfirmBefore: confirmBefore,
+	})
+
+	if err := sender.handleEmailEvent(context.Background(), msg); err != nil {
+		t.Fatalf("handleEmailEvent() error = %v", err)
+	}
+	if emailSvc.guestCalls != 1 {
+		t.Fatalf("guestCalls = %d, want 1", emailSvc.guestCalls)
+	}
+	if emailSvc.lastTo != "mary@example.com" || emailSvc.lastName != "Aunt Mary" || emailSvc.lastWishTitle != "Bike" || emailSvc.lastToken != "guest-token" || !emailSvc.lastDate.Equal(confirmBefore) {
+		t.Fatalf("lastTo = %q, lastName = %q, lastWishTitle = %q, lastToken = %q, lastDate = %v", emailSvc.lastTo, emailSvc.lastName, emailSvc.lastWishTitle, emailSvc.lastToken, emailSvc.lastDate)
+	}
+}
+
 func mustMarshalEvent(t *testing.T, eventType events.Type, payload any) []byte {
 	t.Helper()
 
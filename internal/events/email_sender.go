@@ -1,6 +1,13 @@
 package events
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+)
 
 type EmailSender struct {
 	publisher *Publisher
@@ -25,3 +32,86 @@ func (s *EmailSender) SendEmailVerification(ctx context.Context, userID, to, tok
 		Token:  token,
 	})
 }
+
+func (s *EmailSender) SendReservationReminder(ctx context.Context, reservation models.ExpiringReservation) error {
+	return s.publisher.PublishReservationReminder(ctx, ReservationReminderPayload{
+		UserID:        reservation.UserID.String(),
+		Email:         reservation.Email,
+		WishTitle:     reservation.WishTitle,
+		ListID:        reservation.ListID.String(),
+		ReservedUntil: *reservation.ReservedUntil,
+	})
+}
+
+func (s *EmailSender) SendPriceDrop(ctx context.Context, recipient models.PriceDropRecipient, drop models.PriceDrop) error {
+	var currency string
+	if drop.Currency != nil {
+		currency = *drop.Currency
+	}
+
+	return s.publisher.PublishPriceDropped(ctx, PriceDroppedPayload{
+		UserID:     recipient.UserID.String(),
+		Email:      recipient.Email,
+		WishTitle:  drop.WishTitle,
+		ListID:     drop.ListID.String(),
+		OldPrice:   drop.OldPrice,
+		NewPrice:   drop.NewPrice,
+		Currency:   currency,
+		ToReserver: recipient.IsReserver,
+	})
+}
+
+func (s *EmailSender) SendDataExportReady(ctx context.Context, userID uuid.UUID, to, token string, expiresAt time.Time) error {
+	return s.publisher.PublishDataExportReady(ctx, DataExportReadyPayload{
+		UserID:    userID.String(),
+		Email:     to,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+func (s *EmailSender) SendAccountDeletion(ctx context.Context, userID, to, token string, deleteAfter time.Time) error {
+	return s.publisher.PublishAccountDeletion(ctx, AccountDeletionPayload{
+		UserID:      userID,
+		Email:       to,
+		Token:       token,
+		DeleteAfter: deleteAfter,
+	})
+}
+
+func (s *EmailSender) SendOccasionReminder(ctx context.Context, recipient models.OccasionRecipient, reminder models.OccasionReminder) error {
+	return s.publisher.PublishOccasionReminder(ctx, OccasionReminderPayload{
+		UserID:       recipient.UserID.String(),
+		Email:        recipient.Email,
+		ListTitle:    reminder.ListTitle,
+		ListID:       reminder.ListID.String(),
+		OccasionDate: reminder.Date,
+		ToReserver:   recipient.IsReserver,
+	})
+}
+
+func (s *EmailSender) SendGroupAssignment(ctx context.Context, userID, to string, notice models.GroupAssignmentNotice) error {
+	var listID string
+	if notice.ListID != nil {
+		listID = notice.ListID.String()
+	}
+	return s.publisher.PublishGroupAssignment(ctx, GroupAssignmentPayload{
+		UserID:       userID,
+		Email:        to,
+		GroupID:      notice.GroupID.String(),
+		GroupTitle:   notice.GroupTitle,
+		ReceiverName: notice.ReceiverName,
+		ListID:       listID,
+	})
+}
+
+func (s *EmailSender) SendGuestReservation(ctx context.Context, to string, notice models.GuestReservationNotice) error {
+	return s.publisher.PublishGuestReservation(ctx, GuestReservationPayload{
+		Email:         to,
+		Name:          notice.Name,
+		WishTitle:     notice.WishTitle,
+		ListTitle:     notice.ListTitle,
+		Token:         notice.Token,
+		ConfirmBefore: notice.ConfirmBefore,
+	})
+}
@@ -17,8 +17,15 @@ const (
 type Type string
 
 const (
-	TypeEmailVerification Type = "email.verification"
-	TypePasswordReset     Type = "email.password_reset"
+	TypeEmailVerification   Type = "email.verification"
+	TypePasswordReset       Type = "email.password_reset"
+	TypeReservationReminder Type = "email.reservation_reminder"
+	TypePriceDropped        Type = "wish.price_dropped"
+	TypeDataExportReady     Type = "email.data_export_ready"
+	TypeAccountDeletion     Type = "email.account_deletion"
+	TypeOccasionReminder    Type = "list.occasion_reminder"
+	TypeGroupAssignment     Type = "email.group_assignment"
+	TypeGuestReservation    Type = "email.guest_reservation"
 )
 
 type Envelope struct {
@@ -40,6 +47,66 @@ type PasswordResetPayload struct {
 	Token  string `json:"token"`
 }
 
+type ReservationReminderPayload struct {
+	UserID        string    `json:"user_id"`
+	Email         string    `json:"email"`
+	WishTitle     string    `json:"wish_title"`
+	ListID        string    `json:"list_id"`
+	ReservedUntil time.Time `json:"reserved_until"`
+}
+
+type PriceDroppedPayload struct {
+	UserID     string `json:"user_id"`
+	Email      string `json:"email"`
+	WishTitle  string `json:"wish_title"`
+	ListID     string `json:"list_id"`
+	OldPrice   int64  `json:"old_price"`
+	NewPrice   int64  `json:"new_price"`
+	Currency   string `json:"currency,omitempty"`
+	ToReserver bool   `json:"to_reserver"`
+}
+
+type DataExportReadyPayload struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type AccountDeletionPayload struct {
+	UserID      string    `json:"user_id"`
+	Email       string    `json:"email"`
+	Token       string    `json:"token"`
+	DeleteAfter time.Time `json:"delete_after"`
+}
+
+type OccasionReminderPayload struct {
+	UserID       string    `json:"user_id"`
+	Email        string    `json:"email"`
+	ListTitle    string    `json:"list_title"`
+	ListID       string    `json:"list_id"`
+	OccasionDate time.Time `json:"occasion_date"`
+	ToReserver   bool      `json:"to_reserver"`
+}
+
+type GroupAssignmentPayload struct {
+	UserID       string `json:"user_id"`
+	Email        string `json:"email"`
+	GroupID      string `json:"group_id"`
+	GroupTitle   string `json:"group_title"`
+	ReceiverName string `json:"receiver_name"`
+	ListID       string `json:"list_id,omitempty"`
+}
+
+type GuestReservationPayload struct {
+	Email         string    `json:"email"`
+	Name          string    `json:"name"`
+	WishTitle     string    `json:"wish_title"`
+	ListTitle     string    `json:"list_title"`
+	Token         string    `json:"token"`
+	ConfirmBefore time.Time `json:"confirm_before"`
+}
+
 func EmailTopic() string {
 	prefix := strings.Trim(viper.GetString(config.KafkaTopicPrefix), ". ")
 	if prefix == "" {
@@ -63,6 +63,34 @@ func (p *Publisher) PublishPasswordReset(ctx context.Context, payload PasswordRe
 	return p.publish(ctx, EmailTopic(), TypePasswordReset, payload)
 }
 
+func (p *Publisher) PublishReservationReminder(ctx context.Context, payload ReservationReminderPayload) error {
+	return p.publish(ctx, EmailTopic(), TypeReservationReminder, payload)
+}
+
+func (p *Publisher) PublishPriceDropped(ctx context.Context, payload PriceDroppedPayload) error {
+	return p.publish(ctx, EmailTopic(), TypePriceDropped, payload)
+}
+
+func (p *Publisher) PublishDataExportReady(ctx context.Context, payload DataExportReadyPayload) error {
+	return p.publish(ctx, EmailTopic(), TypeDataExportReady, payload)
+}
+
+func (p *Publisher) PublishAccountDeletion(ctx context.Context, payload AccountDeletionPayload) error {
+	return p.publish(ctx, EmailTopic(), TypeAccountDeletion, payload)
+}
+
+func (p *Publisher) PublishOccasionReminder(ctx context.Context, payload OccasionReminderPayload) error {
+	return p.publish(ctx, EmailTopic(), TypeOccasionReminder, payload)
+}
+
+func (p *Publisher) PublishGroupAssignment(ctx context.Context, payload GroupAssignmentPayload) error {
+	return p.publish(ctx, EmailTopic(), TypeGroupAssignment, payload)
+}
+
+func (p *Publisher) PublishGuestReservation(ctx context.Context, payload GuestReservationPayload) error {
+	return p.publish(ctx, EmailTopic(), TypeGuestReservation, payload)
+}
+
 func (p *Publisher) publish(ctx context.Context, topic string, eventType Type, payload any) error {
 	payloadData, err := json.Marshal(payload)
 	if err != nil {
@@ -0,0 +1,161 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // Registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+)
+
+type Variant string
+
+const (
+	Thumbnail Variant = "thumbnail" // Cards and avatars in lists
+	Medium    Variant = "medium"    // Detail views
+	Original  Variant = "original"  // Full size, without metadata
+)
+
+// Variants lists every variant an uploaded image is stored in
+var Variants = []Variant{Original, Medium, Thumbnail}
+
+const (
+	thumbnailSize = 320  // Longest side in pixels
+	mediumSize    = 1280 // Longest side in pixels
+	jpegQuality   = 85
+	MaxPixels     = 40_000_000 // Bigger images are refused before decoding, so a small file cannot take all the memory
+)
+
+var (
+	ErrUnsupported = errors.New("unsupported image format")
+	ErrTooLarge    = errors.New("image dimensions are too large")
+)
+
+// Encoded is one variant ready to be uploaded
+type Encoded struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Process checks what the bytes really are, drops EXIF and other metadata, turns the picture upright and
+// returns it in every variant; smaller pictures are never upscaled and WebP is never resized, see processWebP
+func Process(data []byte) (map[Variant]Encoded, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	case "image/webp":
+		return processWebP(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	upright := applyOrientation(toRGBA(src), readOrientation(data, contentType))
+
+	variants := make(map[Variant]Encoded, len(Variants))
+	for _, variant := range Variants {
+		if variant == Original && contentType == "image/gif" {
+			// Re-encoding would keep only the first frame, GIFs carry no EXIF so the upload is safe as it is
+			variants[variant] = Encoded{Data: data, ContentType: contentType, Width: cfg.Width, Height: cfg.Height}
+			continue
+		}
+
+		img := upright
+		if size := maxSide(variant); size > 0 {
+			img = fit(upright, size)
+		}
+		encoded, err := encode(img, contentType)
+		if err != nil {
+			return nil, err
+		}
+		variants[variant] = encoded
+	}
+
+	return variants, nil
+}
+
+// ObjectName is where a variant of the image stored under base lives
+func ObjectName(base string, variant Variant) string {
+	return base + "/" + string(variant)
+}
+
+// VariantURLs maps each variant to its URL given the URL of the original, images uploaded before
+// variants existed have only the original and are served for every size
+func VariantURLs(originalURL string) map[Variant]string {
+	urls := make(map[Variant]string, len(Variants))
+	base, ok := strings.CutSuffix(originalURL, "/"+string(Original))
+	for _, variant := range Variants {
+		if ok {
+			urls[variant] = ObjectName(base, variant)
+		} else {
+			urls[variant] = originalURL
+		}
+	}
+	return urls
+}
+
+// BaseName strips the variant from an object name, names without one are returned as they are
+func BaseName(objectName string) string {
+	for _, variant := range Variants {
+		if base, ok := strings.CutSuffix(objectName, "/"+string(variant)); ok {
+			return base
+		}
+	}
+	return objectName
+}
+
+func maxSide(variant Variant) int {
+	switch variant {
+	case Thumbnail:
+		return thumbnailSize
+	case Medium:
+		return mediumSize
+	default:
+		return 0
+	}
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
+
+// encode writes JPEGs back as JPEG and everything else as PNG to keep transparency
+func encode(img *image.RGBA, contentType string) (Encoded, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		contentType = "image/png"
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	}
+	if err != nil {
+		return Encoded{}, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return Encoded{Data: buf.Bytes(), ContentType: contentType, Width: img.Rect.Dx(), Height: img.Rect.Dy()}, nil
+}
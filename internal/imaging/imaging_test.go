@@ -0,0 +1,169 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testPicture(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			if x < w/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+// withOrientation puts an APP1 segment with the given EXIF orientation right after the SOI marker
+func withOrientation(t *testing.T, jpg []byte, orientation uint16) []byte {
+	t.Helper()
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1) // One entry
+	tiff = binary.BigEndian.AppendUint16(tiff, orientationTag)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0) // Padding and no next IFD
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	out = binary.BigEndian.AppendUint16(out, uint16(len(segment)+2))
+	out = append(out, segment...)
+	return append(out, jpg[2:]...)
+}
+
+func TestProcess_JPEGOrientationAndEXIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testPicture(40, 20), nil); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	data := withOrientation(t, buf.Bytes(), 6)
+	if got := readOrientation(data, "image/jpeg"); got != 6 {
+		t.Fatalf("readOrientation() = %d, want 6", got)
+	}
+
+	variants, err := Process(data)
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	original := variants[Original]
+	if original.ContentType != "image/jpeg" || original.Width != 20 || original.Height != 40 {
+		t.Fatalf("original = %s %dx%d, want image/jpeg 20x40", original.ContentType, original.Width, original.Height)
+	}
+	if bytes.Contains(original.Data, []byte("Exif")) {
+		t.Fatal("original still carries EXIF")
+	}
+
+	// The red left half ends up on top after a clockwise turn
+	img, err := jpeg.Decode(bytes.NewReader(original.Data))
+	if err != nil {
+		t.Fatalf("jpeg.Decode() error = %v", err)
+	}
+	if r, _, b, _ := img.At(10, 5).RGBA(); r < b {
+		t.Fatalf("top of the picture is not red, r=%d b=%d", r, b)
+	}
+}
+
+func TestProcess_Sizes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testPicture(2000, 1000)); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+
+	variants, err := Process(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	want := map[Variant][2]int{Original: {2000, 1000}, Medium: {1280, 640}, Thumbnail: {320, 160}}
+	for variant, size := range want {
+		got := variants[variant]
+		if got.ContentType != "image/png" || got.Width != size[0] || got.Height != size[1] {
+			t.Fatalf("%s = %s %dx%d, want image/png %dx%d", variant, got.ContentType, got.Width, got.Height, size[0], size[1])
+		}
+	}
+
+	small := fit(testPicture(100, 50), thumbnailSize)
+	if small.Rect.Dx() != 100 {
+		t.Fatalf("fit() upscaled a small picture to %d", small.Rect.Dx())
+	}
+}
+
+func TestProcess_Rejects(t *testing.T) {
+	if _, err := Process([]byte("#!/bin/sh\necho not an image")); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Process(script) error = %v, want ErrUnsupported", err)
+	}
+
+	// A PNG header claiming 10000x10000 pixels is refused before anything is decoded
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testPicture(1, 1)); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	data := bytes.Clone(buf.Bytes())
+	binary.BigEndian.PutUint32(data[16:], 10000)
+	binary.BigEndian.PutUint32(data[20:], 10000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	if _, err := Process(data); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Process(huge) error = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestProcess_WebPStripsMetadata(t *testing.T) {
+	chunk := func(fourCC string, payload []byte) []byte {
+		out := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(payload)))...)
+		out = append(out, payload...)
+		if len(payload)%2 == 1 {
+			out = append(out, 0)
+		}
+		return out
+	}
+
+	body := []byte("WEBP")
+	body = append(body, chunk("VP8X", []byte{vp8xEXIFFlag, 0, 0, 0, 0, 0, 0, 0, 0, 0})...)
+	body = append(body, chunk("VP8L", []byte{0x2f, 1, 2, 3, 4})...)
+	body = append(body, chunk("EXIF", []byte("GPS secrets"))...)
+	data := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...)
+	data = append(data, body...)
+
+	variants, err := Process(data)
+	if err != nil {
+		t.Fatalf("Process(webp) error = %v", err)
+	}
+	got := variants[Thumbnail].Data
+	if bytes.Contains(got, []byte("GPS secrets")) || got[20]&vp8xEXIFFlag != 0 {
+		t.Fatal("WebP still carries EXIF")
+	}
+	if int(binary.LittleEndian.Uint32(got[4:])) != len(got)-8 {
+		t.Fatal("RIFF size was not updated")
+	}
+}
+
+func TestVariantURLs(t *testing.T) {
+	urls := VariantURLs("http://minio/bucket/wishes/1/2/original")
+	if urls[Thumbnail] != "http://minio/bucket/wishes/1/2/thumbnail" || urls[Original] != "http://minio/bucket/wishes/1/2/original" {
+		t.Fatalf("VariantURLs() = %v", urls)
+	}
+
+	legacy := VariantURLs("http://minio/bucket/wishes/1/2")
+	if legacy[Medium] != "http://minio/bucket/wishes/1/2" {
+		t.Fatalf("VariantURLs(legacy) = %v, want the original for every size", legacy)
+	}
+
+	if got := BaseName("wishes/1/2/medium"); got != "wishes/1/2" {
+		t.Fatalf("BaseName() = %q", got)
+	}
+}
@@ -0,0 +1,84 @@
+package imaging
+
+import (
+	"image"
+)
+
+// applyOrientation turns the picture the way EXIF orientation asks, so it no longer depends on the tag we strip
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+
+	var dst *image.RGBA
+	var from func(x, y int) (int, int) // Source pixel of each destination pixel
+	switch orientation {
+	case 2: // Mirrored
+		dst, from = image.NewRGBA(image.Rect(0, 0, w, h)), func(x, y int) (int, int) { return w - 1 - x, y }
+	case 3: // Upside down
+		dst, from = image.NewRGBA(image.Rect(0, 0, w, h)), func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }
+	case 4: // Upside down and mirrored
+		dst, from = image.NewRGBA(image.Rect(0, 0, w, h)), func(x, y int) (int, int) { return x, h - 1 - y }
+	case 5: // Transposed
+		dst, from = image.NewRGBA(image.Rect(0, 0, h, w)), func(x, y int) (int, int) { return y, x }
+	case 6: // Needs a clockwise turn
+		dst, from = image.NewRGBA(image.Rect(0, 0, h, w)), func(x, y int) (int, int) { return y, h - 1 - x }
+	case 7: // Transversed
+		dst, from = image.NewRGBA(image.Rect(0, 0, h, w)), func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }
+	case 8: // Needs a counterclockwise turn
+		dst, from = image.NewRGBA(image.Rect(0, 0, h, w)), func(x, y int) (int, int) { return w - 1 - y, x }
+	default:
+		return src
+	}
+
+	dw, dh := dst.Rect.Dx(), dst.Rect.Dy()
+	for y := range dh {
+		for x := range dw {
+			sx, sy := from(x, y)
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+
+	return dst
+}
+
+// fit scales the picture down so its longest side is at most size, averaging every source pixel that falls
+// into a destination pixel; pictures that already fit are returned as they are
+func fit(src *image.RGBA, size int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	if sw <= size && sh <= size {
+		return src
+	}
+
+	dw, dh := size, max(sh*size/sw, 1)
+	if sh > sw {
+		dw, dh = max(sw*size/sh, 1), size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := range dh {
+		sy0, sy1 := y*sh/dh, max((y+1)*sh/dh, y*sh/dh+1)
+		for x := range dw {
+			sx0, sx1 := x*sw/dw, max((x+1)*sw/dw, x*sw/dw+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				offset := src.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,67 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	vp8xEXIFFlag = 0x08
+	vp8xXMPFlag  = 0x04
+)
+
+// processWebP drops EXIF and XMP chunks from a WebP file and stores it as it is in every variant. It is not resized:
+// the standard library can neither decode nor encode WebP, and the decoder in golang.org/x/image has no encoder and
+// no animation support, so smaller variants would turn into PNGs bigger than the original. WebP files are made for
+// the web and are usually small enough already
+func processWebP(data []byte) (map[Variant]Encoded, error) {
+	stripped, err := stripWebPMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := make(map[Variant]Encoded, len(Variants))
+	for _, variant := range Variants {
+		variants[variant] = Encoded{Data: stripped, ContentType: "image/webp"}
+	}
+	return variants, nil
+}
+
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("%w: broken WebP header", ErrUnsupported)
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:12])
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, fmt.Errorf("%w: broken WebP chunk", ErrUnsupported)
+		}
+		fourCC := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size%2 // Chunks are padded to an even size
+		if size < 0 || end > len(data) {
+			return nil, fmt.Errorf("%w: broken WebP chunk", ErrUnsupported)
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+			// Dropped
+		case "VP8X":
+			chunk := bytes.Clone(data[i:end])
+			if size > 0 {
+				chunk[8] &^= vp8xEXIFFlag | vp8xXMPFlag
+			}
+			out.Write(chunk)
+		default:
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+
+	result := out.Bytes()
+	binary.LittleEndian.PutUint32(result[4:], uint32(len(result)-8))
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ActivityKind string
+
+const (
+	ActivityListCreated   ActivityKind = "list_created"
+	ActivityListUpdated   ActivityKind = "list_updated"
+	ActivityWishCreated   ActivityKind = "wish_created"
+	ActivityWishUpdated   ActivityKind = "wish_updated"
+	ActivityWishDeleted   ActivityKind = "wish_deleted"
+	ActivityWishReceived  ActivityKind = "wish_received"
+	ActivityWishArchived  ActivityKind = "wish_archived"
+	ActivityWishReserved  ActivityKind = "wish_reserved"
+	ActivityWishReleased  ActivityKind = "wish_released"
+	ActivityWishPurchased ActivityKind = "wish_purchased"
+)
+
+// IsReservation tells whether the event would tell who reserved a wish, those are kept out of the feed
+func (k ActivityKind) IsReservation() bool {
+	return k == ActivityWishReserved || k == ActivityWishReleased || k == ActivityWishPurchased
+}
+
+type ActivityEvent struct {
+	ID            uuid.UUID
+	ActorID       uuid.UUID
+	ActorUsername string // Only when read for the feed
+	ListID        uuid.UUID
+	ListTitle     string // Only when read for the feed
+	WishID        *uuid.UUID
+	Kind          ActivityKind
+	Title         string // Title of the list or the wish at the time of the change
+	CreatedAt     time.Time
+}
+
+func (e ActivityEvent) ToResponse() ActivityEventResponse {
+	response := ActivityEventResponse{
+		ID:        e.ID,
+		ListID:    e.ListID,
+		ListTitle: e.ListTitle,
+		WishID:    e.WishID,
+		Kind:      e.Kind,
+		Title:     e.Title,
+		CreatedAt: e.CreatedAt,
+	}
+	if !e.Kind.IsReservation() { // Other viewers don't get to know who reserved, same as on the list itself
+		response.ActorID, response.ActorUsername = &e.ActorID, &e.ActorUsername
+	}
+
+	return response
+}
+
+// FeedCursor points at the last event of a feed page, the next page starts right after it
+type FeedCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+var ErrInvalidFeedCursor = errors.New("invalid feed cursor")
+
+func (c FeedCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.CreatedAt.UnixMicro(), 10) + ":" + c.ID.String()))
+}
+
+func ParseFeedCursor(s string) (FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return FeedCursor{}, ErrInvalidFeedCursor
+	}
+
+	micros, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return FeedCursor{}, ErrInvalidFeedCursor
+	}
+	unix, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return FeedCursor{}, ErrInvalidFeedCursor
+	}
+	eventID, err := uuid.Parse(id)
+	if err != nil {
+		return FeedCursor{}, ErrInvalidFeedCursor
+	}
+
+	return FeedCursor{CreatedAt: time.UnixMicro(unix), ID: eventID}, nil
+}
+
+type Feed struct {
+	Events     []ActivityEvent
+	NextCursor *FeedCursor // Nil on the last page
+}
+
+func (f Feed) ToResponse() FeedResponse {
+	response := FeedResponse{Events: make([]ActivityEventResponse, len(f.Events))}
+	for i, event := range f.Events {
+		response.Events[i] = event.ToResponse()
+	}
+	if f.NextCursor != nil {
+		response.NextCursor = new(f.NextCursor.Encode())
+	}
+
+	return response
+}
+
+type ActivityEventResponse struct {
+	ID            uuid.UUID    `json:"id"`
+	ActorID       *uuid.UUID   `json:"actor_id,omitempty"`
+	ActorUsername *string      `json:"actor_username,omitempty" example:"alice"`
+	ListID        uuid.UUID    `json:"list_id"`
+	ListTitle     string       `json:"list_title" example:"Birthday"`
+	WishID        *uuid.UUID   `json:"wish_id,omitempty"`
+	Kind          ActivityKind `json:"kind" example:"wish_created"`
+	Title         string       `json:"title" example:"Coffee grinder"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type FeedResponse struct {
+	Events     []ActivityEventResponse `json:"events"`
+	NextCursor *string                 `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WishContribution struct {
+	WishID    uuid.UUID
+	UserID    uuid.UUID
+	Amount    int64 // In minor units of the wish currency, like the price
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (c WishContribution) ToResponse() WishContributionResponse {
+	return WishContributionResponse{
+		WishID:    c.WishID,
+		UserID:    c.UserID,
+		Amount:    c.Amount,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+type ContributeRequest struct {
+	Amount int64 `json:"amount" binding:"required,gt=0"`
+}
+
+type WishContributionResponse struct {
+	WishID    uuid.UUID `json:"wish_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HeldReservation is a reservation the user holds, with the wish and list it belongs to
+type HeldReservation struct {
+	WishReservation
+	WishTitle string
+	ListID    uuid.UUID
+	ListTitle string
+}
+
+// DataExport is the result of an export request: the archive itself, or nothing yet when it is too big
+// to be built while the client waits and a download link is going to be emailed instead
+type DataExport struct {
+	FileName string
+	Data     []byte
+	Pending  bool
+	Email    string // Where the link goes, only when Pending
+}
+
+// ExportProfile is profile.json of the archive, the password hash never leaves the database
+type ExportProfile struct {
+	ID            uuid.UUID `json:"id"`
+	Avatar        *string   `json:"avatar,omitempty"` // Path of the picture inside the archive
+	Name          string    `json:"name"`
+	Username      string    `json:"username"`
+	Email         *string   `json:"email,omitempty"`
+	EmailVerified bool      `json:"email_verified"`
+	Currency      *string   `json:"currency,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ExportList is one file under lists/ of the archive, its wishes keep the field names of CreateWishRequest
+// so the file can be imported back
+type ExportList struct {
+	ID         uuid.UUID      `json:"id"`
+	Image      *string        `json:"image,omitempty"`
+	Title      string         `json:"title"`
+	Notes      *string        `json:"notes,omitempty"`
+	Visibility ListVisibility `json:"visibility"`
+	Role       ListRole       `json:"role"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	Wishes     []ExportWish   `json:"wishes"`
+
+	OccasionDate         *string `json:"occasion_date,omitempty"`
+	OccasionRecursYearly bool    `json:"occasion_recurs_yearly,omitempty"`
+}
+
+type ExportWish struct {
+	ID         uuid.UUID    `json:"id"`
+	Image      *string      `json:"image,omitempty"` // Path inside the archive, or the link when the image is on another site
+	Title      string       `json:"title"`
+	Notes      *string      `json:"notes,omitempty"`
+	Link       *string      `json:"link,omitempty"`
+	Price      *int64       `json:"price,omitempty"`
+	Currency   *string      `json:"currency,omitempty"`
+	Quantity   int          `json:"quantity"`
+	Priority   WishPriority `json:"priority"`
+	PriceAlert *int64       `json:"price_alert_below,omitempty"`
+	Status     WishStatus   `json:"status"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// ExportReservation is an entry of reservations.json
+type ExportReservation struct {
+	WishID        uuid.UUID  `json:"wish_id"`
+	WishTitle     string     `json:"wish_title"`
+	ListID        uuid.UUID  `json:"list_id"`
+	ListTitle     string     `json:"list_title"`
+	Units         int        `json:"units"`
+	ReservedUntil *time.Time `json:"reserved_until,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type DataExportResponse struct {
+	Message string `json:"message"`
+	Email   string `json:"email"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FollowStatus string
+
+const (
+	FollowStatusNone      FollowStatus = "none"
+	FollowStatusRequested FollowStatus = "requested"
+	FollowStatusAccepted  FollowStatus = "accepted"
+)
+
+type Follow struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (f Follow) IsAccepted() bool {
+	return f.AcceptedAt != nil
+}
+
+func (f Follow) Status() FollowStatus {
+	if f.IsAccepted() {
+		return FollowStatusAccepted
+	}
+	return FollowStatusRequested
+}
+
+func (f Follow) ToResponse() FollowResponse {
+	return FollowResponse{
+		FollowerID: f.FollowerID,
+		FolloweeID: f.FolloweeID,
+		Status:     f.Status(),
+		AcceptedAt: f.AcceptedAt,
+		CreatedAt:  f.CreatedAt,
+	}
+}
+
+// FollowState describes the relation between the requesting user and another one, in both directions
+type FollowState struct {
+	Following  FollowStatus `json:"following" example:"accepted"`    // Requesting user follows the other one
+	FollowedBy FollowStatus `json:"followed_by" example:"requested"` // The other user follows the requesting one
+}
+
+type FollowResponse struct {
+	FollowerID uuid.UUID    `json:"follower_id"`
+	FolloweeID uuid.UUID    `json:"followee_id"`
+	Status     FollowStatus `json:"status" example:"accepted"`
+	AcceptedAt *time.Time   `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Group is a gift exchange, e.g. an office Secret Santa: its organizer invites members and draws who gives to whom
+type Group struct {
+	ID           uuid.UUID
+	OrganizerID  uuid.UUID
+	Title        string
+	Notes        *string
+	MembersCount int        // Accepted members, the organizer included
+	DrawnAt      *time.Time // Time of the latest draw, nil before the first one
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (g Group) ToResponse() GroupResponse {
+	return GroupResponse{
+		ID:           g.ID,
+		OrganizerID:  g.OrganizerID,
+		Title:        g.Title,
+		Notes:        g.Notes,
+		MembersCount: g.MembersCount,
+		DrawnAt:      g.DrawnAt,
+		CreatedAt:    g.CreatedAt,
+		UpdatedAt:    g.UpdatedAt,
+	}
+}
+
+type GroupMember struct {
+	GroupID    uuid.UUID
+	UserID     uuid.UUID
+	Username   string
+	Name       string
+	Email      *string    // Only for the assignment emails, never in responses
+	ListID     *uuid.UUID // The list the member wants gifts from
+	InvitedBy  uuid.UUID
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (m GroupMember) IsAccepted() bool {
+	return m.AcceptedAt != nil
+}
+
+func (m GroupMember) ToResponse() GroupMemberResponse {
+	status := "invited"
+	if m.IsAccepted() {
+		status = "accepted"
+	}
+
+	return GroupMemberResponse{
+		UserID:     m.UserID,
+		Username:   m.Username,
+		Name:       m.Name,
+		ListID:     m.ListID,
+		Status:     status,
+		InvitedBy:  m.InvitedBy,
+		AcceptedAt: m.AcceptedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+// GroupExclusion keeps the giver from drawing the receiver
+type GroupExclusion struct {
+	GroupID    uuid.UUID
+	GiverID    uuid.UUID
+	ReceiverID uuid.UUID
+	CreatedAt  time.Time
+}
+
+func (e GroupExclusion) ToResponse() GroupExclusionResponse {
+	return GroupExclusionResponse{
+		GiverID:    e.GiverID,
+		ReceiverID: e.ReceiverID,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+// GroupDraw is one run of the draw as kept in the audit trail. The same seed and members give the same assignments,
+// so the seed never leaves the server: with it, the members and the exclusions anyone could work out who got whom
+type GroupDraw struct {
+	ID           uuid.UUID
+	GroupID      uuid.UUID
+	DrawnBy      uuid.UUID
+	Seed         int64
+	Participants int
+	StaleAt      *time.Time // When a member who took part left, the assignments no longer add up
+	CreatedAt    time.Time
+}
+
+// Commitment is a hash of the seed, it shows later that a draw was not changed without telling anything about it
+func (d GroupDraw) Commitment() string {
+	sum := sha256.Sum256(binary.BigEndian.AppendUint64(d.ID[:], uint64(d.Seed)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (d GroupDraw) ToResponse() GroupDrawResponse {
+	return GroupDrawResponse{
+		ID:           d.ID,
+		DrawnBy:      d.DrawnBy,
+		Commitment:   d.Commitment(),
+		Participants: d.Participants,
+		StaleAt:      d.StaleAt,
+		CreatedAt:    d.CreatedAt,
+	}
+}
+
+// GroupAssignment pairs a giver with the receiver drawn for them
+type GroupAssignment struct {
+	DrawID     uuid.UUID
+	GiverID    uuid.UUID
+	ReceiverID uuid.UUID
+}
+
+// GroupAssignee is what a giver learns about their receiver from the latest draw
+type GroupAssignee struct {
+	GroupID  uuid.UUID
+	DrawID   uuid.UUID
+	UserID   uuid.UUID
+	Username string
+	Name     string
+	ListID   *uuid.UUID
+	DrawnAt  time.Time
+	StaleAt  *time.Time // Set when the draw lost a member, see GroupDraw
+}
+
+// GroupAssignmentNotice is emailed to a giver after a draw
+type GroupAssignmentNotice struct {
+	GroupID      uuid.UUID
+	GroupTitle   string
+	ReceiverName string
+	ListID       *uuid.UUID // The receiver's list, when they chose one
+}
+
+type CreateGroupRequest struct {
+	Title string  `json:"title" binding:"required" example:"Office Secret Santa"`
+	Notes *string `json:"notes" example:"Up to 30 EUR, presents on December 20"`
+}
+
+type InviteGroupMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required" example:"019cd349-d176-7562-b03b-1db2223b9a01"`
+}
+
+// SetGroupListRequest picks the list the member wants gifts from, null clears it
+type SetGroupListRequest struct {
+	ListID *uuid.UUID `json:"list_id" example:"019cd349-d176-7562-b03b-1db2223b9a01"`
+}
+
+type CreateGroupExclusionRequest struct {
+	GiverID    uuid.UUID `json:"giver_id" binding:"required"`
+	ReceiverID uuid.UUID `json:"receiver_id" binding:"required"`
+	Mutual     bool      `json:"mutual"` // Also keeps the receiver from drawing the giver, e.g. for partners
+}
+
+type GroupResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	OrganizerID  uuid.UUID  `json:"organizer_id"`
+	Title        string     `json:"title" example:"Office Secret Santa"`
+	Notes        *string    `json:"notes,omitempty"`
+	MembersCount int        `json:"members_count"`
+	DrawnAt      *time.Time `json:"drawn_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type GroupMemberResponse struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Username   string     `json:"username" example:"alice"`
+	Name       string     `json:"name" example:"Alice"`
+	ListID     *uuid.UUID `json:"list_id,omitempty"`
+	Status     string     `json:"status" example:"accepted"`
+	InvitedBy  uuid.UUID  `json:"invited_by"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type GroupDetailsResponse struct {
+	GroupResponse
+	Members []GroupMemberResponse `json:"members"`
+}
+
+type GroupExclusionResponse struct {
+	GiverID    uuid.UUID `json:"giver_id"`
+	ReceiverID uuid.UUID `json:"receiver_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GroupDrawResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	DrawnBy      uuid.UUID  `json:"drawn_by"`
+	Commitment   string     `json:"commitment" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Participants int        `json:"participants" example:"8"`
+	StaleAt      *time.Time `json:"stale_at,omitempty"` // A member left since, the organizer has to draw again
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// GroupAssignmentResponse shows the giver their receiver and the receiver's list with its wishes
+type GroupAssignmentResponse struct {
+	GroupID  uuid.UUID     `json:"group_id"`
+	DrawID   uuid.UUID     `json:"draw_id"`
+	UserID   uuid.UUID     `json:"user_id"`
+	Username string        `json:"username" example:"bob"`
+	Name     string        `json:"name" example:"Bob"`
+	List     *ListResponse `json:"list,omitempty"` // Absent when the receiver chose no list or it is no longer public
+	DrawnAt  time.Time     `json:"drawn_at"`
+}
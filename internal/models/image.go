@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strings"
+
+	"wishlist/internal/imaging"
+)
+
+// ImageVariants maps each size of a stored picture to its URL, ready to be put into srcset
+type ImageVariants map[imaging.Variant]string
+
+// resolveMediaURL turns an object key into a URL the client can load, see SetMediaURLResolver
+var resolveMediaURL = func(key string) string { return key }
+
+// SetMediaURLResolver is called once at startup with whatever serves the bucket, e.g. presigned URLs for a private one.
+// Avatars and wish images are stored as object keys and only become URLs in responses
+func SetMediaURLResolver(resolve func(key string) string) {
+	resolveMediaURL = resolve
+}
+
+// IsExternalURL reports whether an image is a link to another site rather than an object key of ours
+func IsExternalURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+func mediaURL(key *string) *string {
+	if key == nil || *key == "" {
+		return key
+	}
+	if IsExternalURL(*key) {
+		return key
+	}
+	return new(resolveMediaURL(*key))
+}
+
+func newImageVariants(key *string) ImageVariants {
+	if key == nil || *key == "" {
+		return nil
+	}
+	if IsExternalURL(*key) {
+		return imaging.VariantURLs(*key)
+	}
+
+	variants := imaging.VariantURLs(*key)
+	for variant, variantKey := range variants {
+		variants[variant] = resolveMediaURL(variantKey)
+	}
+	return variants
+}
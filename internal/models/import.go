@@ -0,0 +1,24 @@
+package models
+
+import "github.com/google/uuid"
+
+// WishImportRow is one entry of an imported file, Error is set when the row could not be read or failed validation
+type WishImportRow struct {
+	Row     int // Line of a CSV file, position in a JSON array or among bookmarks, counted from 1
+	Request CreateWishRequest
+	Error   string
+}
+
+type WishImportResult struct {
+	Row    int        `json:"row"`
+	Title  string     `json:"title,omitempty"`
+	WishID *uuid.UUID `json:"wish_id,omitempty"` // Set for created wishes
+	Error  string     `json:"error,omitempty"`   // Set for rejected rows
+}
+
+// WishImportReport lists every row of the file in order, created wishes are all added at once or not at all
+type WishImportReport struct {
+	Created  int                `json:"created"`
+	Rejected int                `json:"rejected"`
+	Rows     []WishImportResult `json:"rows"`
+}
@@ -6,58 +6,114 @@ import (
 	"github.com/google/uuid"
 )
 
+type ListVisibility string
+
+const (
+	ListVisibilityPrivate   ListVisibility = "private"   // Owner and members only
+	ListVisibilityLinkOnly  ListVisibility = "link_only" // Anyone who has the shared link
+	ListVisibilityFollowers ListVisibility = "followers" // Accepted followers of the owner
+	ListVisibilityPublic    ListVisibility = "public"    // Everyone, listed on the owner's profile
+)
+
 type List struct {
 	ID          uuid.UUID
 	UserID      uuid.UUID
 	Image       *string
 	Title       string
 	Notes       *string
-	IsPublic    bool
+	Visibility  ListVisibility
 	Slug        string
 	WishesCount int
+	Total       *PriceTotal // Prices of the wishes not archived, in the viewer's currency; only when wishes are loaded
+	Role        ListRole    // Role of the requesting user, empty for outsiders
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	OccasionDate         *time.Time // Birthday, wedding or holiday the list is for
+	OccasionRecursYearly bool
+}
+
+// NextOccasion returns the coming occurrence of the list's occasion, nil without one or once a one-off date has passed
+func (l List) NextOccasion(now time.Time) *time.Time {
+	if l.OccasionDate == nil {
+		return nil
+	}
+	return NextOccasion(*l.OccasionDate, l.OccasionRecursYearly, now)
+}
+
+func (l List) withOccasion(response ListResponse) ListResponse {
+	if l.OccasionDate == nil {
+		return response
+	}
+
+	now := time.Now()
+	response.OccasionDate = new(l.OccasionDate.Format(OccasionDateLayout))
+	response.OccasionRecursYearly = l.OccasionRecursYearly
+	if next := l.NextOccasion(now); next != nil {
+		response.NextOccasion = new(next.Format(OccasionDateLayout))
+		response.DaysUntilOccasion = new(DaysUntil(*next, now))
+	}
+	return response
 }
 
 func (l List) ToOwnerResponse() ListResponse {
-	return ListResponse{
+	return l.withOccasion(ListResponse{
 		ID:          l.ID,
 		UserID:      l.UserID,
 		Image:       l.Image,
 		Title:       l.Title,
 		Notes:       l.Notes,
-		IsPublic:    l.IsPublic,
+		IsPublic:    l.Visibility == ListVisibilityPublic,
+		Visibility:  l.Visibility,
 		Slug:        l.Slug,
 		WishesCount: l.WishesCount,
+		Total:       l.Total,
+		Role:        l.Role,
 		CreatedAt:   l.CreatedAt,
 		UpdatedAt:   l.UpdatedAt,
-	}
+	})
 }
 
 func (l List) ToViewerResponse() ListResponse {
-	return ListResponse{
+	return l.withOccasion(ListResponse{
 		ID:          l.ID,
 		UserID:      l.UserID,
 		Image:       l.Image,
 		Title:       l.Title,
 		Notes:       l.Notes,
-		IsPublic:    l.IsPublic,
+		IsPublic:    l.Visibility == ListVisibilityPublic,
+		Visibility:  l.Visibility,
 		WishesCount: l.WishesCount,
+		Total:       l.Total,
+		Role:        l.Role,
 		CreatedAt:   l.CreatedAt,
 		UpdatedAt:   l.UpdatedAt,
-	}
+	})
 }
 
 type CreateListRequest struct {
-	Title string  `json:"title" binding:"required"`
-	Notes *string `json:"notes"`
+	Title                string          `json:"title" binding:"required"`
+	Notes                *string         `json:"notes"`
+	Visibility           *ListVisibility `json:"visibility" binding:"omitempty,oneof=private link_only followers public"`
+	OccasionDate         *string         `json:"occasion_date" binding:"omitempty,datetime=2006-01-02" example:"2026-12-24"`
+	OccasionRecursYearly *bool           `json:"occasion_recurs_yearly"`
+}
+
+// DuplicateListRequest names the copy, without a body it keeps the title and is private until shared
+type DuplicateListRequest struct {
+	Title      *string         `json:"title"`
+	Visibility *ListVisibility `json:"visibility" binding:"omitempty,oneof=private link_only followers public"`
 }
 
 type UpdateListRequest struct {
-	Image    *string `json:"image"`
-	Title    *string `json:"title"`
-	Notes    *string `json:"notes"`
-	IsPublic *bool   `json:"is_public"`
+	Image      *string         `json:"image"`
+	Title      *string         `json:"title"`
+	Notes      *string         `json:"notes"`
+	Visibility *ListVisibility `json:"visibility" binding:"omitempty,oneof=private link_only followers public"`
+	IsPublic   *bool           `json:"is_public"` // Deprecated: use Visibility, true maps to "public" and false to "link_only"
+
+	OccasionDate         *string `json:"occasion_date" binding:"omitempty,datetime=2006-01-02" example:"2026-12-24"` // Empty string removes the occasion
+	OccasionRecursYearly *bool   `json:"occasion_recurs_yearly"`
 }
 
 type ListResponse struct {
@@ -67,9 +123,17 @@ type ListResponse struct {
 	Title       string         `json:"title"`
 	Notes       *string        `json:"notes,omitempty"`
 	IsPublic    bool           `json:"is_public"`
+	Visibility  ListVisibility `json:"visibility"`
 	Slug        string         `json:"slug"`
 	WishesCount int            `json:"wishes_count"`
+	Total       *PriceTotal    `json:"total,omitempty"`
+	Role        ListRole       `json:"role,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	Wishes      []WishResponse `json:"wishes,omitempty"`
+
+	OccasionDate         *string `json:"occasion_date,omitempty"`
+	OccasionRecursYearly bool    `json:"occasion_recurs_yearly"`
+	NextOccasion         *string `json:"next_occasion,omitempty"`       // Absent once a one-off occasion has passed
+	DaysUntilOccasion    *int    `json:"days_until_occasion,omitempty"` // 0 on the day itself
 }
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListRole string
+
+const (
+	ListRoleOwner  ListRole = "owner"
+	ListRoleEditor ListRole = "editor"
+	ListRoleViewer ListRole = "viewer"
+)
+
+func (r ListRole) CanEdit() bool {
+	return r == ListRoleOwner || r == ListRoleEditor
+}
+
+func (r ListRole) CanManage() bool {
+	return r == ListRoleOwner
+}
+
+type ListMember struct {
+	ListID     uuid.UUID
+	UserID     uuid.UUID
+	Role       ListRole
+	InvitedBy  uuid.UUID
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (m ListMember) IsAccepted() bool {
+	return m.AcceptedAt != nil
+}
+
+func (m ListMember) ToResponse() ListMemberResponse {
+	status := "invited"
+	if m.IsAccepted() {
+		status = "accepted"
+	}
+
+	return ListMemberResponse{
+		ListID:     m.ListID,
+		UserID:     m.UserID,
+		Role:       m.Role,
+		Status:     status,
+		InvitedBy:  m.InvitedBy,
+		AcceptedAt: m.AcceptedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+type InviteListMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required" example:"019cd349-d176-7562-b03b-1db2223b9a01"`
+	Role   ListRole  `json:"role" binding:"required,oneof=owner editor viewer" example:"editor"`
+}
+
+type ListMemberResponse struct {
+	ListID     uuid.UUID  `json:"list_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Role       ListRole   `json:"role" example:"editor"`
+	Status     string     `json:"status" example:"accepted"`
+	InvitedBy  uuid.UUID  `json:"invited_by"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
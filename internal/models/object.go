@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ObjectOwner is what an uploaded object belongs to. UserID is the account that owns it: the user for an avatar,
+// the list owner for a wish image. Objects uploaded for a wish that does not exist yet have no owner
+type ObjectOwner struct {
+	UserID *uuid.UUID
+	ListID *uuid.UUID
+	WishID *uuid.UUID
+}
+
+// StoredObject records who owns an object in S3, ObjectName is the base name shared by all variants of an image
+type StoredObject struct {
+	ObjectName string
+	URL        string // Value saved in users.avatar or wishes.image, an object key or a URL from before keys were stored
+	Owner      ObjectOwner
+	CreatedAt  time.Time
+}
+
+// BucketObject is one key in the bucket, as listed by the sweep or checked when a direct upload is confirmed
+type BucketObject struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// ObjectSweepReport sums up one reconciliation sweep of the bucket
+type ObjectSweepReport struct {
+	DryRun        bool
+	Scanned       int      // Keys in the bucket
+	Referenced    int      // Keys still used by an avatar or a wish image
+	Recent        int      // Unused keys left alone because they are younger than the grace period
+	Orphaned      []string // Unused keys, removed unless DryRun is set
+	OrphanedBytes int64
+	Failed        []string // Orphaned keys that could not be removed
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OccasionDateLayout is how occasion dates are written in requests and responses
+const OccasionDateLayout = "2006-01-02"
+
+// NextOccasion returns the first occurrence of the date on the day of now or later, nil once a one-off date has passed.
+// A yearly occasion on February 29 falls on February 28 in other years
+func NextOccasion(date time.Time, recursYearly bool, now time.Time) *time.Time {
+	today := StartOfDay(now)
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if !date.Before(today) {
+		return &date
+	}
+	if !recursYearly {
+		return nil
+	}
+
+	for year := today.Year(); ; year++ {
+		next := anniversary(date, year)
+		if !next.Before(today) {
+			return &next
+		}
+	}
+}
+
+// DaysUntil counts the calendar days from the day of now to the date
+func DaysUntil(date, now time.Time) int {
+	return int(date.Sub(StartOfDay(now)).Hours() / 24)
+}
+
+// StartOfDay returns midnight of the UTC day of t, occasion dates have no time zone
+func StartOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func anniversary(date time.Time, year int) time.Time {
+	day := date.Day()
+	if date.Month() == time.February && day == 29 && time.Date(year, time.February, 29, 0, 0, 0, 0, time.UTC).Month() != time.February {
+		day = 28
+	}
+	return time.Date(year, date.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Occasion is the date set on a list as seen by the reminder job
+type Occasion struct {
+	ListID       uuid.UUID
+	ListTitle    string
+	Date         time.Time
+	RecursYearly bool
+	RemindedFor  *time.Time // Occurrence the last reminder was sent for
+}
+
+// Next returns the coming occurrence, nil once a one-off occasion has passed
+func (o Occasion) Next(now time.Time) *time.Time {
+	return NextOccasion(o.Date, o.RecursYearly, now)
+}
+
+// OccasionRecipient is the owner, a member or a reserver of a list whose occasion is coming up
+type OccasionRecipient struct {
+	UserID     uuid.UUID
+	Email      string
+	IsReserver bool
+}
+
+// OccasionReminder describes the occurrence a reminder is sent for
+type OccasionReminder struct {
+	ListID    uuid.UUID
+	ListTitle string
+	Date      time.Time
+}
@@ -0,0 +1,32 @@
+package models
+
+type LinkPreview struct {
+	Link     string
+	Title    *string
+	ImageURL *string // Where the shop hosts the image
+	Image    *string // Object key of our copy of it, set once uploaded
+	Price    *int64
+	Currency *string
+}
+
+func (p LinkPreview) ToResponse() LinkPreviewResponse {
+	return LinkPreviewResponse{
+		Link:     p.Link,
+		Title:    p.Title,
+		Image:    mediaURL(p.Image),
+		Price:    p.Price,
+		Currency: p.Currency,
+	}
+}
+
+type PreviewLinkRequest struct {
+	Link string `json:"link" binding:"required,url"`
+}
+
+type LinkPreviewResponse struct {
+	Link     string  `json:"link"`
+	Title    *string `json:"title,omitempty"`
+	Image    *string `json:"image,omitempty"`
+	Price    *int64  `json:"price,omitempty"`
+	Currency *string `json:"currency,omitempty"`
+}
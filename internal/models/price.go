@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WishPrice is one price seen on the wish link by the price tracking job
+type WishPrice struct {
+	ID         uuid.UUID
+	WishID     uuid.UUID
+	Price      int64
+	Currency   *string
+	RecordedAt time.Time
+}
+
+func (p WishPrice) ToResponse() WishPriceResponse {
+	return WishPriceResponse{
+		Price:      p.Price,
+		Currency:   p.Currency,
+		RecordedAt: p.RecordedAt,
+	}
+}
+
+// PriceDropRecipient is the owner or a reserver of a wish that got cheaper
+type PriceDropRecipient struct {
+	UserID     uuid.UUID
+	Email      string
+	IsReserver bool
+}
+
+// PriceDrop describes a wish that got cheaper
+type PriceDrop struct {
+	WishID    uuid.UUID
+	ListID    uuid.UUID
+	WishTitle string
+	OldPrice  int64
+	NewPrice  int64
+	Currency  *string
+}
+
+type WishPriceResponse struct {
+	Price      int64     `json:"price"`
+	Currency   *string   `json:"currency,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WishReservation struct {
+	WishID        uuid.UUID
+	UserID        uuid.UUID
+	Guest         bool // Held by someone without an account through a shared link, UserID is uuid.Nil then
+	Units         int
+	ReservedUntil *time.Time // Released automatically once passed, nil keeps it forever
+	RemindedAt    *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// ExpiringReservation is a reservation whose deadline is close, with everything needed to remind the reserver
+type ExpiringReservation struct {
+	WishReservation
+	Email     string
+	WishTitle string
+	ListID    uuid.UUID
+}
+
+type ReserveWishRequest struct {
+	Units         int        `json:"units" binding:"omitempty,gt=0"` // One unit if omitted
+	ReservedUntil *time.Time `json:"reserved_until"`
+}
+
+type ReleaseWishRequest struct {
+	Units int `json:"units" binding:"omitempty,gt=0"` // All held units if omitted
+}
+
+// GuestReservation is held by someone who opened a shared link without an account, they manage it through the emailed token
+type GuestReservation struct {
+	WishID        uuid.UUID
+	Name          string
+	Email         string
+	Token         string
+	Units         int
+	ReservedUntil *time.Time // Set until the guest confirms, the reservation is released if they never do
+	ConfirmedAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// GuestReservationLimits keep a shared link from being used to flood mailboxes or to hold a whole list for nothing,
+// zero turns a limit off
+type GuestReservationLimits struct {
+	PerIPHourly int // Reservations one client address may start in an hour
+	PerList     int // Unconfirmed reservations a list may have at a time
+	PerEmail    int // Unconfirmed reservations one email address may have on a list
+}
+
+// GuestReservationDetails is a guest reservation with what its management page shows
+type GuestReservationDetails struct {
+	GuestReservation
+	WishTitle  string
+	WishStatus WishStatus // As stored, a reserved wish reads as open
+	ListTitle  string
+	ListSlug   string
+}
+
+// GuestReservationNotice is what the guest gets by email to confirm or release their reservation later
+type GuestReservationNotice struct {
+	Name          string
+	WishTitle     string
+	ListTitle     string
+	Token         string
+	ConfirmBefore time.Time
+}
+
+type GuestReserveWishRequest struct {
+	Name  string `json:"name" binding:"required,max=64" example:"Aunt Mary"`
+	Email string `json:"email" binding:"required,email" example:"mary@example.com"`
+	Units int    `json:"units" binding:"omitempty,gt=0"` // One unit if omitted
+}
+
+type GuestReservationResponse struct {
+	WishID        uuid.UUID  `json:"wish_id"`
+	WishTitle     string     `json:"wish_title" example:"Bike"`
+	ListTitle     string     `json:"list_title" example:"Birthday"`
+	ListSlug      string     `json:"list_slug"`
+	Name          string     `json:"name" example:"Aunt Mary"`
+	Units         int        `json:"units" example:"1"`
+	Confirmed     bool       `json:"confirmed"`
+	ReservedUntil *time.Time `json:"reserved_until,omitempty"` // Confirm before then or the wish is freed
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (r GuestReservationDetails) ToResponse() GuestReservationResponse {
+	return GuestReservationResponse{
+		WishID:        r.WishID,
+		WishTitle:     r.WishTitle,
+		ListTitle:     r.ListTitle,
+		ListSlug:      r.ListSlug,
+		Name:          r.Name,
+		Units:         r.Units,
+		Confirmed:     r.ConfirmedAt != nil,
+		ReservedUntil: r.ReservedUntil,
+		CreatedAt:     r.CreatedAt,
+	}
+}
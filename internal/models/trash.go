@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashItemType string
+
+const (
+	TrashItemList TrashItemType = "list"
+	TrashItemWish TrashItemType = "wish"
+)
+
+// TrashItem is a deleted list or wish the user may restore. Wishes of a deleted list are not listed,
+// they come back with the list
+type TrashItem struct {
+	Type      TrashItemType
+	ID        uuid.UUID
+	ListID    *uuid.UUID // List of a wish
+	ListTitle *string
+	Image     *string
+	Title     string
+	Role      ListRole // Role of the user in the list the item belongs to
+	DeletedAt time.Time
+	PurgeAt   time.Time // When the purge job removes it for good
+}
+
+func (i TrashItem) ToResponse() TrashItemResponse {
+	return TrashItemResponse{
+		Type:      i.Type,
+		ID:        i.ID,
+		ListID:    i.ListID,
+		ListTitle: i.ListTitle,
+		Image:     mediaURL(i.Image),
+		Title:     i.Title,
+		DeletedAt: i.DeletedAt,
+		PurgeAt:   i.PurgeAt,
+	}
+}
+
+type TrashItemResponse struct {
+	Type      TrashItemType `json:"type" example:"list"`
+	ID        uuid.UUID     `json:"id"`
+	ListID    *uuid.UUID    `json:"list_id,omitempty"`
+	ListTitle *string       `json:"list_title,omitempty"`
+	Image     *string       `json:"image,omitempty"`
+	Title     string        `json:"title"`
+	DeletedAt time.Time     `json:"deleted_at"`
+	PurgeAt   time.Time     `json:"purge_at"`
+}
+
+// TrashPurgeReport sums up one run of the purge job
+type TrashPurgeReport struct {
+	Lists  []uuid.UUID
+	Wishes []uuid.UUID // Wishes deleted on their own, those of purged lists go with them
+}
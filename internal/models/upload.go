@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// UploadURLRequest describes the file the client is about to upload straight to S3
+type UploadURLRequest struct {
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png image/webp image/gif"`
+	Size        int64  `json:"size" binding:"required,gt=0"`
+}
+
+// UploadURLResponse tells the client where to PUT the file, the headers must be sent exactly as they are
+type UploadURLResponse struct {
+	URL       string            `json:"upload_url"`
+	ObjectKey string            `json:"object_key"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type ConfirmUploadRequest struct {
+	ObjectKey string `json:"object_key" binding:"required"`
+}
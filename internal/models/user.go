@@ -8,24 +8,33 @@ import (
 
 type User struct {
 	ID            uuid.UUID
-	Avatar        *string
+	Avatar        *string // Object key, resolved to a URL in responses
 	Name          string
 	Username      string
 	Email         *string
 	EmailVerified bool
 	Password      string
+	Currency      *string    // Preferred ISO 4217 code prices are converted to
+	DeleteAfter   *time.Time // Set while the account waits to be deleted, it is deactivated until then
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
 
+// IsDeactivated reports whether the account is scheduled for deletion, it looks deleted to everyone else
+func (u User) IsDeactivated() bool {
+	return u.DeleteAfter != nil
+}
+
 func (u User) ToPrivateResponse() UserResponse {
 	return UserResponse{
 		ID:            u.ID,
-		Avatar:        u.Avatar,
+		Avatar:        mediaURL(u.Avatar),
+		Avatars:       newImageVariants(u.Avatar),
 		Name:          &u.Name,
 		Username:      u.Username,
 		Email:         u.Email,
 		EmailVerified: u.EmailVerified,
+		Currency:      u.Currency,
 		CreatedAt:     u.CreatedAt,
 		UpdatedAt:     u.UpdatedAt,
 	}
@@ -34,7 +43,8 @@ func (u User) ToPrivateResponse() UserResponse {
 func (u User) ToPublicResponse() UserResponse {
 	return UserResponse{
 		ID:       u.ID,
-		Avatar:   u.Avatar,
+		Avatar:   mediaURL(u.Avatar),
+		Avatars:  newImageVariants(u.Avatar),
 		Name:     &u.Name,
 		Username: u.Username,
 	}
@@ -52,8 +62,13 @@ type VerifyEmailRequest struct {
 }
 
 type LogInUserRequest struct {
-	Username string `json:"username" binding:"required" example:"user421"`
-	Password string `json:"password" binding:"required" example:"P4s5w0rd"`
+	Username   string `json:"username" binding:"required" example:"user421"`
+	Password   string `json:"password" binding:"required" example:"P4s5w0rd"`
+	Reactivate bool   `json:"reactivate" example:"false"` // Cancel the scheduled deletion of the account and log in
+}
+
+type CancelDeletionRequest struct {
+	Token string `json:"token" binding:"required" example:"9f2c6d0e8b1a4f7c3e5d2b9a6c8e1f4d7b0a3c6e9f2d5b8a1c4e7f0a3d6b9c2e"`
 }
 
 type RefreshTokenRequest struct {
@@ -65,7 +80,8 @@ type UpdateUserRequest struct {
 	Avatar   *string `json:"-"` // Remove?
 	Username *string `json:"username" example:"alice421"`
 	Email    *string `json:"email" binding:"omitempty,email" example:"alice412+wishlist@email.com"`
-	Password *string `json:"-"` // Remove?
+	Password *string `json:"-"`                                // Remove?
+	Currency *string `json:"preferred_currency" example:"EUR"` // Empty string goes back to the default currency
 }
 
 type ChangePasswordRequest struct {
@@ -86,13 +102,21 @@ type DeleteAccountRequest struct {
 	CurrentPassword string `json:"password" binding:"required" example:"P4s5w0rd"`
 }
 
+// AccountDeletionResponse tells when a deactivated account is going to be deleted for good
+type AccountDeletionResponse struct {
+	Message     string    `json:"message" example:"account is scheduled for deletion"`
+	DeleteAfter time.Time `json:"delete_after" example:"2026-04-07T18:00:00.000000+03:00"`
+}
+
 type UserResponse struct {
-	ID            uuid.UUID `json:"id" example:"019cd349-d176-7562-b03b-1db2223b9a01"`
-	Avatar        *string   `json:"avatar" example:"null"`
-	Name          *string   `json:"name" example:"Alice"`
-	Username      string    `json:"username" example:"alice421"`
-	Email         *string   `json:"email" example:"alice412@email.com"`
-	EmailVerified bool      `json:"email_verified" example:"true"`
-	CreatedAt     time.Time `json:"created_at" example:"2026-03-08T18:00:00.000000+03:00"`
-	UpdatedAt     time.Time `json:"updated_at" example:"2026-03-08T18:03:00.000000+03:00"`
+	ID            uuid.UUID     `json:"id" example:"019cd349-d176-7562-b03b-1db2223b9a01"`
+	Avatar        *string       `json:"avatar" example:"null"` // Deprecated: use Avatars, this is the original
+	Avatars       ImageVariants `json:"avatar_variants,omitempty"`
+	Name          *string       `json:"name" example:"Alice"`
+	Username      string        `json:"username" example:"alice421"`
+	Email         *string       `json:"email" example:"alice412@email.com"`
+	EmailVerified bool          `json:"email_verified" example:"true"`
+	Currency      *string       `json:"preferred_currency,omitempty" example:"EUR"`
+	CreatedAt     time.Time     `json:"created_at" example:"2026-03-08T18:00:00.000000+03:00"`
+	UpdatedAt     time.Time     `json:"updated_at" example:"2026-03-08T18:03:00.000000+03:00"`
 }
@@ -1,93 +1,276 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type WishStatus string
+
+const (
+	WishStatusOpen      WishStatus = "open"      // Nobody has reserved it yet
+	WishStatusReserved  WishStatus = "reserved"  // Someone holds a reservation, derived from reservations and never stored
+	WishStatusPurchased WishStatus = "purchased" // The reserver bought it
+	WishStatusReceived  WishStatus = "received"  // The owner got it
+	WishStatusArchived  WishStatus = "archived"  // Kept for history, not counted in the list
+)
+
+var wishStatusTransitions = map[WishStatus][]WishStatus{
+	WishStatusOpen:      {WishStatusArchived},
+	WishStatusReserved:  {WishStatusPurchased, WishStatusReceived, WishStatusArchived},
+	WishStatusPurchased: {WishStatusReceived, WishStatusArchived},
+	WishStatusReceived:  {WishStatusArchived},
+}
+
+func (s WishStatus) IsValid() bool {
+	switch s {
+	case WishStatusOpen, WishStatusReserved, WishStatusPurchased, WishStatusReceived, WishStatusArchived:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo reports whether the wish may move from s to next through the status endpoints
+func (s WishStatus) CanTransitionTo(next WishStatus) bool {
+	return slices.Contains(wishStatusTransitions[s], next)
+}
+
+// AcceptsReservations reports whether the wish can still be reserved, released or chipped in for
+func (s WishStatus) AcceptsReservations() bool {
+	return s == WishStatusOpen || s == WishStatusReserved
+}
+
+// Stored maps the status to the value kept in the database
+func (s WishStatus) Stored() WishStatus {
+	if s == WishStatusReserved {
+		return WishStatusOpen
+	}
+	return s
+}
+
+type WishPriority string
+
+const (
+	WishPriorityMustHave   WishPriority = "must_have"
+	WishPriorityNiceToHave WishPriority = "nice_to_have"
+)
+
+type WishSort string
+
+const (
+	WishSortPosition  WishSort = "position" // Order set by the owner, the default
+	WishSortPrice     WishSort = "price"
+	WishSortPriority  WishSort = "priority" // Must-haves first
+	WishSortCreatedAt WishSort = "created_at"
+)
+
+func (s WishSort) IsValid() bool {
+	switch s {
+	case WishSortPosition, WishSortPrice, WishSortPriority, WishSortCreatedAt:
+		return true
+	default:
+		return false
+	}
+}
+
+type WishFilter struct {
+	Status *WishStatus
+	Sort   WishSort // Empty sorts by position
+	Desc   bool
+}
+
+// Money is an amount in minor units of an ISO 4217 currency
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// PriceTotal sums prices converted to one currency, Partial tells that some of them could not be converted and are missing
+type PriceTotal struct {
+	Money
+	Partial bool `json:"partial,omitempty"`
+}
+
 type Wish struct {
-	ID         uuid.UUID
-	ListID     uuid.UUID
-	Image      *string
-	Title      string
-	Notes      *string
-	Link       *string
-	Price      *int64
-	Currency   *string
-	ReservedBy *uuid.UUID
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID           uuid.UUID
+	ListID       uuid.UUID
+	Image        *string // Object key, resolved to a URL in responses; links to other sites are kept as they are
+	Title        string
+	Notes        *string
+	Link         *string
+	Price        *int64  // In minor units of Currency, e.g. cents
+	CurrentPrice *int64  // Last price the price tracking job saw on the link, Price stays as the owner set it
+	Currency     *string // ISO 4217 code
+	Converted    *Money  // Price in the viewer's currency, filled by the service
+	Quantity     int
+	Status       WishStatus
+	Priority     WishPriority
+	Position     int    // Place in the list set by the owner, starting from 0
+	PriceAlert   *int64 // Notify when the tracked price goes below it, owners only
+	Reservations []WishReservation
+	Contributed  int64 // Sum of all chip-in contributions
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (w Wish) ReservedUnits() int {
+	var units int
+	for _, r := range w.Reservations {
+		units += r.Units
+	}
+	return units
+}
+
+func (w Wish) RemainingUnits() int {
+	return max(w.Quantity-w.ReservedUnits(), 0)
+}
+
+func (w Wish) IsFullyReserved() bool {
+	return len(w.Reservations) > 0 && w.RemainingUnits() == 0
+}
+
+// ReservationOf returns the reservation held by userID, nil for anonymous requests or when there is none.
+// Guest reservations never match, they only count towards the reserved units
+func (w Wish) ReservationOf(userID *uuid.UUID) *WishReservation {
+	if userID == nil {
+		return nil
+	}
+	for _, r := range w.Reservations {
+		if !r.Guest && r.UserID == *userID {
+			return &r
+		}
+	}
+	return nil
+}
+
+// FundedPercent reports chip-in progress towards the price, nil when the wish has no price
+func (w Wish) FundedPercent() *int {
+	if w.Price == nil || *w.Price <= 0 {
+		return nil
+	}
+	return new(int(min(w.Contributed*100 / *w.Price, 100)))
 }
 
 func (w Wish) ToOwnerResponse() WishResponse {
 	return WishResponse{
-		ID:         w.ID,
-		ListID:     w.ListID,
-		Image:      w.Image,
-		Title:      w.Title,
-		Notes:      w.Notes,
-		Link:       w.Link,
-		Price:      w.Price,
-		Currency:   w.Currency,
-		Reserved:   w.ReservedBy != nil,
-		ReservedBy: nil, // Surprise
-		CreatedAt:  w.CreatedAt,
-		UpdatedAt:  w.UpdatedAt,
+		ID:             w.ID,
+		ListID:         w.ListID,
+		Image:          mediaURL(w.Image),
+		Images:         newImageVariants(w.Image),
+		Title:          w.Title,
+		Notes:          w.Notes,
+		Link:           w.Link,
+		Price:          w.Price,
+		CurrentPrice:   w.CurrentPrice,
+		Currency:       w.Currency,
+		ConvertedPrice: w.Converted,
+		Quantity:       w.Quantity,
+		Status:         w.Status,
+		Priority:       w.Priority,
+		Position:       w.Position,
+		PriceAlert:     w.PriceAlert,
+		Reserved:       w.IsFullyReserved(),
+		ReservedBy:     nil, // Surprise
+		ReservedUnits:  w.ReservedUnits(),
+		RemainingUnits: w.RemainingUnits(),
+		FundedPercent:  w.FundedPercent(), // Progress only, never who chipped in
+		CreatedAt:      w.CreatedAt,
+		UpdatedAt:      w.UpdatedAt,
 	}
 }
 
 func (w Wish) ToViewerResponse(requestedByUserID *uuid.UUID) WishResponse {
 	var reservedBy *uuid.UUID
-	if w.ReservedBy != nil && requestedByUserID != nil && *w.ReservedBy == *requestedByUserID {
-		reservedBy = w.ReservedBy
+	var reservedUntil *time.Time
+	var reservedByMe int
+	if own := w.ReservationOf(requestedByUserID); own != nil {
+		reservedBy, reservedUntil, reservedByMe = &own.UserID, own.ReservedUntil, own.Units
 	}
 
 	return WishResponse{
-		ID:         w.ID,
-		ListID:     w.ListID,
-		Image:      w.Image,
-		Title:      w.Title,
-		Notes:      w.Notes,
-		Link:       w.Link,
-		Price:      w.Price,
-		Currency:   w.Currency,
-		Reserved:   w.ReservedBy != nil,
-		ReservedBy: reservedBy, // Only show if user who requested == user who reserved
-		CreatedAt:  w.CreatedAt,
-		UpdatedAt:  w.UpdatedAt,
+		ID:             w.ID,
+		ListID:         w.ListID,
+		Image:          mediaURL(w.Image),
+		Images:         newImageVariants(w.Image),
+		Title:          w.Title,
+		Notes:          w.Notes,
+		Link:           w.Link,
+		Price:          w.Price,
+		CurrentPrice:   w.CurrentPrice,
+		Currency:       w.Currency,
+		ConvertedPrice: w.Converted,
+		Quantity:       w.Quantity,
+		Status:         w.Status,
+		Priority:       w.Priority,
+		Position:       w.Position,
+		Reserved:       w.IsFullyReserved(),
+		ReservedBy:     reservedBy, // Only show if user who requested == user who reserved
+		ReservedUnits:  w.ReservedUnits(),
+		RemainingUnits: w.RemainingUnits(),
+		ReservedByMe:   reservedByMe,
+		ReservedUntil:  reservedUntil,
+		FundedPercent:  w.FundedPercent(),
+		CreatedAt:      w.CreatedAt,
+		UpdatedAt:      w.UpdatedAt,
 	}
 }
 
 type CreateWishRequest struct {
-	Image    *string `json:"image,omitempty"`
-	Title    string  `json:"title" binding:"required"`
-	Notes    *string `json:"notes"`
-	Link     *string `json:"link" binding:"omitempty,url"`
-	Price    *int64  `json:"price"`
-	Currency *string `json:"currency"`
+	Image      *string       `json:"image,omitempty"`
+	Title      string        `json:"title" binding:"required_without=Autofill"`
+	Notes      *string       `json:"notes"`
+	Link       *string       `json:"link" binding:"omitempty,url"`
+	Price      *int64        `json:"price"`
+	Currency   *string       `json:"currency"`
+	Quantity   *int          `json:"quantity" binding:"omitempty,gt=0"`
+	Priority   *WishPriority `json:"priority" binding:"omitempty,oneof=must_have nice_to_have"`
+	PriceAlert *int64        `json:"price_alert_below" binding:"omitempty,gt=0"`
+	Autofill   bool          `json:"autofill"` // Fill title, price and image from the link, fields that are sent are kept
 }
 
 type UpdateWishRequest struct {
-	Image    *string `json:"image,omitempty"`
-	Title    *string `json:"title"`
-	Notes    *string `json:"notes"`
-	Link     *string `json:"link" binding:"omitempty,url"`
-	Price    *int64  `json:"price"`
-	Currency *string `json:"currency"`
+	Image      *string       `json:"image,omitempty"`
+	Title      *string       `json:"title"`
+	Notes      *string       `json:"notes"`
+	Link       *string       `json:"link" binding:"omitempty,url"`
+	Price      *int64        `json:"price"`
+	Currency   *string       `json:"currency"`
+	Quantity   *int          `json:"quantity" binding:"omitempty,gt=0"`
+	Priority   *WishPriority `json:"priority" binding:"omitempty,oneof=must_have nice_to_have"`
+	PriceAlert *int64        `json:"price_alert_below" binding:"omitempty,gt=0"`
 }
 
 type WishResponse struct {
-	ID         uuid.UUID  `json:"id"`
-	ListID     uuid.UUID  `json:"list_id"`
-	Image      *string    `json:"image,omitempty"`
-	Title      string     `json:"title"`
-	Notes      *string    `json:"notes,omitempty"`
-	Link       *string    `json:"link,omitempty"`
-	Price      *int64     `json:"price,omitempty"`
-	Currency   *string    `json:"currency,omitempty"`
-	Reserved   bool       `json:"reserved"`
-	ReservedBy *uuid.UUID `json:"reserved_by,omitempty"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
+	ID             uuid.UUID     `json:"id"`
+	ListID         uuid.UUID     `json:"list_id"`
+	Image          *string       `json:"image,omitempty"` // Deprecated: use Images, this is the original
+	Images         ImageVariants `json:"image_variants,omitempty"`
+	Title          string        `json:"title"`
+	Notes          *string       `json:"notes,omitempty"`
+	Link           *string       `json:"link,omitempty"`
+	Price          *int64        `json:"price,omitempty"`
+	CurrentPrice   *int64        `json:"current_price,omitempty"`
+	Currency       *string       `json:"currency,omitempty"`
+	ConvertedPrice *Money        `json:"converted_price,omitempty"`
+	Quantity       int           `json:"quantity"`
+	Status         WishStatus    `json:"status"`
+	Priority       WishPriority  `json:"priority"`
+	Position       int           `json:"position"`
+	PriceAlert     *int64        `json:"price_alert_below,omitempty"`
+	Reserved       bool          `json:"reserved"` // All units are taken
+	ReservedBy     *uuid.UUID    `json:"reserved_by,omitempty"`
+	ReservedUnits  int           `json:"reserved_units"`
+	RemainingUnits int           `json:"remaining_units"`
+	ReservedByMe   int           `json:"reserved_by_me,omitempty"`
+	ReservedUntil  *time.Time    `json:"reserved_until,omitempty"`
+	FundedPercent  *int          `json:"funded_percent,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+type ReorderWishesRequest struct {
+	WishIDs []uuid.UUID `json:"wish_ids" binding:"required,min=1"` // Every wish of the list in the new order
 }
@@ -0,0 +1,370 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"wishlist/internal/currency"
+	"wishlist/internal/models"
+)
+
+const maxPageSize = 2 << 20 // Product pages are big, but metadata lives in <head>
+
+var ErrBlockedAddress = errors.New("address is not allowed")
+
+// Scraper fetches pages and reads product metadata from OpenGraph tags, JSON-LD and the <title> tag
+type Scraper struct {
+	client       *http.Client
+	maxImageSize int64
+}
+
+func NewScraper(timeout time.Duration, maxImageSize int64) *Scraper {
+	dialer := &net.Dialer{Timeout: timeout, Control: denyPrivateAddresses}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil // Proxy would dial instead of us and skip the address check
+
+	return &Scraper{client: &http.Client{Timeout: timeout, Transport: transport}, maxImageSize: maxImageSize}
+}
+
+// specialUseRanges are not covered by the net.IP helpers but are no more public than private ranges are,
+// carrier-grade NAT and NAT64 in particular often lead straight into the hosting network
+var specialUseRanges = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This network"
+	netip.MustParsePrefix("100.64.0.0/10"),   // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // Documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // Documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // Documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // Local-use NAT64
+	netip.MustParsePrefix("100::/64"),        // Discard-only
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4, may wrap any IPv4 address
+}
+
+// imageTypes are the content types FetchImage accepts, told by the bytes themselves. Vector formats like SVG can carry
+// scripts and are never stored
+var imageTypes = map[string]struct{}{
+	"image/png":  {},
+	"image/jpeg": {},
+	"image/gif":  {},
+	"image/webp": {},
+}
+
+// denyPrivateAddresses keeps user supplied links from reaching the internal network
+func denyPrivateAddresses(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	for _, prefix := range specialUseRanges {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+		}
+	}
+	return nil
+}
+
+func (s *Scraper) Scrape(ctx context.Context, link string) (models.LinkPreview, error) {
+	pageURL, err := url.Parse(link)
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return models.LinkPreview{}, fmt.Errorf("invalid link '%s'", link)
+	}
+
+	resp, err := s.get(ctx, pageURL.String(), "text/html,application/xhtml+xml")
+	if err != nil {
+		return models.LinkPreview{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "" && mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return models.LinkPreview{}, fmt.Errorf("link '%s' is not a web page (%s)", link, mediaType)
+	}
+
+	preview := parsePage(io.LimitReader(resp.Body, maxPageSize))
+	preview.Link = link
+	if preview.ImageURL != nil {
+		if imageURL, err := resp.Request.URL.Parse(*preview.ImageURL); err == nil { // Relative to the final URL after redirects
+			preview.ImageURL = new(imageURL.String())
+		}
+	}
+
+	return preview, nil
+}
+
+// FetchImage downloads an image found by Scrape, anything that is not a raster image or is too big is rejected.
+// The declared content type only turns pages away early, the returned one is sniffed from the bytes
+func (s *Scraper) FetchImage(ctx context.Context, imageURL string) ([]byte, string, error) {
+	resp, err := s.get(ctx, imageURL, "image/*")
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); !strings.HasPrefix(mediaType, "image/") {
+		return nil, "", fmt.Errorf("'%s' is not an image (%s)", imageURL, contentType)
+	}
+	if resp.ContentLength > s.maxImageSize {
+		return nil, "", fmt.Errorf("image '%s' is too large", imageURL)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.maxImageSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image '%s': %w", imageURL, err)
+	}
+	if int64(len(data)) > s.maxImageSize {
+		return nil, "", fmt.Errorf("image '%s' is too large", imageURL)
+	}
+
+	contentType = http.DetectContentType(data)
+	if _, ok := imageTypes[contentType]; !ok {
+		return nil, "", fmt.Errorf("'%s' is not a supported image (%s)", imageURL, contentType)
+	}
+
+	return data, contentType, nil
+}
+
+func (s *Scraper) get(ctx context.Context, target, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request for '%s': %w", target, err)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; WishlistBot/1.0)") // Some shops refuse requests without one
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch '%s': %w", target, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch '%s': status %d", target, resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+// parsePage prefers JSON-LD product data, then OpenGraph, then the <title> tag
+func parsePage(r io.Reader) models.LinkPreview {
+	var title, ogTitle, ogImage, ogPrice, ogCurrency string
+	var ldBlocks []string
+
+	z := html.NewTokenizer(r)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		name, hasAttr := z.TagName()
+		switch string(name) {
+		case "title":
+			if title == "" && z.Next() == html.TextToken {
+				title = string(z.Text())
+			}
+		case "meta":
+			var key, content string
+			for hasAttr {
+				var k, v []byte
+				k, v, hasAttr = z.TagAttr()
+				switch string(k) {
+				case "property", "name":
+					key = strings.ToLower(string(v))
+				case "content":
+					content = strings.TrimSpace(string(v))
+				}
+			}
+			switch key {
+			case "og:title":
+				ogTitle = content
+			case "og:image", "og:image:url", "og:image:secure_url":
+				if ogImage == "" {
+					ogImage = content
+				}
+			case "product:price:amount", "og:price:amount":
+				ogPrice = content
+			case "product:price:currency", "og:price:currency":
+				ogCurrency = content
+			}
+		case "script":
+			var isLD bool
+			for hasAttr {
+				var k, v []byte
+				k, v, hasAttr = z.TagAttr()
+				if string(k) == "type" && strings.EqualFold(strings.TrimSpace(string(v)), "application/ld+json") {
+					isLD = true
+				}
+			}
+			if isLD && z.Next() == html.TextToken {
+				ldBlocks = append(ldBlocks, string(z.Text()))
+			}
+		}
+	}
+
+	var preview models.LinkPreview
+	for _, block := range ldBlocks {
+		if product, ok := findProduct(block); ok {
+			preview = product
+			break
+		}
+	}
+
+	if preview.Title == nil {
+		preview.Title = firstNonEmpty(ogTitle, strings.TrimSpace(title))
+	}
+	if preview.ImageURL == nil {
+		preview.ImageURL = firstNonEmpty(ogImage)
+	}
+	if preview.Price == nil {
+		preview.Price, preview.Currency = parsePrice(ogPrice, ogCurrency)
+	}
+
+	return preview
+}
+
+type ldNode = map[string]any
+
+// findProduct looks for a schema.org Product in one JSON-LD block, which may be a single node, an array or a @graph
+func findProduct(block string) (models.LinkPreview, bool) {
+	var raw any
+	if err := json.NewDecoder(bytes.NewBufferString(block)).Decode(&raw); err != nil {
+		return models.LinkPreview{}, false
+	}
+
+	queue := []any{raw}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		switch v := current.(type) {
+		case []any:
+			queue = append(queue, v...)
+		case ldNode:
+			if graph, ok := v["@graph"]; ok {
+				queue = append(queue, graph)
+			}
+			if hasType(v, "Product") {
+				return productPreview(v), true
+			}
+		}
+	}
+
+	return models.LinkPreview{}, false
+}
+
+func productPreview(product ldNode) models.LinkPreview {
+	preview := models.LinkPreview{
+		Title:    firstNonEmpty(stringValue(product["name"])),
+		ImageURL: firstNonEmpty(imageValue(product["image"])),
+	}
+
+	offers := product["offers"]
+	if list, ok := offers.([]any); ok && len(list) > 0 {
+		offers = list[0]
+	}
+	if offer, ok := offers.(ldNode); ok {
+		price, code := stringValue(offer["price"]), stringValue(offer["priceCurrency"])
+		if price == "" {
+			price = stringValue(offer["lowPrice"]) // AggregateOffer
+		}
+		if spec, ok := offer["priceSpecification"].(ldNode); ok && price == "" {
+			price, code = stringValue(spec["price"]), stringValue(spec["priceCurrency"])
+		}
+		preview.Price, preview.Currency = parsePrice(price, code)
+	}
+
+	return preview
+}
+
+func hasType(node ldNode, want string) bool {
+	switch t := node["@type"].(type) {
+	case string:
+		return t == want || t == "https://schema.org/"+want || t == "http://schema.org/"+want
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func stringValue(v any) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+func imageValue(v any) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case []any:
+		if len(t) > 0 {
+			return imageValue(t[0])
+		}
+	case ldNode:
+		return stringValue(t["url"])
+	}
+	return ""
+}
+
+// parsePrice reads prices like "1299", "1 299,00" or "19.99" into minor units of the currency,
+// codes that are not ISO 4217 are dropped and the price gets the default scale
+func parsePrice(raw, code string) (*int64, *string) {
+	code = currency.Normalize(code)
+	value, ok := currency.ParseMajor(raw, code)
+	if !ok {
+		return nil, nil
+	}
+
+	if !currency.IsValid(code) {
+		return new(currency.FromMajor(value, "")), nil
+	}
+	return new(currency.FromMajor(value, code)), &code
+}
+
+func firstNonEmpty(values ...string) *string {
+	for _, v := range values {
+		if v != "" {
+			return &v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const productPage = `<!doctype html>
+<html><head>
+<title>Shop &amp; Co — Headphones</title>
+<meta property="og:title" content="OG Headphones">
+<meta property="og:image" content="/img/og.jpg">
+<script type="application/ld+json">{"@context":"https://schema.org","@type":"BreadcrumbList","itemListElement":[]}</script>
+<script type="application/ld+json">
+{"@context":"https://schema.org","@graph":[
+  {"@type":"Organization","name":"Shop"},
+  {"@type":["Product"],"name":"Studio Headphones","image":["/img/product.jpg"],
+   "offers":[{"@type":"Offer","price":"1 299,00","priceCurrency":"eur"}]}
+]}
+</script>
+</head><body><h1>Studio Headphones</h1></body></html>`
+
+const pngBytes = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+func newTestServer(t *testing.T, pages map[string]func(w http.ResponseWriter)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		page(w)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func htmlPage(body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestScraper_Scrape_PrefersJSONLD(t *testing.T) {
+	srv := newTestServer(t, map[string]func(w http.ResponseWriter){"/p/1": htmlPage(productPage)})
+	s := &Scraper{client: srv.Client(), maxImageSize: 1 << 20}
+
+	preview, err := s.Scrape(context.Background(), srv.URL+"/p/1")
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if preview.Title == nil || *preview.Title != "Studio Headphones" {
+		t.Fatalf("title = %v, want Studio Headphones", preview.Title)
+	}
+	if preview.Price == nil || *preview.Price != 129900 {
+		t.Fatalf("price = %v, want 129900", preview.Price)
+	}
+	if preview.Currency == nil || *preview.Currency != "EUR" {
+		t.Fatalf("currency = %v, want EUR", preview.Currency)
+	}
+	if preview.ImageURL == nil || *preview.ImageURL != srv.URL+"/img/product.jpg" {
+		t.Fatalf("image = %v, want absolute product image URL", preview.ImageURL)
+	}
+}
+
+func TestScraper_Scrape_FallsBack(t *testing.T) {
+	srv := newTestServer(t, map[string]func(w http.ResponseWriter){
+		"/og": htmlPage(`<html><head><title>Page</title>
+			<meta property="og:title" content="Kettle"><meta property="og:image" content="https://cdn.example.com/kettle.png">
+			<meta property="product:price:amount" content="19.99"><meta property="product:price:currency" content="usd">
+			<script type="application/ld+json">{ broken</script></head></html>`),
+		"/title": htmlPage(`<html><head><title>
+			Just a page &amp; nothing else </title></head></html>`),
+	})
+	s := &Scraper{client: srv.Client(), maxImageSize: 1 << 20}
+
+	preview, err := s.Scrape(context.Background(), srv.URL+"/og")
+	if err != nil {
+		t.Fatalf("Scrape(og) error = %v", err)
+	}
+	if *preview.Title != "Kettle" || *preview.Price != 1999 || *preview.Currency != "USD" || *preview.ImageURL != "https://cdn.example.com/kettle.png" {
+		t.Fatalf("Scrape(og) = %+v", preview)
+	}
+
+	preview, err = s.Scrape(context.Background(), srv.URL+"/title")
+	if err != nil {
+		t.Fatalf("Scrape(title) error = %v", err)
+	}
+	if preview.Title == nil || *preview.Title != "Just a page & nothing else" {
+		t.Fatalf("title = %v", preview.Title)
+	}
+	if preview.Price != nil || preview.ImageURL != nil {
+		t.Fatalf("Scrape(title) = %+v, want only a title", preview)
+	}
+}
+
+func TestParsePrice_MinorUnits(t *testing.T) {
+	tests := []struct {
+		raw, code    string
+		wantAmount   int64
+		wantCurrency string
+	}{
+		{"1 299,00", "eur", 129900, "EUR"},
+		{"1,500", "JPY", 1500, "JPY"},
+		{"12.5", "BHD", 12500, "BHD"},
+		{"10", "руб.", 1000, ""},
+	}
+
+	for _, tt := range tests {
+		amount, code := parsePrice(tt.raw, tt.code)
+		if amount == nil || *amount != tt.wantAmount {
+			t.Fatalf("parsePrice(%q, %q) amount = %v, want %d", tt.raw, tt.code, amount, tt.wantAmount)
+		}
+		gotCurrency := ""
+		if code != nil {
+			gotCurrency = *code
+		}
+		if gotCurrency != tt.wantCurrency {
+			t.Fatalf("parsePrice(%q, %q) currency = %q, want %q", tt.raw, tt.code, gotCurrency, tt.wantCurrency)
+		}
+	}
+}
+
+func TestScraper_Scrape_Errors(t *testing.T) {
+	srv := newTestServer(t, map[string]func(w http.ResponseWriter){
+		"/pdf": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF"))
+		},
+	})
+	s := &Scraper{client: srv.Client(), maxImageSize: 1 << 20}
+
+	for _, link := range []string{srv.URL + "/missing", srv.URL + "/pdf", "ftp://example.com/file", "not a link"} {
+		if _, err := s.Scrape(context.Background(), link); err == nil {
+			t.Fatalf("Scrape(%s) error = nil, want error", link)
+		}
+	}
+}
+
+func TestScraper_FetchImage(t *testing.T) {
+	srv := newTestServer(t, map[string]func(w http.ResponseWriter){
+		"/small.png": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte(pngBytes))
+		},
+		"/logo.svg": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "image/svg+xml")
+			_, _ = w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`))
+		},
+		"/disguised.png": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("<html><script></script></html>"))
+		},
+		"/big.png": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte(strings.Repeat("x", 256)))
+		},
+		"/page": htmlPage("<html></html>"),
+	})
+	s := &Scraper{client: srv.Client(), maxImageSize: 128}
+
+	data, contentType, err := s.FetchImage(context.Background(), srv.URL+"/small.png")
+	if err != nil || string(data) != pngBytes || contentType != "image/png" {
+		t.Fatalf("FetchImage() = %q, %q, %v", data, contentType, err)
+	}
+	if _, _, err = s.FetchImage(context.Background(), srv.URL+"/big.png"); err == nil {
+		t.Fatal("FetchImage(big) error = nil, want size error")
+	}
+	if _, _, err = s.FetchImage(context.Background(), srv.URL+"/page"); err == nil {
+		t.Fatal("FetchImage(page) error = nil, want content type error")
+	}
+	for _, path := range []string{"/logo.svg", "/disguised.png"} {
+		if _, _, err = s.FetchImage(context.Background(), srv.URL+path); err == nil {
+			t.Fatalf("FetchImage(%s) error = nil, want the sniffed type to be rejected", path)
+		}
+	}
+}
+
+func TestDenyPrivateAddresses(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "10.1.2.3:80", "100.64.0.1:443", "198.18.0.1:80", "[::ffff:100.100.1.1]:80", "[64:ff9b::a00:1]:80", "[fd00::1]:80", "localhost:80"} {
+		if err := denyPrivateAddresses("tcp", address, nil); !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("denyPrivateAddresses(%s) error = %v, want ErrBlockedAddress", address, err)
+		}
+	}
+	for _, address := range []string{"93.184.216.34:443", "[2606:2800:220:1::1]:443"} {
+		if err := denyPrivateAddresses("tcp", address, nil); err != nil {
+			t.Errorf("denyPrivateAddresses(%s) error = %v, want none", address, err)
+		}
+	}
+}
+
+func TestNewScraper_BlocksPrivateAddresses(t *testing.T) {
+	srv := newTestServer(t, map[string]func(w http.ResponseWriter){"/": htmlPage(productPage)})
+	s := NewScraper(time.Second, 1<<20)
+
+	_, err := s.Scrape(context.Background(), srv.URL+"/")
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("Scrape(loopback) error = %v, want ErrBlockedAddress", err)
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+)
+
+type AccountDeletionStorage interface {
+	DeleteScheduledUsers(ctx context.Context, before time.Time) ([]uuid.UUID, error)
+}
+
+// AccountDeletionJob deletes the accounts whose grace period is over, with their lists and images
+type AccountDeletionJob struct {
+	users    AccountDeletionStorage
+	objects  *ObjectTracker
+	log      ReportLogger
+	interval time.Duration
+}
+
+func NewAccountDeletionJob(as AccountDeletionStorage, ot *ObjectTracker, l ReportLogger, interval time.Duration) *AccountDeletionJob {
+	return &AccountDeletionJob{users: as, objects: ot, log: l, interval: interval}
+}
+
+// Run blocks until ctx is cancelled, errors are logged and retried on the next tick
+func (job *AccountDeletionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		job.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (job *AccountDeletionJob) RunOnce(ctx context.Context) {
+	userIDs, err := job.users.DeleteScheduledUsers(ctx, time.Now())
+	if err != nil {
+		job.log.Error("Account deletion job: %v", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		job.objects.Release(ctx, models.ObjectOwner{UserID: &userID}) // The avatar and the images of wishes in lists the user owned
+	}
+
+	if len(userIDs) > 0 {
+		job.log.Info("Account deletion job: deleted %d accounts", len(userIDs))
+	}
+}
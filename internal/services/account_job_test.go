@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type accountDeletionStorageMock struct {
+	before  time.Time
+	deleted []uuid.UUID
+	err     error
+}
+
+func (m *accountDeletionStorageMock) DeleteScheduledUsers(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	m.before = before
+	return m.deleted, m.err
+}
+
+func TestAccountDeletionJob_RunOnce(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	storage := &accountDeletionStorageMock{deleted: []uuid.UUID{first, second}}
+	records := &objectRecordStorageMock{}
+	log := &reportLoggerMock{}
+	job := NewAccountDeletionJob(storage, NewObjectTracker(records, &userAvatarStorageMock{}, log), log, time.Hour)
+
+	job.RunOnce(context.Background())
+
+	if time.Since(storage.before) > time.Minute {
+		t.Fatalf("deleted accounts scheduled before %s, want now", storage.before)
+	}
+	if len(records.owners) != 2 || *records.owners[0].UserID != first || *records.owners[1].UserID != second {
+		t.Fatalf("released %+v, want the objects of both accounts", records.owners)
+	}
+	if log.infos != 1 || log.calls != 0 {
+		t.Fatalf("logged %d reports and %d errors", log.infos, log.calls)
+	}
+}
+
+func TestAccountDeletionJob_RunOnce_StorageError(t *testing.T) {
+	storage := &accountDeletionStorageMock{err: errors.New("db error")}
+	records := &objectRecordStorageMock{}
+	log := &reportLoggerMock{}
+	job := NewAccountDeletionJob(storage, NewObjectTracker(records, &userAvatarStorageMock{}, log), log, time.Hour)
+
+	job.RunOnce(context.Background())
+
+	if log.calls != 1 || len(records.owners) != 0 {
+		t.Fatalf("logged %d errors and released %+v, want only the error", log.calls, records.owners)
+	}
+}
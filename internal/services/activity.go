@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+)
+
+type ActivityStorage interface {
+	RecordActivity(ctx context.Context, event models.ActivityEvent) error
+	GetFeed(ctx context.Context, viewerID uuid.UUID, cursor *models.FeedCursor, limit int) ([]models.ActivityEvent, error)
+}
+
+// ActivityRecorder writes list and wish changes for the activity feed.
+// A nil recorder does nothing, the feed just misses those changes
+type ActivityRecorder struct {
+	events ActivityStorage
+	log    Logger
+}
+
+func NewActivityRecorder(as ActivityStorage, l Logger) *ActivityRecorder {
+	return &ActivityRecorder{events: as, log: l}
+}
+
+// RecordList records a change of the list. Errors are only logged, the change itself already happened
+func (r *ActivityRecorder) RecordList(ctx context.Context, actorID uuid.UUID, kind models.ActivityKind, list models.List) {
+	r.record(ctx, models.ActivityEvent{ActorID: actorID, ListID: list.ID, Kind: kind, Title: list.Title})
+}
+
+// RecordWish records a change of the wish. Errors are only logged, the change itself already happened
+func (r *ActivityRecorder) RecordWish(ctx context.Context, actorID uuid.UUID, kind models.ActivityKind, wish models.Wish) {
+	r.record(ctx, models.ActivityEvent{ActorID: actorID, ListID: wish.ListID, WishID: &wish.ID, Kind: kind, Title: wish.Title})
+}
+
+func (r *ActivityRecorder) record(ctx context.Context, event models.ActivityEvent) {
+	if r == nil {
+		return
+	}
+
+	event.ID, event.CreatedAt = uuid.New(), time.Now()
+	if err := r.events.RecordActivity(ctx, event); err != nil {
+		r.log.Error("Activity recorder: %v", err)
+	}
+}
+
+type FeedServiceImpl struct {
+	events ActivityStorage
+}
+
+func NewFeedService(as ActivityStorage) *FeedServiceImpl {
+	return &FeedServiceImpl{events: as}
+}
+
+// GetFeed returns a page of what happened in the lists the user can read, cursor is the one of the previous page
+func (svc *FeedServiceImpl) GetFeed(ctx context.Context, userID uuid.UUID, cursor string, limit int) (models.Feed, error) {
+	if limit <= 0 {
+		limit = defaultFeedLimit
+	}
+	limit = min(limit, maxFeedLimit)
+
+	var after *models.FeedCursor
+	if cursor != "" {
+		parsed, err := models.ParseFeedCursor(cursor)
+		if err != nil {
+			return models.Feed{}, svcErr.ValidationError{Message: "invalid cursor"}
+		}
+		after = &parsed
+	}
+
+	events, err := svc.events.GetFeed(ctx, userID, after, limit+1) // One more tells whether there is a next page
+	if err != nil {
+		return models.Feed{}, err
+	}
+
+	feed := models.Feed{Events: events}
+	if len(events) > limit {
+		feed.Events = events[:limit]
+		last := feed.Events[limit-1]
+		feed.NextCursor = &models.FeedCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return feed, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type activityStorageMock struct {
+	recordErr error
+	feed      []models.ActivityEvent
+
+	recorded  []models.ActivityEvent
+	gotCursor *models.FeedCursor
+	gotLimit  int
+}
+
+func (m *activityStorageMock) RecordActivity(ctx context.Context, event models.ActivityEvent) error {
+	m.recorded = append(m.recorded, event)
+	return m.recordErr
+}
+
+func (m *activityStorageMock) GetFeed(ctx context.Context, viewerID uuid.UUID, cursor *models.FeedCursor, limit int) ([]models.ActivityEvent, error) {
+	m.gotCursor, m.gotLimit = cursor, limit
+	feed := m.feed
+	if cursor != nil {
+		for i, event := range feed {
+			if event.ID == cursor.ID {
+				feed = feed[i+1:]
+				break
+			}
+		}
+	}
+	return feed[:min(limit, len(feed))], nil
+}
+
+func TestActivityRecorder_Record(t *testing.T) {
+	var nilRecorder *ActivityRecorder
+	nilRecorder.RecordList(context.Background(), uuid.New(), models.ActivityListCreated, models.List{}) // Must not panic
+
+	events := &activityStorageMock{recordErr: errors.New("db down")}
+	log := &userLoggerMock{}
+	recorder := NewActivityRecorder(events, log)
+
+	wish := models.Wish{ID: uuid.New(), ListID: uuid.New(), Title: "Mug"}
+	recorder.RecordWish(context.Background(), uuid.New(), models.ActivityWishCreated, wish)
+
+	if len(events.recorded) != 1 {
+		t.Fatalf("RecordWish() recorded %d events, want 1", len(events.recorded))
+	}
+	got := events.recorded[0]
+	if got.ID == uuid.Nil || got.ListID != wish.ListID || got.WishID == nil || *got.WishID != wish.ID || got.Title != "Mug" {
+		t.Fatalf("RecordWish() recorded %+v", got)
+	}
+	if log.calls != 1 {
+		t.Fatalf("RecordWish() logged %d errors, want 1", log.calls)
+	}
+}
+
+func TestFeedService_GetFeed(t *testing.T) {
+	now := time.Now()
+	feed := make([]models.ActivityEvent, 3)
+	for i := range feed {
+		feed[i] = models.ActivityEvent{ID: uuid.New(), Kind: models.ActivityWishCreated, CreatedAt: now.Add(-time.Duration(i) * time.Minute)}
+	}
+	events := &activityStorageMock{feed: feed}
+	svc := NewFeedService(events)
+
+	page, err := svc.GetFeed(context.Background(), uuid.New(), "", 2)
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if len(page.Events) != 2 || page.NextCursor == nil || page.NextCursor.ID != feed[1].ID {
+		t.Fatalf("GetFeed() = %d events, cursor %+v; want 2 and a cursor at the second one", len(page.Events), page.NextCursor)
+	}
+
+	next, err := svc.GetFeed(context.Background(), uuid.New(), page.NextCursor.Encode(), 2)
+	if err != nil {
+		t.Fatalf("GetFeed() next page error = %v", err)
+	}
+	if events.gotCursor == nil || events.gotCursor.ID != feed[1].ID || !events.gotCursor.CreatedAt.Equal(feed[1].CreatedAt.Truncate(time.Microsecond)) {
+		t.Fatalf("GetFeed() passed cursor %+v, want the second event", events.gotCursor)
+	}
+	if next.NextCursor != nil {
+		t.Fatal("GetFeed() last page has a next cursor")
+	}
+
+	if _, err = svc.GetFeed(context.Background(), uuid.New(), "", 1000); err != nil || events.gotLimit != maxFeedLimit+1 {
+		t.Fatalf("GetFeed() error = %v limit = %d, want %d", err, events.gotLimit, maxFeedLimit+1)
+	}
+
+	var validation svcErr.ValidationError
+	if _, err = svc.GetFeed(context.Background(), uuid.New(), "not a cursor", 0); !errors.As(err, &validation) {
+		t.Fatalf("GetFeed() bad cursor error = %T, want ValidationError", err)
+	}
+}
+
+func TestWishService_RecordsActivity(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	ownerID := uuid.New()
+	callerID := uuid.New()
+
+	wishStorage := &wishSvcWishStorageMock{
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Title: "Mug", Status: models.WishStatusOpen},
+	}
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID, Visibility: models.ListVisibilityPublic}}
+	events := &activityStorageMock{}
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, NewActivityRecorder(events, &userLoggerMock{}))
+
+	if err := svc.ReserveWish(context.Background(), listID, wishID, callerID, models.ReserveWishRequest{}); err != nil {
+		t.Fatalf("ReserveWish() error = %v", err)
+	}
+	if err := svc.DeleteWish(context.Background(), listID, wishID, ownerID); err != nil {
+		t.Fatalf("DeleteWish() error = %v", err)
+	}
+	if err := svc.DeleteWish(context.Background(), listID, wishID, callerID); err == nil {
+		t.Fatal("DeleteWish() by a stranger error = nil, want forbidden")
+	}
+
+	want := []struct {
+		actor uuid.UUID
+		kind  models.ActivityKind
+	}{
+		{actor: callerID, kind: models.ActivityWishReserved},
+		{actor: ownerID, kind: models.ActivityWishDeleted},
+	}
+	if len(events.recorded) != len(want) {
+		t.Fatalf("recorded %d events, want %d", len(events.recorded), len(want))
+	}
+	for i, w := range want {
+		if got := events.recorded[i]; got.ActorID != w.actor || got.Kind != w.kind || got.ListID != listID || got.Title != "Mug" {
+			t.Fatalf("event %d = %+v, want %s by %s", i, got, w.kind, w.actor)
+		}
+	}
+}
+
+func TestActivityEvent_ToResponse_HidesReserver(t *testing.T) {
+	reserved := models.ActivityEvent{ID: uuid.New(), ActorID: uuid.New(), ActorUsername: "alice", Kind: models.ActivityWishReserved}
+	if got := reserved.ToResponse(); got.ActorID != nil || got.ActorUsername != nil {
+		t.Fatalf("ToResponse() of a reservation = %+v, want no actor", got)
+	}
+
+	created := models.ActivityEvent{ID: uuid.New(), ActorID: uuid.New(), ActorUsername: "alice", Kind: models.ActivityWishCreated}
+	if got := created.ToResponse(); got.ActorID == nil || *got.ActorUsername != "alice" {
+		t.Fatalf("ToResponse() of a new wish = %+v, want the actor", got)
+	}
+}
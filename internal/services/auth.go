@@ -18,9 +18,14 @@ type TokenStorage interface {
 	DeleteEmailVerificationToken(ctx context.Context, tokenID string) error
 	CheckIfAuthTokenRevoked(ctx context.Context, tokenID string) (bool, error)
 	RevokeAuthTokens(ctx context.Context, tokenID string, remainingTTL time.Duration) error
+	RevokeUserAuthTokens(ctx context.Context, userID string, issuedBefore time.Time) error
+	GetUserAuthTokensRevokedAt(ctx context.Context, userID string) (time.Time, error)
 	SavePasswordResetToken(ctx context.Context, tokenID string, userID string) error
 	GetPasswordResetToken(ctx context.Context, tokenID string) (string, error)
 	DeletePasswordResetToken(ctx context.Context, tokenID string) error
+	SaveAccountDeletionToken(ctx context.Context, tokenID, userID string, ttl time.Duration) error
+	GetAccountDeletionToken(ctx context.Context, tokenID string) (string, error)
+	DeleteAccountDeletionToken(ctx context.Context, userID string) error
 }
 
 type AuthServiceImpl struct {
@@ -84,13 +89,8 @@ func (svc *AuthServiceImpl) ValidateAccessToken(ctx context.Context, tokenString
 		return uuid.Nil, err
 	}
 
-	revoked, err := svc.tokenStorage.CheckIfAuthTokenRevoked(ctx, claims.ID)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to check token revocation: %w", err)
-	}
-
-	if revoked {
-		return uuid.Nil, fmt.Errorf("token has been revoked")
+	if err = svc.checkRevoked(ctx, claims); err != nil {
+		return uuid.Nil, err
 	}
 
 	return claims.UserID, nil
@@ -103,16 +103,35 @@ func (svc *AuthServiceImpl) ValidateRefreshToken(ctx context.Context, tokenStrin
 		return uuid.Nil, err
 	}
 
+	if err = svc.checkRevoked(ctx, claims); err != nil {
+		return uuid.Nil, err
+	}
+
+	return claims.UserID, nil
+}
+
+// checkRevoked fails for a token revoked on logout and for any token of the user issued before all of them were
+// revoked, as when the account is deactivated
+func (svc *AuthServiceImpl) checkRevoked(ctx context.Context, claims *Claims) error {
 	revoked, err := svc.tokenStorage.CheckIfAuthTokenRevoked(ctx, claims.ID)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to check token revocation: %w", err)
+		return fmt.Errorf("failed to check token revocation: %w", err)
 	}
 
 	if revoked {
-		return uuid.Nil, fmt.Errorf("token has been revoked")
+		return fmt.Errorf("token has been revoked")
 	}
 
-	return claims.UserID, nil
+	revokedAt, err := svc.tokenStorage.GetUserAuthTokensRevokedAt(ctx, claims.UserID.String())
+	if err != nil {
+		return fmt.Errorf("failed to check token revocation: %w", err)
+	}
+
+	if !revokedAt.IsZero() && (claims.IssuedAt == nil || !claims.IssuedAt.After(revokedAt)) {
+		return fmt.Errorf("token has been revoked")
+	}
+
+	return nil
 }
 
 func (svc *AuthServiceImpl) validateToken(tokenString, secretString string) (*Claims, error) {
@@ -20,6 +20,8 @@ type tokenStorageMock struct {
 	revokeCalls int
 	revokeIDs   []string
 	revokeTTLs  []time.Duration
+
+	userRevokedAt time.Time
 }
 
 func (m *tokenStorageMock) SaveEmailVerificationToken(ctx context.Context, tokenID, userID string) error {
@@ -45,6 +47,15 @@ func (m *tokenStorageMock) RevokeAuthTokens(ctx context.Context, tokenID string,
 	return m.revokeErr
 }
 
+func (m *tokenStorageMock) RevokeUserAuthTokens(ctx context.Context, userID string, issuedBefore time.Time) error {
+	m.userRevokedAt = issuedBefore
+	return nil
+}
+
+func (m *tokenStorageMock) GetUserAuthTokensRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	return m.userRevokedAt, nil
+}
+
 func (m *tokenStorageMock) SavePasswordResetToken(ctx context.Context, tokenID string, userID string) error {
 	return nil
 }
@@ -57,6 +68,18 @@ func (m *tokenStorageMock) DeletePasswordResetToken(ctx context.Context, tokenID
 	return nil
 }
 
+func (m *tokenStorageMock) SaveAccountDeletionToken(ctx context.Context, tokenID, userID string, ttl time.Duration) error {
+	return nil
+}
+
+func (m *tokenStorageMock) GetAccountDeletionToken(ctx context.Context, tokenID string) (string, error) {
+	return "", nil
+}
+
+func (m *tokenStorageMock) DeleteAccountDeletionToken(ctx context.Context, userID string) error {
+	return nil
+}
+
 func setAuthConfigForTests() {
 	viper.Reset()
 	viper.Set(config.AccessTokenSecret, "access-secret-for-tests")
@@ -116,6 +139,31 @@ func TestAuthService_ValidateAccessToken_Revoked(t *testing.T) {
 	}
 }
 
+func TestAuthService_ValidateTokens_UserRevoked(t *testing.T) {
+	setAuthConfigForTests()
+	storage := &tokenStorageMock{}
+	svc := NewAuthService(storage)
+
+	userID := uuid.New()
+	accessToken, refreshToken, err := svc.GenerateTokens(userID)
+	if err != nil {
+		t.Fatalf("GenerateTokens() error = %v", err)
+	}
+
+	storage.userRevokedAt = time.Now()
+	if _, err = svc.ValidateAccessToken(context.Background(), accessToken); err == nil {
+		t.Fatal("ValidateAccessToken() error = nil, want the token issued before the revocation rejected")
+	}
+	if _, err = svc.ValidateRefreshToken(context.Background(), refreshToken); err == nil {
+		t.Fatal("ValidateRefreshToken() error = nil, want the token issued before the revocation rejected")
+	}
+
+	storage.userRevokedAt = time.Now().Add(-time.Minute)
+	if _, err = svc.ValidateAccessToken(context.Background(), accessToken); err != nil {
+		t.Fatalf("ValidateAccessToken() of a token issued after the revocation error = %v", err)
+	}
+}
+
 func TestAuthService_RevokeAuthTokens_BothTokens(t *testing.T) {
 	setAuthConfigForTests()
 	storage := &tokenStorageMock{}
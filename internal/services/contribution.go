@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type WishContributionStorage interface {
+	SaveContribution(ctx context.Context, contribution models.WishContribution) error
+	DeleteContribution(ctx context.Context, wishID, userID uuid.UUID) error
+}
+
+type WishContributionServiceImpl struct {
+	contributions WishContributionStorage
+	wishes        WishStorage
+	wishlists     ListStorage
+	members       ListMemberStorage
+	follows       FollowStorage
+}
+
+func NewWishContributionService(cs WishContributionStorage, ws WishStorage, wl ListStorage, ms ListMemberStorage, fs FollowStorage) *WishContributionServiceImpl {
+	return &WishContributionServiceImpl{contributions: cs, wishes: ws, wishlists: wl, members: ms, follows: fs}
+}
+
+func (svc *WishContributionServiceImpl) Contribute(ctx context.Context, listID, wishID, userID uuid.UUID, req models.ContributeRequest) (models.WishContribution, error) {
+	wish, err := svc.wishes.GetWishByID(ctx, wishID)
+	if err != nil {
+		return models.WishContribution{}, err
+	}
+
+	if wish.ListID != listID {
+		return models.WishContribution{}, svcErr.ValidationError{Message: "wish does not belong to this list"}
+	}
+
+	if !wish.Status.AcceptsReservations() {
+		return models.WishContribution{}, svcErr.ValidationError{Message: "wish is no longer available"}
+	}
+
+	list, err := svc.wishlists.GetListByID(ctx, wish.ListID)
+	if err != nil {
+		return models.WishContribution{}, err
+	}
+
+	role, err := resolveReadableListRole(ctx, svc.members, svc.follows, list, userID)
+	if err != nil {
+		return models.WishContribution{}, err
+	}
+	if role.CanEdit() {
+		return models.WishContribution{}, svcErr.ValidationError{Message: "you cannot chip in for your own wish"}
+	}
+
+	if wish.Price == nil || *wish.Price <= 0 {
+		return models.WishContribution{}, svcErr.ValidationError{Message: "wish has no price to chip in for"}
+	}
+
+	contribution := models.WishContribution{
+		WishID:    wishID,
+		UserID:    userID,
+		Amount:    req.Amount,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err = svc.contributions.SaveContribution(ctx, contribution); err != nil {
+		return models.WishContribution{}, err
+	}
+
+	return contribution, nil
+}
+
+func (svc *WishContributionServiceImpl) WithdrawContribution(ctx context.Context, listID, wishID, userID uuid.UUID) error {
+	wish, err := svc.wishes.GetWishByID(ctx, wishID)
+	if err != nil {
+		return err
+	}
+
+	if wish.ListID != listID {
+		return svcErr.ValidationError{Message: "wish does not belong to this list"}
+	}
+
+	return svc.contributions.DeleteContribution(ctx, wishID, userID)
+}
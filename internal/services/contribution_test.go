@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type wishContributionStorageMock struct {
+	saveErr   error
+	deleteErr error
+
+	saved     models.WishContribution
+	deletedID uuid.UUID
+}
+
+func (m *wishContributionStorageMock) SaveContribution(ctx context.Context, contribution models.WishContribution) error {
+	m.saved = contribution
+	return m.saveErr
+}
+
+func (m *wishContributionStorageMock) DeleteContribution(ctx context.Context, wishID, userID uuid.UUID) error {
+	m.deletedID = wishID
+	return m.deleteErr
+}
+
+func TestWishContributionService_Contribute(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	friendID := uuid.New()
+	wishStorage := &wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID, Price: new(int64(50000)), Status: models.WishStatusOpen}}
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: uuid.New(), Visibility: models.ListVisibilityLinkOnly}}
+	cs := &wishContributionStorageMock{}
+	svc := NewWishContributionService(cs, wishStorage, listStorage, &listMemberStorageMock{}, nil)
+
+	contribution, err := svc.Contribute(context.Background(), listID, wishID, friendID, models.ContributeRequest{Amount: 10000})
+	if err != nil {
+		t.Fatalf("Contribute() error = %v", err)
+	}
+	if contribution.Amount != 10000 || cs.saved.UserID != friendID || cs.saved.WishID != wishID {
+		t.Fatalf("Contribute() stored unexpected contribution: %+v", cs.saved)
+	}
+}
+
+func TestWishContributionService_Contribute_Rejected(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	ownerID := uuid.New()
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID, Visibility: models.ListVisibilityPublic}}
+
+	tests := []struct {
+		name   string
+		wish   models.Wish
+		userID uuid.UUID
+	}{
+		{name: "owner", wish: models.Wish{ID: wishID, ListID: listID, Price: new(int64(100))}, userID: ownerID},
+		{name: "no price", wish: models.Wish{ID: wishID, ListID: listID}, userID: uuid.New()},
+		{name: "other list", wish: models.Wish{ID: wishID, ListID: uuid.New(), Price: new(int64(100))}, userID: uuid.New()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &wishContributionStorageMock{}
+			svc := NewWishContributionService(cs, &wishSvcWishStorageMock{wishToReturn: tt.wish}, listStorage, &listMemberStorageMock{}, nil)
+
+			var validation svcErr.ValidationError
+			if _, err := svc.Contribute(context.Background(), listID, wishID, tt.userID, models.ContributeRequest{Amount: 10}); !errors.As(err, &validation) {
+				t.Fatalf("Contribute() error = %T, want ValidationError", err)
+			}
+			if cs.saved.WishID != uuid.Nil {
+				t.Fatal("Contribute() stored contribution, want none")
+			}
+		})
+	}
+}
+
+func TestWishContributionService_Contribute_HiddenList(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	wish := models.Wish{ID: wishID, ListID: listID, Price: new(int64(100)), Status: models.WishStatusOpen}
+
+	for _, visibility := range []models.ListVisibility{models.ListVisibilityPrivate, models.ListVisibilityFollowers} {
+		t.Run(string(visibility), func(t *testing.T) {
+			cs := &wishContributionStorageMock{}
+			listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: uuid.New(), Visibility: visibility}}
+			svc := NewWishContributionService(cs, &wishSvcWishStorageMock{wishToReturn: wish}, listStorage, &listMemberStorageMock{}, &followStorageMock{})
+
+			if _, err := svc.Contribute(context.Background(), listID, wishID, uuid.New(), models.ContributeRequest{Amount: 10}); !errors.As(err, new(svcErr.ForbiddenError)) {
+				t.Fatalf("Contribute() error = %T, want ForbiddenError", err)
+			}
+			if cs.saved.WishID != uuid.Nil {
+				t.Fatal("Contribute() stored contribution on a list the user cannot see")
+			}
+		})
+	}
+}
+
+func TestWishContributionService_WithdrawContribution(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	cs := &wishContributionStorageMock{}
+	svc := NewWishContributionService(cs, &wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID}}, &wishSvcListStorageMock{}, &listMemberStorageMock{}, nil)
+
+	if err := svc.WithdrawContribution(context.Background(), listID, wishID, uuid.New()); err != nil {
+		t.Fatalf("WithdrawContribution() error = %v", err)
+	}
+	if cs.deletedID != wishID {
+		t.Fatalf("WithdrawContribution() deleted %s, want %s", cs.deletedID, wishID)
+	}
+}
+
+func TestWishService_ReserveWish_ChippedIn(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	wishStorage := &wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID, Price: new(int64(100)), Contributed: 40}}
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: uuid.New(), Visibility: models.ListVisibilityPublic}}
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+	var validation svcErr.ValidationError
+	if err := svc.ReserveWish(context.Background(), listID, wishID, uuid.New(), models.ReserveWishRequest{}); !errors.As(err, &validation) {
+		t.Fatalf("ReserveWish() error = %T, want ValidationError", err)
+	}
+	if wishStorage.reservedID != uuid.Nil {
+		t.Fatal("ReserveWish() reached storage for a chipped in wish")
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/currency"
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type RatesProvider interface {
+	Base() string // Currency for viewers who did not pick one
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+type UserCurrencyStorage interface {
+	GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error)
+}
+
+// PriceConverter shows prices in the currency the viewer prefers, a nil converter leaves them as they are
+type PriceConverter struct {
+	users UserCurrencyStorage
+	rates RatesProvider
+}
+
+func NewPriceConverter(us UserCurrencyStorage, rp RatesProvider) *PriceConverter {
+	return &PriceConverter{users: us, rates: rp}
+}
+
+// ViewerCurrency returns the preferred currency of the user, the base currency for anonymous viewers
+func (c *PriceConverter) ViewerCurrency(ctx context.Context, userID *uuid.UUID) (string, error) {
+	if userID == nil {
+		return c.rates.Base(), nil
+	}
+
+	user, err := c.users.GetUserByID(ctx, *userID)
+	if err != nil {
+		return "", err
+	}
+	if user.Currency == nil {
+		return c.rates.Base(), nil
+	}
+
+	return *user.Currency, nil
+}
+
+// ConvertWishes fills Converted of each priced wish and returns the total of the ones not archived. Wishes without
+// a currency or a known rate cannot be added up, the total is then marked partial instead of silently coming out lower
+func (c *PriceConverter) ConvertWishes(ctx context.Context, userID *uuid.UUID, wishes []models.Wish) (*models.PriceTotal, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	target, err := c.ViewerCurrency(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	total := models.PriceTotal{Money: models.Money{Currency: target}}
+	for i, wish := range wishes {
+		if wish.Price == nil {
+			continue
+		}
+		counted := wish.Status != models.WishStatusArchived
+		if wish.Currency == nil {
+			total.Partial = total.Partial || counted
+			continue
+		}
+		rate, err := c.rates.Rate(ctx, *wish.Currency, target)
+		if err != nil {
+			total.Partial = total.Partial || counted // Rates of rare currencies may be missing, the original price is still shown
+			continue
+		}
+
+		wishes[i].Converted = &models.Money{Amount: currency.Convert(*wish.Price, *wish.Currency, target, rate), Currency: target}
+		if counted {
+			total.Amount += wishes[i].Converted.Amount * int64(wish.Quantity)
+		}
+	}
+
+	return &total, nil
+}
+
+// sortByConvertedPrice orders wishes sorted by price in storage again by their converted price, raw prices
+// of different currencies cannot be compared. Wishes that could not be converted keep their order at the end
+func sortByConvertedPrice(wishes []models.Wish, filter models.WishFilter) {
+	if filter.Sort != models.WishSortPrice {
+		return
+	}
+
+	slices.SortStableFunc(wishes, func(a, b models.Wish) int {
+		switch {
+		case a.Converted == nil || b.Converted == nil:
+			return cmp.Compare(convertedRank(a), convertedRank(b))
+		case filter.Desc:
+			return cmp.Compare(b.Converted.Amount, a.Converted.Amount)
+		default:
+			return cmp.Compare(a.Converted.Amount, b.Converted.Amount)
+		}
+	})
+}
+
+func convertedRank(wish models.Wish) int {
+	if wish.Converted == nil {
+		return 1
+	}
+	return 0
+}
+
+// normalizeCurrency upper-cases the code and checks it against ISO 4217, an empty code stays empty
+func normalizeCurrency(code string) (string, error) {
+	code = currency.Normalize(code)
+	if code != "" && !currency.IsValid(code) {
+		return "", svcErr.ValidationError{Message: "currency must be an ISO 4217 code like EUR or USD"}
+	}
+	return code, nil
+}
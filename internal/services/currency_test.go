@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/currency"
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+func newTestPriceConverter(t *testing.T, user models.User) *PriceConverter {
+	t.Helper()
+	rates, err := currency.NewStaticRates("EUR", map[string]float64{"USD": 1.25, "JPY": 160})
+	if err != nil {
+		t.Fatalf("NewStaticRates() error = %v", err)
+	}
+	return NewPriceConverter(&userStorageServiceMock{userByID: user}, rates)
+}
+
+func TestPriceConverter_ConvertWishes(t *testing.T) {
+	userID := uuid.New()
+	pc := newTestPriceConverter(t, models.User{ID: userID, Currency: new("USD")})
+
+	wishes := []models.Wish{
+		{Price: new(int64(1000)), Currency: new("EUR"), Quantity: 2},
+		{Price: new(int64(500)), Currency: new("USD"), Quantity: 1, Status: models.WishStatusArchived},
+		{Price: new(int64(700)), Currency: new("CHF"), Quantity: 1},
+		{Price: new(int64(300)), Quantity: 1},
+	}
+
+	total, err := pc.ConvertWishes(context.Background(), &userID, wishes)
+	if err != nil {
+		t.Fatalf("ConvertWishes() error = %v", err)
+	}
+	if total == nil || total.Currency != "USD" || total.Amount != 2500 || !total.Partial {
+		t.Fatalf("ConvertWishes() total = %+v, want 2500 USD marked partial", total)
+	}
+	if wishes[0].Converted == nil || wishes[0].Converted.Amount != 1250 {
+		t.Fatalf("converted price = %+v, want 1250 USD", wishes[0].Converted)
+	}
+	if wishes[1].Converted == nil || wishes[1].Converted.Amount != 500 {
+		t.Fatalf("archived wish converted = %+v, want 500 USD", wishes[1].Converted)
+	}
+	if wishes[2].Converted != nil || wishes[3].Converted != nil {
+		t.Fatal("wishes without a rate or a currency must not be converted")
+	}
+}
+
+func TestPriceConverter_ConvertWishes_BaseForAnonymous(t *testing.T) {
+	pc := newTestPriceConverter(t, models.User{})
+
+	wishes := []models.Wish{{Price: new(int64(125)), Currency: new("USD"), Quantity: 1}, {Price: new(int64(320)), Currency: new("JPY"), Quantity: 1}}
+	total, err := pc.ConvertWishes(context.Background(), nil, wishes)
+	if err != nil {
+		t.Fatalf("ConvertWishes() error = %v", err)
+	}
+	if total.Currency != "EUR" || total.Amount != 300 || total.Partial {
+		t.Fatalf("ConvertWishes() total = %+v, want all of 300 EUR", total)
+	}
+}
+
+func TestPriceConverter_NilLeavesPrices(t *testing.T) {
+	var pc *PriceConverter
+	wishes := []models.Wish{{Price: new(int64(100)), Currency: new("EUR"), Quantity: 1}}
+
+	total, err := pc.ConvertWishes(context.Background(), nil, wishes)
+	if err != nil || total != nil || wishes[0].Converted != nil {
+		t.Fatalf("ConvertWishes() = %+v, %v, want nothing converted", total, err)
+	}
+}
+
+func TestNormalizeCurrency(t *testing.T) {
+	if code, err := normalizeCurrency(" usd "); err != nil || code != "USD" {
+		t.Fatalf("normalizeCurrency() = %q, %v, want USD", code, err)
+	}
+	if code, err := normalizeCurrency(""); err != nil || code != "" {
+		t.Fatalf("normalizeCurrency(\"\") = %q, %v", code, err)
+	}
+	if _, err := normalizeCurrency("₽"); !isCurrencyValidationError(err) {
+		t.Fatalf("normalizeCurrency(\"₽\") error = %v, want ValidationError", err)
+	}
+}
+
+func TestUserService_UpdateUserByID_InvalidCurrency(t *testing.T) {
+	st := &userStorageServiceMock{}
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
+
+	err := svc.UpdateUserByID(context.Background(), uuid.New(), models.UpdateUserRequest{Currency: new("euro")})
+	if !isCurrencyValidationError(err) {
+		t.Fatalf("UpdateUserByID() error = %v, want ValidationError", err)
+	}
+	if st.updatedUserReq.Currency != nil {
+		t.Fatal("invalid currency must not reach storage")
+	}
+}
+
+func isCurrencyValidationError(err error) bool {
+	_, ok := errors.AsType[svcErr.ValidationError](err)
+	return ok
+}
+
+func TestSortByConvertedPrice(t *testing.T) {
+	userID := uuid.New()
+	pc := newTestPriceConverter(t, models.User{ID: userID, Currency: new("EUR")})
+
+	// Sorted as stored: 1000 JPY is the biggest number but less than 9 EUR
+	wishes := []models.Wish{
+		{Title: "Sushi", Price: new(int64(1000)), Currency: new("JPY"), Quantity: 1},
+		{Title: "Book", Price: new(int64(900)), Currency: new("EUR"), Quantity: 1},
+		{Title: "Watch", Price: new(int64(800)), Currency: new("CHF"), Quantity: 1},
+		{Title: "Lamp", Price: new(int64(500)), Currency: new("USD"), Quantity: 1},
+	}
+	if _, err := pc.ConvertWishes(context.Background(), &userID, wishes); err != nil {
+		t.Fatalf("ConvertWishes() error = %v", err)
+	}
+
+	sortByConvertedPrice(wishes, models.WishFilter{Sort: models.WishSortPrice, Desc: true})
+
+	var got []string
+	for _, wish := range wishes {
+		got = append(got, wish.Title)
+	}
+	if want := []string{"Book", "Sushi", "Lamp", "Watch"}; !slices.Equal(got, want) {
+		t.Fatalf("order = %v, want %v with the unconvertible wish last", got, want)
+	}
+}
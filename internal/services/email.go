@@ -8,10 +8,12 @@ import (
 	"net/smtp"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 
 	"wishlist/internal/config"
+	"wishlist/internal/currency"
 )
 
 type EmailServiceImpl struct {
@@ -21,6 +23,7 @@ type EmailServiceImpl struct {
 	password string
 	from     string
 	domain   string
+	apiPath  string
 	sender   func(to, subject, body string) error
 }
 
@@ -32,6 +35,7 @@ func NewEmailService() *EmailServiceImpl {
 		password: viper.GetString(config.EmailPassword),
 		from:     viper.GetString(config.EmailFrom),
 		domain:   getDomainURL(viper.GetString(config.WebAppDomain)),
+		apiPath:  "/" + strings.Trim(viper.GetString(config.ApiBasePath), "/"),
 	}
 	es.sender = es.send
 	return es
@@ -79,6 +83,51 @@ func (svc *EmailServiceImpl) SendPasswordResetLetter(_ context.Context, to, toke
 	return svc.sendEmail(to, "Reset your password", body)
 }
 
+func (svc *EmailServiceImpl) SendReservationReminderLetter(_ context.Context, to, wishTitle, listID string, reservedUntil time.Time) error {
+	body := fmt.Sprintf("Your reservation of \"%s\" ends on %s.\n\n"+
+		"After that it will be released automatically so others can pick it up. The wishlist is here:\n\n"+
+		"%s",
+		wishTitle, reservedUntil.UTC().Format("January 2, 2006 15:04 MST"),
+		fmt.Sprintf("%s/wishlist/%s", svc.domain, listID))
+	return svc.sendEmail(to, "Your reservation is about to expire", body)
+}
+
+// SendPriceDropLetter tells the owner or a reserver that a wish got cheaper
+func (svc *EmailServiceImpl) SendPriceDropLetter(_ context.Context, to, wishTitle, listID string, oldPrice, newPrice int64, code string, toReserver bool) error {
+	intro := fmt.Sprintf("\"%s\" from your wishlist got cheaper", wishTitle)
+	if toReserver {
+		intro = fmt.Sprintf("\"%s\" you reserved got cheaper", wishTitle)
+	}
+
+	body := fmt.Sprintf("%s: %s instead of %s.\n\n"+
+		"See the wishlist here:\n\n"+
+		"%s",
+		intro, currency.Format(newPrice, code), currency.Format(oldPrice, code),
+		fmt.Sprintf("%s/wishlist/%s", svc.domain, listID))
+	return svc.sendEmail(to, "Price drop: "+wishTitle, body)
+}
+
+// SendDataExportLetter sends the link to an archive that was too big to be downloaded right away
+func (svc *EmailServiceImpl) SendDataExportLetter(_ context.Context, to, token string, expiresAt time.Time) error {
+	body := fmt.Sprintf("The export of your wishlist data is ready.\n\n"+
+		"Download it here:\n\n"+
+		"%s\n\n"+
+		"The link works until %s. If you didn't ask for an export, change your password.",
+		fmt.Sprintf("%s%s/exports/%s", svc.domain, svc.apiPath, token), expiresAt.UTC().Format("January 2, 2006 15:04 MST"))
+	return svc.sendEmail(to, "Your data export is ready", body)
+}
+
+// SendAccountDeletionLetter confirms that the account is going away and gives a link to keep it
+func (svc *EmailServiceImpl) SendAccountDeletionLetter(_ context.Context, to, token string, deleteAfter time.Time) error {
+	body := fmt.Sprintf("Your wishlist account is deactivated and will be deleted with all your lists on %s.\n\n"+
+		"Changed your mind? Keep the account by clicking the link below or just log in again before then:\n\n"+
+		"%s\n\n"+
+		"If you didn't delete your account, use the link and change your password.",
+		deleteAfter.UTC().Format("January 2, 2006 15:04 MST"),
+		fmt.Sprintf("%s/cancel-deletion?token=%s", svc.domain, token))
+	return svc.sendEmail(to, "Your account will be deleted", body)
+}
+
 func buildMessage(from, to, subject, body string) []byte {
 	var sb strings.Builder
 	sb.WriteString("From: " + from + "\r\n")
@@ -143,3 +192,42 @@ func (svc *EmailServiceImpl) sendTLS(addr string, auth smtp.Auth, to string, msg
 
 	return w.Close()
 }
+
+// SendOccasionReminderLetter reminds about the occasion of a list, reservers are reminded of the gift they picked
+func (svc *EmailServiceImpl) SendOccasionReminderLetter(_ context.Context, to, listTitle, listID string, date time.Time, toReserver bool) error {
+	intro := fmt.Sprintf("The occasion of \"%s\" is coming up on %s.", listTitle, date.Format("Monday, January 2"))
+	if toReserver {
+		intro = fmt.Sprintf("\"%s\" is coming up on %s and you reserved a gift from it. There is still time to get it ready.", listTitle, date.Format("Monday, January 2"))
+	}
+
+	body := fmt.Sprintf("%s\n\n"+
+		"See the wishlist here:\n\n"+
+		"%s",
+		intro, fmt.Sprintf("%s/wishlist/%s", svc.domain, listID))
+	return svc.sendEmail(to, "Coming up: "+listTitle, body)
+}
+
+// SendGroupAssignmentLetter tells a gift exchange participant who they drew, with a link to the receiver's list
+func (svc *EmailServiceImpl) SendGroupAssignmentLetter(_ context.Context, to, groupTitle, receiverName, listID string) error {
+	link := svc.domain
+	if listID != "" {
+		link = fmt.Sprintf("%s/wishlist/%s", svc.domain, listID)
+	}
+
+	body := fmt.Sprintf("The names for \"%s\" are drawn and you are giving a gift to %s.\n\n"+
+		"Keep it a secret! See what they wish for here:\n\n"+
+		"%s",
+		groupTitle, receiverName, link)
+	return svc.sendEmail(to, "Your gift exchange draw: "+groupTitle, body)
+}
+
+func (svc *EmailServiceImpl) SendGuestReservationLetter(_ context.Context, to, name, wishTitle, listTitle, token string, confirmBefore time.Time) error {
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"you reserved \"%s\" from the wishlist \"%s\". Confirm the reservation before %s, or the wish is freed for others:\n\n"+
+		"%s\n\n"+
+		"The same link lets you release the reservation if your plans change. Keep it to yourself, anyone with it can manage your reservation.",
+		name, wishTitle, listTitle,
+		confirmBefore.UTC().Format("January 2, 2006 15:04 MST"),
+		fmt.Sprintf("%s/guest-reservation?token=%s", svc.domain, token))
+	return svc.sendEmail(to, "Confirm your reservation: "+wishTitle, body)
+}
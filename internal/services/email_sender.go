@@ -1,6 +1,13 @@
 package services
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+)
 
 type SMTPEmailSender struct {
 	email EmailService
@@ -17,3 +24,39 @@ func (s *SMTPEmailSender) SendPasswordReset(ctx context.Context, _ string, to, t
 func (s *SMTPEmailSender) SendEmailVerification(ctx context.Context, _ string, to, token string) error {
 	return s.email.SendEmailVerificationLetter(ctx, to, token)
 }
+
+func (s *SMTPEmailSender) SendReservationReminder(ctx context.Context, reservation models.ExpiringReservation) error {
+	return s.email.SendReservationReminderLetter(ctx, reservation.Email, reservation.WishTitle, reservation.ListID.String(), *reservation.ReservedUntil)
+}
+
+func (s *SMTPEmailSender) SendPriceDrop(ctx context.Context, recipient models.PriceDropRecipient, drop models.PriceDrop) error {
+	var currency string
+	if drop.Currency != nil {
+		currency = *drop.Currency
+	}
+	return s.email.SendPriceDropLetter(ctx, recipient.Email, drop.WishTitle, drop.ListID.String(), drop.OldPrice, drop.NewPrice, currency, recipient.IsReserver)
+}
+
+func (s *SMTPEmailSender) SendDataExportReady(ctx context.Context, _ uuid.UUID, to, token string, expiresAt time.Time) error {
+	return s.email.SendDataExportLetter(ctx, to, token, expiresAt)
+}
+
+func (s *SMTPEmailSender) SendAccountDeletion(ctx context.Context, _ string, to, token string, deleteAfter time.Time) error {
+	return s.email.SendAccountDeletionLetter(ctx, to, token, deleteAfter)
+}
+
+func (s *SMTPEmailSender) SendOccasionReminder(ctx context.Context, recipient models.OccasionRecipient, reminder models.OccasionReminder) error {
+	return s.email.SendOccasionReminderLetter(ctx, recipient.Email, reminder.ListTitle, reminder.ListID.String(), reminder.Date, recipient.IsReserver)
+}
+
+func (s *SMTPEmailSender) SendGroupAssignment(ctx context.Context, _ string, to string, notice models.GroupAssignmentNotice) error {
+	var listID string
+	if notice.ListID != nil {
+		listID = notice.ListID.String()
+	}
+	return s.email.SendGroupAssignmentLetter(ctx, to, notice.GroupTitle, notice.ReceiverName, listID)
+}
+
+func (s *SMTPEmailSender) SendGuestReservation(ctx context.Context, to string, notice models.GuestReservationNotice) error {
+	return s.email.SendGuestReservationLetter(ctx, to, notice.Name, notice.WishTitle, notice.ListTitle, notice.Token, notice.ConfirmBefore)
+}
@@ -5,6 +5,7 @@ import (
 	"net/smtp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 
@@ -134,6 +135,137 @@ func TestEmailService_SendPasswordResetLetter_ComposesResetLink(t *testing.T) {
 	}
 }
 
+func TestEmailService_SendReservationReminderLetter_ComposesListLink(t *testing.T) {
+	svc := &EmailServiceImpl{domain: "https://wishlist.example.com"}
+
+	var gotTo, gotBody string
+	svc.sender = func(to, subject, body string) error {
+		gotTo = to
+		gotBody = body
+		return nil
+	}
+
+	reservedUntil := time.Date(2030, time.March, 8, 12, 0, 0, 0, time.UTC)
+	if err := svc.SendReservationReminderLetter(context.Background(), "bob@example.com", "Bike", "list-1", reservedUntil); err != nil {
+		t.Fatalf("SendReservationReminderLetter() error = %v", err)
+	}
+
+	if gotTo != "bob@example.com" {
+		t.Fatalf("to = %s, want bob@example.com", gotTo)
+	}
+	if !strings.Contains(gotBody, "https://wishlist.example.com/wishlist/list-1") {
+		t.Fatalf("reminder body does not contain list link: %s", gotBody)
+	}
+	if !strings.Contains(gotBody, `"Bike" ends on March 8, 2030`) {
+		t.Fatalf("reminder body missing wish and deadline: %s", gotBody)
+	}
+}
+
+func TestEmailService_SendPriceDropLetter_WordsByRecipient(t *testing.T) {
+	svc := &EmailServiceImpl{domain: "https://wishlist.example.com"}
+
+	var gotSubject, gotBody string
+	svc.sender = func(to, subject, body string) error {
+		gotSubject = subject
+		gotBody = body
+		return nil
+	}
+
+	if err := svc.SendPriceDropLetter(context.Background(), "alice@example.com", "Kettle", "list-1", 4000, 2999, "EUR", false); err != nil {
+		t.Fatalf("SendPriceDropLetter(owner) error = %v", err)
+	}
+	if gotSubject != "Price drop: Kettle" {
+		t.Fatalf("subject = %q", gotSubject)
+	}
+	if !strings.Contains(gotBody, `"Kettle" from your wishlist got cheaper: 29.99 EUR instead of 40.00 EUR`) || !strings.Contains(gotBody, "https://wishlist.example.com/wishlist/list-1") {
+		t.Fatalf("owner body = %s", gotBody)
+	}
+
+	if err := svc.SendPriceDropLetter(context.Background(), "bob@example.com", "Kettle", "list-1", 4000, 3000, "", true); err != nil {
+		t.Fatalf("SendPriceDropLetter(reserver) error = %v", err)
+	}
+	if !strings.Contains(gotBody, `"Kettle" you reserved got cheaper: 30.00 instead of 40.00`) {
+		t.Fatalf("reserver body = %s", gotBody)
+	}
+}
+
+func TestEmailService_SendDataExportLetter_ComposesDownloadLink(t *testing.T) {
+	svc := &EmailServiceImpl{domain: "https://wishlist.example.com", apiPath: "/api/v1"}
+
+	var gotSubject, gotBody string
+	svc.sender = func(to, subject, body string) error {
+		gotSubject = subject
+		gotBody = body
+		return nil
+	}
+
+	expiresAt := time.Date(2030, time.March, 8, 12, 0, 0, 0, time.UTC)
+	if err := svc.SendDataExportLetter(context.Background(), "alice@example.com", "export-789", expiresAt); err != nil {
+		t.Fatalf("SendDataExportLetter() error = %v", err)
+	}
+
+	if gotSubject != "Your data export is ready" {
+		t.Fatalf("subject = %s, want Your data export is ready", gotSubject)
+	}
+	if !strings.Contains(gotBody, "https://wishlist.example.com/api/v1/exports/export-789") {
+		t.Fatalf("export body does not contain download link: %s", gotBody)
+	}
+	if !strings.Contains(gotBody, "until March 8, 2030") {
+		t.Fatalf("export body missing expiration: %s", gotBody)
+	}
+}
+
+func TestEmailService_SendGroupAssignmentLetter_LinksReceiverList(t *testing.T) {
+	svc := &EmailServiceImpl{domain: "https://wishlist.example.com"}
+
+	var gotSubject, gotBody string
+	svc.sender = func(to, subject, body string) error {
+		gotSubject = subject
+		gotBody = body
+		return nil
+	}
+
+	if err := svc.SendGroupAssignmentLetter(context.Background(), "alice@example.com", "Office Secret Santa", "Bob", "list-1"); err != nil {
+		t.Fatalf("SendGroupAssignmentLetter() error = %v", err)
+	}
+	if gotSubject != "Your gift exchange draw: Office Secret Santa" {
+		t.Fatalf("subject = %q", gotSubject)
+	}
+	if !strings.Contains(gotBody, "giving a gift to Bob") || !strings.Contains(gotBody, "https://wishlist.example.com/wishlist/list-1") {
+		t.Fatalf("body = %s", gotBody)
+	}
+
+	if err := svc.SendGroupAssignmentLetter(context.Background(), "alice@example.com", "Office Secret Santa", "Bob", ""); err != nil {
+		t.Fatalf("SendGroupAssignmentLetter(no list) error = %v", err)
+	}
+	if strings.Contains(gotBody, "/wishlist/") || !strings.HasSuffix(gotBody, "https://wishlist.example.com") {
+		t.Fatalf("body without a list = %s", gotBody)
+	}
+}
+
+func TestEmailService_SendGuestReservationLetter_ComposesManagementLink(t *testing.T) {
+	svc := &EmailServiceImpl{domain: "https://wishlist.example.com"}
+
+	var gotTo, gotSubject, gotBody string
+	svc.sender = func(to, subject, body string) error {
+		gotTo = to
+		gotSubject = subject
+		gotBody = body
+		return nil
+	}
+
+	confirmBefore := time.Date(2026, time.December, 20, 18, 0, 0, 0, time.UTC)
+	if err := svc.SendGuestReservationLetter(context.Background(), "mary@example.com", "Aunt Mary", "Bike", "Birthday", "guest-token", confirmBefore); err != nil {
+		t.Fatalf("SendGuestReservationLetter() error = %v", err)
+	}
+	if gotTo != "mary@example.com" || gotSubject != "Confirm your reservation: Bike" {
+		t.Fatalf("to = %q, subject = %q", gotTo, gotSubject)
+	}
+	if !strings.Contains(gotBody, "Hi Aunt Mary") || !strings.Contains(gotBody, "December 20, 2026 18:00 UTC") || !strings.Contains(gotBody, "https://wishlist.example.com/guest-reservation?token=guest-token") {
+		t.Fatalf("body = %s", gotBody)
+	}
+}
+
 func TestEmailService_send_SMTPError(t *testing.T) {
 	svc := &EmailServiceImpl{
 		host:     "127.0.0.1",
@@ -30,6 +30,15 @@ func (e ValidationError) Error() string {
 	return e.Message
 }
 
+// TooManyRequestsError is returned when the caller has to wait before trying again
+type TooManyRequestsError struct {
+	Message string
+}
+
+func (e TooManyRequestsError) Error() string {
+	return e.Message
+}
+
 type ForbiddenError struct {
 	Message string
 }
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+	"wishlist/internal/storage"
+	"wishlist/internal/utils/str"
+)
+
+const exportBuildTimeout = 30 * time.Minute
+
+// exportDir is where archives built in the background wait for their download, see storage.ExportPrefix
+var exportDir = strings.SplitN(storage.ExportPrefix, "/", 2)[0] + "/"
+
+type ExportUserStorage interface {
+	GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error)
+}
+
+type ExportListStorage interface {
+	GetListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error)
+}
+
+type ExportWishStorage interface {
+	GetWishesByListID(ctx context.Context, listID uuid.UUID, filter models.WishFilter) ([]models.Wish, error)
+	GetReservationsByUserID(ctx context.Context, userID uuid.UUID) ([]models.HeldReservation, error)
+}
+
+type DataExportTokenStorage interface {
+	SaveDataExportToken(ctx context.Context, tokenID, objectKey string, ttl time.Duration) error
+	GetDataExportToken(ctx context.Context, tokenID string) (string, error)
+}
+
+type DataExportSender interface {
+	SendDataExportReady(ctx context.Context, userID uuid.UUID, to, token string, expiresAt time.Time) error
+}
+
+// DataExportServiceImpl puts everything of a user into a ZIP archive: profile, lists with their wishes,
+// reservations and the original image files
+type DataExportServiceImpl struct {
+	users     ExportUserStorage
+	lists     ExportListStorage
+	wishes    ExportWishStorage
+	tokens    DataExportTokenStorage
+	s3        AvatarStorage
+	sender    DataExportSender
+	log       Logger
+	syncLimit int64         // Image bytes above which the archive is built in the background
+	linkTTL   time.Duration // How long the emailed link works
+	building  sync.Map      // IDs of the users whose archive is being built in the background
+}
+
+func NewDataExportService(us ExportUserStorage, ls ExportListStorage, ws ExportWishStorage, ts DataExportTokenStorage, s3 AvatarStorage, des DataExportSender, l Logger, syncLimit int64, linkTTL time.Duration) *DataExportServiceImpl {
+	return &DataExportServiceImpl{users: us, lists: ls, wishes: ws, tokens: ts, s3: s3, sender: des, log: l, syncLimit: syncLimit, linkTTL: linkTTL}
+}
+
+// exportImage is an image file to be copied into the archive
+type exportImage struct {
+	key  string // Object key in the bucket
+	path string // Where it goes in the archive
+}
+
+type exportContent struct {
+	fileName     string
+	profile      models.ExportProfile
+	lists        []models.ExportList
+	reservations []models.ExportReservation
+	images       []exportImage
+	imageBytes   int64
+}
+
+// ExportUserData returns the archive right away when the images of the user fit into the sync limit. Bigger accounts
+// get Pending: the archive is built in the background and a download link is emailed
+func (svc *DataExportServiceImpl) ExportUserData(ctx context.Context, userID uuid.UUID) (models.DataExport, error) {
+	user, err := svc.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.DataExport{}, err
+	}
+
+	content, err := svc.collect(ctx, user)
+	if err != nil {
+		return models.DataExport{}, err
+	}
+
+	if content.imageBytes <= svc.syncLimit {
+		var buf bytes.Buffer
+		if err = svc.writeArchive(ctx, &buf, content); err != nil {
+			return models.DataExport{}, err
+		}
+		return models.DataExport{FileName: content.fileName, Data: buf.Bytes()}, nil
+	}
+
+	if user.Email == nil || *user.Email == "" {
+		return models.DataExport{}, svcErr.ValidationError{Message: "your account is too large to be downloaded at once, add an email address to get a download link"}
+	}
+	if _, running := svc.building.LoadOrStore(user.ID, struct{}{}); running {
+		return models.DataExport{}, svcErr.ConflictError{Message: "your data export is already being prepared"}
+	}
+
+	go func() {
+		defer svc.building.Delete(user.ID)
+
+		buildCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exportBuildTimeout)
+		defer cancel()
+		if err := svc.exportInBackground(buildCtx, user.ID, *user.Email, content); err != nil {
+			svc.log.Error("failed to export data of user '%s': %v", user.ID, err)
+		}
+	}()
+
+	return models.DataExport{FileName: content.fileName, Pending: true, Email: *user.Email}, nil
+}
+
+// DownloadDataExport opens the archive an emailed link points to
+func (svc *DataExportServiceImpl) DownloadDataExport(ctx context.Context, token string) (io.ReadCloser, models.BucketObject, error) {
+	objectKey, err := svc.tokens.GetDataExportToken(ctx, token)
+	if err != nil {
+		return nil, models.BucketObject{}, svcErr.NotFoundError{Entity: "data export", Field: "token", Value: token}
+	}
+
+	info, err := svc.s3.StatObject(ctx, objectKey)
+	if err != nil {
+		if _, ok := errors.AsType[svcErr.NotFoundError](err); ok { // Removed before the link expired
+			return nil, models.BucketObject{}, svcErr.NotFoundError{Entity: "data export", Field: "token", Value: token}
+		}
+		return nil, models.BucketObject{}, err
+	}
+	object, err := svc.s3.GetObject(ctx, objectKey)
+	if err != nil {
+		return nil, models.BucketObject{}, err
+	}
+
+	return object, info, nil
+}
+
+// exportInBackground builds the archive in a temporary file, uploads it next to the images and emails the link.
+// Nothing keeps the archive once the link expires, the object sweep removes it
+func (svc *DataExportServiceImpl) exportInBackground(ctx context.Context, userID uuid.UUID, email string, content exportContent) error {
+	file, err := os.CreateTemp("", "wishlist-export-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	if err = svc.writeArchive(ctx, file, content); err != nil {
+		return err
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to measure archive: %w", err)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind archive: %w", err)
+	}
+
+	objectKey := fmt.Sprintf(storage.ExportPrefix, userID, uuid.NewString()+"/"+content.fileName)
+	if err = svc.s3.UploadObject(ctx, objectKey, file, size, "application/zip"); err != nil {
+		return err
+	}
+
+	token, err := str.GenerateRandomString(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+	if err = svc.tokens.SaveDataExportToken(ctx, token, objectKey, svc.linkTTL); err != nil {
+		return fmt.Errorf("failed to save data export token: %w", err)
+	}
+
+	return svc.sender.SendDataExportReady(ctx, userID, email, token, time.Now().Add(svc.linkTTL))
+}
+
+// collect reads everything that goes into the archive, image files are only looked up to know their size
+func (svc *DataExportServiceImpl) collect(ctx context.Context, user models.User) (exportContent, error) {
+	content := exportContent{fileName: fmt.Sprintf("wishlist-export-%s-%s.zip", user.Username, time.Now().UTC().Format("2006-01-02"))}
+	content.profile = models.ExportProfile{
+		ID:            user.ID,
+		Avatar:        svc.addImage(ctx, &content, user.Avatar),
+		Name:          user.Name,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Currency:      user.Currency,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+
+	lists, err := svc.lists.GetListsByUserID(ctx, user.ID)
+	if err != nil {
+		return exportContent{}, err
+	}
+	for _, list := range lists {
+		wishes, err := svc.wishes.GetWishesByListID(ctx, list.ID, models.WishFilter{})
+		if err != nil {
+			return exportContent{}, err
+		}
+
+		exported := models.ExportList{
+			ID:         list.ID,
+			Image:      svc.addImage(ctx, &content, list.Image),
+			Title:      list.Title,
+			Notes:      list.Notes,
+			Visibility: list.Visibility,
+			Role:       list.Role,
+			CreatedAt:  list.CreatedAt,
+			UpdatedAt:  list.UpdatedAt,
+			Wishes:     make([]models.ExportWish, 0, len(wishes)),
+
+			OccasionRecursYearly: list.OccasionRecursYearly,
+		}
+		if list.OccasionDate != nil {
+			exported.OccasionDate = new(list.OccasionDate.Format(models.OccasionDateLayout))
+		}
+		for _, wish := range wishes {
+			exported.Wishes = append(exported.Wishes, models.ExportWish{
+				ID:         wish.ID,
+				Image:      svc.addImage(ctx, &content, wish.Image),
+				Title:      wish.Title,
+				Notes:      wish.Notes,
+				Link:       wish.Link,
+				Price:      wish.Price,
+				Currency:   wish.Currency,
+				Quantity:   wish.Quantity,
+				Priority:   wish.Priority,
+				PriceAlert: wish.PriceAlert,
+				Status:     wish.Status,
+				CreatedAt:  wish.CreatedAt,
+				UpdatedAt:  wish.UpdatedAt,
+			})
+		}
+		content.lists = append(content.lists, exported)
+	}
+
+	reservations, err := svc.wishes.GetReservationsByUserID(ctx, user.ID)
+	if err != nil {
+		return exportContent{}, err
+	}
+	content.reservations = make([]models.ExportReservation, 0, len(reservations))
+	for _, r := range reservations {
+		content.reservations = append(content.reservations, models.ExportReservation{
+			WishID:        r.WishID,
+			WishTitle:     r.WishTitle,
+			ListID:        r.ListID,
+			ListTitle:     r.ListTitle,
+			Units:         r.Units,
+			ReservedUntil: r.ReservedUntil,
+			CreatedAt:     r.CreatedAt,
+		})
+	}
+
+	return content, nil
+}
+
+// addImage schedules the original of a stored image for the archive and returns its path there.
+// Links to other sites are kept as they are, an image missing from the bucket is left out
+func (svc *DataExportServiceImpl) addImage(ctx context.Context, content *exportContent, stored *string) *string {
+	if stored == nil || *stored == "" {
+		return nil
+	}
+	objectName := storedObjectName(*stored, svc.s3.GetBaseURL())
+	if objectName == "" {
+		return stored
+	}
+
+	for _, image := range content.images {
+		if image.key == objectName {
+			return &image.path
+		}
+	}
+
+	info, err := svc.s3.StatObject(ctx, objectName)
+	if err != nil {
+		svc.log.Error("failed to add image '%s' to data export: %v", objectName, err)
+		return nil
+	}
+
+	image := exportImage{key: objectName, path: "images/" + objectName + imageExtension(info.ContentType)}
+	content.images = append(content.images, image)
+	content.imageBytes += info.Size
+	return &image.path
+}
+
+func (svc *DataExportServiceImpl) writeArchive(ctx context.Context, w io.Writer, content exportContent) error {
+	archive := zip.NewWriter(w)
+
+	names := []string{"profile.json", "reservations.json"}
+	values := []any{content.profile, content.reservations}
+	for _, list := range content.lists {
+		names = append(names, path.Join("lists", list.ID.String()+".json"))
+		values = append(values, list)
+	}
+	for i, name := range names {
+		data, err := json.MarshalIndent(values[i], "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		if err = writeArchiveFile(archive, name, bytes.NewReader(data)); err != nil {
+			return err
+		}
+	}
+
+	for _, image := range content.images {
+		object, err := svc.s3.GetObject(ctx, image.key)
+		if err != nil {
+			return err
+		}
+		err = writeArchiveFile(archive, image.path, object)
+		_ = object.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+func writeArchiveFile(archive *zip.Writer, name string, r io.Reader) error {
+	file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err = io.Copy(file, r); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+// imageExtension names image files in the archive so they open with a double click, the keys have no extension
+func imageExtension(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ""
+	}
+}
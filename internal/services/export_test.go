@@ -0,0 +1,233 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type exportStorageMock struct {
+	user         models.User
+	lists        []models.List
+	wishes       map[uuid.UUID][]models.Wish
+	reservations []models.HeldReservation
+}
+
+func (m *exportStorageMock) GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error) {
+	if id != m.user.ID {
+		return models.User{}, svcErr.NotFoundError{Entity: "User", Field: "id", Value: id.String()}
+	}
+	return m.user, nil
+}
+
+func (m *exportStorageMock) GetListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
+	return m.lists, nil
+}
+
+func (m *exportStorageMock) GetWishesByListID(ctx context.Context, listID uuid.UUID, filter models.WishFilter) ([]models.Wish, error) {
+	return m.wishes[listID], nil
+}
+
+func (m *exportStorageMock) GetReservationsByUserID(ctx context.Context, userID uuid.UUID) ([]models.HeldReservation, error) {
+	return m.reservations, nil
+}
+
+type exportTokenStorageMock struct {
+	tokens map[string]string
+	ttl    time.Duration
+}
+
+func (m *exportTokenStorageMock) SaveDataExportToken(ctx context.Context, tokenID, objectKey string, ttl time.Duration) error {
+	m.tokens[tokenID] = objectKey
+	m.ttl = ttl
+	return nil
+}
+
+func (m *exportTokenStorageMock) GetDataExportToken(ctx context.Context, tokenID string) (string, error) {
+	objectKey, ok := m.tokens[tokenID]
+	if !ok {
+		return "", errors.New("redis: nil")
+	}
+	return objectKey, nil
+}
+
+type exportSenderMock struct {
+	sent chan string // Tokens of the emailed links
+	to   string
+}
+
+func (m *exportSenderMock) SendDataExportReady(ctx context.Context, userID uuid.UUID, to, token string, expiresAt time.Time) error {
+	m.to = to
+	m.sent <- token
+	return nil
+}
+
+func newExportFixture() (*exportStorageMock, *userAvatarStorageMock) {
+	userID, listID := uuid.New(), uuid.New()
+	storage := &exportStorageMock{
+		user: models.User{ID: userID, Name: "Alice", Username: "alice", Email: new("alice@example.com"), Password: "$2a$10$secret-hash", Avatar: new("avatars/a/1/original")},
+		lists: []models.List{
+			{ID: listID, UserID: userID, Title: "Birthday", Visibility: "private", Role: models.ListRoleOwner},
+		},
+		wishes: map[uuid.UUID][]models.Wish{listID: {
+			{ID: uuid.New(), ListID: listID, Title: "Kettle", Image: new("wishes/l/1/original"), Price: new(int64(2999)), Quantity: 1, Status: models.WishStatusOpen},
+			{ID: uuid.New(), ListID: listID, Title: "Socks", Image: new("https://shop.example/socks.jpg"), Quantity: 2, Status: models.WishStatusReserved},
+			{ID: uuid.New(), ListID: listID, Title: "Lamp", Image: new("wishes/l/gone/original"), Quantity: 1, Status: models.WishStatusOpen},
+		}},
+		reservations: []models.HeldReservation{
+			{WishReservation: models.WishReservation{WishID: uuid.New(), UserID: userID, Units: 1}, WishTitle: "Bike", ListID: uuid.New(), ListTitle: "Bob's list"},
+		},
+	}
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist", stored: map[string]storedObjectMock{
+		"avatars/a/1/original": {data: []byte("avatar-bytes"), contentType: "image/jpeg"},
+		"wishes/l/1/original":  {data: []byte("kettle-bytes"), contentType: "image/webp"},
+	}}
+	return storage, s3
+}
+
+func readExport(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("archive is not a ZIP: %v", err)
+	}
+	files := make(map[string][]byte, len(archive.File))
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+		files[file.Name], _ = io.ReadAll(r)
+		_ = r.Close()
+	}
+	return files
+}
+
+func TestDataExportService_ExportUserData_Archive(t *testing.T) {
+	storage, s3 := newExportFixture()
+	log := &userLoggerMock{}
+	svc := NewDataExportService(storage, storage, storage, &exportTokenStorageMock{}, s3, &exportSenderMock{}, log, 1<<20, time.Hour)
+
+	export, err := svc.ExportUserData(context.Background(), storage.user.ID)
+	if err != nil {
+		t.Fatalf("ExportUserData() error = %v", err)
+	}
+	if export.Pending || !strings.HasPrefix(export.FileName, "wishlist-export-alice-") {
+		t.Fatalf("export = %+v, want the archive right away", export)
+	}
+
+	files := readExport(t, export.Data)
+	if bytes.Contains(files["profile.json"], []byte("secret-hash")) || bytes.Contains(files["profile.json"], []byte("password")) {
+		t.Fatalf("profile.json leaks the password: %s", files["profile.json"])
+	}
+	var profile models.ExportProfile
+	if err = json.Unmarshal(files["profile.json"], &profile); err != nil || profile.Username != "alice" || *profile.Avatar != "images/avatars/a/1/original.jpg" {
+		t.Fatalf("profile = %+v, %v", profile, err)
+	}
+	if string(files["images/avatars/a/1/original.jpg"]) != "avatar-bytes" || string(files["images/wishes/l/1/original.webp"]) != "kettle-bytes" {
+		t.Fatalf("image files missing, archive has %d files", len(files))
+	}
+
+	var list models.ExportList
+	if err = json.Unmarshal(files["lists/"+storage.lists[0].ID.String()+".json"], &list); err != nil || len(list.Wishes) != 3 {
+		t.Fatalf("list = %+v, %v", list, err)
+	}
+	if *list.Wishes[0].Image != "images/wishes/l/1/original.webp" || *list.Wishes[1].Image != "https://shop.example/socks.jpg" || list.Wishes[2].Image != nil {
+		t.Fatalf("wish images = %v %v %v, want archive path, the link, nothing for a missing file", *list.Wishes[0].Image, *list.Wishes[1].Image, list.Wishes[2].Image)
+	}
+	if log.calls != 1 {
+		t.Fatalf("logged %d errors, want the missing image", log.calls)
+	}
+
+	var reservations []models.ExportReservation
+	if err = json.Unmarshal(files["reservations.json"], &reservations); err != nil || len(reservations) != 1 || reservations[0].WishTitle != "Bike" {
+		t.Fatalf("reservations = %+v, %v", reservations, err)
+	}
+}
+
+func TestDataExportService_ExportUserData_Background(t *testing.T) {
+	t.Run("emails a link", func(t *testing.T) {
+		storage, s3 := newExportFixture()
+		tokens := &exportTokenStorageMock{tokens: map[string]string{}}
+		sender := &exportSenderMock{sent: make(chan string, 1)}
+		svc := NewDataExportService(storage, storage, storage, tokens, s3, sender, &userLoggerMock{}, 10, 72*time.Hour)
+
+		export, err := svc.ExportUserData(context.Background(), storage.user.ID)
+		if err != nil {
+			t.Fatalf("ExportUserData() error = %v", err)
+		}
+		if !export.Pending || export.Email != "alice@example.com" || export.Data != nil {
+			t.Fatalf("export = %+v, want it pending", export)
+		}
+
+		var token string
+		select {
+		case token = <-sender.sent:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no download link was sent")
+		}
+		objectKey := tokens.tokens[token]
+		if sender.to != "alice@example.com" || tokens.ttl != 72*time.Hour || !strings.HasPrefix(objectKey, "exports/"+storage.user.ID.String()+"/") {
+			t.Fatalf("sent to %s, saved %s for %s", sender.to, objectKey, tokens.ttl)
+		}
+		if s3.uploadedObjectName != objectKey || s3.uploadedType != "application/zip" || s3.uploadedSize == 0 {
+			t.Fatalf("uploaded %s (%s, %d bytes)", s3.uploadedObjectName, s3.uploadedType, s3.uploadedSize)
+		}
+	})
+
+	t.Run("without email", func(t *testing.T) {
+		storage, s3 := newExportFixture()
+		storage.user.Email = nil
+		svc := NewDataExportService(storage, storage, storage, &exportTokenStorageMock{}, s3, &exportSenderMock{}, &userLoggerMock{}, 10, time.Hour)
+
+		_, err := svc.ExportUserData(context.Background(), storage.user.ID)
+		if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+			t.Fatalf("ExportUserData() error = %v, want ValidationError", err)
+		}
+	})
+
+	t.Run("already running", func(t *testing.T) {
+		storage, s3 := newExportFixture()
+		svc := NewDataExportService(storage, storage, storage, &exportTokenStorageMock{}, s3, &exportSenderMock{}, &userLoggerMock{}, 10, time.Hour)
+		svc.building.Store(storage.user.ID, struct{}{})
+
+		_, err := svc.ExportUserData(context.Background(), storage.user.ID)
+		if _, ok := errors.AsType[svcErr.ConflictError](err); !ok {
+			t.Fatalf("ExportUserData() error = %v, want ConflictError", err)
+		}
+	})
+}
+
+func TestDataExportService_DownloadDataExport(t *testing.T) {
+	_, s3 := newExportFixture()
+	s3.stored["exports/u/1/wishlist-export.zip"] = storedObjectMock{data: []byte("zip"), contentType: "application/zip"}
+	tokens := &exportTokenStorageMock{tokens: map[string]string{"valid": "exports/u/1/wishlist-export.zip", "swept": "exports/u/0/wishlist-export.zip"}}
+	svc := NewDataExportService(nil, nil, nil, tokens, s3, nil, &userLoggerMock{}, 0, time.Hour)
+
+	archive, info, err := svc.DownloadDataExport(context.Background(), "valid")
+	if err != nil {
+		t.Fatalf("DownloadDataExport() error = %v", err)
+	}
+	if data, _ := io.ReadAll(archive); string(data) != "zip" || info.Size != 3 {
+		t.Fatalf("downloaded %q, size %d", data, info.Size)
+	}
+
+	for _, token := range []string{"unknown", "swept"} {
+		_, _, err = svc.DownloadDataExport(context.Background(), token)
+		if _, ok := errors.AsType[svcErr.NotFoundError](err); !ok {
+			t.Fatalf("DownloadDataExport(%s) error = %v, want NotFoundError", token, err)
+		}
+	}
+}
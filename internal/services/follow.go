@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type FollowStorage interface {
+	CreateFollow(ctx context.Context, follow models.Follow) error
+	GetFollow(ctx context.Context, followerID, followeeID uuid.UUID) (models.Follow, error)
+	GetFollowers(ctx context.Context, userID uuid.UUID) ([]models.Follow, error)
+	GetFollowing(ctx context.Context, userID uuid.UUID) ([]models.Follow, error)
+	AcceptFollow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	DeleteFollow(ctx context.Context, followerID, followeeID uuid.UUID) error
+}
+
+type FollowServiceImpl struct {
+	follows FollowStorage
+}
+
+func NewFollowService(fs FollowStorage) *FollowServiceImpl {
+	return &FollowServiceImpl{follows: fs}
+}
+
+// followStatus returns how far followerID got in following followeeID
+func followStatus(ctx context.Context, follows FollowStorage, followerID, followeeID uuid.UUID) (models.FollowStatus, error) {
+	follow, err := follows.GetFollow(ctx, followerID, followeeID)
+	if err != nil {
+		if _, ok := errors.AsType[svcErr.NotFoundError](err); ok {
+			return models.FollowStatusNone, nil
+		}
+		return "", err
+	}
+
+	return follow.Status(), nil
+}
+
+// followsListOwner tells whether userID may read a followers-only list as an accepted follower of its owner
+func followsListOwner(ctx context.Context, follows FollowStorage, list models.List, userID uuid.UUID) (bool, error) {
+	if list.Visibility != models.ListVisibilityFollowers || list.Role != "" {
+		return false, nil // Only outsiders of followers-only lists need the lookup
+	}
+
+	status, err := followStatus(ctx, follows, userID, list.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return status == models.FollowStatusAccepted, nil
+}
+
+// Follow sends a follow request, the followed user has to accept it before followers-only lists open up
+func (svc *FollowServiceImpl) Follow(ctx context.Context, followerID, followeeID uuid.UUID) (models.Follow, error) {
+	if followerID == followeeID {
+		return models.Follow{}, svcErr.ValidationError{Message: "you cannot follow yourself"}
+	}
+
+	follow := models.Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := svc.follows.CreateFollow(ctx, follow); err != nil {
+		return models.Follow{}, err
+	}
+
+	return follow, nil
+}
+
+// Unfollow stops following the user or withdraws a request that was not accepted yet
+func (svc *FollowServiceImpl) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	return svc.follows.DeleteFollow(ctx, followerID, followeeID)
+}
+
+func (svc *FollowServiceImpl) GetFollowState(ctx context.Context, userID, otherUserID uuid.UUID) (models.FollowState, error) {
+	if userID == otherUserID {
+		return models.FollowState{Following: models.FollowStatusNone, FollowedBy: models.FollowStatusNone}, nil
+	}
+
+	following, err := followStatus(ctx, svc.follows, userID, otherUserID)
+	if err != nil {
+		return models.FollowState{}, err
+	}
+
+	followedBy, err := followStatus(ctx, svc.follows, otherUserID, userID)
+	if err != nil {
+		return models.FollowState{}, err
+	}
+
+	return models.FollowState{Following: following, FollowedBy: followedBy}, nil
+}
+
+func (svc *FollowServiceImpl) GetFollowers(ctx context.Context, userID uuid.UUID) ([]models.Follow, error) {
+	return svc.follows.GetFollowers(ctx, userID)
+}
+
+func (svc *FollowServiceImpl) GetFollowing(ctx context.Context, userID uuid.UUID) ([]models.Follow, error) {
+	return svc.follows.GetFollowing(ctx, userID)
+}
+
+func (svc *FollowServiceImpl) AcceptFollower(ctx context.Context, userID, followerID uuid.UUID) error {
+	return svc.follows.AcceptFollow(ctx, followerID, userID)
+}
+
+// RemoveFollower declines a pending request or drops an accepted follower
+func (svc *FollowServiceImpl) RemoveFollower(ctx context.Context, userID, followerID uuid.UUID) error {
+	return svc.follows.DeleteFollow(ctx, followerID, userID)
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type followKey struct{ follower, followee uuid.UUID }
+
+type followStorageMock struct {
+	createErr error
+
+	follows map[followKey]models.Follow
+
+	created  models.Follow
+	accepted followKey
+	deleted  followKey
+}
+
+func (m *followStorageMock) CreateFollow(ctx context.Context, follow models.Follow) error {
+	m.created = follow
+	return m.createErr
+}
+
+func (m *followStorageMock) GetFollow(ctx context.Context, followerID, followeeID uuid.UUID) (models.Follow, error) {
+	follow, ok := m.follows[followKey{followerID, followeeID}]
+	if !ok {
+		return models.Follow{}, svcErr.NotFoundError{Entity: "follow", Field: "user_id", Value: followeeID.String()}
+	}
+	return follow, nil
+}
+
+func (m *followStorageMock) GetFollowers(ctx context.Context, userID uuid.UUID) ([]models.Follow, error) {
+	var follows []models.Follow
+	for key, follow := range m.follows {
+		if key.followee == userID {
+			follows = append(follows, follow)
+		}
+	}
+	return follows, nil
+}
+
+func (m *followStorageMock) GetFollowing(ctx context.Context, userID uuid.UUID) ([]models.Follow, error) {
+	var follows []models.Follow
+	for key, follow := range m.follows {
+		if key.follower == userID {
+			follows = append(follows, follow)
+		}
+	}
+	return follows, nil
+}
+
+func (m *followStorageMock) AcceptFollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	m.accepted = followKey{followerID, followeeID}
+	return nil
+}
+
+func (m *followStorageMock) DeleteFollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	m.deleted = followKey{followerID, followeeID}
+	return nil
+}
+
+func acceptedFollow(followerID, followeeID uuid.UUID) models.Follow {
+	return models.Follow{FollowerID: followerID, FolloweeID: followeeID, AcceptedAt: new(time.Now())}
+}
+
+func TestFollowService_Follow(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+	fs := &followStorageMock{}
+	svc := NewFollowService(fs)
+
+	var validation svcErr.ValidationError
+	if _, err := svc.Follow(context.Background(), userID, userID); !errors.As(err, &validation) {
+		t.Fatalf("Follow() self error = %T, want ValidationError", err)
+	}
+
+	follow, err := svc.Follow(context.Background(), userID, otherID)
+	if err != nil {
+		t.Fatalf("Follow() error = %v", err)
+	}
+	if follow.IsAccepted() || fs.created.FollowerID != userID || fs.created.FolloweeID != otherID {
+		t.Fatalf("Follow() stored %+v, want a pending request from %s to %s", fs.created, userID, otherID)
+	}
+}
+
+func TestFollowService_GetFollowState(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+	fs := &followStorageMock{follows: map[followKey]models.Follow{
+		{userID, otherID}: acceptedFollow(userID, otherID),
+		{otherID, userID}: {FollowerID: otherID, FolloweeID: userID},
+	}}
+	svc := NewFollowService(fs)
+
+	state, err := svc.GetFollowState(context.Background(), userID, otherID)
+	if err != nil {
+		t.Fatalf("GetFollowState() error = %v", err)
+	}
+	if state.Following != models.FollowStatusAccepted || state.FollowedBy != models.FollowStatusRequested {
+		t.Fatalf("GetFollowState() = %+v, want accepted and requested", state)
+	}
+
+	if state, _ = svc.GetFollowState(context.Background(), userID, uuid.New()); state.Following != models.FollowStatusNone {
+		t.Fatalf("GetFollowState() stranger = %+v, want none", state)
+	}
+}
+
+func TestFollowService_AcceptAndRemoveFollower(t *testing.T) {
+	userID := uuid.New()
+	followerID := uuid.New()
+	fs := &followStorageMock{}
+	svc := NewFollowService(fs)
+
+	if err := svc.AcceptFollower(context.Background(), userID, followerID); err != nil {
+		t.Fatalf("AcceptFollower() error = %v", err)
+	}
+	if fs.accepted != (followKey{followerID, userID}) {
+		t.Fatalf("AcceptFollower() accepted %+v, want the request from %s", fs.accepted, followerID)
+	}
+
+	if err := svc.RemoveFollower(context.Background(), userID, followerID); err != nil {
+		t.Fatalf("RemoveFollower() error = %v", err)
+	}
+	if fs.deleted != (followKey{followerID, userID}) {
+		t.Fatalf("RemoveFollower() deleted %+v, want the follow of %s", fs.deleted, followerID)
+	}
+}
+
+func TestListService_GetListByID_Followers(t *testing.T) {
+	ownerID := uuid.New()
+	followerID := uuid.New()
+	pendingID := uuid.New()
+	list := models.List{ID: uuid.New(), UserID: ownerID, Visibility: models.ListVisibilityFollowers, Slug: "12345678901234567890123456789012"}
+	ls := &listStorageMock{listToReturn: list}
+	fs := &followStorageMock{follows: map[followKey]models.Follow{
+		{followerID, ownerID}: acceptedFollow(followerID, ownerID),
+		{pendingID, ownerID}:  {FollowerID: pendingID, FolloweeID: ownerID},
+	}}
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, fs, nil, nil, nil, nil)
+
+	if _, err := svc.GetListByID(context.Background(), list.ID, followerID); err != nil {
+		t.Fatalf("GetListByID() follower error = %v", err)
+	}
+	if _, err := svc.GetListBySharedLink(context.Background(), list.Slug, &followerID); err != nil {
+		t.Fatalf("GetListBySharedLink() follower error = %v", err)
+	}
+
+	var forbidden svcErr.ForbiddenError
+	if _, err := svc.GetListByID(context.Background(), list.ID, pendingID); !errors.As(err, &forbidden) {
+		t.Fatalf("GetListByID() pending follower error = %T, want ForbiddenError", err)
+	}
+	if _, err := svc.GetListBySharedLink(context.Background(), list.Slug, &pendingID); !errors.As(err, &forbidden) {
+		t.Fatalf("GetListBySharedLink() pending follower error = %T, want ForbiddenError", err)
+	}
+}
+
+func TestListService_GetPublicListsByUserID_Followers(t *testing.T) {
+	ownerID := uuid.New()
+	followerID := uuid.New()
+	ls := &listStorageMock{}
+	fs := &followStorageMock{follows: map[followKey]models.Follow{
+		{followerID, ownerID}: acceptedFollow(followerID, ownerID),
+	}}
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, fs, nil, nil, nil, nil)
+
+	tests := []struct {
+		name          string
+		requestedBy   uuid.UUID
+		withFollowers bool
+	}{
+		{name: "follower", requestedBy: followerID, withFollowers: true},
+		{name: "stranger", requestedBy: uuid.New(), withFollowers: false},
+		{name: "owner", requestedBy: ownerID, withFollowers: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.GetPublicListsByUserID(context.Background(), ownerID, tt.requestedBy); err != nil {
+				t.Fatalf("GetPublicListsByUserID() error = %v", err)
+			}
+			if ls.withFollowers != tt.withFollowers {
+				t.Fatalf("GetPublicListsByUserID() withFollowers = %v, want %v", ls.withFollowers, tt.withFollowers)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+const (
+	minDrawParticipants = 3
+	maxDrawSteps        = 100000 // Gives up on exclusions that leave no or almost no valid draw
+)
+
+type GroupStorage interface {
+	CreateGroup(ctx context.Context, group models.Group, organizer models.GroupMember) error
+	GetGroupByID(ctx context.Context, id uuid.UUID) (models.Group, error)
+	GetGroupsByUserID(ctx context.Context, userID uuid.UUID, accepted bool) ([]models.Group, error)
+	DeleteGroup(ctx context.Context, id uuid.UUID) error
+	AddGroupMember(ctx context.Context, member models.GroupMember) error
+	GetGroupMember(ctx context.Context, groupID, userID uuid.UUID) (models.GroupMember, error)
+	GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]models.GroupMember, error)
+	AcceptGroupMember(ctx context.Context, groupID, userID uuid.UUID) error
+	SetGroupMemberList(ctx context.Context, groupID, userID uuid.UUID, listID *uuid.UUID) error
+	DeleteGroupMember(ctx context.Context, groupID, userID uuid.UUID) error
+	AddGroupExclusions(ctx context.Context, exclusions []models.GroupExclusion) error
+	GetGroupExclusions(ctx context.Context, groupID uuid.UUID) ([]models.GroupExclusion, error)
+	DeleteGroupExclusion(ctx context.Context, groupID, giverID, receiverID uuid.UUID) error
+	CreateGroupDraw(ctx context.Context, draw models.GroupDraw, assignments []models.GroupAssignment) error
+	GetGroupDraws(ctx context.Context, groupID uuid.UUID) ([]models.GroupDraw, error)
+	GetGroupAssignee(ctx context.Context, groupID, giverID uuid.UUID) (models.GroupAssignee, error)
+}
+
+type GroupServiceImpl struct {
+	groups GroupStorage
+	lists  ListStorage
+	email  EmailSender
+	log    Logger
+}
+
+func NewGroupService(gs GroupStorage, ls ListStorage, es EmailSender, l Logger) *GroupServiceImpl {
+	return &GroupServiceImpl{groups: gs, lists: ls, email: es, log: l}
+}
+
+func (svc *GroupServiceImpl) CreateGroup(ctx context.Context, userID uuid.UUID, req models.CreateGroupRequest) (models.Group, error) {
+	now := time.Now()
+	group := models.Group{
+		ID:           uuid.New(),
+		OrganizerID:  userID,
+		Title:        req.Title,
+		Notes:        req.Notes,
+		MembersCount: 1,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	organizer := models.GroupMember{
+		GroupID:    group.ID,
+		UserID:     userID,
+		InvitedBy:  userID,
+		AcceptedAt: &now,
+		CreatedAt:  now,
+	}
+
+	if err := svc.groups.CreateGroup(ctx, group, organizer); err != nil {
+		return models.Group{}, err
+	}
+
+	return group, nil
+}
+
+func (svc *GroupServiceImpl) GetUserGroups(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	return svc.groups.GetGroupsByUserID(ctx, userID, true)
+}
+
+func (svc *GroupServiceImpl) GetGroupInvitations(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	return svc.groups.GetGroupsByUserID(ctx, userID, false)
+}
+
+// GetGroup returns the group with its members, invited users may look at it before accepting
+func (svc *GroupServiceImpl) GetGroup(ctx context.Context, groupID, userID uuid.UUID) (models.Group, []models.GroupMember, error) {
+	group, err := svc.groups.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return models.Group{}, nil, err
+	}
+
+	members, err := svc.groups.GetGroupMembers(ctx, groupID)
+	if err != nil {
+		return models.Group{}, nil, err
+	}
+	if !slices.ContainsFunc(members, func(m models.GroupMember) bool { return m.UserID == userID }) {
+		return models.Group{}, nil, svcErr.ForbiddenError{Message: "you are not a member of this group"}
+	}
+
+	return group, members, nil
+}
+
+func (svc *GroupServiceImpl) DeleteGroup(ctx context.Context, groupID, userID uuid.UUID) error {
+	if _, err := svc.organizedGroup(ctx, groupID, userID); err != nil {
+		return err
+	}
+
+	return svc.groups.DeleteGroup(ctx, groupID)
+}
+
+func (svc *GroupServiceImpl) InviteMember(ctx context.Context, groupID, userID uuid.UUID, req models.InviteGroupMemberRequest) (models.GroupMember, error) {
+	if _, err := svc.organizedGroup(ctx, groupID, userID); err != nil {
+		return models.GroupMember{}, err
+	}
+
+	if req.UserID == userID {
+		return models.GroupMember{}, svcErr.ValidationError{Message: "you cannot invite yourself"}
+	}
+
+	member := models.GroupMember{
+		GroupID:   groupID,
+		UserID:    req.UserID,
+		InvitedBy: userID,
+		CreatedAt: time.Now(),
+	}
+
+	if err := svc.groups.AddGroupMember(ctx, member); err != nil {
+		return models.GroupMember{}, err
+	}
+
+	return member, nil
+}
+
+func (svc *GroupServiceImpl) AcceptInvitation(ctx context.Context, groupID, userID uuid.UUID) error {
+	return svc.groups.AcceptGroupMember(ctx, groupID, userID)
+}
+
+func (svc *GroupServiceImpl) RemoveMember(ctx context.Context, groupID, memberID, userID uuid.UUID) error {
+	group, err := svc.groups.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	if memberID == group.OrganizerID {
+		return svcErr.ValidationError{Message: "the organizer cannot leave the group"}
+	}
+	if memberID != userID && userID != group.OrganizerID { // Anyone may leave or decline on their own
+		return svcErr.ForbiddenError{Message: "you are not the organizer of this group"}
+	}
+
+	return svc.groups.DeleteGroupMember(ctx, groupID, memberID)
+}
+
+// SetMemberList picks the list the member wants gifts from. It has to be public, as their giver opens it without
+// following them
+func (svc *GroupServiceImpl) SetMemberList(ctx context.Context, groupID, userID uuid.UUID, req models.SetGroupListRequest) error {
+	if _, err := svc.acceptedMember(ctx, groupID, userID); err != nil {
+		return err
+	}
+
+	if req.ListID != nil {
+		list, err := svc.lists.GetListByID(ctx, *req.ListID)
+		if err != nil {
+			return err
+		}
+		if list.UserID != userID {
+			return svcErr.ForbiddenError{Message: "you are not the owner of this wishlist"}
+		}
+		if list.Visibility != models.ListVisibilityPublic {
+			return svcErr.ValidationError{Message: "the wishlist must be public for your giver to see it"}
+		}
+	}
+
+	return svc.groups.SetGroupMemberList(ctx, groupID, userID, req.ListID)
+}
+
+func (svc *GroupServiceImpl) AddExclusion(ctx context.Context, groupID, userID uuid.UUID, req models.CreateGroupExclusionRequest) ([]models.GroupExclusion, error) {
+	if _, err := svc.organizedGroup(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.GiverID == req.ReceiverID {
+		return nil, svcErr.ValidationError{Message: "nobody draws themselves anyway"}
+	}
+	for _, id := range []uuid.UUID{req.GiverID, req.ReceiverID} {
+		if _, err := svc.groups.GetGroupMember(ctx, groupID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	exclusions := []models.GroupExclusion{{GroupID: groupID, GiverID: req.GiverID, ReceiverID: req.ReceiverID, CreatedAt: time.Now()}}
+	if req.Mutual {
+		exclusions = append(exclusions, models.GroupExclusion{GroupID: groupID, GiverID: req.ReceiverID, ReceiverID: req.GiverID, CreatedAt: time.Now()})
+	}
+
+	if err := svc.groups.AddGroupExclusions(ctx, exclusions); err != nil {
+		return nil, err
+	}
+
+	return exclusions, nil
+}
+
+func (svc *GroupServiceImpl) GetExclusions(ctx context.Context, groupID, userID uuid.UUID) ([]models.GroupExclusion, error) {
+	if _, err := svc.organizedGroup(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	return svc.groups.GetGroupExclusions(ctx, groupID)
+}
+
+func (svc *GroupServiceImpl) DeleteExclusion(ctx context.Context, groupID, giverID, receiverID, userID uuid.UUID) error {
+	if _, err := svc.organizedGroup(ctx, groupID, userID); err != nil {
+		return err
+	}
+
+	return svc.groups.DeleteGroupExclusion(ctx, groupID, giverID, receiverID)
+}
+
+// Draw assigns every accepted member someone else to give to. Drawing again replaces the assignments everyone sees,
+// the earlier draws stay in the audit trail. The seed is always random, a chosen one would let the organizer pick
+// a draw they worked out beforehand
+func (svc *GroupServiceImpl) Draw(ctx context.Context, groupID, userID uuid.UUID) (models.GroupDraw, error) {
+	group, err := svc.organizedGroup(ctx, groupID, userID)
+	if err != nil {
+		return models.GroupDraw{}, err
+	}
+
+	members, err := svc.groups.GetGroupMembers(ctx, groupID)
+	if err != nil {
+		return models.GroupDraw{}, err
+	}
+	members = slices.DeleteFunc(members, func(m models.GroupMember) bool { return !m.IsAccepted() })
+	if len(members) < minDrawParticipants {
+		return models.GroupDraw{}, svcErr.ValidationError{Message: "at least 3 accepted members are needed for a draw"}
+	}
+
+	exclusions, err := svc.groups.GetGroupExclusions(ctx, groupID)
+	if err != nil {
+		return models.GroupDraw{}, err
+	}
+	excluded := make(map[drawPair]bool, len(exclusions))
+	for _, e := range exclusions {
+		excluded[drawPair{giver: e.GiverID, receiver: e.ReceiverID}] = true
+	}
+
+	seed := rand.Int64()
+
+	participants := make([]uuid.UUID, len(members))
+	for i, m := range members {
+		participants[i] = m.UserID
+	}
+	pairs, ok := drawAssignments(participants, excluded, seed)
+	if !ok {
+		return models.GroupDraw{}, svcErr.ValidationError{Message: "no valid draw with these exclusions"}
+	}
+
+	draw := models.GroupDraw{
+		ID:           uuid.New(),
+		GroupID:      groupID,
+		DrawnBy:      userID,
+		Seed:         seed,
+		Participants: len(participants),
+		CreatedAt:    time.Now(),
+	}
+	assignments := make([]models.GroupAssignment, len(pairs))
+	for i, p := range pairs {
+		assignments[i] = models.GroupAssignment{DrawID: draw.ID, GiverID: p.giver, ReceiverID: p.receiver}
+	}
+
+	if err = svc.groups.CreateGroupDraw(ctx, draw, assignments); err != nil {
+		return models.GroupDraw{}, err
+	}
+
+	svc.notifyAssignments(ctx, group, members, pairs)
+
+	return draw, nil
+}
+
+// GetDraws returns the audit trail of the group, it only shows who drew and when, never who got whom
+func (svc *GroupServiceImpl) GetDraws(ctx context.Context, groupID, userID uuid.UUID) ([]models.GroupDraw, error) {
+	if _, err := svc.organizedGroup(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	return svc.groups.GetGroupDraws(ctx, groupID)
+}
+
+// GetAssignment returns who the user gives to in the latest draw, nobody learns the other assignments. A draw a member
+// left since is not served, someone's receiver would end up without a giver
+func (svc *GroupServiceImpl) GetAssignment(ctx context.Context, groupID, userID uuid.UUID) (models.GroupAssignee, error) {
+	if _, err := svc.acceptedMember(ctx, groupID, userID); err != nil {
+		return models.GroupAssignee{}, err
+	}
+
+	assignee, err := svc.groups.GetGroupAssignee(ctx, groupID, userID)
+	if err != nil {
+		return models.GroupAssignee{}, err
+	}
+	if assignee.StaleAt != nil {
+		return models.GroupAssignee{}, svcErr.ConflictError{Message: "a member left after the draw, the organizer has to draw again"}
+	}
+
+	return assignee, nil
+}
+
+// notifyAssignments emails every giver their receiver, failures are only logged as the draw itself is saved
+func (svc *GroupServiceImpl) notifyAssignments(ctx context.Context, group models.Group, members []models.GroupMember, pairs []drawPair) {
+	byID := make(map[uuid.UUID]models.GroupMember, len(members))
+	for _, m := range members {
+		byID[m.UserID] = m
+	}
+
+	for _, p := range pairs {
+		giver, receiver := byID[p.giver], byID[p.receiver]
+		if giver.Email == nil {
+			continue
+		}
+
+		notice := models.GroupAssignmentNotice{
+			GroupID:      group.ID,
+			GroupTitle:   group.Title,
+			ReceiverName: receiver.Name,
+			ListID:       receiver.ListID,
+		}
+		if err := svc.email.SendGroupAssignment(ctx, giver.UserID.String(), *giver.Email, notice); err != nil {
+			svc.log.Error("failed to send assignment of group '%s' to user '%s': %v", group.ID, giver.UserID, err)
+		}
+	}
+}
+
+// organizedGroup returns the group when the user organizes it
+func (svc *GroupServiceImpl) organizedGroup(ctx context.Context, groupID, userID uuid.UUID) (models.Group, error) {
+	group, err := svc.groups.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return models.Group{}, err
+	}
+	if group.OrganizerID != userID {
+		return models.Group{}, svcErr.ForbiddenError{Message: "you are not the organizer of this group"}
+	}
+
+	return group, nil
+}
+
+// acceptedMember returns the membership of the user, pending invitations grant nothing yet
+func (svc *GroupServiceImpl) acceptedMember(ctx context.Context, groupID, userID uuid.UUID) (models.GroupMember, error) {
+	member, err := svc.groups.GetGroupMember(ctx, groupID, userID)
+	if err != nil {
+		if _, ok := errors.AsType[svcErr.NotFoundError](err); ok {
+			return models.GroupMember{}, svcErr.ForbiddenError{Message: "you are not a member of this group"}
+		}
+		return models.GroupMember{}, err
+	}
+	if !member.IsAccepted() {
+		return models.GroupMember{}, svcErr.ForbiddenError{Message: "you are not a member of this group"}
+	}
+
+	return member, nil
+}
+
+type drawPair struct {
+	giver, receiver uuid.UUID
+}
+
+// drawAssignments finds a derangement of the participants that respects the exclusions: everyone gives to exactly one
+// other participant and receives from exactly one. The same participants, exclusions and seed always give the same
+// result, whatever order the participants come in. It reports false when no such draw was found
+func drawAssignments(participants []uuid.UUID, excluded map[drawPair]bool, seed int64) ([]drawPair, bool) {
+	sorted := slices.Clone(participants)
+	slices.SortFunc(sorted, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+
+	n := len(sorted)
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	givers := rng.Perm(n)
+	receiverOf := make([]int, n)
+	taken := make([]bool, n)
+	steps := 0
+
+	// Backtracking over random receiver orders, exclusions make a plain shuffle fail too often
+	var assign func(i int) bool
+	assign = func(i int) bool {
+		if i == n {
+			return true
+		}
+		giver := givers[i]
+		for _, receiver := range rng.Perm(n) {
+			if taken[receiver] || receiver == giver || excluded[drawPair{giver: sorted[giver], receiver: sorted[receiver]}] {
+				continue
+			}
+			if steps++; steps > maxDrawSteps {
+				return false
+			}
+			taken[receiver], receiverOf[giver] = true, receiver
+			if assign(i + 1) {
+				return true
+			}
+			taken[receiver] = false
+		}
+		return false
+	}
+	if !assign(0) {
+		return nil, false
+	}
+
+	pairs := make([]drawPair, n)
+	for giver, receiver := range receiverOf {
+		pairs[giver] = drawPair{giver: sorted[giver], receiver: sorted[receiver]}
+	}
+	return pairs, true
+}
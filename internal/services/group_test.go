@@ -0,0 +1,439 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type groupStorageMock struct {
+	group      models.Group
+	members    []models.GroupMember
+	exclusions []models.GroupExclusion
+
+	createdOrganizer models.GroupMember
+	addedMember      models.GroupMember
+	deletedMember    uuid.UUID
+	setListID        *uuid.UUID
+	draws            []models.GroupDraw
+	assignments      [][]models.GroupAssignment
+}
+
+func (m *groupStorageMock) CreateGroup(ctx context.Context, group models.Group, organizer models.GroupMember) error {
+	m.group, m.createdOrganizer = group, organizer
+	return nil
+}
+
+func (m *groupStorageMock) GetGroupByID(ctx context.Context, id uuid.UUID) (models.Group, error) {
+	if id != m.group.ID {
+		return models.Group{}, svcErr.NotFoundError{Entity: "group", Field: "id", Value: id.String()}
+	}
+	return m.group, nil
+}
+
+func (m *groupStorageMock) GetGroupsByUserID(ctx context.Context, userID uuid.UUID, accepted bool) ([]models.Group, error) {
+	return []models.Group{m.group}, nil
+}
+
+func (m *groupStorageMock) DeleteGroup(ctx context.Context, id uuid.UUID) error { return nil }
+
+func (m *groupStorageMock) AddGroupMember(ctx context.Context, member models.GroupMember) error {
+	m.addedMember = member
+	return nil
+}
+
+func (m *groupStorageMock) GetGroupMember(ctx context.Context, groupID, userID uuid.UUID) (models.GroupMember, error) {
+	for _, member := range m.members {
+		if member.UserID == userID {
+			return member, nil
+		}
+	}
+	return models.GroupMember{}, svcErr.NotFoundError{Entity: "member", Field: "user_id", Value: userID.String()}
+}
+
+func (m *groupStorageMock) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]models.GroupMember, error) {
+	return slices.Clone(m.members), nil
+}
+
+func (m *groupStorageMock) AcceptGroupMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	return nil
+}
+
+func (m *groupStorageMock) SetGroupMemberList(ctx context.Context, groupID, userID uuid.UUID, listID *uuid.UUID) error {
+	m.setListID = listID
+	return nil
+}
+
+func (m *groupStorageMock) DeleteGroupMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	m.deletedMember = userID
+	return nil
+}
+
+func (m *groupStorageMock) AddGroupExclusions(ctx context.Context, exclusions []models.GroupExclusion) error {
+	m.exclusions = append(m.exclusions, exclusions...)
+	return nil
+}
+
+func (m *groupStorageMock) GetGroupExclusions(ctx context.Context, groupID uuid.UUID) ([]models.GroupExclusion, error) {
+	return m.exclusions, nil
+}
+
+func (m *groupStorageMock) DeleteGroupExclusion(ctx context.Context, groupID, giverID, receiverID uuid.UUID) error {
+	return nil
+}
+
+func (m *groupStorageMock) CreateGroupDraw(ctx context.Context, draw models.GroupDraw, assignments []models.GroupAssignment) error {
+	m.draws = append(m.draws, draw)
+	m.assignments = append(m.assignments, assignments)
+	return nil
+}
+
+func (m *groupStorageMock) GetGroupDraws(ctx context.Context, groupID uuid.UUID) ([]models.GroupDraw, error) {
+	return m.draws, nil
+}
+
+func (m *groupStorageMock) GetGroupAssignee(ctx context.Context, groupID, giverID uuid.UUID) (models.GroupAssignee, error) {
+	if len(m.assignments) == 0 {
+		return models.GroupAssignee{}, svcErr.NotFoundError{Entity: "assignment", Field: "group_id", Value: groupID.String()}
+	}
+	for _, a := range m.assignments[len(m.assignments)-1] {
+		if a.GiverID == giverID {
+			return models.GroupAssignee{GroupID: groupID, DrawID: a.DrawID, UserID: a.ReceiverID, StaleAt: m.draws[len(m.draws)-1].StaleAt}, nil
+		}
+	}
+	return models.GroupAssignee{}, svcErr.NotFoundError{Entity: "assignment", Field: "group_id", Value: groupID.String()}
+}
+
+// newGroupFixture returns a group organized by the first of n accepted members, all with an email
+func newGroupFixture(n int) (*groupStorageMock, []uuid.UUID) {
+	now := time.Now()
+	ids := make([]uuid.UUID, n)
+	gs := &groupStorageMock{group: models.Group{ID: uuid.New(), Title: "Office Secret Santa"}}
+	for i := range ids {
+		ids[i] = uuid.New()
+		gs.members = append(gs.members, models.GroupMember{
+			GroupID:    gs.group.ID,
+			UserID:     ids[i],
+			Name:       "Member",
+			Email:      new("member@example.com"),
+			AcceptedAt: &now,
+		})
+	}
+	gs.group.OrganizerID = ids[0]
+	return gs, ids
+}
+
+// checkDerangement fails unless everyone gives to exactly one other participant and receives from exactly one
+func checkDerangement(t *testing.T, participants []uuid.UUID, pairs []drawPair, excluded map[drawPair]bool) {
+	t.Helper()
+
+	if len(pairs) != len(participants) {
+		t.Fatalf("got %d pairs for %d participants", len(pairs), len(participants))
+	}
+	givers, receivers := make(map[uuid.UUID]bool), make(map[uuid.UUID]bool)
+	for _, p := range pairs {
+		if p.giver == p.receiver {
+			t.Fatalf("%s draws themselves", p.giver)
+		}
+		if excluded[p] {
+			t.Fatalf("%s draws %s despite the exclusion", p.giver, p.receiver)
+		}
+		givers[p.giver], receivers[p.receiver] = true, true
+	}
+	for _, id := range participants {
+		if !givers[id] || !receivers[id] {
+			t.Fatalf("%s does not both give and receive", id)
+		}
+	}
+}
+
+func TestDrawAssignments(t *testing.T) {
+	participants := make([]uuid.UUID, 8)
+	for i := range participants {
+		participants[i] = uuid.UUID{15: byte(i + 1)} // Fixed, so the seeds below always give the same draws
+	}
+	// Two couples that must not draw each other
+	excluded := map[drawPair]bool{
+		{giver: participants[0], receiver: participants[1]}: true,
+		{giver: participants[1], receiver: participants[0]}: true,
+		{giver: participants[2], receiver: participants[3]}: true,
+		{giver: participants[3], receiver: participants[2]}: true,
+	}
+
+	for seed := range int64(50) {
+		pairs, ok := drawAssignments(participants, excluded, seed)
+		if !ok {
+			t.Fatalf("drawAssignments(seed %d) found no draw", seed)
+		}
+		checkDerangement(t, participants, pairs, excluded)
+	}
+
+	first, _ := drawAssignments(participants, excluded, 42)
+	reversed := slices.Clone(participants)
+	slices.Reverse(reversed)
+	again, _ := drawAssignments(reversed, excluded, 42)
+	if !slices.Equal(first, again) {
+		t.Fatalf("drawAssignments() with the same seed = %v, want %v", again, first)
+	}
+
+	other, _ := drawAssignments(participants, excluded, 43)
+	if slices.Equal(first, other) {
+		t.Fatalf("drawAssignments() gave the same draw for different seeds")
+	}
+}
+
+func TestDrawAssignments_Infeasible(t *testing.T) {
+	participants := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	// The first one may give to nobody
+	excluded := map[drawPair]bool{
+		{giver: participants[0], receiver: participants[1]}: true,
+		{giver: participants[0], receiver: participants[2]}: true,
+	}
+
+	if pairs, ok := drawAssignments(participants, excluded, 1); ok {
+		t.Fatalf("drawAssignments() = %v, want no draw", pairs)
+	}
+}
+
+func TestGroupService_CreateGroup(t *testing.T) {
+	gs := &groupStorageMock{}
+	svc := NewGroupService(gs, &listStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{})
+	organizerID := uuid.New()
+
+	group, err := svc.CreateGroup(context.Background(), organizerID, models.CreateGroupRequest{Title: "Office Secret Santa"})
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if group.OrganizerID != organizerID || group.MembersCount != 1 {
+		t.Fatalf("CreateGroup() = %+v, want the organizer counted as the first member", group)
+	}
+	if gs.createdOrganizer.UserID != organizerID || !gs.createdOrganizer.IsAccepted() || gs.createdOrganizer.GroupID != group.ID {
+		t.Fatalf("organizer stored as %+v, want an accepted member", gs.createdOrganizer)
+	}
+}
+
+func TestGroupService_OrganizerOnly(t *testing.T) {
+	gs, ids := newGroupFixture(3)
+	svc := NewGroupService(gs, &listStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{})
+	ctx := context.Background()
+
+	_, err := svc.InviteMember(ctx, gs.group.ID, ids[1], models.InviteGroupMemberRequest{UserID: uuid.New()})
+	if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+		t.Fatalf("InviteMember() by a member error = %v, want ForbiddenError", err)
+	}
+	_, err = svc.Draw(ctx, gs.group.ID, ids[1])
+	if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+		t.Fatalf("Draw() by a member error = %v, want ForbiddenError", err)
+	}
+	_, err = svc.GetDraws(ctx, gs.group.ID, ids[1])
+	if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+		t.Fatalf("GetDraws() by a member error = %v, want ForbiddenError", err)
+	}
+
+	_, err = svc.InviteMember(ctx, gs.group.ID, ids[0], models.InviteGroupMemberRequest{UserID: ids[0]})
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("InviteMember(self) error = %v, want ValidationError", err)
+	}
+	invitedID := uuid.New()
+	if _, err = svc.InviteMember(ctx, gs.group.ID, ids[0], models.InviteGroupMemberRequest{UserID: invitedID}); err != nil {
+		t.Fatalf("InviteMember() error = %v", err)
+	}
+	if gs.addedMember.UserID != invitedID || gs.addedMember.IsAccepted() {
+		t.Fatalf("added %+v, want a pending invitation", gs.addedMember)
+	}
+}
+
+func TestGroupService_RemoveMember(t *testing.T) {
+	gs, ids := newGroupFixture(3)
+	svc := NewGroupService(gs, &listStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{})
+	ctx := context.Background()
+
+	err := svc.RemoveMember(ctx, gs.group.ID, ids[0], ids[0])
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("RemoveMember(organizer) error = %v, want ValidationError", err)
+	}
+	err = svc.RemoveMember(ctx, gs.group.ID, ids[2], ids[1])
+	if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+		t.Fatalf("RemoveMember(other) by a member error = %v, want ForbiddenError", err)
+	}
+	if err = svc.RemoveMember(ctx, gs.group.ID, ids[1], ids[1]); err != nil || gs.deletedMember != ids[1] {
+		t.Fatalf("RemoveMember(self) error = %v, deleted %s", err, gs.deletedMember)
+	}
+}
+
+func TestGroupService_SetMemberList(t *testing.T) {
+	gs, ids := newGroupFixture(3)
+	public := models.List{ID: uuid.New(), UserID: ids[1], Visibility: models.ListVisibilityPublic}
+	private := models.List{ID: uuid.New(), UserID: ids[1], Visibility: models.ListVisibilityPrivate}
+	someoneElses := models.List{ID: uuid.New(), UserID: ids[2], Visibility: models.ListVisibilityPublic}
+	ls := &listStorageMock{listsByID: map[uuid.UUID]models.List{public.ID: public, private.ID: private, someoneElses.ID: someoneElses}}
+	svc := NewGroupService(gs, ls, &userEmailServiceMock{}, &userLoggerMock{})
+	ctx := context.Background()
+
+	err := svc.SetMemberList(ctx, gs.group.ID, ids[1], models.SetGroupListRequest{ListID: &private.ID})
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("SetMemberList(private) error = %v, want ValidationError", err)
+	}
+	err = svc.SetMemberList(ctx, gs.group.ID, ids[1], models.SetGroupListRequest{ListID: &someoneElses.ID})
+	if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+		t.Fatalf("SetMemberList(someone else's) error = %v, want ForbiddenError", err)
+	}
+	err = svc.SetMemberList(ctx, gs.group.ID, uuid.New(), models.SetGroupListRequest{ListID: &public.ID})
+	if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+		t.Fatalf("SetMemberList() by an outsider error = %v, want ForbiddenError", err)
+	}
+
+	if err = svc.SetMemberList(ctx, gs.group.ID, ids[1], models.SetGroupListRequest{ListID: &public.ID}); err != nil || *gs.setListID != public.ID {
+		t.Fatalf("SetMemberList() error = %v, set %v", err, gs.setListID)
+	}
+}
+
+func TestGroupService_AddExclusion(t *testing.T) {
+	gs, ids := newGroupFixture(3)
+	svc := NewGroupService(gs, &listStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{})
+	ctx := context.Background()
+
+	exclusions, err := svc.AddExclusion(ctx, gs.group.ID, ids[0], models.CreateGroupExclusionRequest{GiverID: ids[1], ReceiverID: ids[2], Mutual: true})
+	if err != nil {
+		t.Fatalf("AddExclusion() error = %v", err)
+	}
+	if len(exclusions) != 2 || exclusions[1].GiverID != ids[2] || exclusions[1].ReceiverID != ids[1] {
+		t.Fatalf("AddExclusion() = %+v, want both directions", exclusions)
+	}
+
+	_, err = svc.AddExclusion(ctx, gs.group.ID, ids[0], models.CreateGroupExclusionRequest{GiverID: ids[1], ReceiverID: uuid.New()})
+	if _, ok := errors.AsType[svcErr.NotFoundError](err); !ok {
+		t.Fatalf("AddExclusion(outsider) error = %v, want NotFoundError", err)
+	}
+	_, err = svc.AddExclusion(ctx, gs.group.ID, ids[0], models.CreateGroupExclusionRequest{GiverID: ids[1], ReceiverID: ids[1]})
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("AddExclusion(self) error = %v, want ValidationError", err)
+	}
+}
+
+func TestGroupService_Draw(t *testing.T) {
+	gs, ids := newGroupFixture(5)
+	listID := uuid.New()
+	gs.members[2].ListID = &listID
+	gs.members[3].Email = nil
+	gs.members[4].AcceptedAt = nil // Still invited, left out of the draw
+	gs.exclusions = []models.GroupExclusion{{GiverID: ids[0], ReceiverID: ids[1]}, {GiverID: ids[1], ReceiverID: ids[0]}}
+	mailer := &userEmailServiceMock{}
+	svc := NewGroupService(gs, &listStorageMock{}, mailer, &userLoggerMock{})
+	ctx := context.Background()
+
+	draw, err := svc.Draw(ctx, gs.group.ID, ids[0])
+	if err != nil {
+		t.Fatalf("Draw() error = %v", err)
+	}
+	if draw.Participants != 4 || draw.DrawnBy != ids[0] {
+		t.Fatalf("Draw() = %+v, want the 4 accepted members recorded", draw)
+	}
+
+	assignments := gs.assignments[0]
+	pairs := make([]drawPair, len(assignments))
+	for i, a := range assignments {
+		if a.DrawID != draw.ID {
+			t.Fatalf("assignment %+v does not belong to the draw", a)
+		}
+		pairs[i] = drawPair{giver: a.GiverID, receiver: a.ReceiverID}
+	}
+	excluded := map[drawPair]bool{{giver: ids[0], receiver: ids[1]}: true, {giver: ids[1], receiver: ids[0]}: true}
+	checkDerangement(t, ids[:4], pairs, excluded)
+
+	if len(mailer.groupNotices) != 3 {
+		t.Fatalf("sent %d emails, want one per giver with an email", len(mailer.groupNotices))
+	}
+	for _, notice := range mailer.groupNotices {
+		if notice.GroupTitle != "Office Secret Santa" || notice.GroupID != gs.group.ID {
+			t.Fatalf("notice = %+v, want the group", notice)
+		}
+	}
+	withList := slices.ContainsFunc(mailer.groupNotices, func(n models.GroupAssignmentNotice) bool { return n.ListID != nil && *n.ListID == listID })
+	if givesToListOwner := slices.Contains(pairs, drawPair{giver: ids[3], receiver: ids[2]}); withList == givesToListOwner {
+		t.Fatalf("notices %+v, want the list sent unless its owner was drawn by the member without an email", mailer.groupNotices)
+	}
+
+	// Drawing again keeps the first draw in the audit trail
+	redraw, err := svc.Draw(ctx, gs.group.ID, ids[0])
+	if err != nil {
+		t.Fatalf("Draw() again error = %v", err)
+	}
+	if len(gs.draws) != 2 || redraw.Commitment() == draw.Commitment() {
+		t.Fatalf("%d draws stored, want both with their own commitment", len(gs.draws))
+	}
+
+	assignee, err := svc.GetAssignment(ctx, gs.group.ID, ids[1])
+	if err != nil || assignee.UserID == ids[0] || assignee.UserID == ids[1] {
+		t.Fatalf("GetAssignment() = %+v, %v, want someone but the member and their partner", assignee, err)
+	}
+	_, err = svc.GetAssignment(ctx, gs.group.ID, ids[4])
+	if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+		t.Fatalf("GetAssignment() by an invited user error = %v, want ForbiddenError", err)
+	}
+}
+
+func TestGroupService_GetAssignment_Stale(t *testing.T) {
+	gs, ids := newGroupFixture(3)
+	svc := NewGroupService(gs, &listStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{})
+	ctx := context.Background()
+
+	if _, err := svc.Draw(ctx, gs.group.ID, ids[0]); err != nil {
+		t.Fatalf("Draw() error = %v", err)
+	}
+	gs.draws[0].StaleAt = new(time.Now()) // A member left after the draw
+
+	_, err := svc.GetAssignment(ctx, gs.group.ID, ids[1])
+	if _, ok := errors.AsType[svcErr.ConflictError](err); !ok {
+		t.Fatalf("GetAssignment() from a stale draw error = %v, want ConflictError", err)
+	}
+
+	if _, err = svc.Draw(ctx, gs.group.ID, ids[0]); err != nil {
+		t.Fatalf("Draw() again error = %v", err)
+	}
+	if _, err = svc.GetAssignment(ctx, gs.group.ID, ids[1]); err != nil {
+		t.Fatalf("GetAssignment() after drawing again error = %v", err)
+	}
+}
+
+func TestGroupService_Draw_Rejected(t *testing.T) {
+	ctx := context.Background()
+
+	gs, ids := newGroupFixture(2)
+	svc := NewGroupService(gs, &listStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{})
+	_, err := svc.Draw(ctx, gs.group.ID, ids[0])
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("Draw() with 2 members error = %v, want ValidationError", err)
+	}
+
+	gs, ids = newGroupFixture(3)
+	gs.exclusions = []models.GroupExclusion{{GiverID: ids[0], ReceiverID: ids[1]}, {GiverID: ids[0], ReceiverID: ids[2]}}
+	svc = NewGroupService(gs, &listStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{})
+	_, err = svc.Draw(ctx, gs.group.ID, ids[0])
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("Draw() with impossible exclusions error = %v, want ValidationError", err)
+	}
+	if len(gs.draws) != 0 {
+		t.Fatalf("stored %d draws, want none", len(gs.draws))
+	}
+}
+
+func TestGroupService_Draw_EmailFails(t *testing.T) {
+	gs, ids := newGroupFixture(3)
+	log := &userLoggerMock{}
+	svc := NewGroupService(gs, &listStorageMock{}, &userEmailServiceMock{groupErr: errors.New("broker down")}, log)
+
+	if _, err := svc.Draw(context.Background(), gs.group.ID, ids[0]); err != nil {
+		t.Fatalf("Draw() error = %v, want the draw saved anyway", err)
+	}
+	if len(gs.draws) != 1 || log.calls != 3 {
+		t.Fatalf("stored %d draws and logged %d errors, want the draw and every failed email", len(gs.draws), log.calls)
+	}
+}
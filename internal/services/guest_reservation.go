@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+	"wishlist/internal/utils/str"
+)
+
+type GuestReservationStorage interface {
+	CreateGuestReservation(ctx context.Context, reservation models.GuestReservation, listID uuid.UUID, limits models.GuestReservationLimits) error
+	GetGuestReservation(ctx context.Context, token string) (models.GuestReservationDetails, error)
+	ConfirmGuestReservation(ctx context.Context, token string) error
+	DeleteGuestReservation(ctx context.Context, token string) error
+}
+
+type RateLimiter interface {
+	Hit(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
+
+// GuestReservationServiceImpl lets people without an account reserve wishes of a list shared with them by link
+type GuestReservationServiceImpl struct {
+	reservations  GuestReservationStorage
+	lists         ListStorage
+	wishes        WishStorage
+	email         EmailSender
+	log           Logger
+	limiter       RateLimiter
+	confirmWithin time.Duration
+	limits        models.GuestReservationLimits
+}
+
+func NewGuestReservationService(gs GuestReservationStorage, ls ListStorage, ws WishStorage, es EmailSender, l Logger, rl RateLimiter, confirmWithin time.Duration, limits models.GuestReservationLimits) *GuestReservationServiceImpl {
+	return &GuestReservationServiceImpl{reservations: gs, lists: ls, wishes: ws, email: es, log: l, limiter: rl, confirmWithin: confirmWithin, limits: limits}
+}
+
+// ReserveWish holds units for the guest until they confirm through the emailed link, the token itself is never returned
+// so only someone reading that mailbox can keep or release the reservation. Anyone with the link may call it, so every
+// call counts against the hourly limit of the client address before anything is looked up or sent
+func (svc *GuestReservationServiceImpl) ReserveWish(ctx context.Context, slug string, wishID uuid.UUID, clientIP string, req models.GuestReserveWishRequest) (models.GuestReservationDetails, error) {
+	if svc.limits.PerIPHourly > 0 {
+		allowed, err := svc.limiter.Hit(ctx, "guest_reserve:"+clientIP, svc.limits.PerIPHourly, time.Hour)
+		if err != nil {
+			return models.GuestReservationDetails{}, fmt.Errorf("failed to check reservation limit: %w", err)
+		}
+		if !allowed {
+			return models.GuestReservationDetails{}, svcErr.TooManyRequestsError{Message: "too many reservations from your address, try again later"}
+		}
+	}
+
+	list, err := svc.lists.GetListBySharedLink(ctx, slug)
+	if err != nil {
+		return models.GuestReservationDetails{}, err
+	}
+	if !canReadList(list, true, false) {
+		return models.GuestReservationDetails{}, svcErr.ForbiddenError{Message: "this wishlist is private"}
+	}
+
+	wish, err := svc.wishes.GetWishByID(ctx, wishID)
+	if err != nil {
+		return models.GuestReservationDetails{}, err
+	}
+	if wish.ListID != list.ID {
+		return models.GuestReservationDetails{}, svcErr.ValidationError{Message: "wish does not belong to this list"}
+	}
+	if !wish.Status.AcceptsReservations() {
+		return models.GuestReservationDetails{}, svcErr.ValidationError{Message: "wish is no longer available"}
+	}
+	if wish.Contributed > 0 {
+		return models.GuestReservationDetails{}, svcErr.ValidationError{Message: "wish is already being chipped in for"}
+	}
+
+	name, email := strings.TrimSpace(req.Name), strings.TrimSpace(req.Email)
+	if name == "" {
+		return models.GuestReservationDetails{}, svcErr.ValidationError{Message: "name must not be empty"}
+	}
+
+	units := 1 // default
+	if req.Units > 0 {
+		units = req.Units
+	}
+
+	token, err := str.GenerateRandomString(32)
+	if err != nil {
+		return models.GuestReservationDetails{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	now := time.Now()
+	reservation := models.GuestReservation{
+		WishID:        wishID,
+		Name:          name,
+		Email:         email,
+		Token:         token,
+		Units:         units,
+		ReservedUntil: new(now.Add(svc.confirmWithin)),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err = svc.reservations.CreateGuestReservation(ctx, reservation, list.ID, svc.limits); err != nil {
+		return models.GuestReservationDetails{}, err
+	}
+
+	notice := models.GuestReservationNotice{
+		Name:          name,
+		WishTitle:     wish.Title,
+		ListTitle:     list.Title,
+		Token:         token,
+		ConfirmBefore: *reservation.ReservedUntil,
+	}
+	if err = svc.email.SendGuestReservation(ctx, email, notice); err != nil {
+		// Without the link the guest could neither confirm nor release it, so don't keep the wish held for nothing
+		if releaseErr := svc.reservations.DeleteGuestReservation(ctx, token); releaseErr != nil {
+			svc.log.Error("Failed to release guest reservation of wish '%s' after the link was not sent: %v", wishID, releaseErr)
+		}
+		return models.GuestReservationDetails{}, fmt.Errorf("failed to send guest reservation link: %w", err)
+	}
+
+	return models.GuestReservationDetails{GuestReservation: reservation, WishTitle: wish.Title, WishStatus: wish.Status.Stored(), ListTitle: list.Title, ListSlug: list.Slug}, nil
+}
+
+func (svc *GuestReservationServiceImpl) GetReservation(ctx context.Context, token string) (models.GuestReservationDetails, error) {
+	return svc.reservations.GetGuestReservation(ctx, token)
+}
+
+// ConfirmReservation keeps the reservation without a deadline, confirming twice changes nothing
+func (svc *GuestReservationServiceImpl) ConfirmReservation(ctx context.Context, token string) (models.GuestReservationDetails, error) {
+	if err := svc.reservations.ConfirmGuestReservation(ctx, token); err != nil {
+		return models.GuestReservationDetails{}, err
+	}
+
+	return svc.reservations.GetGuestReservation(ctx, token)
+}
+
+// ReleaseReservation frees the wish again, a wish already bought or received stays with the guest like with any reserver
+func (svc *GuestReservationServiceImpl) ReleaseReservation(ctx context.Context, token string) error {
+	reservation, err := svc.reservations.GetGuestReservation(ctx, token)
+	if err != nil {
+		return err
+	}
+	if !reservation.WishStatus.AcceptsReservations() {
+		return svcErr.ValidationError{Message: "wish is already " + string(reservation.WishStatus)}
+	}
+
+	return svc.reservations.DeleteGuestReservation(ctx, token)
+}
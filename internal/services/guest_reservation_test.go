@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type guestReservationStorageMock struct {
+	createErr  error
+	getErr     error
+	confirmErr error
+
+	created   []models.GuestReservation
+	toReturn  models.GuestReservationDetails
+	confirmed string
+	deleted   []string
+	limits    models.GuestReservationLimits
+}
+
+type rateLimiterMock struct {
+	hits map[string]int
+}
+
+func (m *rateLimiterMock) Hit(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	if m.hits == nil {
+		m.hits = map[string]int{}
+	}
+	m.hits[key]++
+	return m.hits[key] <= limit, nil
+}
+
+func (m *guestReservationStorageMock) CreateGuestReservation(ctx context.Context, reservation models.GuestReservation, listID uuid.UUID, limits models.GuestReservationLimits) error {
+	m.limits = limits
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.created = append(m.created, reservation)
+	return nil
+}
+
+func (m *guestReservationStorageMock) GetGuestReservation(ctx context.Context, token string) (models.GuestReservationDetails, error) {
+	if m.getErr != nil {
+		return models.GuestReservationDetails{}, m.getErr
+	}
+	return m.toReturn, nil
+}
+
+func (m *guestReservationStorageMock) ConfirmGuestReservation(ctx context.Context, token string) error {
+	m.confirmed = token
+	return m.confirmErr
+}
+
+func (m *guestReservationStorageMock) DeleteGuestReservation(ctx context.Context, token string) error {
+	m.deleted = append(m.deleted, token)
+	return nil
+}
+
+func TestGuestReservationService_ReserveWish(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	list := models.List{ID: listID, UserID: uuid.New(), Title: "Birthday", Slug: "secret", Visibility: models.ListVisibilityLinkOnly}
+	wish := models.Wish{ID: wishID, ListID: listID, Title: "Bike", Quantity: 2, Status: models.WishStatusOpen}
+	req := models.GuestReserveWishRequest{Name: "  Aunt Mary ", Email: "mary@example.com"}
+
+	t.Run("holds the wish and emails the link", func(t *testing.T) {
+		guests := &guestReservationStorageMock{}
+		mailer := &userEmailServiceMock{}
+		svc := NewGuestReservationService(guests, &listStorageMock{listToReturn: list}, &wishSvcWishStorageMock{wishToReturn: wish}, mailer, &userLoggerMock{}, nil, 48*time.Hour, models.GuestReservationLimits{})
+
+		reservation, err := svc.ReserveWish(context.Background(), "secret", wishID, "192.0.2.1", req)
+		if err != nil {
+			t.Fatalf("ReserveWish() error = %v", err)
+		}
+		if len(guests.created) != 1 {
+			t.Fatalf("created = %d reservations, want 1", len(guests.created))
+		}
+		created := guests.created[0]
+		if created.WishID != wishID || created.Name != "Aunt Mary" || created.Units != 1 || len(created.Token) != 64 {
+			t.Fatalf("created = %+v, want one unit for the trimmed name with a fresh token", created)
+		}
+		if created.ReservedUntil == nil || created.ReservedUntil.Before(time.Now().Add(47*time.Hour)) {
+			t.Fatalf("ReservedUntil = %v, want the confirmation deadline", created.ReservedUntil)
+		}
+		if mailer.guestTo != "mary@example.com" || len(mailer.guestNotices) != 1 || mailer.guestNotices[0].Token != created.Token || mailer.guestNotices[0].WishTitle != "Bike" {
+			t.Fatalf("mail to %q with %+v, want the token sent to the guest", mailer.guestTo, mailer.guestNotices)
+		}
+		if response := reservation.ToResponse(); response.Confirmed || response.ListSlug != "secret" {
+			t.Fatalf("response = %+v, want a pending reservation on the shared list", response)
+		}
+	})
+
+	t.Run("list not shared with guests", func(t *testing.T) {
+		guests := &guestReservationStorageMock{}
+		private := list
+		private.Visibility = models.ListVisibilityPrivate
+		svc := NewGuestReservationService(guests, &listStorageMock{listToReturn: private}, &wishSvcWishStorageMock{wishToReturn: wish}, &userEmailServiceMock{}, &userLoggerMock{}, nil, time.Hour, models.GuestReservationLimits{})
+
+		_, err := svc.ReserveWish(context.Background(), "secret", wishID, "192.0.2.1", req)
+		if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok || len(guests.created) != 0 {
+			t.Fatalf("err = %v, created = %d, want ForbiddenError and nothing reserved", err, len(guests.created))
+		}
+	})
+
+	t.Run("wish of another list", func(t *testing.T) {
+		guests := &guestReservationStorageMock{}
+		other := wish
+		other.ListID = uuid.New()
+		svc := NewGuestReservationService(guests, &listStorageMock{listToReturn: list}, &wishSvcWishStorageMock{wishToReturn: other}, &userEmailServiceMock{}, &userLoggerMock{}, nil, time.Hour, models.GuestReservationLimits{})
+
+		_, err := svc.ReserveWish(context.Background(), "secret", wishID, "192.0.2.1", req)
+		if _, ok := errors.AsType[svcErr.ValidationError](err); !ok || len(guests.created) != 0 {
+			t.Fatalf("err = %v, created = %d, want ValidationError and nothing reserved", err, len(guests.created))
+		}
+	})
+
+	t.Run("blank name", func(t *testing.T) {
+		guests := &guestReservationStorageMock{}
+		svc := NewGuestReservationService(guests, &listStorageMock{listToReturn: list}, &wishSvcWishStorageMock{wishToReturn: wish}, &userEmailServiceMock{}, &userLoggerMock{}, nil, time.Hour, models.GuestReservationLimits{})
+
+		_, err := svc.ReserveWish(context.Background(), "secret", wishID, "192.0.2.1", models.GuestReserveWishRequest{Name: "   ", Email: "mary@example.com"})
+		if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+			t.Fatalf("err = %v, want ValidationError", err)
+		}
+	})
+
+	t.Run("link not sent releases the wish", func(t *testing.T) {
+		guests := &guestReservationStorageMock{}
+		svc := NewGuestReservationService(guests, &listStorageMock{listToReturn: list}, &wishSvcWishStorageMock{wishToReturn: wish}, &userEmailServiceMock{guestErr: errors.New("smtp down")}, &userLoggerMock{}, nil, time.Hour, models.GuestReservationLimits{})
+
+		if _, err := svc.ReserveWish(context.Background(), "secret", wishID, "192.0.2.1", req); err == nil {
+			t.Fatal("ReserveWish() error = nil, want the send error")
+		}
+		if len(guests.created) != 1 || len(guests.deleted) != 1 || guests.deleted[0] != guests.created[0].Token {
+			t.Fatalf("created = %d, deleted = %v, want the reservation released again", len(guests.created), guests.deleted)
+		}
+	})
+}
+
+func TestGuestReservationService_ReserveWish_Limits(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	list := models.List{ID: listID, UserID: uuid.New(), Title: "Birthday", Slug: "secret", Visibility: models.ListVisibilityLinkOnly}
+	wish := models.Wish{ID: wishID, ListID: listID, Title: "Bike", Quantity: 5, Status: models.WishStatusOpen}
+	req := models.GuestReserveWishRequest{Name: "Aunt Mary", Email: "mary@example.com"}
+	limits := models.GuestReservationLimits{PerIPHourly: 2, PerList: 10, PerEmail: 1}
+
+	guests := &guestReservationStorageMock{}
+	mailer := &userEmailServiceMock{}
+	svc := NewGuestReservationService(guests, &listStorageMock{listToReturn: list}, &wishSvcWishStorageMock{wishToReturn: wish}, mailer, &userLoggerMock{}, &rateLimiterMock{}, time.Hour, limits)
+
+	for range 2 {
+		if _, err := svc.ReserveWish(context.Background(), "secret", wishID, "192.0.2.1", req); err != nil {
+			t.Fatalf("ReserveWish() error = %v", err)
+		}
+	}
+	if guests.limits != limits {
+		t.Fatalf("storage got limits %+v, want %+v for the unconfirmed reservations", guests.limits, limits)
+	}
+
+	_, err := svc.ReserveWish(context.Background(), "secret", wishID, "192.0.2.1", req)
+	if _, ok := errors.AsType[svcErr.TooManyRequestsError](err); !ok {
+		t.Fatalf("ReserveWish() over the hourly limit error = %v, want TooManyRequestsError", err)
+	}
+	if len(guests.created) != 2 || len(mailer.guestNotices) != 2 {
+		t.Fatalf("created %d reservations and sent %d emails, want nothing more over the limit", len(guests.created), len(mailer.guestNotices))
+	}
+
+	if _, err = svc.ReserveWish(context.Background(), "secret", wishID, "198.51.100.7", req); err != nil {
+		t.Fatalf("ReserveWish() from another address error = %v", err)
+	}
+}
+
+func TestGuestReservationService_ReleaseReservation(t *testing.T) {
+	t.Run("open wish", func(t *testing.T) {
+		guests := &guestReservationStorageMock{toReturn: models.GuestReservationDetails{WishStatus: models.WishStatusOpen}}
+		svc := NewGuestReservationService(guests, &listStorageMock{}, &wishSvcWishStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{}, nil, time.Hour, models.GuestReservationLimits{})
+
+		if err := svc.ReleaseReservation(context.Background(), "token"); err != nil {
+			t.Fatalf("ReleaseReservation() error = %v", err)
+		}
+		if len(guests.deleted) != 1 || guests.deleted[0] != "token" {
+			t.Fatalf("deleted = %v, want the reservation of the token", guests.deleted)
+		}
+	})
+
+	t.Run("wish already purchased", func(t *testing.T) {
+		guests := &guestReservationStorageMock{toReturn: models.GuestReservationDetails{WishStatus: models.WishStatusPurchased}}
+		svc := NewGuestReservationService(guests, &listStorageMock{}, &wishSvcWishStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{}, nil, time.Hour, models.GuestReservationLimits{})
+
+		err := svc.ReleaseReservation(context.Background(), "token")
+		if _, ok := errors.AsType[svcErr.ValidationError](err); !ok || len(guests.deleted) != 0 {
+			t.Fatalf("err = %v, deleted = %v, want ValidationError and the reservation kept", err, guests.deleted)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		guests := &guestReservationStorageMock{getErr: svcErr.NotFoundError{Entity: "reservation", Field: "token", Value: "token"}}
+		svc := NewGuestReservationService(guests, &listStorageMock{}, &wishSvcWishStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{}, nil, time.Hour, models.GuestReservationLimits{})
+
+		err := svc.ReleaseReservation(context.Background(), "token")
+		if _, ok := errors.AsType[svcErr.NotFoundError](err); !ok {
+			t.Fatalf("err = %v, want NotFoundError", err)
+		}
+	})
+}
+
+func TestGuestReservationService_ConfirmReservation(t *testing.T) {
+	guests := &guestReservationStorageMock{toReturn: models.GuestReservationDetails{GuestReservation: models.GuestReservation{ConfirmedAt: new(time.Now())}}}
+	svc := NewGuestReservationService(guests, &listStorageMock{}, &wishSvcWishStorageMock{}, &userEmailServiceMock{}, &userLoggerMock{}, nil, time.Hour, models.GuestReservationLimits{})
+
+	reservation, err := svc.ConfirmReservation(context.Background(), "token")
+	if err != nil {
+		t.Fatalf("ConfirmReservation() error = %v", err)
+	}
+	if guests.confirmed != "token" || !reservation.ToResponse().Confirmed {
+		t.Fatalf("confirmed = %q, reservation = %+v, want the confirmed reservation of the token", guests.confirmed, reservation)
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"wishlist/internal/imaging"
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+// uploadImage runs the picture through the image pipeline and stores each variant under base,
+// the key of the original is what gets saved on the user or the wish. Whatever content type the client declared,
+// the format is told from the bytes, so callers don't pass one
+func uploadImage(ctx context.Context, s3 AvatarStorage, base string, reader io.Reader) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	variants, err := imaging.Process(data)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrUnsupported):
+			return "", svcErr.ValidationError{Message: "unsupported image format (PNG, JPG, WEBP or GIF only)"}
+		case errors.Is(err, imaging.ErrTooLarge):
+			return "", svcErr.ValidationError{Message: "image dimensions are too large"}
+		}
+		return "", err
+	}
+
+	for i, variant := range imaging.Variants {
+		encoded := variants[variant]
+		if err = s3.UploadObject(ctx, imaging.ObjectName(base, variant), bytes.NewReader(encoded.Data), int64(len(encoded.Data)), encoded.ContentType); err != nil {
+			for _, uploaded := range imaging.Variants[:i] { // Don't leave half an image behind
+				_ = s3.DeleteObject(ctx, imaging.ObjectName(base, uploaded))
+			}
+			return "", err
+		}
+	}
+
+	return imaging.ObjectName(base, imaging.Original), nil
+}
+
+// copyImage copies every variant of a stored image under base, so the copy can be deleted on its own. Links to other
+// sites are not ours to copy and come back as they are
+func copyImage(ctx context.Context, s3 AvatarStorage, stored, base string) (string, error) {
+	objectName := storedObjectName(stored, s3.GetBaseURL())
+	if objectName == "" {
+		return stored, nil
+	}
+
+	source := imaging.BaseName(objectName)
+	if source == objectName { // From before the pipeline, a single object
+		if err := s3.CopyObject(ctx, objectName, base); err != nil {
+			return "", err
+		}
+		return base, nil
+	}
+
+	for i, variant := range imaging.Variants {
+		if err := s3.CopyObject(ctx, imaging.ObjectName(source, variant), imaging.ObjectName(base, variant)); err != nil {
+			for _, copied := range imaging.Variants[:i] { // Don't leave half an image behind
+				_ = s3.DeleteObject(ctx, imaging.ObjectName(base, copied))
+			}
+			return "", err
+		}
+	}
+
+	return imaging.ObjectName(base, imaging.Original), nil
+}
+
+// deleteImage removes every variant of a stored avatar or wish image
+func deleteImage(ctx context.Context, s3 AvatarStorage, stored string) error {
+	objectName := storedObjectName(stored, s3.GetBaseURL())
+	if objectName == "" {
+		return nil
+	}
+
+	var errs []error
+	for _, name := range imageObjectNames(objectName) {
+		if err := s3.DeleteObject(ctx, name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// storedObjectName returns the object key of an avatar or wish image. Keys are what is stored, URLs of our bucket
+// come from before that or from a client sending back what it was given; links to other sites give ""
+func storedObjectName(value, baseURL string) string {
+	if !models.IsExternalURL(value) {
+		return value
+	}
+	if !strings.HasPrefix(value, strings.TrimSuffix(baseURL, "/")+"/") {
+		return ""
+	}
+	return extractObjectNameFromURL(value, baseURL)
+}
+
+// wishImageKey returns the key of an image we host for wishes. Anything else in the bucket, avatars, pending uploads
+// or data exports, must not end up on a wish where mediaURL would hand out a link to it
+func wishImageKey(value, baseURL string) (string, bool) {
+	key := storedObjectName(value, baseURL)
+	if !strings.HasPrefix(key, "wishes/") || strings.Contains(key, "..") {
+		return "", false
+	}
+	return key, true
+}
+
+// imageObjectNames returns the keys of every variant of the image, images from before the pipeline are a single object
+func imageObjectNames(objectName string) []string {
+	base := imaging.BaseName(objectName)
+	if base == objectName {
+		return []string{objectName}
+	}
+
+	names := make([]string, 0, len(imaging.Variants))
+	for _, variant := range imaging.Variants {
+		names = append(names, imaging.ObjectName(base, variant))
+	}
+	return names
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 2))); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestWishService_UpdateWishImage(t *testing.T) {
+	listID := uuid.New()
+	ownerID := uuid.New()
+	wishID := uuid.New()
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
+
+	t.Run("stores every variant", func(t *testing.T) {
+		s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+		records := &objectRecordStorageMock{}
+		svc := NewWishService(&wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID}}, listStorage, &listMemberStorageMock{}, nil, s3, nil, nil, NewObjectTracker(records, s3, &userLoggerMock{}), nil)
+
+		if err := svc.UpdateWishImage(context.Background(), listID, wishID, ownerID, bytes.NewReader(testPNG(t))); err != nil {
+			t.Fatalf("UpdateWishImage() error = %v", err)
+		}
+		if len(records.recorded) != 1 || *records.recorded[0].Owner.UserID != ownerID || *records.recorded[0].Owner.WishID != wishID {
+			t.Fatalf("recorded %+v, want the new image owned by the list owner and the wish", records.recorded)
+		}
+		if records.owner.WishID == nil || *records.owner.WishID != wishID {
+			t.Fatalf("released %+v, want the previous images of the wish", records.owner)
+		}
+		if len(s3.uploadedObjects) != 3 {
+			t.Fatalf("uploaded %v, want original, medium and thumbnail", s3.uploadedObjects)
+		}
+		for _, name := range s3.uploadedObjects {
+			if !strings.HasPrefix(name, "wishes/"+wishID.String()+"/") {
+				t.Fatalf("uploaded %q, want it under the wish", name)
+			}
+		}
+		if s3.uploadedType != "image/png" {
+			t.Fatalf("content type = %s, want the sniffed image/png", s3.uploadedType)
+		}
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+		svc := NewWishService(&wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID}}, listStorage, &listMemberStorageMock{}, nil, s3, nil, nil, nil, nil)
+
+		err := svc.UpdateWishImage(context.Background(), listID, wishID, ownerID, strings.NewReader("MZ\x90\x00"))
+		if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+			t.Fatalf("UpdateWishImage() error = %v, want ValidationError", err)
+		}
+		if len(s3.uploadedObjects) != 0 {
+			t.Fatalf("uploaded %v, want nothing", s3.uploadedObjects)
+		}
+	})
+
+	t.Run("failed upload cleans up", func(t *testing.T) {
+		s3 := &failingAfterUploadsMock{userAvatarStorageMock: userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}, allowed: 1}
+		svc := NewWishService(&wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID}}, listStorage, &listMemberStorageMock{}, nil, s3, nil, nil, nil, nil)
+
+		if err := svc.UpdateWishImage(context.Background(), listID, wishID, ownerID, bytes.NewReader(testPNG(t))); err == nil {
+			t.Fatal("UpdateWishImage() error = nil, want upload error")
+		}
+		if len(s3.deletedObjs) != 1 || s3.deletedObjs[0] != s3.uploadedObjects[0] {
+			t.Fatalf("deleted %v, want the variant that made it to S3", s3.deletedObjs)
+		}
+	})
+}
+
+type failingAfterUploadsMock struct {
+	userAvatarStorageMock
+	allowed int
+}
+
+func (m *failingAfterUploadsMock) UploadObject(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) error {
+	if len(m.uploadedObjects) >= m.allowed {
+		return errors.New("s3 unavailable")
+	}
+	return m.userAvatarStorageMock.UploadObject(ctx, objectName, reader, size, contentType)
+}
+
+func TestDeleteImage(t *testing.T) {
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+
+	if err := deleteImage(context.Background(), s3, "http://minio:9000/wishlist/avatars/u/1/original"); err != nil {
+		t.Fatalf("deleteImage() error = %v", err)
+	}
+	if strings.Join(s3.deletedObjs, ",") != "avatars/u/1/original,avatars/u/1/medium,avatars/u/1/thumbnail" {
+		t.Fatalf("deleted %v, want every variant", s3.deletedObjs)
+	}
+
+	s3.deletedObjs = nil
+	if err := deleteImage(context.Background(), s3, "http://minio:9000/wishlist/avatars/u/legacy"); err != nil {
+		t.Fatalf("deleteImage(legacy) error = %v", err)
+	}
+	if len(s3.deletedObjs) != 1 || s3.deletedObjs[0] != "avatars/u/legacy" {
+		t.Fatalf("deleted %v, want the single legacy object", s3.deletedObjs)
+	}
+}
+
+func TestStoredObjectName(t *testing.T) {
+	base := "http://minio:9000/wishlist"
+	tests := map[string]string{
+		"wishes/w/1/original":                                 "wishes/w/1/original",
+		base + "/wishes/w/1/original":                         "wishes/w/1/original",
+		base + "/wishes/w/1/original?X-Amz-Signature=abc&X=1": "wishes/w/1/original",
+		"https://shop.example/picture.jpg":                    "",
+	}
+	for stored, want := range tests {
+		if got := storedObjectName(stored, base); got != want {
+			t.Fatalf("storedObjectName(%q) = %q, want %q", stored, got, want)
+		}
+	}
+
+	// Filesystem driver, files are served by the API itself
+	filesBase := "https://wishlist.example/files"
+	if got := storedObjectName(filesBase+"/avatars/u/1/original?expires=1&signature=abc", filesBase); got != "avatars/u/1/original" {
+		t.Fatalf("storedObjectName(files URL) = %q", got)
+	}
+	if got := storedObjectName("https://wishlist.example/static/logo.png", filesBase); got != "" {
+		t.Fatalf("storedObjectName(static URL) = %q, want \"\"", got)
+	}
+}
@@ -2,34 +2,44 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"wishlist/internal/models"
 	"wishlist/internal/services/errors"
+	"wishlist/internal/storage"
 	"wishlist/internal/utils/str"
 )
 
 type ListStorage interface {
 	CreateList(ctx context.Context, list models.List) error
+	CreateListWithWishes(ctx context.Context, list models.List, wishes []models.Wish) error
 	GetListByID(ctx context.Context, id uuid.UUID) (models.List, error)
 	GetListBySharedLink(ctx context.Context, slug string) (models.List, error)
 	GetListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error)
-	GetPublicListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error)
+	GetPublicListsByUserID(ctx context.Context, userID uuid.UUID, withFollowers bool) ([]models.List, error)
 	UpdateListByID(ctx context.Context, id uuid.UUID, req models.UpdateListRequest) error
 	RotateSharedLink(ctx context.Context, id uuid.UUID, slug string) error
 	DeleteListByID(ctx context.Context, id uuid.UUID) error
 }
 
 type ListServiceImpl struct {
-	lists  ListStorage
-	wishes WishStorage
+	lists    ListStorage
+	wishes   WishStorage
+	members  ListMemberStorage
+	follows  FollowStorage
+	s3       AvatarStorage
+	prices   *PriceConverter
+	objects  *ObjectTracker
+	activity *ActivityRecorder
 }
 
-func NewListService(ls ListStorage, ws WishStorage) *ListServiceImpl {
-	return &ListServiceImpl{lists: ls, wishes: ws}
+func NewListService(ls ListStorage, ws WishStorage, ms ListMemberStorage, fs FollowStorage, s3 AvatarStorage, pc *PriceConverter, ot *ObjectTracker, ar *ActivityRecorder) *ListServiceImpl {
+	return &ListServiceImpl{lists: ls, wishes: ws, members: ms, follows: fs, s3: s3, prices: pc, objects: ot, activity: ar}
 }
 
 func (svc *ListServiceImpl) CreateList(ctx context.Context, userID uuid.UUID, req models.CreateListRequest) (models.List, error) {
@@ -38,20 +48,35 @@ func (svc *ListServiceImpl) CreateList(ctx context.Context, userID uuid.UUID, re
 		return models.List{}, fmt.Errorf("failed to generate slug: %w", err)
 	}
 
+	visibility := models.ListVisibilityPublic // default
+	if req.Visibility != nil {
+		visibility = *req.Visibility
+	}
+
+	occasionDate, err := parseOccasionDate(req.OccasionDate)
+	if err != nil {
+		return models.List{}, err
+	}
+
 	list := models.List{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Title:     req.Title,
-		Notes:     req.Notes,
-		IsPublic:  true, // default
-		Slug:      slug,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:         uuid.New(),
+		UserID:     userID,
+		Title:      req.Title,
+		Notes:      req.Notes,
+		Visibility: visibility,
+		Slug:       slug,
+		Role:       models.ListRoleOwner,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+
+		OccasionDate:         occasionDate,
+		OccasionRecursYearly: req.OccasionRecursYearly != nil && *req.OccasionRecursYearly,
 	}
 
 	if err = svc.lists.CreateList(ctx, list); err != nil {
 		return models.List{}, err
 	}
+	svc.activity.RecordList(ctx, userID, models.ActivityListCreated, list)
 
 	return list, nil
 }
@@ -62,42 +87,81 @@ func (svc *ListServiceImpl) GetListByID(ctx context.Context, listID, requestedBy
 		return models.List{}, err
 	}
 
-	if list.UserID != requestedByUserID && !list.IsPublic {
+	list.Role, err = resolveListRole(ctx, svc.members, list, requestedByUserID)
+	if err != nil {
+		return models.List{}, err
+	}
+
+	following, err := followsListOwner(ctx, svc.follows, list, requestedByUserID)
+	if err != nil {
+		return models.List{}, err
+	}
+
+	if !canReadList(list, false, following) {
 		return models.List{}, svcErr.ForbiddenError{Message: "this wishlist is private"}
 	}
 
 	return list, nil
 }
 
-func (svc *ListServiceImpl) GetListBySharedLink(ctx context.Context, slug string) (models.List, error) {
-	return svc.lists.GetListBySharedLink(ctx, slug)
+func (svc *ListServiceImpl) GetListBySharedLink(ctx context.Context, slug string, requestedByUserID *uuid.UUID) (models.List, error) {
+	list, err := svc.lists.GetListBySharedLink(ctx, slug)
+	if err != nil {
+		return models.List{}, err
+	}
+
+	var following bool
+	if requestedByUserID != nil {
+		if list.Role, err = resolveListRole(ctx, svc.members, list, *requestedByUserID); err != nil {
+			return models.List{}, err
+		}
+		if following, err = followsListOwner(ctx, svc.follows, list, *requestedByUserID); err != nil {
+			return models.List{}, err
+		}
+	}
+
+	if !canReadList(list, true, following) {
+		return models.List{}, svcErr.ForbiddenError{Message: "this wishlist is private"}
+	}
+
+	return list, nil
 }
 
-func (svc *ListServiceImpl) GetListWithWishes(ctx context.Context, listID, requestedByUserID uuid.UUID) (models.List, []models.Wish, error) {
+func (svc *ListServiceImpl) GetListWithWishes(ctx context.Context, listID, requestedByUserID uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
 	list, err := svc.GetListByID(ctx, listID, requestedByUserID)
 	if err != nil {
 		return models.List{}, nil, err
 	}
 
-	wishes, err := svc.wishes.GetWishesByListID(ctx, listID)
+	wishes, err := svc.wishes.GetWishesByListID(ctx, listID, filter)
 	if err != nil {
 		return models.List{}, nil, err
 	}
 
+	if list.Total, err = svc.prices.ConvertWishes(ctx, &requestedByUserID, wishes); err != nil {
+		return models.List{}, nil, err
+	}
+	sortByConvertedPrice(wishes, filter)
+
 	return list, wishes, nil
 }
 
-func (svc *ListServiceImpl) GetListWithWishesBySharedLink(ctx context.Context, slug string) (models.List, []models.Wish, error) {
-	list, err := svc.lists.GetListBySharedLink(ctx, slug)
+func (svc *ListServiceImpl) GetListWithWishesBySharedLink(ctx context.Context, slug string, requestedByUserID *uuid.UUID, filter models.WishFilter) (models.List, []models.Wish, error) {
+	list, err := svc.GetListBySharedLink(ctx, slug, requestedByUserID)
 	if err != nil {
 		return models.List{}, nil, err
 	}
 
-	wishes, err := svc.wishes.GetWishesByListID(ctx, list.ID)
+	wishes, err := svc.wishes.GetWishesByListID(ctx, list.ID, filter)
 	if err != nil {
 		return models.List{}, nil, err
 	}
 
+	if list.Total, err = svc.prices.ConvertWishes(ctx, requestedByUserID, wishes); err != nil {
+		return models.List{}, nil, err
+	}
+	sortByConvertedPrice(wishes, filter)
+
 	return list, wishes, nil
 }
 
@@ -105,8 +169,18 @@ func (svc *ListServiceImpl) GetCurrentUserLists(ctx context.Context, userID uuid
 	return svc.lists.GetListsByUserID(ctx, userID)
 }
 
-func (svc *ListServiceImpl) GetPublicListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
-	return svc.lists.GetPublicListsByUserID(ctx, userID)
+// GetPublicListsByUserID returns the lists on the user's profile, accepted followers also see the followers-only ones
+func (svc *ListServiceImpl) GetPublicListsByUserID(ctx context.Context, userID, requestedByUserID uuid.UUID) ([]models.List, error) {
+	var following bool
+	if userID != requestedByUserID {
+		status, err := followStatus(ctx, svc.follows, requestedByUserID, userID)
+		if err != nil {
+			return nil, err
+		}
+		following = status == models.FollowStatusAccepted
+	}
+
+	return svc.lists.GetPublicListsByUserID(ctx, userID, following)
 }
 
 func (svc *ListServiceImpl) UpdateList(ctx context.Context, listID, userID uuid.UUID, req models.UpdateListRequest) error {
@@ -115,11 +189,34 @@ func (svc *ListServiceImpl) UpdateList(ctx context.Context, listID, userID uuid.
 		return err
 	}
 
-	if list.UserID != userID {
-		return svcErr.ForbiddenError{Message: "you are not the owner of this wishlist"}
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return svcErr.ForbiddenError{Message: "you are not allowed to edit this wishlist"}
+	}
+
+	if req.Visibility == nil && req.IsPublic != nil {
+		visibility := models.ListVisibilityLinkOnly
+		if *req.IsPublic {
+			visibility = models.ListVisibilityPublic
+		}
+		req.Visibility = &visibility
+	}
+	if _, err = parseOccasionDate(req.OccasionDate); err != nil {
+		return err
 	}
 
-	return svc.lists.UpdateListByID(ctx, listID, req)
+	if err = svc.lists.UpdateListByID(ctx, listID, req); err != nil {
+		return err
+	}
+	if req.Title != nil {
+		list.Title = *req.Title
+	}
+	svc.activity.RecordList(ctx, userID, models.ActivityListUpdated, list)
+
+	return nil
 }
 
 func (svc *ListServiceImpl) RotateSharedLink(ctx context.Context, listID, userID uuid.UUID) (string, error) {
@@ -128,8 +225,12 @@ func (svc *ListServiceImpl) RotateSharedLink(ctx context.Context, listID, userID
 		return "", err
 	}
 
-	if list.UserID != userID {
-		return "", svcErr.ForbiddenError{Message: "you are not the owner of this wishlist"}
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return "", err
+	}
+	if !role.CanEdit() {
+		return "", svcErr.ForbiddenError{Message: "you are not allowed to edit this wishlist"}
 	}
 
 	slug, err := str.GenerateRandomString(16)
@@ -150,9 +251,235 @@ func (svc *ListServiceImpl) DeleteList(ctx context.Context, listID, userID uuid.
 		return err
 	}
 
-	if list.UserID != userID {
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanManage() {
 		return svcErr.ForbiddenError{Message: "you are not the owner of this wishlist"}
 	}
 
+	// The list goes to the trash, TrashPurgeJob removes it and the images of its wishes after the retention period
 	return svc.lists.DeleteListByID(ctx, listID)
 }
+
+// DuplicateList copies a list the user can read into a new list of theirs, e.g. last year's birthday list as a template.
+// The copy starts over: archived wishes are left out, the rest are open with no reservations or contributions, and
+// their images are copied so that deleting one list never takes the pictures of the other
+func (svc *ListServiceImpl) DuplicateList(ctx context.Context, listID, userID uuid.UUID, req models.DuplicateListRequest) (models.List, error) {
+	source, err := svc.GetListByID(ctx, listID, userID)
+	if err != nil {
+		return models.List{}, err
+	}
+
+	wishes, err := svc.wishes.GetWishesByListID(ctx, listID, models.WishFilter{})
+	if err != nil {
+		return models.List{}, err
+	}
+
+	slug, err := str.GenerateRandomString(16)
+	if err != nil {
+		return models.List{}, fmt.Errorf("failed to generate slug: %w", err)
+	}
+
+	list := models.List{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Image:      source.Image,
+		Title:      source.Title,
+		Notes:      source.Notes,
+		Visibility: models.ListVisibilityPrivate, // Shared once the user is done editing it
+		Slug:       slug,
+		Role:       models.ListRoleOwner,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if req.Title != nil && strings.TrimSpace(*req.Title) != "" {
+		list.Title = *req.Title
+	}
+	if req.Visibility != nil {
+		list.Visibility = *req.Visibility
+	}
+	if source.UserID == userID { // Someone else's birthday is not the occasion of the copy
+		list.OccasionDate, list.OccasionRecursYearly = source.OccasionDate, source.OccasionRecursYearly
+	}
+
+	copies := make([]models.Wish, 0, len(wishes))
+	for _, wish := range wishes {
+		if wish.Status == models.WishStatusArchived {
+			continue
+		}
+		copied, err := svc.copyWish(ctx, wish, list.ID, source.Role.CanEdit())
+		if err != nil {
+			svc.discardImages(ctx, wishes, copies)
+			return models.List{}, err
+		}
+		copies = append(copies, copied)
+	}
+
+	if err = svc.lists.CreateListWithWishes(ctx, list, copies); err != nil {
+		svc.discardImages(ctx, wishes, copies)
+		return models.List{}, err
+	}
+	for _, wish := range copies {
+		if wish.Image != nil {
+			svc.objects.Track(ctx, *wish.Image, wishImageOwner(list, wish.ID))
+		}
+	}
+	list.WishesCount = len(copies)
+	svc.activity.RecordList(ctx, userID, models.ActivityListCreated, list)
+
+	return list, nil
+}
+
+// CopyWish copies a wish of a list the user can read to the end of a list they can edit, e.g. a friend's wish into
+// their own list. Reservations and contributions stay with the original, the image is copied
+func (svc *ListServiceImpl) CopyWish(ctx context.Context, listID, wishID, targetListID, userID uuid.UUID) (models.Wish, error) {
+	source, err := svc.GetListByID(ctx, listID, userID)
+	if err != nil {
+		return models.Wish{}, err
+	}
+
+	wish, err := svc.wishes.GetWishByID(ctx, wishID)
+	if err != nil {
+		return models.Wish{}, err
+	}
+	if wish.ListID != listID {
+		return models.Wish{}, svcErr.ValidationError{Message: "wish does not belong to this list"}
+	}
+
+	target, err := svc.lists.GetListByID(ctx, targetListID)
+	if err != nil {
+		return models.Wish{}, err
+	}
+	role, err := resolveListRole(ctx, svc.members, target, userID)
+	if err != nil {
+		return models.Wish{}, err
+	}
+	if !role.CanEdit() {
+		return models.Wish{}, svcErr.ForbiddenError{Message: "you are not allowed to edit this wishlist"}
+	}
+
+	copied, err := svc.copyWish(ctx, wish, target.ID, source.Role.CanEdit())
+	if err != nil {
+		return models.Wish{}, err
+	}
+
+	if err = svc.wishes.CreateWish(ctx, copied); err != nil {
+		svc.discardImages(ctx, []models.Wish{wish}, []models.Wish{copied})
+		return models.Wish{}, err
+	}
+	if copied.Image != nil {
+		svc.objects.Track(ctx, *copied.Image, wishImageOwner(target, copied.ID))
+	}
+	svc.activity.RecordWish(ctx, userID, models.ActivityWishCreated, copied)
+
+	return copied, nil
+}
+
+// copyWish makes a new open wish out of wish for the list, the price alert is kept only when the user could see it
+func (svc *ListServiceImpl) copyWish(ctx context.Context, wish models.Wish, listID uuid.UUID, keepPriceAlert bool) (models.Wish, error) {
+	copied := models.Wish{
+		ID:        uuid.New(),
+		ListID:    listID,
+		Title:     wish.Title,
+		Notes:     wish.Notes,
+		Link:      wish.Link,
+		Price:     wish.Price,
+		Currency:  wish.Currency,
+		Quantity:  wish.Quantity,
+		Status:    models.WishStatusOpen,
+		Priority:  wish.Priority,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if keepPriceAlert {
+		copied.PriceAlert = wish.PriceAlert
+	}
+
+	if wish.Image != nil {
+		image, err := copyImage(ctx, svc.s3, *wish.Image, fmt.Sprintf(storage.WishImagePrefix, copied.ID, uuid.NewString()))
+		if err != nil {
+			if _, ok := errors.AsType[svcErr.NotFoundError](err); !ok {
+				return models.Wish{}, fmt.Errorf("failed to copy wish image: %w", err)
+			}
+			image = "" // Already gone from S3, the copy does fine without a picture
+		}
+		if image != "" {
+			copied.Image = &image
+		}
+	}
+
+	return copied, nil
+}
+
+// discardImages removes the images copied for wishes that were never saved, links to other sites were not copied
+func (svc *ListServiceImpl) discardImages(ctx context.Context, originals, copies []models.Wish) {
+	kept := make(map[string]struct{}, len(originals))
+	for _, wish := range originals {
+		if wish.Image != nil {
+			kept[*wish.Image] = struct{}{}
+		}
+	}
+
+	for _, wish := range copies {
+		if wish.Image == nil {
+			continue
+		}
+		if _, ok := kept[*wish.Image]; !ok {
+			_ = deleteImage(ctx, svc.s3, *wish.Image)
+		}
+	}
+}
+
+// parseOccasionDate reads the date of a request, nil when there is none or it is being removed
+func parseOccasionDate(value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(models.OccasionDateLayout, *value)
+	if err != nil {
+		return nil, svcErr.ValidationError{Message: "invalid occasion date, expected YYYY-MM-DD"}
+	}
+	return &date, nil
+}
+
+// canReadList decides whether the requester may see the list, viaSharedLink is set when it was opened by slug
+// and following when the requester is an accepted follower of the owner
+func canReadList(list models.List, viaSharedLink, following bool) bool {
+	if list.Role != "" {
+		return true // Members see the list regardless of its visibility
+	}
+
+	switch list.Visibility {
+	case models.ListVisibilityPublic:
+		return true
+	case models.ListVisibilityLinkOnly:
+		return viaSharedLink
+	case models.ListVisibilityFollowers:
+		return following // The shared link alone is not enough here
+	default:
+		return false
+	}
+}
+
+// resolveReadableListRole resolves the role userID has on the list like resolveListRole, but refuses outsiders
+// the list is hidden from. The ID of a link-only list only reaches those who opened its shared link, so it counts as one
+func resolveReadableListRole(ctx context.Context, members ListMemberStorage, follows FollowStorage, list models.List, userID uuid.UUID) (models.ListRole, error) {
+	role, err := resolveListRole(ctx, members, list, userID)
+	if err != nil {
+		return "", err
+	}
+	list.Role = role
+
+	following, err := followsListOwner(ctx, follows, list, userID)
+	if err != nil {
+		return "", err
+	}
+	if !canReadList(list, true, following) {
+		return "", svcErr.ForbiddenError{Message: "this wishlist is private"}
+	}
+
+	return role, nil
+}
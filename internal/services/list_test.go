@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -20,10 +21,14 @@ type listStorageMock struct {
 
 	listToReturn  models.List
 	listsToReturn []models.List
-
-	createdList models.List
-	rotatedID   uuid.UUID
-	rotatedWith string
+	listsByID     map[uuid.UUID]models.List // Lists looked up by ID, listToReturn for the rest
+
+	createdList   models.List
+	createdWishes []models.Wish
+	updatedWith   models.UpdateListRequest
+	rotatedID     uuid.UUID
+	rotatedWith   string
+	withFollowers bool
 }
 
 func (m *listStorageMock) CreateList(ctx context.Context, list models.List) error {
@@ -31,10 +36,19 @@ func (m *listStorageMock) CreateList(ctx context.Context, list models.List) erro
 	return m.createErr
 }
 
+func (m *listStorageMock) CreateListWithWishes(ctx context.Context, list models.List, wishes []models.Wish) error {
+	m.createdList = list
+	m.createdWishes = wishes
+	return m.createErr
+}
+
 func (m *listStorageMock) GetListByID(ctx context.Context, id uuid.UUID) (models.List, error) {
 	if m.getErr != nil {
 		return models.List{}, m.getErr
 	}
+	if list, ok := m.listsByID[id]; ok {
+		return list, nil
+	}
 	return m.listToReturn, nil
 }
 
@@ -49,11 +63,13 @@ func (m *listStorageMock) GetListsByUserID(ctx context.Context, userID uuid.UUID
 	return m.listsToReturn, nil
 }
 
-func (m *listStorageMock) GetPublicListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
+func (m *listStorageMock) GetPublicListsByUserID(ctx context.Context, userID uuid.UUID, withFollowers bool) ([]models.List, error) {
+	m.withFollowers = withFollowers
 	return m.listsToReturn, nil
 }
 
 func (m *listStorageMock) UpdateListByID(ctx context.Context, id uuid.UUID, req models.UpdateListRequest) error {
+	m.updatedWith = req
 	return m.updateErr
 }
 
@@ -70,15 +86,30 @@ func (m *listStorageMock) DeleteListByID(ctx context.Context, id uuid.UUID) erro
 type listWishStorageMock struct {
 	wishes    []models.Wish
 	wishesErr error
+
+	createdWish models.Wish
+	createErr   error
+}
+
+func (m *listWishStorageMock) CreateWish(ctx context.Context, wish models.Wish) error {
+	m.createdWish = wish
+	return m.createErr
 }
 
-func (m *listWishStorageMock) CreateWish(ctx context.Context, wish models.Wish) error { return nil }
+func (m *listWishStorageMock) CreateWishes(ctx context.Context, wishes []models.Wish) error {
+	return nil
+}
 
 func (m *listWishStorageMock) GetWishByID(ctx context.Context, wishID uuid.UUID) (models.Wish, error) {
-	return models.Wish{}, nil
+	for _, wish := range m.wishes {
+		if wish.ID == wishID {
+			return wish, nil
+		}
+	}
+	return models.Wish{}, svcErr.NotFoundError{Entity: "wish", Field: "id", Value: wishID.String()}
 }
 
-func (m *listWishStorageMock) GetWishesByListID(ctx context.Context, listID uuid.UUID) ([]models.Wish, error) {
+func (m *listWishStorageMock) GetWishesByListID(ctx context.Context, listID uuid.UUID, filter models.WishFilter) ([]models.Wish, error) {
 	if m.wishesErr != nil {
 		return nil, m.wishesErr
 	}
@@ -89,11 +120,19 @@ func (m *listWishStorageMock) UpdateWishByID(ctx context.Context, wishID uuid.UU
 	return nil
 }
 
-func (m *listWishStorageMock) ReserveWish(ctx context.Context, wishID, userID uuid.UUID) error {
+func (m *listWishStorageMock) UpdateWishStatus(ctx context.Context, wishID uuid.UUID, from, to models.WishStatus) error {
+	return nil
+}
+
+func (m *listWishStorageMock) ReorderWishes(ctx context.Context, listID uuid.UUID, wishIDs []uuid.UUID) error {
+	return nil
+}
+
+func (m *listWishStorageMock) ReserveWish(ctx context.Context, reservation models.WishReservation) error {
 	return nil
 }
 
-func (m *listWishStorageMock) ReleaseWish(ctx context.Context, wishID, userID uuid.UUID) error {
+func (m *listWishStorageMock) ReleaseWish(ctx context.Context, wishID, userID uuid.UUID, units int) error {
 	return nil
 }
 
@@ -102,7 +141,7 @@ func (m *listWishStorageMock) DeleteWishByID(ctx context.Context, wishID uuid.UU
 func TestListService_CreateList_DefaultsAndSlug(t *testing.T) {
 	ls := &listStorageMock{}
 	ws := &listWishStorageMock{}
-	svc := NewListService(ls, ws)
+	svc := NewListService(ls, ws, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
 	userID := uuid.New()
 	title := "Birthday"
@@ -114,8 +153,8 @@ func TestListService_CreateList_DefaultsAndSlug(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateList() error = %v", err)
 	}
-	if !list.IsPublic {
-		t.Fatal("CreateList() IsPublic = false, want true")
+	if list.Visibility != models.ListVisibilityPublic {
+		t.Fatalf("CreateList() Visibility = %q, want %q", list.Visibility, models.ListVisibilityPublic)
 	}
 	if len(list.Slug) != 32 {
 		t.Fatalf("CreateList() Slug len = %d, want 32", len(list.Slug))
@@ -129,11 +168,11 @@ func TestListService_GetListByID_PrivateForbidden(t *testing.T) {
 	ownerID := uuid.New()
 	requestedBy := uuid.New()
 	ls := &listStorageMock{listToReturn: models.List{
-		ID:       uuid.New(),
-		UserID:   ownerID,
-		IsPublic: false,
+		ID:         uuid.New(),
+		UserID:     ownerID,
+		Visibility: models.ListVisibilityPrivate,
 	}}
-	svc := NewListService(ls, &listWishStorageMock{})
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
 	_, err := svc.GetListByID(context.Background(), uuid.New(), requestedBy)
 	if err == nil {
@@ -150,14 +189,14 @@ func TestListService_GetListWithWishes(t *testing.T) {
 	userID := uuid.New()
 	wishes := []models.Wish{{ID: uuid.New(), ListID: listID}}
 	ls := &listStorageMock{listToReturn: models.List{
-		ID:       listID,
-		UserID:   userID,
-		IsPublic: true,
+		ID:         listID,
+		UserID:     userID,
+		Visibility: models.ListVisibilityPublic,
 	}}
 	ws := &listWishStorageMock{wishes: wishes}
-	svc := NewListService(ls, ws)
+	svc := NewListService(ls, ws, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
-	gotList, gotWishes, err := svc.GetListWithWishes(context.Background(), listID, userID)
+	gotList, gotWishes, err := svc.GetListWithWishes(context.Background(), listID, userID, models.WishFilter{})
 	if err != nil {
 		t.Fatalf("GetListWithWishes() error = %v", err)
 	}
@@ -176,7 +215,7 @@ func TestListService_UpdateList_NotOwner(t *testing.T) {
 		ID:     uuid.New(),
 		UserID: ownerID,
 	}}
-	svc := NewListService(ls, &listWishStorageMock{})
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
 	err := svc.UpdateList(context.Background(), uuid.New(), callerID, models.UpdateListRequest{})
 	if err == nil {
@@ -195,7 +234,7 @@ func TestListService_RotateSharedLink(t *testing.T) {
 		ID:     listID,
 		UserID: userID,
 	}}
-	svc := NewListService(ls, &listWishStorageMock{})
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
 	slug, err := svc.RotateSharedLink(context.Background(), listID, userID)
 	if err != nil {
@@ -213,11 +252,11 @@ func TestListService_RotateSharedLink(t *testing.T) {
 }
 
 func TestListService_GetListBySharedLink(t *testing.T) {
-	expected := models.List{ID: uuid.New(), Slug: "12345678901234567890123456789012"}
+	expected := models.List{ID: uuid.New(), Slug: "12345678901234567890123456789012", Visibility: models.ListVisibilityLinkOnly}
 	ls := &listStorageMock{listToReturn: expected}
-	svc := NewListService(ls, &listWishStorageMock{})
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
-	actual, err := svc.GetListBySharedLink(context.Background(), expected.Slug)
+	actual, err := svc.GetListBySharedLink(context.Background(), expected.Slug, nil)
 	if err != nil {
 		t.Fatalf("GetListBySharedLink() error = %v", err)
 	}
@@ -226,14 +265,62 @@ func TestListService_GetListBySharedLink(t *testing.T) {
 	}
 }
 
+func TestListService_Visibility(t *testing.T) {
+	ownerID := uuid.New()
+	strangerID := uuid.New()
+	list := models.List{ID: uuid.New(), UserID: ownerID, Slug: "12345678901234567890123456789012"}
+	ls := &listStorageMock{}
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, &followStorageMock{}, nil, nil, nil, nil)
+
+	tests := []struct {
+		visibility models.ListVisibility
+		byID       bool
+		byLink     bool
+	}{
+		{visibility: models.ListVisibilityPublic, byID: true, byLink: true},
+		{visibility: models.ListVisibilityLinkOnly, byID: false, byLink: true},
+		{visibility: models.ListVisibilityFollowers, byID: false, byLink: false},
+		{visibility: models.ListVisibilityPrivate, byID: false, byLink: false},
+	}
+	for _, tt := range tests {
+		list.Visibility = tt.visibility
+		ls.listToReturn = list
+
+		_, err := svc.GetListByID(context.Background(), list.ID, strangerID)
+		if (err == nil) != tt.byID {
+			t.Fatalf("GetListByID() %s error = %v, want readable %v", tt.visibility, err, tt.byID)
+		}
+		_, err = svc.GetListBySharedLink(context.Background(), list.Slug, nil)
+		if (err == nil) != tt.byLink {
+			t.Fatalf("GetListBySharedLink() %s error = %v, want readable %v", tt.visibility, err, tt.byLink)
+		}
+		if _, err = svc.GetListByID(context.Background(), list.ID, ownerID); err != nil {
+			t.Fatalf("GetListByID() %s owner error = %v", tt.visibility, err)
+		}
+	}
+}
+
+func TestListService_UpdateList_LegacyIsPublic(t *testing.T) {
+	ownerID := uuid.New()
+	ls := &listStorageMock{listToReturn: models.List{ID: uuid.New(), UserID: ownerID}}
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
+
+	if err := svc.UpdateList(context.Background(), ls.listToReturn.ID, ownerID, models.UpdateListRequest{IsPublic: new(false)}); err != nil {
+		t.Fatalf("UpdateList() error = %v", err)
+	}
+	if ls.updatedWith.Visibility == nil || *ls.updatedWith.Visibility != models.ListVisibilityLinkOnly {
+		t.Fatalf("UpdateList() Visibility = %v, want %q", ls.updatedWith.Visibility, models.ListVisibilityLinkOnly)
+	}
+}
+
 func TestListService_GetListWithWishesBySharedLink(t *testing.T) {
-	list := models.List{ID: uuid.New(), Slug: "12345678901234567890123456789012"}
+	list := models.List{ID: uuid.New(), Slug: "12345678901234567890123456789012", Visibility: models.ListVisibilityPublic}
 	wishes := []models.Wish{{ID: uuid.New(), ListID: list.ID}}
 	ls := &listStorageMock{listToReturn: list}
 	ws := &listWishStorageMock{wishes: wishes}
-	svc := NewListService(ls, ws)
+	svc := NewListService(ls, ws, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
-	gotList, gotWishes, err := svc.GetListWithWishesBySharedLink(context.Background(), list.Slug)
+	gotList, gotWishes, err := svc.GetListWithWishesBySharedLink(context.Background(), list.Slug, nil, models.WishFilter{})
 	if err != nil {
 		t.Fatalf("GetListWithWishesBySharedLink() error = %v", err)
 	}
@@ -249,7 +336,7 @@ func TestListService_GetCurrentAndPublicUserLists(t *testing.T) {
 	userID := uuid.New()
 	expected := []models.List{{ID: uuid.New(), UserID: userID}}
 	ls := &listStorageMock{listsToReturn: expected}
-	svc := NewListService(ls, &listWishStorageMock{})
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
 	current, err := svc.GetCurrentUserLists(context.Background(), userID)
 	if err != nil {
@@ -259,7 +346,7 @@ func TestListService_GetCurrentAndPublicUserLists(t *testing.T) {
 		t.Fatalf("GetCurrentUserLists() mismatch")
 	}
 
-	public, err := svc.GetPublicListsByUserID(context.Background(), userID)
+	public, err := svc.GetPublicListsByUserID(context.Background(), userID, userID)
 	if err != nil {
 		t.Fatalf("GetPublicListsByUserID() error = %v", err)
 	}
@@ -273,7 +360,7 @@ func TestListService_DeleteList(t *testing.T) {
 	ownerID := uuid.New()
 	callerID := uuid.New()
 	ls := &listStorageMock{listToReturn: models.List{ID: listID, UserID: ownerID}}
-	svc := NewListService(ls, &listWishStorageMock{})
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
 	err := svc.DeleteList(context.Background(), listID, callerID)
 	if err == nil {
@@ -294,7 +381,7 @@ func TestListService_UpdateList_Success(t *testing.T) {
 	listID := uuid.New()
 	ownerID := uuid.New()
 	ls := &listStorageMock{listToReturn: models.List{ID: listID, UserID: ownerID}}
-	svc := NewListService(ls, &listWishStorageMock{})
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
 	title := "Updated"
 	err := svc.UpdateList(context.Background(), listID, ownerID, models.UpdateListRequest{Title: &title})
@@ -308,7 +395,7 @@ func TestListService_RotateSharedLink_NotOwner(t *testing.T) {
 	ownerID := uuid.New()
 	callerID := uuid.New()
 	ls := &listStorageMock{listToReturn: models.List{ID: listID, UserID: ownerID}}
-	svc := NewListService(ls, &listWishStorageMock{})
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
 
 	_, err := svc.RotateSharedLink(context.Background(), listID, callerID)
 	if err == nil {
@@ -319,3 +406,127 @@ func TestListService_RotateSharedLink_NotOwner(t *testing.T) {
 		t.Fatalf("RotateSharedLink() error = %T, want ForbiddenError", err)
 	}
 }
+
+func newCopyFixture() (models.List, []models.Wish, *userAvatarStorageMock) {
+	friendList := models.List{ID: uuid.New(), UserID: uuid.New(), Title: "Birthday", Visibility: models.ListVisibilityPublic}
+	wishes := []models.Wish{
+		{
+			ID: uuid.New(), ListID: friendList.ID, Title: "Kettle", Image: new("wishes/k/1/original"), Quantity: 2,
+			Status: models.WishStatusReserved, PriceAlert: new(int64(1000)), Contributed: 500,
+			Reservations: []models.WishReservation{{UserID: uuid.New(), Units: 1}},
+		},
+		{ID: uuid.New(), ListID: friendList.ID, Title: "Socks", Image: new("https://shop.example/socks.jpg"), Quantity: 1, Status: models.WishStatusReceived},
+		{ID: uuid.New(), ListID: friendList.ID, Title: "Old lamp", Quantity: 1, Status: models.WishStatusArchived},
+	}
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist", stored: map[string]storedObjectMock{
+		"wishes/k/1/original":  {data: []byte("original")},
+		"wishes/k/1/medium":    {data: []byte("medium")},
+		"wishes/k/1/thumbnail": {data: []byte("thumbnail")},
+	}}
+	return friendList, wishes, s3
+}
+
+func TestListService_DuplicateList(t *testing.T) {
+	friendList, wishes, s3 := newCopyFixture()
+	userID := uuid.New()
+	ls := &listStorageMock{listToReturn: friendList}
+	records := &objectRecordStorageMock{}
+	svc := NewListService(ls, &listWishStorageMock{wishes: wishes}, &listMemberStorageMock{}, nil, s3, nil, NewObjectTracker(records, s3, &userLoggerMock{}), nil)
+
+	list, err := svc.DuplicateList(context.Background(), friendList.ID, userID, models.DuplicateListRequest{})
+	if err != nil {
+		t.Fatalf("DuplicateList() error = %v", err)
+	}
+	if list.UserID != userID || list.ID == friendList.ID || list.Title != "Birthday" || list.Visibility != models.ListVisibilityPrivate || list.WishesCount != 2 {
+		t.Fatalf("DuplicateList() = %+v", list)
+	}
+
+	copies := ls.createdWishes
+	if len(copies) != 2 || copies[0].Title != "Kettle" || copies[1].Title != "Socks" {
+		t.Fatalf("copied %+v, want every wish but the archived one", copies)
+	}
+	for _, wish := range copies {
+		if wish.ListID != list.ID || wish.Status != models.WishStatusOpen || len(wish.Reservations) != 0 || wish.Contributed != 0 || wish.PriceAlert != nil {
+			t.Fatalf("copy %+v, want an open wish of the new list without reservations", wish)
+		}
+	}
+	if *copies[0].Image == "wishes/k/1/original" || !strings.HasPrefix(*copies[0].Image, "wishes/"+copies[0].ID.String()+"/") || len(s3.copiedObjs) != 3 {
+		t.Fatalf("image %s, copied %v, want every variant under the new wish", *copies[0].Image, s3.copiedObjs)
+	}
+	if *copies[1].Image != "https://shop.example/socks.jpg" {
+		t.Fatalf("image %s, want the link kept", *copies[1].Image)
+	}
+	if len(records.recorded) != 1 || *records.recorded[0].Owner.WishID != copies[0].ID {
+		t.Fatalf("tracked %+v, want the copied image", records.recorded)
+	}
+}
+
+func TestListService_DuplicateList_Private(t *testing.T) {
+	friendList, wishes, s3 := newCopyFixture()
+	friendList.Visibility = models.ListVisibilityPrivate
+	ls := &listStorageMock{listToReturn: friendList}
+	svc := NewListService(ls, &listWishStorageMock{wishes: wishes}, &listMemberStorageMock{}, nil, s3, nil, nil, nil)
+
+	_, err := svc.DuplicateList(context.Background(), friendList.ID, uuid.New(), models.DuplicateListRequest{})
+	if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+		t.Fatalf("DuplicateList() error = %v, want ForbiddenError", err)
+	}
+	if len(s3.copiedObjs) != 0 {
+		t.Fatalf("copied %v of a list the user can't read", s3.copiedObjs)
+	}
+}
+
+func TestListService_DuplicateList_StorageError(t *testing.T) {
+	friendList, wishes, s3 := newCopyFixture()
+	ls := &listStorageMock{listToReturn: friendList, createErr: errors.New("db error")}
+	svc := NewListService(ls, &listWishStorageMock{wishes: wishes}, &listMemberStorageMock{}, nil, s3, nil, nil, nil)
+
+	if _, err := svc.DuplicateList(context.Background(), friendList.ID, uuid.New(), models.DuplicateListRequest{}); err == nil {
+		t.Fatal("DuplicateList() error = nil, want storage error")
+	}
+	if len(s3.deletedObjs) != 3 {
+		t.Fatalf("deleted %v, want the copied variants removed", s3.deletedObjs)
+	}
+}
+
+func TestListService_CopyWish(t *testing.T) {
+	friendList, wishes, s3 := newCopyFixture()
+	userID := uuid.New()
+	myList := models.List{ID: uuid.New(), UserID: userID, Title: "Mine"}
+	ls := &listStorageMock{listsByID: map[uuid.UUID]models.List{friendList.ID: friendList, myList.ID: myList}}
+	ws := &listWishStorageMock{wishes: wishes}
+	svc := NewListService(ls, ws, &listMemberStorageMock{}, nil, s3, nil, nil, nil)
+
+	wish, err := svc.CopyWish(context.Background(), friendList.ID, wishes[0].ID, myList.ID, userID)
+	if err != nil {
+		t.Fatalf("CopyWish() error = %v", err)
+	}
+	if ws.createdWish.ID != wish.ID || wish.ListID != myList.ID || wish.Status != models.WishStatusOpen || wish.Quantity != 2 || len(wish.Reservations) != 0 {
+		t.Fatalf("CopyWish() = %+v", wish)
+	}
+	if wish.Image == nil || *wish.Image == *wishes[0].Image || len(s3.copiedObjs) != 3 {
+		t.Fatalf("image %v, copied %v", wish.Image, s3.copiedObjs)
+	}
+
+	t.Run("target not editable", func(t *testing.T) {
+		_, err := svc.CopyWish(context.Background(), friendList.ID, wishes[0].ID, friendList.ID, userID)
+		if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+			t.Fatalf("CopyWish() error = %v, want ForbiddenError", err)
+		}
+	})
+
+	t.Run("wish of another list", func(t *testing.T) {
+		_, err := svc.CopyWish(context.Background(), myList.ID, wishes[0].ID, myList.ID, userID)
+		if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+			t.Fatalf("CopyWish() error = %v, want ValidationError", err)
+		}
+	})
+
+	t.Run("image gone", func(t *testing.T) {
+		delete(s3.stored, "wishes/k/1/original")
+		wish, err := svc.CopyWish(context.Background(), friendList.ID, wishes[0].ID, myList.ID, userID)
+		if err != nil || wish.Image != nil {
+			t.Fatalf("CopyWish() = %+v, %v, want the wish copied without the image", wish, err)
+		}
+	})
+}
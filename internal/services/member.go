@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type ListMemberStorage interface {
+	AddListMember(ctx context.Context, member models.ListMember) error
+	GetListMember(ctx context.Context, listID, userID uuid.UUID) (models.ListMember, error)
+	GetListMembers(ctx context.Context, listID uuid.UUID) ([]models.ListMember, error)
+	GetPendingInvitations(ctx context.Context, userID uuid.UUID) ([]models.ListMember, error)
+	AcceptListMember(ctx context.Context, listID, userID uuid.UUID) error
+	DeleteListMember(ctx context.Context, listID, userID uuid.UUID) error
+}
+
+type ListMemberServiceImpl struct {
+	members ListMemberStorage
+	lists   ListStorage
+}
+
+func NewListMemberService(ms ListMemberStorage, ls ListStorage) *ListMemberServiceImpl {
+	return &ListMemberServiceImpl{members: ms, lists: ls}
+}
+
+// resolveListRole returns the role userID has on the list; an empty role means no access beyond what visibility allows
+func resolveListRole(ctx context.Context, members ListMemberStorage, list models.List, userID uuid.UUID) (models.ListRole, error) {
+	if list.UserID == userID {
+		return models.ListRoleOwner, nil
+	}
+
+	member, err := members.GetListMember(ctx, list.ID, userID)
+	if err != nil {
+		if _, ok := errors.AsType[svcErr.NotFoundError](err); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	if !member.IsAccepted() {
+		return "", nil // Pending invitations grant nothing yet
+	}
+
+	return member.Role, nil
+}
+
+func (svc *ListMemberServiceImpl) GetMembers(ctx context.Context, listID, userID uuid.UUID) ([]models.ListMember, error) {
+	list, err := svc.lists.GetListByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, svcErr.ForbiddenError{Message: "you are not a member of this wishlist"}
+	}
+
+	return svc.members.GetListMembers(ctx, listID)
+}
+
+func (svc *ListMemberServiceImpl) GetPendingInvitations(ctx context.Context, userID uuid.UUID) ([]models.ListMember, error) {
+	return svc.members.GetPendingInvitations(ctx, userID)
+}
+
+func (svc *ListMemberServiceImpl) InviteMember(ctx context.Context, listID, userID uuid.UUID, req models.InviteListMemberRequest) (models.ListMember, error) {
+	list, err := svc.lists.GetListByID(ctx, listID)
+	if err != nil {
+		return models.ListMember{}, err
+	}
+
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return models.ListMember{}, err
+	}
+	if !role.CanManage() {
+		return models.ListMember{}, svcErr.ForbiddenError{Message: "you are not the owner of this wishlist"}
+	}
+
+	if req.UserID == list.UserID || req.UserID == userID {
+		return models.ListMember{}, svcErr.ValidationError{Message: "you cannot invite yourself"}
+	}
+
+	member := models.ListMember{
+		ListID:    listID,
+		UserID:    req.UserID,
+		Role:      req.Role,
+		InvitedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err = svc.members.AddListMember(ctx, member); err != nil {
+		return models.ListMember{}, err
+	}
+
+	return member, nil
+}
+
+func (svc *ListMemberServiceImpl) AcceptInvitation(ctx context.Context, listID, userID uuid.UUID) error {
+	return svc.members.AcceptListMember(ctx, listID, userID)
+}
+
+func (svc *ListMemberServiceImpl) RemoveMember(ctx context.Context, listID, memberID, userID uuid.UUID) error {
+	list, err := svc.lists.GetListByID(ctx, listID)
+	if err != nil {
+		return err
+	}
+
+	if memberID == list.UserID {
+		return svcErr.ValidationError{Message: "the creator of the wishlist cannot be removed"}
+	}
+
+	if memberID != userID { // Anyone may leave or decline on their own, removing others takes an owner
+		role, err := resolveListRole(ctx, svc.members, list, userID)
+		if err != nil {
+			return err
+		}
+		if !role.CanManage() {
+			return svcErr.ForbiddenError{Message: "you are not the owner of this wishlist"}
+		}
+	}
+
+	return svc.members.DeleteListMember(ctx, listID, memberID)
+}
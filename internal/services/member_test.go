@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type listMemberStorageMock struct {
+	addErr    error
+	acceptErr error
+	deleteErr error
+
+	members map[uuid.UUID]models.ListMember
+
+	addedMember models.ListMember
+	deletedID   uuid.UUID
+}
+
+func (m *listMemberStorageMock) AddListMember(ctx context.Context, member models.ListMember) error {
+	m.addedMember = member
+	return m.addErr
+}
+
+func (m *listMemberStorageMock) GetListMember(ctx context.Context, listID, userID uuid.UUID) (models.ListMember, error) {
+	member, ok := m.members[userID]
+	if !ok {
+		return models.ListMember{}, svcErr.NotFoundError{Entity: "member", Field: "user_id", Value: userID.String()}
+	}
+	return member, nil
+}
+
+func (m *listMemberStorageMock) GetListMembers(ctx context.Context, listID uuid.UUID) ([]models.ListMember, error) {
+	var members []models.ListMember
+	for _, member := range m.members {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (m *listMemberStorageMock) GetPendingInvitations(ctx context.Context, userID uuid.UUID) ([]models.ListMember, error) {
+	return nil, nil
+}
+
+func (m *listMemberStorageMock) AcceptListMember(ctx context.Context, listID, userID uuid.UUID) error {
+	return m.acceptErr
+}
+
+func (m *listMemberStorageMock) DeleteListMember(ctx context.Context, listID, userID uuid.UUID) error {
+	m.deletedID = userID
+	return m.deleteErr
+}
+
+func acceptedMember(listID, userID uuid.UUID, role models.ListRole) models.ListMember {
+	acceptedAt := time.Now()
+	return models.ListMember{ListID: listID, UserID: userID, Role: role, AcceptedAt: &acceptedAt}
+}
+
+func TestListMemberService_InviteMember(t *testing.T) {
+	listID := uuid.New()
+	ownerID := uuid.New()
+	invitedID := uuid.New()
+	ls := &listStorageMock{listToReturn: models.List{ID: listID, UserID: ownerID}}
+	ms := &listMemberStorageMock{}
+	svc := NewListMemberService(ms, ls)
+
+	member, err := svc.InviteMember(context.Background(), listID, ownerID, models.InviteListMemberRequest{UserID: invitedID, Role: models.ListRoleEditor})
+	if err != nil {
+		t.Fatalf("InviteMember() error = %v", err)
+	}
+	if member.IsAccepted() {
+		t.Fatal("InviteMember() member is accepted, want pending invitation")
+	}
+	if ms.addedMember.UserID != invitedID || ms.addedMember.InvitedBy != ownerID || ms.addedMember.Role != models.ListRoleEditor {
+		t.Fatalf("InviteMember() stored unexpected member: %+v", ms.addedMember)
+	}
+}
+
+func TestListMemberService_InviteMember_EditorForbidden(t *testing.T) {
+	listID := uuid.New()
+	editorID := uuid.New()
+	ls := &listStorageMock{listToReturn: models.List{ID: listID, UserID: uuid.New()}}
+	ms := &listMemberStorageMock{members: map[uuid.UUID]models.ListMember{editorID: acceptedMember(listID, editorID, models.ListRoleEditor)}}
+	svc := NewListMemberService(ms, ls)
+
+	_, err := svc.InviteMember(context.Background(), listID, editorID, models.InviteListMemberRequest{UserID: uuid.New(), Role: models.ListRoleViewer})
+	var forbidden svcErr.ForbiddenError
+	if !errors.As(err, &forbidden) {
+		t.Fatalf("InviteMember() error = %T, want ForbiddenError", err)
+	}
+}
+
+func TestListMemberService_RemoveMember(t *testing.T) {
+	listID := uuid.New()
+	ownerID := uuid.New()
+	viewerID := uuid.New()
+	ls := &listStorageMock{listToReturn: models.List{ID: listID, UserID: ownerID}}
+	ms := &listMemberStorageMock{members: map[uuid.UUID]models.ListMember{viewerID: acceptedMember(listID, viewerID, models.ListRoleViewer)}}
+	svc := NewListMemberService(ms, ls)
+
+	if err := svc.RemoveMember(context.Background(), listID, ownerID, viewerID); err == nil {
+		t.Fatal("RemoveMember() creator error = nil, want validation error")
+	}
+
+	var forbidden svcErr.ForbiddenError
+	if err := svc.RemoveMember(context.Background(), listID, uuid.New(), viewerID); !errors.As(err, &forbidden) {
+		t.Fatalf("RemoveMember() by viewer error = %T, want ForbiddenError", err)
+	}
+
+	if err := svc.RemoveMember(context.Background(), listID, viewerID, viewerID); err != nil {
+		t.Fatalf("RemoveMember() self error = %v", err)
+	}
+	if ms.deletedID != viewerID {
+		t.Fatalf("RemoveMember() deleted %s, want %s", ms.deletedID, viewerID)
+	}
+}
+
+func TestListService_GetListByID_PrivateAllowedForMember(t *testing.T) {
+	listID := uuid.New()
+	viewerID := uuid.New()
+	pendingID := uuid.New()
+	ls := &listStorageMock{listToReturn: models.List{ID: listID, UserID: uuid.New(), Visibility: models.ListVisibilityPrivate}}
+	ms := &listMemberStorageMock{members: map[uuid.UUID]models.ListMember{
+		viewerID:  acceptedMember(listID, viewerID, models.ListRoleViewer),
+		pendingID: {ListID: listID, UserID: pendingID, Role: models.ListRoleEditor},
+	}}
+	svc := NewListService(ls, &listWishStorageMock{}, ms, nil, nil, nil, nil, nil)
+
+	list, err := svc.GetListByID(context.Background(), listID, viewerID)
+	if err != nil {
+		t.Fatalf("GetListByID() error = %v", err)
+	}
+	if list.Role != models.ListRoleViewer {
+		t.Fatalf("GetListByID() role = %q, want %q", list.Role, models.ListRoleViewer)
+	}
+
+	var forbidden svcErr.ForbiddenError
+	if _, err = svc.GetListByID(context.Background(), listID, pendingID); !errors.As(err, &forbidden) {
+		t.Fatalf("GetListByID() pending member error = %T, want ForbiddenError", err)
+	}
+}
+
+func TestWishService_CreateWish_Editor(t *testing.T) {
+	listID := uuid.New()
+	editorID := uuid.New()
+	viewerID := uuid.New()
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: uuid.New()}}
+	ms := &listMemberStorageMock{members: map[uuid.UUID]models.ListMember{
+		editorID: acceptedMember(listID, editorID, models.ListRoleEditor),
+		viewerID: acceptedMember(listID, viewerID, models.ListRoleViewer),
+	}}
+	svc := NewWishService(&wishSvcWishStorageMock{}, listStorage, ms, nil, nil, nil, nil, nil, nil)
+
+	if _, err := svc.CreateWish(context.Background(), listID, editorID, models.CreateWishRequest{Title: "Mug"}); err != nil {
+		t.Fatalf("CreateWish() editor error = %v", err)
+	}
+
+	var forbidden svcErr.ForbiddenError
+	if _, err := svc.CreateWish(context.Background(), listID, viewerID, models.CreateWishRequest{Title: "Mug"}); !errors.As(err, &forbidden) {
+		t.Fatalf("CreateWish() viewer error = %T, want ForbiddenError", err)
+	}
+}
+
+func TestWishService_ReserveWish_EditorRejected(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	editorID := uuid.New()
+	wishStorage := &wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID}}
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: uuid.New()}}
+	ms := &listMemberStorageMock{members: map[uuid.UUID]models.ListMember{editorID: acceptedMember(listID, editorID, models.ListRoleEditor)}}
+	svc := NewWishService(wishStorage, listStorage, ms, nil, nil, nil, nil, nil, nil)
+
+	var validation svcErr.ValidationError
+	if err := svc.ReserveWish(context.Background(), listID, wishID, editorID, models.ReserveWishRequest{}); !errors.As(err, &validation) {
+		t.Fatalf("ReserveWish() error = %T, want ValidationError", err)
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"wishlist/internal/imaging"
+	"wishlist/internal/models"
+)
+
+type ObjectRecordStorage interface {
+	RecordObject(ctx context.Context, object models.StoredObject) error
+	ReleaseObjects(ctx context.Context, owner models.ObjectOwner) ([]models.StoredObject, error)
+	DeleteObjectRecords(ctx context.Context, objectNames []string) error
+}
+
+// ObjectTracker records who owns each uploaded image and removes images from S3 once nothing uses them.
+// A nil tracker does nothing, whatever it would have removed is left to ObjectSweepJob
+type ObjectTracker struct {
+	records ObjectRecordStorage
+	s3      AvatarStorage
+	log     Logger
+}
+
+func NewObjectTracker(rs ObjectRecordStorage, s3 AvatarStorage, l Logger) *ObjectTracker {
+	return &ObjectTracker{records: rs, s3: s3, log: l}
+}
+
+// Track records the owner of a stored image, links to other sites are ignored.
+// Errors are only logged, an untracked image is still found by the sweep
+func (t *ObjectTracker) Track(ctx context.Context, stored string, owner models.ObjectOwner) {
+	if t == nil {
+		return
+	}
+
+	objectName := storedObjectName(stored, t.s3.GetBaseURL())
+	if objectName == "" {
+		return
+	}
+
+	object := models.StoredObject{ObjectName: imaging.BaseName(objectName), URL: stored, Owner: owner, CreatedAt: time.Now()}
+	if err := t.records.RecordObject(ctx, object); err != nil {
+		t.log.Error("Object tracker: %v", err)
+	}
+}
+
+// Release removes the images of the owner that no user or wish uses anymore. Call it after the database change,
+// e.g. once the wish is deleted or got a new image, so images shared with another wish stay
+func (t *ObjectTracker) Release(ctx context.Context, owner models.ObjectOwner) {
+	if t == nil {
+		return
+	}
+
+	objects, err := t.records.ReleaseObjects(ctx, owner)
+	if err != nil {
+		t.log.Error("Object tracker: %v", err)
+		return
+	}
+
+	for _, object := range objects {
+		if err = deleteImage(ctx, t.s3, object.URL); err != nil {
+			t.log.Error("Object tracker: failed to delete '%s': %v", object.ObjectName, err)
+		}
+	}
+}
+
+// Forget drops the record of an image the caller already removed from S3
+func (t *ObjectTracker) Forget(ctx context.Context, stored string) {
+	if t == nil {
+		return
+	}
+
+	objectName := storedObjectName(stored, t.s3.GetBaseURL())
+	if objectName == "" {
+		return
+	}
+
+	if err := t.records.DeleteObjectRecords(ctx, []string{imaging.BaseName(objectName)}); err != nil {
+		t.log.Error("Object tracker: %v", err)
+	}
+}
+
+// Discard removes an object that was never recorded, like a raw upload once its variants are made.
+// Errors are only logged, the sweep finds the object later
+func (t *ObjectTracker) Discard(ctx context.Context, objectName string) {
+	if t == nil {
+		return
+	}
+
+	if err := t.s3.DeleteObject(ctx, objectName); err != nil {
+		t.log.Error("Object tracker: failed to delete '%s': %v", objectName, err)
+	}
+}
+
+type StoredURLConverter interface {
+	ConvertStoredURLs(ctx context.Context, baseURL string, toKey func(string) string) (int64, error)
+}
+
+// MigrateStoredURLs turns avatars and wish images saved as URLs of the bucket into object keys, so they can be served
+// from a private bucket. It is called on every start, the store does the work only the first time
+func MigrateStoredURLs(ctx context.Context, store StoredURLConverter, baseURL string) (int64, error) {
+	return store.ConvertStoredURLs(ctx, strings.TrimSuffix(baseURL, "/"), func(url string) string {
+		return extractObjectNameFromURL(url, baseURL)
+	})
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"wishlist/internal/imaging"
+	"wishlist/internal/models"
+)
+
+type ObjectSweepStorage interface {
+	GetReferencedURLs(ctx context.Context) ([]string, error)
+	DeleteObjectRecords(ctx context.Context, objectNames []string) error
+}
+
+type BucketStorage interface {
+	GetBaseURL() string
+	ListObjects(ctx context.Context) ([]models.BucketObject, error)
+	DeleteObject(ctx context.Context, objectName string) error
+}
+
+type ReportLogger interface {
+	Logger
+	Info(format string, v ...any)
+}
+
+// ObjectSweepJob reconciles the bucket with the database and removes the keys no avatar or wish image uses,
+// it catches whatever ObjectTracker missed: failed deletes, uploads from before tracking, abandoned link previews
+type ObjectSweepJob struct {
+	records     ObjectSweepStorage
+	bucket      BucketStorage
+	log         ReportLogger
+	interval    time.Duration
+	gracePeriod time.Duration // Uploads are saved to the database after they reach S3, younger keys are left alone
+	exportTTL   time.Duration // Data export archives are never referenced, they are kept until their download link expires
+	dryRun      bool
+}
+
+func NewObjectSweepJob(store ObjectSweepStorage, bs BucketStorage, l ReportLogger, interval, gracePeriod, exportTTL time.Duration, dryRun bool) *ObjectSweepJob {
+	return &ObjectSweepJob{records: store, bucket: bs, log: l, interval: interval, gracePeriod: gracePeriod, exportTTL: exportTTL, dryRun: dryRun}
+}
+
+// Run blocks until ctx is cancelled, errors are logged and retried on the next tick
+func (job *ObjectSweepJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		job.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (job *ObjectSweepJob) RunOnce(ctx context.Context) {
+	report, err := job.Sweep(ctx)
+	if err != nil {
+		job.log.Error("Object sweep job: %v", err)
+		return
+	}
+
+	if report.DryRun {
+		for _, key := range report.Orphaned {
+			job.log.Info("Object sweep job: would remove '%s'", key)
+		}
+	}
+	job.log.Info("Object sweep job: scanned %d, referenced %d, recent %d, orphaned %d (%d bytes), failed %d, dry run %t",
+		report.Scanned, report.Referenced, report.Recent, len(report.Orphaned), report.OrphanedBytes, len(report.Failed), report.DryRun)
+}
+
+// Sweep lists the bucket and removes the keys that are not referenced and older than the grace period,
+// in dry-run mode it only reports them
+func (job *ObjectSweepJob) Sweep(ctx context.Context) (models.ObjectSweepReport, error) {
+	referenced, err := job.referencedKeys(ctx)
+	if err != nil {
+		return models.ObjectSweepReport{}, err
+	}
+
+	objects, err := job.bucket.ListObjects(ctx)
+	if err != nil {
+		return models.ObjectSweepReport{}, err
+	}
+
+	report := models.ObjectSweepReport{DryRun: job.dryRun, Scanned: len(objects)}
+	cutoff := time.Now().Add(-job.gracePeriod)
+	exportCutoff := time.Now().Add(-max(job.gracePeriod, job.exportTTL))
+	var removed []string
+	for _, object := range objects {
+		switch {
+		case referenced[object.Key]:
+			report.Referenced++
+			continue
+		case object.LastModified.After(cutoff), strings.HasPrefix(object.Key, exportDir) && object.LastModified.After(exportCutoff):
+			report.Recent++
+			continue
+		}
+
+		report.Orphaned = append(report.Orphaned, object.Key)
+		report.OrphanedBytes += object.Size
+		if job.dryRun {
+			continue
+		}
+		if err = job.bucket.DeleteObject(ctx, object.Key); err != nil {
+			job.log.Error("Object sweep job: %v", err)
+			report.Failed = append(report.Failed, object.Key)
+			continue
+		}
+		removed = append(removed, imaging.BaseName(object.Key))
+	}
+
+	slices.Sort(removed)
+	if err = job.records.DeleteObjectRecords(ctx, slices.Compact(removed)); err != nil {
+		job.log.Error("Object sweep job: %v", err)
+	}
+
+	return report, nil
+}
+
+// referencedKeys returns the keys of every variant of every avatar and wish image saved in the database
+func (job *ObjectSweepJob) referencedKeys(ctx context.Context) (map[string]bool, error) {
+	urls, err := job.records.GetReferencedURLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(urls)*len(imaging.Variants))
+	for _, url := range urls {
+		objectName := storedObjectName(url, job.bucket.GetBaseURL())
+		if objectName == "" {
+			continue // Not hosted by us
+		}
+		for _, key := range imageObjectNames(objectName) {
+			keys[key] = true
+		}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+)
+
+type objectRecordStorageMock struct {
+	recorded  []models.StoredObject
+	released  []models.StoredObject
+	owner     models.ObjectOwner
+	owners    []models.ObjectOwner // Every release, owner is the last one
+	forgotten []string
+	urls      []string
+}
+
+func (m *objectRecordStorageMock) RecordObject(ctx context.Context, object models.StoredObject) error {
+	m.recorded = append(m.recorded, object)
+	return nil
+}
+
+func (m *objectRecordStorageMock) ReleaseObjects(ctx context.Context, owner models.ObjectOwner) ([]models.StoredObject, error) {
+	m.owner = owner
+	m.owners = append(m.owners, owner)
+	return m.released, nil
+}
+
+func (m *objectRecordStorageMock) DeleteObjectRecords(ctx context.Context, objectNames []string) error {
+	m.forgotten = append(m.forgotten, objectNames...)
+	return nil
+}
+
+func (m *objectRecordStorageMock) GetReferencedURLs(ctx context.Context) ([]string, error) {
+	return m.urls, nil
+}
+
+type bucketStorageMock struct {
+	userAvatarStorageMock
+	objects []models.BucketObject
+}
+
+func (m *bucketStorageMock) ListObjects(ctx context.Context) ([]models.BucketObject, error) {
+	return m.objects, nil
+}
+
+type reportLoggerMock struct {
+	userLoggerMock
+	infos int
+}
+
+func (m *reportLoggerMock) Info(format string, v ...any) { m.infos++ }
+
+func TestObjectTracker_Track(t *testing.T) {
+	records := &objectRecordStorageMock{}
+	tracker := NewObjectTracker(records, &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}, &userLoggerMock{})
+	wishID := uuid.New()
+
+	tracker.Track(context.Background(), "http://minio:9000/wishlist/wishes/1/2/original", models.ObjectOwner{WishID: &wishID})
+	tracker.Track(context.Background(), "https://shop.example/picture.jpg", models.ObjectOwner{WishID: &wishID})
+
+	if len(records.recorded) != 1 {
+		t.Fatalf("recorded %v, want only the image we host", records.recorded)
+	}
+	if got := records.recorded[0]; got.ObjectName != "wishes/1/2" || *got.Owner.WishID != wishID {
+		t.Fatalf("recorded %+v, want the base name owned by the wish", got)
+	}
+}
+
+func TestObjectTracker_Release(t *testing.T) {
+	records := &objectRecordStorageMock{released: []models.StoredObject{
+		{ObjectName: "wishes/1/2", URL: "http://minio:9000/wishlist/wishes/1/2/original"},
+		{ObjectName: "wishes/1/legacy", URL: "http://minio:9000/wishlist/wishes/1/legacy"},
+	}}
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+	listID := uuid.New()
+
+	NewObjectTracker(records, s3, &userLoggerMock{}).Release(context.Background(), models.ObjectOwner{ListID: &listID})
+
+	if records.owner.ListID == nil || *records.owner.ListID != listID {
+		t.Fatalf("released owner = %+v, want the list", records.owner)
+	}
+	want := []string{"wishes/1/2/original", "wishes/1/2/medium", "wishes/1/2/thumbnail", "wishes/1/legacy"}
+	if !slices.Equal(s3.deletedObjs, want) {
+		t.Fatalf("deleted %v, want %v", s3.deletedObjs, want)
+	}
+
+	var nilTracker *ObjectTracker
+	nilTracker.Release(context.Background(), models.ObjectOwner{ListID: &listID}) // Must not panic
+}
+
+func TestObjectSweepJob_Sweep(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	newSweep := func(dryRun bool) (*ObjectSweepJob, *objectRecordStorageMock, *bucketStorageMock) {
+		records := &objectRecordStorageMock{urls: []string{
+			"http://minio:9000/wishlist/avatars/u/1/original",
+			"http://minio:9000/wishlist/wishes/w/legacy",
+			"https://shop.example/picture.jpg",
+		}}
+		bucket := &bucketStorageMock{
+			userAvatarStorageMock: userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"},
+			objects: []models.BucketObject{
+				{Key: "avatars/u/1/original", Size: 10, LastModified: old},
+				{Key: "avatars/u/1/thumbnail", Size: 10, LastModified: old},
+				{Key: "wishes/w/legacy", Size: 10, LastModified: old},
+				{Key: "avatars/u/0/original", Size: 7, LastModified: old},
+				{Key: "avatars/u/0/thumbnail", Size: 3, LastModified: old},
+				{Key: "wishes/preview/1/original", Size: 5, LastModified: time.Now()},
+				{Key: "exports/u/1/wishlist-export.zip", Size: 100, LastModified: old},
+			},
+		}
+		return NewObjectSweepJob(records, bucket, &reportLoggerMock{}, time.Hour, 24*time.Hour, 72*time.Hour, dryRun), records, bucket
+	}
+
+	t.Run("removes unreferenced keys", func(t *testing.T) {
+		job, records, bucket := newSweep(false)
+
+		report, err := job.Sweep(context.Background())
+		if err != nil {
+			t.Fatalf("Sweep() error = %v", err)
+		}
+		if report.Scanned != 7 || report.Referenced != 3 || report.Recent != 2 || report.OrphanedBytes != 10 {
+			t.Fatalf("report = %+v", report)
+		}
+		want := []string{"avatars/u/0/original", "avatars/u/0/thumbnail"}
+		if !slices.Equal(report.Orphaned, want) || !slices.Equal(bucket.deletedObjs, want) {
+			t.Fatalf("orphaned %v, deleted %v, want %v", report.Orphaned, bucket.deletedObjs, want)
+		}
+		if !slices.Equal(records.forgotten, []string{"avatars/u/0"}) {
+			t.Fatalf("forgotten %v, want the base name once", records.forgotten)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		job, records, bucket := newSweep(true)
+
+		report, err := job.Sweep(context.Background())
+		if err != nil {
+			t.Fatalf("Sweep() error = %v", err)
+		}
+		if !report.DryRun || len(report.Orphaned) != 2 {
+			t.Fatalf("report = %+v, want two orphans reported", report)
+		}
+		if len(bucket.deletedObjs) != 0 || len(records.forgotten) != 0 {
+			t.Fatalf("deleted %v and forgot %v in a dry run", bucket.deletedObjs, records.forgotten)
+		}
+	})
+
+	t.Run("failed delete", func(t *testing.T) {
+		job, records, bucket := newSweep(false)
+		bucket.deleteErr = errors.New("s3 unavailable")
+
+		report, err := job.Sweep(context.Background())
+		if err != nil {
+			t.Fatalf("Sweep() error = %v", err)
+		}
+		if len(report.Failed) != 2 || len(records.forgotten) != 0 {
+			t.Fatalf("report = %+v, forgotten %v, want failures kept on record", report, records.forgotten)
+		}
+	})
+}
+
+type storedURLConverterMock struct {
+	baseURL   string
+	converted map[string]string
+}
+
+func (m *storedURLConverterMock) ConvertStoredURLs(ctx context.Context, baseURL string, toKey func(string) string) (int64, error) {
+	m.baseURL = baseURL
+	for url := range m.converted {
+		m.converted[url] = toKey(url)
+	}
+	return int64(len(m.converted)), nil
+}
+
+func TestMigrateStoredURLs(t *testing.T) {
+	store := &storedURLConverterMock{converted: map[string]string{"http://minio:9000/wishlist/avatars/u/1/original": ""}}
+
+	converted, err := MigrateStoredURLs(context.Background(), store, "http://minio:9000/wishlist/")
+	if err != nil || converted != 1 {
+		t.Fatalf("MigrateStoredURLs() = %d, %v", converted, err)
+	}
+	if store.baseURL != "http://minio:9000/wishlist" || store.converted["http://minio:9000/wishlist/avatars/u/1/original"] != "avatars/u/1/original" {
+		t.Fatalf("base = %s, converted %v", store.baseURL, store.converted)
+	}
+}
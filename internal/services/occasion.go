@@ -0,0 +1,62 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+)
+
+const (
+	defaultOccasionDays = 30
+	maxOccasionDays     = 366 // A year covers every yearly occasion
+)
+
+type OccasionStorage interface {
+	GetOccasionLists(ctx context.Context, viewerID uuid.UUID, from time.Time) ([]models.List, error)
+	GetOccasions(ctx context.Context, from time.Time) ([]models.Occasion, error)
+	GetOccasionRecipients(ctx context.Context, listID uuid.UUID) ([]models.OccasionRecipient, error)
+	MarkOccasionReminded(ctx context.Context, listID uuid.UUID, date time.Time) error
+}
+
+type OccasionServiceImpl struct {
+	occasions OccasionStorage
+}
+
+func NewOccasionService(store OccasionStorage) *OccasionServiceImpl {
+	return &OccasionServiceImpl{occasions: store}
+}
+
+// GetUpcomingOccasions returns the lists the user can read whose occasion comes within the next days, soonest first
+func (svc *OccasionServiceImpl) GetUpcomingOccasions(ctx context.Context, userID uuid.UUID, days int) ([]models.List, error) {
+	if days <= 0 {
+		days = defaultOccasionDays
+	}
+	days = min(days, maxOccasionDays)
+
+	now := time.Now()
+	lists, err := svc.occasions.GetOccasionLists(ctx, userID, models.StartOfDay(now))
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := make([]models.List, 0, len(lists))
+	next := make(map[uuid.UUID]time.Time, len(lists))
+	for _, list := range lists {
+		date := list.NextOccasion(now)
+		if date == nil || models.DaysUntil(*date, now) > days {
+			continue
+		}
+		next[list.ID] = *date
+		upcoming = append(upcoming, list)
+	}
+	slices.SortFunc(upcoming, func(a, b models.List) int {
+		return cmp.Or(next[a.ID].Compare(next[b.ID]), cmp.Compare(a.Title, b.Title))
+	})
+
+	return upcoming, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"wishlist/internal/models"
+)
+
+type OccasionReminderSender interface {
+	SendOccasionReminder(ctx context.Context, recipient models.OccasionRecipient, reminder models.OccasionReminder) error
+}
+
+// OccasionReminderJob reminds the owner, the members and the reservers of a list a few days before its occasion
+type OccasionReminderJob struct {
+	occasions  OccasionStorage
+	sender     OccasionReminderSender
+	log        Logger
+	interval   time.Duration
+	daysBefore int
+}
+
+func NewOccasionReminderJob(store OccasionStorage, ors OccasionReminderSender, l Logger, interval time.Duration, daysBefore int) *OccasionReminderJob {
+	return &OccasionReminderJob{occasions: store, sender: ors, log: l, interval: interval, daysBefore: daysBefore}
+}
+
+// Run blocks until ctx is cancelled, errors are logged and retried on the next tick
+func (job *OccasionReminderJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		job.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (job *OccasionReminderJob) RunOnce(ctx context.Context) {
+	now := time.Now()
+	occasions, err := job.occasions.GetOccasions(ctx, models.StartOfDay(now))
+	if err != nil {
+		job.log.Error("Occasion reminder job: %v", err)
+		return
+	}
+
+	for _, occasion := range occasions {
+		if ctx.Err() != nil {
+			return
+		}
+		next := occasion.Next(now)
+		if next == nil || models.DaysUntil(*next, now) > job.daysBefore {
+			continue
+		}
+		if occasion.RemindedFor != nil && occasion.RemindedFor.Equal(*next) {
+			continue // Already reminded, a yearly occasion is reminded again next year
+		}
+		job.remind(ctx, models.OccasionReminder{ListID: occasion.ListID, ListTitle: occasion.ListTitle, Date: *next})
+	}
+}
+
+func (job *OccasionReminderJob) remind(ctx context.Context, reminder models.OccasionReminder) {
+	recipients, err := job.occasions.GetOccasionRecipients(ctx, reminder.ListID)
+	if err != nil {
+		job.log.Error("Occasion reminder job: %v", err)
+		return
+	}
+
+	var sent int
+	for _, recipient := range recipients {
+		if err = job.sender.SendOccasionReminder(ctx, recipient, reminder); err != nil {
+			job.log.Error("Occasion reminder job: failed to remind user '%s' about list '%s': %v", recipient.UserID, reminder.ListID, err)
+			continue
+		}
+		sent++
+	}
+	if sent == 0 && len(recipients) > 0 {
+		return // Not marked, so it is retried on the next run. After a partial failure the others are not reminded twice
+	}
+
+	if err = job.occasions.MarkOccasionReminded(ctx, reminder.ListID, reminder.Date); err != nil {
+		job.log.Error("Occasion reminder job: %v", err)
+	}
+}
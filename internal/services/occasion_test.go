@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type occasionStorageMock struct {
+	lists      []models.List
+	occasions  []models.Occasion
+	recipients map[uuid.UUID][]models.OccasionRecipient
+
+	from     time.Time
+	reminded map[uuid.UUID]time.Time
+}
+
+func (m *occasionStorageMock) GetOccasionLists(ctx context.Context, viewerID uuid.UUID, from time.Time) ([]models.List, error) {
+	m.from = from
+	return m.lists, nil
+}
+
+func (m *occasionStorageMock) GetOccasions(ctx context.Context, from time.Time) ([]models.Occasion, error) {
+	m.from = from
+	return m.occasions, nil
+}
+
+func (m *occasionStorageMock) GetOccasionRecipients(ctx context.Context, listID uuid.UUID) ([]models.OccasionRecipient, error) {
+	return m.recipients[listID], nil
+}
+
+func (m *occasionStorageMock) MarkOccasionReminded(ctx context.Context, listID uuid.UUID, date time.Time) error {
+	if m.reminded == nil {
+		m.reminded = make(map[uuid.UUID]time.Time)
+	}
+	m.reminded[listID] = date
+	return nil
+}
+
+type occasionSenderMock struct {
+	sent       []models.OccasionReminder
+	to         []string
+	toReserver []bool
+	err        error
+}
+
+func (m *occasionSenderMock) SendOccasionReminder(ctx context.Context, recipient models.OccasionRecipient, reminder models.OccasionReminder) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, reminder)
+	m.to = append(m.to, recipient.Email)
+	m.toReserver = append(m.toReserver, recipient.IsReserver)
+	return nil
+}
+
+// daysFromToday returns the UTC date the given number of days away, the way occasion dates come from the database
+func daysFromToday(days int) time.Time {
+	return models.StartOfDay(time.Now()).AddDate(0, 0, days)
+}
+
+func TestNextOccasion(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 15, 30, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		date   time.Time
+		yearly bool
+		at     time.Time // now when zero
+		want   *time.Time
+	}{
+		{name: "one-off ahead", date: date(2026, time.December, 24), want: new(date(2026, time.December, 24))},
+		{name: "one-off today", date: date(2026, time.October, 17), want: new(date(2026, time.October, 17))},
+		{name: "one-off passed", date: date(2026, time.October, 16)},
+		{name: "yearly later this year", date: date(1990, time.November, 3), yearly: true, want: new(date(2026, time.November, 3))},
+		{name: "yearly passed this year", date: date(1990, time.March, 8), yearly: true, want: new(date(2027, time.March, 8))},
+		{name: "yearly today", date: date(2020, time.October, 17), yearly: true, want: new(date(2026, time.October, 17))},
+		{name: "leap day in a common year", date: date(2000, time.February, 29), yearly: true, want: new(date(2027, time.February, 28))},
+		{name: "leap day in a leap year", date: date(2000, time.February, 29), yearly: true, at: date(2027, time.March, 1), want: new(date(2028, time.February, 29))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := tt.at
+			if at.IsZero() {
+				at = now
+			}
+			got := models.NextOccasion(tt.date, tt.yearly, at)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Fatalf("NextOccasion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if days := models.DaysUntil(date(2026, time.October, 24), now); days != 7 {
+		t.Fatalf("DaysUntil() = %d, want 7", days)
+	}
+}
+
+func TestOccasionService_GetUpcomingOccasions(t *testing.T) {
+	soon := models.List{ID: uuid.New(), Title: "Wedding", OccasionDate: new(daysFromToday(3))}
+	birthday := models.List{ID: uuid.New(), Title: "Birthday", OccasionDate: new(daysFromToday(10).AddDate(-5, 0, 0)), OccasionRecursYearly: true}
+	later := models.List{ID: uuid.New(), Title: "Christmas", OccasionDate: new(daysFromToday(60))}
+	passed := models.List{ID: uuid.New(), Title: "Housewarming", OccasionDate: new(daysFromToday(-1))}
+	occasions := &occasionStorageMock{lists: []models.List{later, birthday, passed, soon}}
+	svc := NewOccasionService(occasions)
+
+	lists, err := svc.GetUpcomingOccasions(context.Background(), uuid.New(), 0)
+	if err != nil {
+		t.Fatalf("GetUpcomingOccasions() error = %v", err)
+	}
+	if len(lists) != 2 || lists[0].ID != soon.ID || lists[1].ID != birthday.ID {
+		t.Fatalf("GetUpcomingOccasions() = %+v, want the wedding and the birthday within 30 days, soonest first", lists)
+	}
+	if !occasions.from.Equal(daysFromToday(0)) {
+		t.Fatalf("looked from %v, want the start of today", occasions.from)
+	}
+
+	if lists, _ = svc.GetUpcomingOccasions(context.Background(), uuid.New(), 1000); len(lists) != 3 {
+		t.Fatalf("GetUpcomingOccasions(1000) = %d lists, want 3 within a year", len(lists))
+	}
+}
+
+func TestOccasionReminderJob_RunOnce(t *testing.T) {
+	due := models.Occasion{ListID: uuid.New(), ListTitle: "Birthday", Date: daysFromToday(5).AddDate(-30, 0, 0), RecursYearly: true}
+	dueDate := *due.Next(time.Now())
+	done := models.Occasion{ListID: uuid.New(), ListTitle: "Wedding", Date: daysFromToday(2), RemindedFor: new(daysFromToday(2))}
+	far := models.Occasion{ListID: uuid.New(), ListTitle: "Christmas", Date: daysFromToday(30)}
+	occasions := &occasionStorageMock{
+		occasions: []models.Occasion{due, done, far},
+		recipients: map[uuid.UUID][]models.OccasionRecipient{
+			due.ListID: {
+				{UserID: uuid.New(), Email: "owner@example.com"},
+				{UserID: uuid.New(), Email: "friend@example.com", IsReserver: true},
+			},
+			done.ListID: {{UserID: uuid.New(), Email: "owner@example.com"}},
+		},
+	}
+	sender := &occasionSenderMock{}
+	job := NewOccasionReminderJob(occasions, sender, &userLoggerMock{}, time.Hour, 7)
+
+	job.RunOnce(context.Background())
+
+	if len(sender.sent) != 2 || sender.sent[0].ListID != due.ListID || !sender.sent[0].Date.Equal(dueDate) {
+		t.Fatalf("sent %+v, want the birthday reminded for this year's date", sender.sent)
+	}
+	if sender.to[1] != "friend@example.com" || !sender.toReserver[1] || sender.toReserver[0] {
+		t.Fatalf("sent to %v (reserver %v), want the owner and the reserver", sender.to, sender.toReserver)
+	}
+	if len(occasions.reminded) != 1 || !occasions.reminded[due.ListID].Equal(dueDate) {
+		t.Fatalf("reminded %v, want only the birthday marked", occasions.reminded)
+	}
+
+	// The next run finds it marked
+	due.RemindedFor = &dueDate
+	occasions.occasions = []models.Occasion{due}
+	job.RunOnce(context.Background())
+	if len(sender.sent) != 2 {
+		t.Fatalf("sent %d reminders, want the birthday not reminded twice", len(sender.sent))
+	}
+}
+
+func TestOccasionReminderJob_RunOnce_SendFails(t *testing.T) {
+	due := models.Occasion{ListID: uuid.New(), ListTitle: "Birthday", Date: daysFromToday(1)}
+	occasions := &occasionStorageMock{
+		occasions:  []models.Occasion{due},
+		recipients: map[uuid.UUID][]models.OccasionRecipient{due.ListID: {{UserID: uuid.New(), Email: "owner@example.com"}}},
+	}
+	log := &userLoggerMock{}
+	job := NewOccasionReminderJob(occasions, &occasionSenderMock{err: errors.New("broker down")}, log, time.Hour, 7)
+
+	job.RunOnce(context.Background())
+
+	if len(occasions.reminded) != 0 {
+		t.Fatalf("reminded %v, want the occasion left for the next run", occasions.reminded)
+	}
+	if log.calls != 1 {
+		t.Fatalf("logged %d errors, want the failed send", log.calls)
+	}
+}
+
+func TestListService_CreateList_Occasion(t *testing.T) {
+	ls := &listStorageMock{}
+	svc := NewListService(ls, &listWishStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil)
+
+	list, err := svc.CreateList(context.Background(), uuid.New(), models.CreateListRequest{
+		Title:                "Birthday",
+		OccasionDate:         new("1990-11-03"),
+		OccasionRecursYearly: new(true),
+	})
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	if ls.createdList.OccasionDate == nil || ls.createdList.OccasionDate.Format(models.OccasionDateLayout) != "1990-11-03" || !ls.createdList.OccasionRecursYearly {
+		t.Fatalf("stored %+v, want the yearly occasion", ls.createdList)
+	}
+	if response := list.ToOwnerResponse(); response.NextOccasion == nil || response.DaysUntilOccasion == nil || *response.OccasionDate != "1990-11-03" {
+		t.Fatalf("ToOwnerResponse() = %+v, want the next occurrence and a countdown", response)
+	}
+
+	_, err = svc.CreateList(context.Background(), uuid.New(), models.CreateListRequest{Title: "Birthday", OccasionDate: new("03.11.1990")})
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("CreateList() error = %v, want ValidationError", err)
+	}
+}
+
+func TestListService_DuplicateList_Occasion(t *testing.T) {
+	friendList, wishes, s3 := newCopyFixture()
+	friendList.OccasionDate, friendList.OccasionRecursYearly = new(daysFromToday(10)), true
+	ls := &listStorageMock{listToReturn: friendList}
+	svc := NewListService(ls, &listWishStorageMock{wishes: wishes}, &listMemberStorageMock{}, nil, s3, nil, nil, nil)
+
+	copied, err := svc.DuplicateList(context.Background(), friendList.ID, uuid.New(), models.DuplicateListRequest{})
+	if err != nil {
+		t.Fatalf("DuplicateList() error = %v", err)
+	}
+	if copied.OccasionDate != nil || copied.OccasionRecursYearly {
+		t.Fatalf("DuplicateList() = %+v, want the friend's occasion left behind", copied)
+	}
+
+	own, err := svc.DuplicateList(context.Background(), friendList.ID, friendList.UserID, models.DuplicateListRequest{})
+	if err != nil {
+		t.Fatalf("DuplicateList() own error = %v", err)
+	}
+	if own.OccasionDate == nil || !own.OccasionRecursYearly {
+		t.Fatalf("DuplicateList() own = %+v, want the occasion kept", own)
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+)
+
+const priceTrackingBatchSize = 100 // Wishes fetched per run, the rest waits for the next tick
+
+type WishPriceStorage interface {
+	GetWishesToTrack(ctx context.Context, checkedBefore time.Time, limit int) ([]models.Wish, error)
+	RecordWishPrice(ctx context.Context, price models.WishPrice) error
+	MarkWishPriceChecked(ctx context.Context, wishID uuid.UUID) error
+	GetPriceDropRecipients(ctx context.Context, wishID uuid.UUID) ([]models.PriceDropRecipient, error)
+}
+
+type PriceDropSender interface {
+	SendPriceDrop(ctx context.Context, recipient models.PriceDropRecipient, drop models.PriceDrop) error
+}
+
+// PriceTrackingJob re-reads the price of wishes from their links and tells the owner and reservers when it drops
+type PriceTrackingJob struct {
+	prices      WishPriceStorage
+	scraper     LinkScraper
+	sender      PriceDropSender
+	log         Logger
+	interval    time.Duration
+	dropPercent int64
+}
+
+func NewPriceTrackingJob(ps WishPriceStorage, ls LinkScraper, pds PriceDropSender, l Logger, interval time.Duration, dropPercent int) *PriceTrackingJob {
+	return &PriceTrackingJob{prices: ps, scraper: ls, sender: pds, log: l, interval: interval, dropPercent: int64(dropPercent)}
+}
+
+// Run blocks until ctx is cancelled, errors are logged and retried on the next tick
+func (job *PriceTrackingJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		job.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (job *PriceTrackingJob) RunOnce(ctx context.Context) {
+	wishes, err := job.prices.GetWishesToTrack(ctx, time.Now().Add(-job.interval), priceTrackingBatchSize)
+	if err != nil {
+		job.log.Error("Price tracking job: %v", err)
+	}
+
+	for _, wish := range wishes {
+		if ctx.Err() != nil {
+			return
+		}
+		job.track(ctx, wish)
+	}
+}
+
+func (job *PriceTrackingJob) track(ctx context.Context, wish models.Wish) {
+	preview, err := job.scraper.Scrape(ctx, *wish.Link)
+	if err != nil || preview.Price == nil || !sameCurrency(wish.Currency, preview.Currency) {
+		// Shops go down and change layouts, try again on the next interval instead of on every tick
+		if err = job.prices.MarkWishPriceChecked(ctx, wish.ID); err != nil {
+			job.log.Error("Price tracking job: %v", err)
+		}
+		return
+	}
+
+	price := models.WishPrice{
+		ID:         uuid.New(),
+		WishID:     wish.ID,
+		Price:      *preview.Price,
+		Currency:   preview.Currency,
+		RecordedAt: time.Now(),
+	}
+	if err = job.prices.RecordWishPrice(ctx, price); err != nil {
+		job.log.Error("Price tracking job: %v", err)
+		return
+	}
+
+	if !job.isDrop(wish, price.Price) {
+		return
+	}
+
+	drop := models.PriceDrop{
+		WishID:    wish.ID,
+		ListID:    wish.ListID,
+		WishTitle: wish.Title,
+		OldPrice:  lastSeenPrice(wish),
+		NewPrice:  price.Price,
+		Currency:  wish.Currency,
+	}
+	if drop.Currency == nil {
+		drop.Currency = price.Currency
+	}
+
+	recipients, err := job.prices.GetPriceDropRecipients(ctx, wish.ID)
+	if err != nil {
+		job.log.Error("Price tracking job: %v", err)
+		return
+	}
+	for _, recipient := range recipients {
+		if err = job.sender.SendPriceDrop(ctx, recipient, drop); err != nil {
+			job.log.Error("Price tracking job: failed to tell user '%s' about price drop of wish '%s': %v", recipient.UserID, wish.ID, err)
+		}
+	}
+}
+
+// isDrop reports whether the new price crossed the owner's alert threshold, or fell by the configured percent when there is none
+func (job *PriceTrackingJob) isDrop(wish models.Wish, price int64) bool {
+	old := lastSeenPrice(wish)
+	if price >= old {
+		return false
+	}
+	if wish.PriceAlert != nil {
+		return price < *wish.PriceAlert && old >= *wish.PriceAlert // Only once, when it goes below
+	}
+	return job.dropPercent > 0 && (old-price)*100 >= old*job.dropPercent
+}
+
+// lastSeenPrice is what the price is compared to, the price set by the owner until the link was read once
+func lastSeenPrice(wish models.Wish) int64 {
+	if wish.CurrentPrice != nil {
+		return *wish.CurrentPrice
+	}
+	return *wish.Price
+}
+
+func sameCurrency(a, b *string) bool {
+	if a == nil || b == nil {
+		return true // Unknown on one side, the number is all we have
+	}
+	return strings.EqualFold(*a, *b)
+}
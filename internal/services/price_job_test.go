@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type wishPriceStorageMock struct {
+	toTrack    []models.Wish
+	recipients []models.PriceDropRecipient
+	recorded   []models.WishPrice
+	checked    []uuid.UUID
+	history    []models.WishPrice
+}
+
+func (m *wishPriceStorageMock) GetWishesToTrack(ctx context.Context, checkedBefore time.Time, limit int) ([]models.Wish, error) {
+	return m.toTrack, nil
+}
+
+func (m *wishPriceStorageMock) RecordWishPrice(ctx context.Context, price models.WishPrice) error {
+	m.recorded = append(m.recorded, price)
+	return nil
+}
+
+func (m *wishPriceStorageMock) MarkWishPriceChecked(ctx context.Context, wishID uuid.UUID) error {
+	m.checked = append(m.checked, wishID)
+	return nil
+}
+
+func (m *wishPriceStorageMock) GetPriceDropRecipients(ctx context.Context, wishID uuid.UUID) ([]models.PriceDropRecipient, error) {
+	return m.recipients, nil
+}
+
+func (m *wishPriceStorageMock) GetPriceHistory(ctx context.Context, wishID uuid.UUID) ([]models.WishPrice, error) {
+	return m.history, nil
+}
+
+// priceScraperMock answers with a price per link, links without one fail
+type priceScraperMock struct {
+	prices   map[string]int64
+	currency *string
+}
+
+func (m *priceScraperMock) Scrape(ctx context.Context, link string) (models.LinkPreview, error) {
+	price, ok := m.prices[link]
+	if !ok {
+		return models.LinkPreview{}, errors.New("page is down")
+	}
+	return models.LinkPreview{Link: link, Price: &price, Currency: m.currency}, nil
+}
+
+func (m *priceScraperMock) FetchImage(ctx context.Context, imageURL string) ([]byte, string, error) {
+	return nil, "", errors.New("not implemented")
+}
+
+type priceDropSenderMock struct {
+	sent []models.PriceDrop
+	to   []string
+}
+
+func (m *priceDropSenderMock) SendPriceDrop(ctx context.Context, recipient models.PriceDropRecipient, drop models.PriceDrop) error {
+	m.sent = append(m.sent, drop)
+	m.to = append(m.to, recipient.Email)
+	return nil
+}
+
+func TestPriceTrackingJob_RunOnce(t *testing.T) {
+	dropped := models.Wish{ID: uuid.New(), Title: "Kettle", Link: new("https://shop.example.com/kettle"), Price: new(int64(100)), Currency: new("EUR")}
+	same := models.Wish{ID: uuid.New(), Title: "Mug", Link: new("https://shop.example.com/mug"), Price: new(int64(10)), Currency: new("EUR")}
+	down := models.Wish{ID: uuid.New(), Title: "Lamp", Link: new("https://shop.example.com/lamp"), Price: new(int64(50))}
+
+	ps := &wishPriceStorageMock{
+		toTrack: []models.Wish{dropped, same, down},
+		recipients: []models.PriceDropRecipient{
+			{UserID: uuid.New(), Email: "owner@example.com"},
+			{UserID: uuid.New(), Email: "reserver@example.com", IsReserver: true},
+		},
+	}
+	scraper := &priceScraperMock{prices: map[string]int64{*dropped.Link: 85, *same.Link: 10}, currency: new("eur")}
+	sender := &priceDropSenderMock{}
+	job := NewPriceTrackingJob(ps, scraper, sender, &userLoggerMock{}, time.Hour, 10)
+
+	job.RunOnce(context.Background())
+
+	if len(ps.recorded) != 2 {
+		t.Fatalf("recorded prices = %d, want 2", len(ps.recorded))
+	}
+	if len(ps.checked) != 1 || ps.checked[0] != down.ID {
+		t.Fatalf("marked checked = %v, want only %s", ps.checked, down.ID)
+	}
+	if len(sender.sent) != 2 || sender.to[0] != "owner@example.com" || sender.to[1] != "reserver@example.com" {
+		t.Fatalf("sent to = %v, want owner and reserver", sender.to)
+	}
+	if drop := sender.sent[0]; drop.WishID != dropped.ID || drop.OldPrice != 100 || drop.NewPrice != 85 {
+		t.Fatalf("drop = %+v, want 100 -> 85 for %s", drop, dropped.ID)
+	}
+}
+
+func TestPriceTrackingJob_RunOnce_SkipsOtherCurrency(t *testing.T) {
+	wish := models.Wish{ID: uuid.New(), Link: new("https://shop.example.com/kettle"), Price: new(int64(100)), Currency: new("EUR")}
+	ps := &wishPriceStorageMock{toTrack: []models.Wish{wish}}
+	scraper := &priceScraperMock{prices: map[string]int64{*wish.Link: 20}, currency: new("USD")}
+	sender := &priceDropSenderMock{}
+	job := NewPriceTrackingJob(ps, scraper, sender, &userLoggerMock{}, time.Hour, 10)
+
+	job.RunOnce(context.Background())
+
+	if len(ps.recorded) != 0 || len(sender.sent) != 0 {
+		t.Fatalf("recorded = %d, sent = %d, want nothing for another currency", len(ps.recorded), len(sender.sent))
+	}
+	if len(ps.checked) != 1 {
+		t.Fatalf("marked checked = %d, want 1", len(ps.checked))
+	}
+}
+
+func TestPriceTrackingJob_isDrop(t *testing.T) {
+	job := &PriceTrackingJob{dropPercent: 10}
+
+	tests := []struct {
+		name  string
+		wish  models.Wish
+		price int64
+		want  bool
+	}{
+		{name: "small drop", wish: models.Wish{Price: new(int64(100))}, price: 95, want: false},
+		{name: "drop by percent", wish: models.Wish{Price: new(int64(100))}, price: 90, want: true},
+		{name: "price went up", wish: models.Wish{Price: new(int64(100))}, price: 120, want: false},
+		{name: "crossed alert", wish: models.Wish{Price: new(int64(100)), PriceAlert: new(int64(98))}, price: 97, want: true},
+		{name: "above alert", wish: models.Wish{Price: new(int64(100)), PriceAlert: new(int64(50))}, price: 80, want: false},
+		{name: "already below alert", wish: models.Wish{Price: new(int64(40)), PriceAlert: new(int64(50))}, price: 30, want: false},
+		{name: "seen below alert before", wish: models.Wish{Price: new(int64(100)), CurrentPrice: new(int64(45)), PriceAlert: new(int64(50))}, price: 44, want: false},
+		{name: "drop from the last seen price", wish: models.Wish{Price: new(int64(100)), CurrentPrice: new(int64(200))}, price: 150, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := job.isDrop(tt.wish, tt.price); got != tt.want {
+				t.Fatalf("isDrop() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWishService_GetPriceHistory(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	ownerID := uuid.New()
+	history := []models.WishPrice{{WishID: wishID, Price: 100}, {WishID: wishID, Price: 85}}
+	wishStorage := &wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID}}
+
+	private := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID, Visibility: models.ListVisibilityPrivate}}
+	svc := NewWishService(wishStorage, private, &listMemberStorageMock{}, nil, nil, nil, &wishPriceStorageMock{history: history}, nil, nil)
+
+	got, err := svc.GetPriceHistory(context.Background(), listID, wishID, ownerID)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("GetPriceHistory(owner) = %v, %v", got, err)
+	}
+
+	_, err = svc.GetPriceHistory(context.Background(), listID, wishID, uuid.New())
+	if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+		t.Fatalf("GetPriceHistory(stranger) error = %v, want ForbiddenError", err)
+	}
+
+	_, err = svc.GetPriceHistory(context.Background(), uuid.New(), wishID, ownerID)
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("GetPriceHistory(other list) error = %v, want ValidationError", err)
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+)
+
+type ReservationExpiryStorage interface {
+	GetReservationsToRemind(ctx context.Context, before time.Time) ([]models.ExpiringReservation, error)
+	MarkReservationReminded(ctx context.Context, wishID, userID uuid.UUID) error
+	ReleaseExpiredReservations(ctx context.Context) (int64, error)
+}
+
+type ReservationReminderSender interface {
+	SendReservationReminder(ctx context.Context, reservation models.ExpiringReservation) error
+}
+
+// ReservationExpiryJob reminds reservers shortly before their deadline and releases reservations once it passes
+type ReservationExpiryJob struct {
+	reservations ReservationExpiryStorage
+	sender       ReservationReminderSender
+	log          Logger
+	interval     time.Duration
+	remindBefore time.Duration
+}
+
+func NewReservationExpiryJob(rs ReservationExpiryStorage, rrs ReservationReminderSender, l Logger, interval, remindBefore time.Duration) *ReservationExpiryJob {
+	return &ReservationExpiryJob{reservations: rs, sender: rrs, log: l, interval: interval, remindBefore: remindBefore}
+}
+
+// Run blocks until ctx is cancelled, errors are logged and retried on the next tick
+func (job *ReservationExpiryJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		job.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (job *ReservationExpiryJob) RunOnce(ctx context.Context) {
+	reservations, err := job.reservations.GetReservationsToRemind(ctx, time.Now().Add(job.remindBefore))
+	if err != nil {
+		job.log.Error("Reservation expiry job: %v", err)
+	}
+	for _, reservation := range reservations {
+		if err = job.sender.SendReservationReminder(ctx, reservation); err != nil {
+			job.log.Error("Reservation expiry job: failed to remind user '%s' about wish '%s': %v", reservation.UserID, reservation.WishID, err)
+			continue // Not marked, so it is retried on the next run
+		}
+		if err = job.reservations.MarkReservationReminded(ctx, reservation.WishID, reservation.UserID); err != nil {
+			job.log.Error("Reservation expiry job: %v", err)
+		}
+	}
+
+	if _, err = job.reservations.ReleaseExpiredReservations(ctx); err != nil {
+		job.log.Error("Reservation expiry job: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+)
+
+type reservationExpiryStorageMock struct {
+	toRemind   []models.ExpiringReservation
+	getErr     error
+	remindedAt []uuid.UUID
+	released   int
+}
+
+func (m *reservationExpiryStorageMock) GetReservationsToRemind(ctx context.Context, before time.Time) ([]models.ExpiringReservation, error) {
+	return m.toRemind, m.getErr
+}
+
+func (m *reservationExpiryStorageMock) MarkReservationReminded(ctx context.Context, wishID, userID uuid.UUID) error {
+	m.remindedAt = append(m.remindedAt, wishID)
+	return nil
+}
+
+func (m *reservationExpiryStorageMock) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	m.released++
+	return 0, nil
+}
+
+type reservationReminderSenderMock struct {
+	failFor uuid.UUID
+	sent    []string
+}
+
+func (m *reservationReminderSenderMock) SendReservationReminder(ctx context.Context, reservation models.ExpiringReservation) error {
+	if reservation.WishID == m.failFor {
+		return errors.New("broker down")
+	}
+	m.sent = append(m.sent, reservation.Email)
+	return nil
+}
+
+func TestReservationExpiryJob_RunOnce(t *testing.T) {
+	reservedUntil := time.Now().Add(time.Hour)
+	okWishID := uuid.New()
+	failingWishID := uuid.New()
+	rs := &reservationExpiryStorageMock{toRemind: []models.ExpiringReservation{
+		{WishReservation: models.WishReservation{WishID: failingWishID, UserID: uuid.New(), ReservedUntil: &reservedUntil}, Email: "alice@example.com"},
+		{WishReservation: models.WishReservation{WishID: okWishID, UserID: uuid.New(), ReservedUntil: &reservedUntil}, Email: "bob@example.com"},
+	}}
+	sender := &reservationReminderSenderMock{failFor: failingWishID}
+	log := &userLoggerMock{}
+	job := NewReservationExpiryJob(rs, sender, log, time.Minute, 24*time.Hour)
+
+	job.RunOnce(context.Background())
+
+	if len(sender.sent) != 1 || sender.sent[0] != "bob@example.com" {
+		t.Fatalf("sent reminders = %v, want only bob@example.com", sender.sent)
+	}
+	if len(rs.remindedAt) != 1 || rs.remindedAt[0] != okWishID {
+		t.Fatalf("marked reminded = %v, want only %s", rs.remindedAt, okWishID)
+	}
+	if log.calls != 1 {
+		t.Fatalf("logged errors = %d, want 1", log.calls)
+	}
+	if rs.released != 1 {
+		t.Fatalf("ReleaseExpiredReservations() calls = %d, want 1", rs.released)
+	}
+}
+
+func TestReservationExpiryJob_RunOnce_ReleasesEvenIfReminderLookupFails(t *testing.T) {
+	rs := &reservationExpiryStorageMock{getErr: errors.New("db")}
+	job := NewReservationExpiryJob(rs, &reservationReminderSenderMock{}, &userLoggerMock{}, time.Minute, time.Hour)
+
+	job.RunOnce(context.Background())
+
+	if rs.released != 1 {
+		t.Fatalf("ReleaseExpiredReservations() calls = %d, want 1", rs.released)
+	}
+}
+
+func TestWishService_ReserveWish_DeadlineInPast(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	wishStorage := &wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID, Quantity: 1, Status: models.WishStatusOpen}}
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: uuid.New()}}
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+	err := svc.ReserveWish(context.Background(), listID, wishID, uuid.New(), models.ReserveWishRequest{ReservedUntil: new(time.Now().Add(-time.Minute))})
+	if err == nil {
+		t.Fatal("ReserveWish() error = nil, want validation error")
+	}
+	if wishStorage.reservedID != uuid.Nil {
+		t.Fatal("ReserveWish() reached storage with a past deadline")
+	}
+}
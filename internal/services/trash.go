@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type TrashStorage interface {
+	GetTrashItems(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error)
+	GetTrashItem(ctx context.Context, id, userID uuid.UUID) (models.TrashItem, error)
+	RestoreListByID(ctx context.Context, listID uuid.UUID) error
+	RestoreWishByID(ctx context.Context, wishID uuid.UUID) error
+}
+
+// TrashServiceImpl shows deleted lists and wishes and brings them back until the purge job removes them
+type TrashServiceImpl struct {
+	trash     TrashStorage
+	retention time.Duration
+}
+
+func NewTrashService(ts TrashStorage, retention time.Duration) *TrashServiceImpl {
+	return &TrashServiceImpl{trash: ts, retention: retention}
+}
+
+// GetTrash returns the deleted lists the user manages and the deleted wishes of lists the user edits
+func (svc *TrashServiceImpl) GetTrash(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error) {
+	items, err := svc.trash.GetTrashItems(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(svc.retention)
+	}
+
+	return items, nil
+}
+
+// RestoreItem takes a list or a wish out of the trash, whoever could delete it may restore it
+func (svc *TrashServiceImpl) RestoreItem(ctx context.Context, id, userID uuid.UUID) (models.TrashItem, error) {
+	item, err := svc.trash.GetTrashItem(ctx, id, userID)
+	if err != nil {
+		return models.TrashItem{}, err
+	}
+
+	switch item.Type {
+	case models.TrashItemList:
+		if !item.Role.CanManage() {
+			return models.TrashItem{}, svcErr.ForbiddenError{Message: "you are not the owner of this wishlist"}
+		}
+		err = svc.trash.RestoreListByID(ctx, id)
+	default:
+		if !item.Role.CanEdit() {
+			return models.TrashItem{}, svcErr.ForbiddenError{Message: "you are not allowed to edit this wish"}
+		}
+		err = svc.trash.RestoreWishByID(ctx, id)
+	}
+	if err != nil {
+		return models.TrashItem{}, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"wishlist/internal/models"
+)
+
+type TrashPurgeStorage interface {
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) (models.TrashPurgeReport, error)
+}
+
+// TrashPurgeJob deletes for good the lists and wishes that stayed in the trash longer than the retention period,
+// and their images with them
+type TrashPurgeJob struct {
+	trash     TrashPurgeStorage
+	objects   *ObjectTracker
+	log       ReportLogger
+	interval  time.Duration
+	retention time.Duration
+}
+
+func NewTrashPurgeJob(ts TrashPurgeStorage, ot *ObjectTracker, l ReportLogger, interval, retention time.Duration) *TrashPurgeJob {
+	return &TrashPurgeJob{trash: ts, objects: ot, log: l, interval: interval, retention: retention}
+}
+
+// Run blocks until ctx is cancelled, errors are logged and retried on the next tick
+func (job *TrashPurgeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		job.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (job *TrashPurgeJob) RunOnce(ctx context.Context) {
+	report, err := job.trash.PurgeTrash(ctx, time.Now().Add(-job.retention))
+	if err != nil {
+		job.log.Error("Trash purge job: %v", err)
+		return
+	}
+
+	// Images are released once the rows are gone, a trashed wish still holds on to its image
+	for _, listID := range report.Lists {
+		job.objects.Release(ctx, models.ObjectOwner{ListID: &listID})
+	}
+	for _, wishID := range report.Wishes {
+		job.objects.Release(ctx, models.ObjectOwner{WishID: &wishID})
+	}
+
+	if len(report.Lists) > 0 || len(report.Wishes) > 0 {
+		job.log.Info("Trash purge job: purged %d lists and %d wishes", len(report.Lists), len(report.Wishes))
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
+)
+
+type trashStorageMock struct {
+	items        []models.TrashItem
+	restoredList uuid.UUID
+	restoredWish uuid.UUID
+	purgeBefore  time.Time
+	report       models.TrashPurgeReport
+	purgeErr     error
+}
+
+func (m *trashStorageMock) GetTrashItems(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error) {
+	return m.items, nil
+}
+
+func (m *trashStorageMock) GetTrashItem(ctx context.Context, id, userID uuid.UUID) (models.TrashItem, error) {
+	for _, item := range m.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return models.TrashItem{}, svcErr.NotFoundError{Entity: "trash item", Field: "id", Value: id.String()}
+}
+
+func (m *trashStorageMock) RestoreListByID(ctx context.Context, listID uuid.UUID) error {
+	m.restoredList = listID
+	return nil
+}
+
+func (m *trashStorageMock) RestoreWishByID(ctx context.Context, wishID uuid.UUID) error {
+	m.restoredWish = wishID
+	return nil
+}
+
+func (m *trashStorageMock) PurgeTrash(ctx context.Context, deletedBefore time.Time) (models.TrashPurgeReport, error) {
+	m.purgeBefore = deletedBefore
+	return m.report, m.purgeErr
+}
+
+func TestTrashService_GetTrash(t *testing.T) {
+	deletedAt := time.Date(2030, 3, 8, 12, 0, 0, 0, time.UTC)
+	storage := &trashStorageMock{items: []models.TrashItem{{Type: models.TrashItemList, ID: uuid.New(), DeletedAt: deletedAt}}}
+	svc := NewTrashService(storage, 720*time.Hour)
+
+	items, err := svc.GetTrash(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetTrash() error = %v", err)
+	}
+	if len(items) != 1 || !items[0].PurgeAt.Equal(deletedAt.Add(720*time.Hour)) {
+		t.Fatalf("GetTrash() = %+v, want the purge time after the retention period", items)
+	}
+}
+
+func TestTrashService_RestoreItem(t *testing.T) {
+	listID, wishID, editorsListID := uuid.New(), uuid.New(), uuid.New()
+	storage := &trashStorageMock{items: []models.TrashItem{
+		{Type: models.TrashItemList, ID: listID, Role: models.ListRoleOwner},
+		{Type: models.TrashItemWish, ID: wishID, ListID: new(uuid.New()), Role: models.ListRoleEditor},
+		{Type: models.TrashItemList, ID: editorsListID, Role: models.ListRoleEditor},
+	}}
+	svc := NewTrashService(storage, time.Hour)
+
+	t.Run("list", func(t *testing.T) {
+		item, err := svc.RestoreItem(context.Background(), listID, uuid.New())
+		if err != nil || item.ID != listID || storage.restoredList != listID {
+			t.Fatalf("RestoreItem() = %+v, %v, restored list %s", item, err, storage.restoredList)
+		}
+	})
+
+	t.Run("wish", func(t *testing.T) {
+		if _, err := svc.RestoreItem(context.Background(), wishID, uuid.New()); err != nil || storage.restoredWish != wishID {
+			t.Fatalf("RestoreItem() error = %v, restored wish %s", err, storage.restoredWish)
+		}
+	})
+
+	t.Run("list of an editor", func(t *testing.T) {
+		_, err := svc.RestoreItem(context.Background(), editorsListID, uuid.New())
+		if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+			t.Fatalf("RestoreItem() error = %v, want ForbiddenError", err)
+		}
+	})
+
+	t.Run("not in trash", func(t *testing.T) {
+		_, err := svc.RestoreItem(context.Background(), uuid.New(), uuid.New())
+		if _, ok := errors.AsType[svcErr.NotFoundError](err); !ok {
+			t.Fatalf("RestoreItem() error = %v, want NotFoundError", err)
+		}
+	})
+}
+
+func TestTrashPurgeJob_RunOnce(t *testing.T) {
+	listID, wishID := uuid.New(), uuid.New()
+	storage := &trashStorageMock{report: models.TrashPurgeReport{Lists: []uuid.UUID{listID}, Wishes: []uuid.UUID{wishID}}}
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+	records := &objectRecordStorageMock{released: []models.StoredObject{{ObjectName: "wishes/1/2", URL: "wishes/1/2/original"}}}
+	log := &reportLoggerMock{}
+	job := NewTrashPurgeJob(storage, NewObjectTracker(records, s3, log), log, time.Hour, 720*time.Hour)
+
+	job.RunOnce(context.Background())
+
+	if since := time.Since(storage.purgeBefore); since < 720*time.Hour || since > 721*time.Hour {
+		t.Fatalf("purged items deleted before %s, want the retention period ago", storage.purgeBefore)
+	}
+	if len(records.owners) != 2 || *records.owners[0].ListID != listID || *records.owners[1].WishID != wishID {
+		t.Fatalf("released %+v, want the purged list and wish", records.owners)
+	}
+	if len(s3.deletedObjs) != 6 || log.infos != 1 {
+		t.Fatalf("deleted %v and logged %d reports", s3.deletedObjs, log.infos)
+	}
+}
+
+func TestTrashPurgeJob_RunOnce_StorageError(t *testing.T) {
+	storage := &trashStorageMock{purgeErr: errors.New("db error")}
+	records := &objectRecordStorageMock{}
+	log := &reportLoggerMock{}
+	job := NewTrashPurgeJob(storage, NewObjectTracker(records, &userAvatarStorageMock{}, log), log, time.Hour, time.Hour)
+
+	job.RunOnce(context.Background())
+
+	if log.calls != 1 || len(records.owners) != 0 {
+		t.Fatalf("logged %d errors and released %+v, want only the error", log.calls, records.owners)
+	}
+}
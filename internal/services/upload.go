@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+	"wishlist/internal/storage"
+)
+
+const uploadURLTTL = 15 * time.Minute
+
+// newUploadURL presigns a key under the uploads of the user, nothing is recorded: a file that is never confirmed
+// is unreferenced and goes away with the object sweep
+func newUploadURL(ctx context.Context, s3 AvatarStorage, userID uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error) {
+	objectKey := fmt.Sprintf(storage.UploadPrefix, userID, uuid.NewString())
+	uploadURL, err := s3.PresignUpload(ctx, objectKey, req.ContentType, req.Size, uploadURLTTL)
+	if err != nil {
+		return models.UploadURLResponse{}, err
+	}
+
+	return models.UploadURLResponse{
+		URL:       uploadURL,
+		ObjectKey: objectKey,
+		Headers:   map[string]string{"Content-Type": req.ContentType},
+		ExpiresAt: time.Now().Add(uploadURLTTL),
+	}, nil
+}
+
+// confirmUpload checks the file the user put to S3 and hands it to attach, which runs it through the image pipeline.
+// The raw upload is discarded afterwards, only the processed variants are kept
+func confirmUpload(ctx context.Context, s3 AvatarStorage, objects *ObjectTracker, userID uuid.UUID, objectKey string, maxSize int64, attach func(reader io.Reader) error) error {
+	if !strings.HasPrefix(objectKey, fmt.Sprintf(storage.UploadPrefix, userID, "")) || strings.Contains(objectKey, "..") {
+		return svcErr.ForbiddenError{Message: "this upload does not belong to you"}
+	}
+
+	info, err := s3.StatObject(ctx, objectKey)
+	if err != nil {
+		return err
+	}
+	if info.Size > maxSize {
+		return svcErr.ValidationError{Message: fmt.Sprintf("image file too large (max %d MB)", maxSize>>20)}
+	}
+	switch info.ContentType {
+	case "image/jpeg", "image/png", "image/webp", "image/gif":
+	default:
+		return svcErr.ValidationError{Message: "unsupported image format (PNG, JPG, WEBP or GIF only)"}
+	}
+
+	object, err := s3.GetObject(ctx, objectKey)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = object.Close() }()
+
+	if err = attach(io.LimitReader(object, maxSize)); err != nil {
+		return err
+	}
+
+	objects.Discard(ctx, objectKey) // The image is attached already, a leftover upload is not the client's problem
+
+	return nil
+}
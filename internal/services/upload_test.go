@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+func TestUserService_CreateAvatarUploadURL(t *testing.T) {
+	id := uuid.New()
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+	svc := NewUserService(&userEmailServiceMock{}, &userStorageServiceMock{}, &userTokenStorageMock{}, s3, &userLoggerMock{}, nil, 0)
+
+	upload, err := svc.CreateAvatarUploadURL(context.Background(), id, models.UploadURLRequest{ContentType: "image/png", Size: 1024})
+	if err != nil {
+		t.Fatalf("CreateAvatarUploadURL() error = %v", err)
+	}
+	if !strings.HasPrefix(upload.ObjectKey, "uploads/"+id.String()+"/") || upload.ObjectKey != s3.presignedObject {
+		t.Fatalf("object key = %s, want one under the uploads of the user", upload.ObjectKey)
+	}
+	if upload.Headers["Content-Type"] != "image/png" || upload.ExpiresAt.IsZero() {
+		t.Fatalf("upload = %+v, want the signed content type and an expiry", upload)
+	}
+}
+
+func TestUserService_ConfirmAvatarUpload(t *testing.T) {
+	id := uuid.New()
+	key := "uploads/" + id.String() + "/1"
+	newService := func(t *testing.T, contentType string) (*UserServiceImpl, *userStorageServiceMock, *userAvatarStorageMock) {
+		st := &userStorageServiceMock{userByID: models.User{ID: id}}
+		s3 := &userAvatarStorageMock{
+			baseURL: "http://minio:9000/wishlist",
+			stored:  map[string]storedObjectMock{key: {data: testPNG(t), contentType: contentType}},
+		}
+		objects := NewObjectTracker(&objectRecordStorageMock{}, s3, &userLoggerMock{})
+		return NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, s3, &userLoggerMock{}, objects, 0), st, s3
+	}
+
+	t.Run("sets the avatar and removes the raw upload", func(t *testing.T) {
+		svc, st, s3 := newService(t, "image/png")
+
+		if err := svc.ConfirmAvatarUpload(context.Background(), id, key, 5<<20); err != nil {
+			t.Fatalf("ConfirmAvatarUpload() error = %v", err)
+		}
+		if st.updatedUserReq.Avatar == nil || !strings.HasSuffix(*st.updatedUserReq.Avatar, "/original") || len(s3.uploadedObjects) != 3 {
+			t.Fatalf("avatar = %v, uploaded %v, want the processed variants", st.updatedUserReq.Avatar, s3.uploadedObjects)
+		}
+		if s3.deletedObj != key {
+			t.Fatalf("deleted %s, want the raw upload %s", s3.deletedObj, key)
+		}
+	})
+
+	t.Run("raw upload left behind", func(t *testing.T) {
+		svc, st, s3 := newService(t, "image/png")
+		s3.deleteErr = errors.New("minio unavailable")
+
+		if err := svc.ConfirmAvatarUpload(context.Background(), id, key, 5<<20); err != nil {
+			t.Fatalf("ConfirmAvatarUpload() error = %v, want the attached avatar reported as done", err)
+		}
+		if st.updatedUserReq.Avatar == nil {
+			t.Fatal("avatar was not set")
+		}
+	})
+
+	t.Run("upload of someone else", func(t *testing.T) {
+		svc, _, _ := newService(t, "image/png")
+
+		err := svc.ConfirmAvatarUpload(context.Background(), id, "uploads/"+uuid.NewString()+"/1", 5<<20)
+		if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+			t.Fatalf("ConfirmAvatarUpload() error = %v, want ForbiddenError", err)
+		}
+	})
+
+	t.Run("never uploaded", func(t *testing.T) {
+		svc, _, _ := newService(t, "image/png")
+
+		err := svc.ConfirmAvatarUpload(context.Background(), id, "uploads/"+id.String()+"/2", 5<<20)
+		if _, ok := errors.AsType[svcErr.NotFoundError](err); !ok {
+			t.Fatalf("ConfirmAvatarUpload() error = %v, want NotFoundError", err)
+		}
+	})
+
+	t.Run("wrong type or size", func(t *testing.T) {
+		svc, st, _ := newService(t, "text/html")
+
+		err := svc.ConfirmAvatarUpload(context.Background(), id, key, 5<<20)
+		if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+			t.Fatalf("ConfirmAvatarUpload(html) error = %v, want ValidationError", err)
+		}
+
+		svc, st, _ = newService(t, "image/png")
+		err = svc.ConfirmAvatarUpload(context.Background(), id, key, 10)
+		if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+			t.Fatalf("ConfirmAvatarUpload(large) error = %v, want ValidationError", err)
+		}
+		if st.updatedUserReq.Avatar != nil {
+			t.Fatal("avatar was set from a rejected upload")
+		}
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -21,11 +22,21 @@ import (
 type EmailService interface {
 	SendPasswordResetLetter(ctx context.Context, to, token string) error
 	SendEmailVerificationLetter(ctx context.Context, to, token string) error
+	SendReservationReminderLetter(ctx context.Context, to, wishTitle, listID string, reservedUntil time.Time) error
+	SendPriceDropLetter(ctx context.Context, to, wishTitle, listID string, oldPrice, newPrice int64, currency string, toReserver bool) error
+	SendDataExportLetter(ctx context.Context, to, token string, expiresAt time.Time) error
+	SendAccountDeletionLetter(ctx context.Context, to, token string, deleteAfter time.Time) error
+	SendOccasionReminderLetter(ctx context.Context, to, listTitle, listID string, date time.Time, toReserver bool) error
+	SendGroupAssignmentLetter(ctx context.Context, to, groupTitle, receiverName, listID string) error
+	SendGuestReservationLetter(ctx context.Context, to, name, wishTitle, listTitle, token string, confirmBefore time.Time) error
 }
 
 type EmailSender interface {
 	SendPasswordReset(ctx context.Context, userID, to, token string) error
 	SendEmailVerification(ctx context.Context, userID, to, token string) error
+	SendAccountDeletion(ctx context.Context, userID, to, token string, deleteAfter time.Time) error
+	SendGroupAssignment(ctx context.Context, userID, to string, notice models.GroupAssignmentNotice) error
+	SendGuestReservation(ctx context.Context, to string, notice models.GuestReservationNotice) error
 }
 
 type UserStorage interface {
@@ -37,7 +48,8 @@ type UserStorage interface {
 	GetUserByEmail(ctx context.Context, email string) (models.User, error)
 	UpdateUserByID(ctx context.Context, id uuid.UUID, req models.UpdateUserRequest) error
 	RemoveUserAvatar(ctx context.Context, id uuid.UUID) error
-	DeleteUserByID(ctx context.Context, id uuid.UUID) error
+	ScheduleUserDeletion(ctx context.Context, id uuid.UUID, deleteAfter time.Time) error
+	CancelUserDeletion(ctx context.Context, id uuid.UUID) error
 }
 
 type AvatarStorage interface {
@@ -45,6 +57,10 @@ type AvatarStorage interface {
 	UploadObject(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) error
 	GetObjectURL(objectName string) string
 	DeleteObject(ctx context.Context, objectName string) error
+	PresignUpload(ctx context.Context, objectName, contentType string, size int64, expires time.Duration) (string, error)
+	StatObject(ctx context.Context, objectName string) (models.BucketObject, error)
+	GetObject(ctx context.Context, objectName string) (io.ReadCloser, error)
+	CopyObject(ctx context.Context, srcName, dstName string) error
 }
 
 type Logger interface {
@@ -57,10 +73,12 @@ type UserServiceImpl struct {
 	storage UserStorage
 	s3      AvatarStorage
 	log     Logger //MARK: Unsure if it is a good idea, but definitely better than putting logger from controller
+	objects *ObjectTracker
+	grace   time.Duration // How long a deleted account can be reactivated, AccountDeletionJob deletes it afterwards
 }
 
-func NewUserService(es EmailSender, us UserStorage, ts TokenStorage, ms AvatarStorage, l Logger) *UserServiceImpl {
-	return &UserServiceImpl{email: es, tokens: ts, storage: us, s3: ms, log: l}
+func NewUserService(es EmailSender, us UserStorage, ts TokenStorage, ms AvatarStorage, l Logger, ot *ObjectTracker, deletionGrace time.Duration) *UserServiceImpl {
+	return &UserServiceImpl{email: es, tokens: ts, storage: us, s3: ms, log: l, objects: ot, grace: deletionGrace}
 }
 
 func (svc *UserServiceImpl) Register(ctx context.Context, req models.RegisterUserRequest) (models.User, error) {
@@ -145,6 +163,17 @@ func (svc *UserServiceImpl) LogIn(ctx context.Context, req models.LogInUserReque
 		return models.User{}, fmt.Errorf("invalid password: %w", err)
 	}
 
+	// A deactivated account is returned as it is, the caller offers to reactivate it instead of logging in
+	if user.IsDeactivated() && req.Reactivate {
+		if err = svc.storage.CancelUserDeletion(ctx, user.ID); err != nil {
+			return models.User{}, err
+		}
+		if err = svc.tokens.DeleteAccountDeletionToken(ctx, user.ID.String()); err != nil { // The emailed link has nothing left to cancel
+			svc.log.Error("failed to delete account deletion token for user '%s': %v", user.ID, err)
+		}
+		user.DeleteAfter = nil
+	}
+
 	//if user.Email != nil && !user.EmailVerified {
 	//	return models.User{}, svcErr.ValidationError{Message: "email not verified"}
 	//}
@@ -152,13 +181,30 @@ func (svc *UserServiceImpl) LogIn(ctx context.Context, req models.LogInUserReque
 	return user, nil
 }
 
+// GetUserByID returns an active account, one scheduled for deletion is not found until it is reactivated
 func (svc *UserServiceImpl) GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error) {
-	return svc.storage.GetUserByID(ctx, id)
+	user, err := svc.storage.GetUserByID(ctx, id)
+	if err != nil {
+		return models.User{}, err
+	}
+	if user.IsDeactivated() {
+		return models.User{}, svcErr.NotFoundError{Entity: "user", Field: "id", Value: id.String()}
+	}
+
+	return user, nil
 }
 
 func (svc *UserServiceImpl) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
 	username = getCanonicalUsername(username)
-	return svc.storage.GetUserByUsername(ctx, username)
+	user, err := svc.storage.GetUserByUsername(ctx, username)
+	if err != nil {
+		return models.User{}, err
+	}
+	if user.IsDeactivated() {
+		return models.User{}, svcErr.NotFoundError{Entity: "user", Field: "username", Value: username}
+	}
+
+	return user, nil
 }
 
 func (svc *UserServiceImpl) SearchUsersByUsername(ctx context.Context, query string, limit int) ([]models.User, error) {
@@ -174,6 +220,13 @@ func (svc *UserServiceImpl) UpdateUserByID(ctx context.Context, id uuid.UUID, re
 		}
 		req.Username = &trimmedUsername
 	}
+	if req.Currency != nil {
+		code, err := normalizeCurrency(*req.Currency)
+		if err != nil {
+			return err
+		}
+		req.Currency = new(code)
+	}
 
 	return svc.storage.UpdateUserByID(ctx, id, req)
 }
@@ -195,10 +248,12 @@ func extractObjectNameFromURL(fileURL, baseURL string) string {
 	return strings.TrimPrefix(objectName, "/")
 }
 
-func (svc *UserServiceImpl) UpdateAvatar(ctx context.Context, id uuid.UUID, reader io.Reader, size int64, contentType string) error {
-	objectName := fmt.Sprintf(storage.AvatarPrefix, id, uuid.NewString())
-
-	if err := svc.s3.UploadObject(ctx, objectName, reader, size, contentType); err != nil {
+func (svc *UserServiceImpl) UpdateAvatar(ctx context.Context, id uuid.UUID, reader io.Reader) error {
+	avatarKey, err := uploadImage(ctx, svc.s3, fmt.Sprintf(storage.AvatarPrefix, id, uuid.NewString()), reader)
+	if err != nil {
+		if _, ok := errors.AsType[svcErr.ValidationError](err); ok {
+			return err
+		}
 		return fmt.Errorf("failed to upload avatar: %w", err)
 	}
 
@@ -207,22 +262,33 @@ func (svc *UserServiceImpl) UpdateAvatar(ctx context.Context, id uuid.UUID, read
 		return err
 	}
 
-	if err = svc.storage.UpdateUserByID(ctx, id, models.UpdateUserRequest{Avatar: new(svc.s3.GetObjectURL(objectName))}); err != nil {
-		return fmt.Errorf("failed to save avatar URL: %w", err)
+	if err = svc.storage.UpdateUserByID(ctx, id, models.UpdateUserRequest{Avatar: &avatarKey}); err != nil {
+		return fmt.Errorf("failed to save avatar: %w", err)
 	}
+	svc.objects.Track(ctx, avatarKey, models.ObjectOwner{UserID: &id})
 
 	if user.Avatar != nil {
-		objectName = extractObjectNameFromURL(*user.Avatar, svc.s3.GetBaseURL())
-		if objectName != "" {
-			if deleteErr := svc.s3.DeleteObject(ctx, objectName); deleteErr != nil {
-				svc.log.Error("failed to delete previous avatar for user with ID '%s': %v", user.ID.String(), deleteErr)
-			}
+		if deleteErr := deleteImage(ctx, svc.s3, *user.Avatar); deleteErr != nil {
+			svc.log.Error("failed to delete previous avatar for user with ID '%s': %v", user.ID.String(), deleteErr)
+		} else {
+			svc.objects.Forget(ctx, *user.Avatar)
 		}
 	}
 
 	return nil
 }
 
+// CreateAvatarUploadURL lets the client upload the avatar straight to S3, ConfirmAvatarUpload then sets it
+func (svc *UserServiceImpl) CreateAvatarUploadURL(ctx context.Context, id uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error) {
+	return newUploadURL(ctx, svc.s3, id, req)
+}
+
+func (svc *UserServiceImpl) ConfirmAvatarUpload(ctx context.Context, id uuid.UUID, objectKey string, maxSize int64) error {
+	return confirmUpload(ctx, svc.s3, svc.objects, id, objectKey, maxSize, func(reader io.Reader) error {
+		return svc.UpdateAvatar(ctx, id, reader)
+	})
+}
+
 func (svc *UserServiceImpl) DeleteAvatar(ctx context.Context, id uuid.UUID) error {
 	user, err := svc.storage.GetUserByID(ctx, id)
 	if err != nil {
@@ -233,11 +299,10 @@ func (svc *UserServiceImpl) DeleteAvatar(ctx context.Context, id uuid.UUID) erro
 		return nil // Nothing to delete
 	}
 
-	objectName := extractObjectNameFromURL(*user.Avatar, svc.s3.GetBaseURL())
-	if objectName != "" {
-		if deleteErr := svc.s3.DeleteObject(ctx, objectName); deleteErr != nil {
-			svc.log.Error("failed to delete avatar for user with ID '%s': %v", id, deleteErr)
-		}
+	if deleteErr := deleteImage(ctx, svc.s3, *user.Avatar); deleteErr != nil {
+		svc.log.Error("failed to delete avatar for user with ID '%s': %v", id, deleteErr)
+	} else {
+		svc.objects.Forget(ctx, *user.Avatar)
 	}
 
 	return svc.storage.RemoveUserAvatar(ctx, id)
@@ -329,8 +394,64 @@ func (svc *UserServiceImpl) ResetPassword(ctx context.Context, token, newPasswor
 	return nil
 }
 
-func (svc *UserServiceImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	return svc.storage.DeleteUserByID(ctx, id)
+// Delete deactivates the account and schedules its deletion after the grace period, AccountDeletionJob does the rest.
+// Logging in with Reactivate or the link from the email keeps the account
+func (svc *UserServiceImpl) Delete(ctx context.Context, id uuid.UUID) (time.Time, error) {
+	user, err := svc.storage.GetUserByID(ctx, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if user.IsDeactivated() {
+		return *user.DeleteAfter, nil // Already scheduled, the first email still has a working link
+	}
+
+	token, err := str.GenerateRandomString(32)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	// Every session ends with the deactivation, logging in again with Reactivate is the way back
+	if err = svc.tokens.RevokeUserAuthTokens(ctx, id.String(), time.Now()); err != nil {
+		return time.Time{}, fmt.Errorf("failed to revoke auth tokens: %w", err)
+	}
+
+	deleteAfter := time.Now().Add(svc.grace)
+	if err = svc.storage.ScheduleUserDeletion(ctx, id, deleteAfter); err != nil {
+		return time.Time{}, err
+	}
+
+	if user.Email != nil {
+		if err = svc.tokens.SaveAccountDeletionToken(ctx, token, id.String(), svc.grace); err != nil {
+			svc.log.Error("failed to save account deletion token for user '%s': %v", id, err)
+		}
+		if err = svc.email.SendAccountDeletion(ctx, id.String(), *user.Email, token, deleteAfter); err != nil {
+			svc.log.Error("failed to send account deletion email for user '%s': %v", id, err)
+		}
+	}
+
+	return deleteAfter, nil
+}
+
+// CancelDeletion reactivates the account from the link in the account deletion email
+func (svc *UserServiceImpl) CancelDeletion(ctx context.Context, token string) error {
+	userIDStr, err := svc.tokens.GetAccountDeletionToken(ctx, token)
+	if err != nil {
+		return svcErr.ValidationError{Message: "invalid or expired cancellation link"}
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse user ID: %w", err)
+	}
+
+	if err = svc.storage.CancelUserDeletion(ctx, userID); err != nil {
+		return err
+	}
+
+	if err = svc.tokens.DeleteAccountDeletionToken(ctx, userID.String()); err != nil {
+		svc.log.Error("failed to delete account deletion token for user '%s': %v", userID, err)
+	}
+
+	return nil
 }
 
 var usernamePattern = regexp.MustCompile(`^[a-z0-9а-я_-]+$`)
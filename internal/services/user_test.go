@@ -1,10 +1,10 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
-	"strings"
 	"testing"
 	"time"
 
@@ -25,6 +25,19 @@ type userEmailServiceMock struct {
 	resetToken string
 	resetCalls int
 	resetErr   error
+
+	deletionTo          string
+	deletionToken       string
+	deletionDeleteAfter time.Time
+	deletionCalls       int
+
+	groupTo      []string
+	groupNotices []models.GroupAssignmentNotice
+	groupErr     error
+
+	guestTo      string
+	guestNotices []models.GuestReservationNotice
+	guestErr     error
 }
 
 func (m *userEmailServiceMock) SendPasswordReset(ctx context.Context, userID, to, token string) error {
@@ -34,6 +47,32 @@ func (m *userEmailServiceMock) SendPasswordReset(ctx context.Context, userID, to
 	return m.resetErr
 }
 
+func (m *userEmailServiceMock) SendAccountDeletion(ctx context.Context, userID, to, token string, deleteAfter time.Time) error {
+	m.deletionTo = to
+	m.deletionToken = token
+	m.deletionDeleteAfter = deleteAfter
+	m.deletionCalls++
+	return nil
+}
+
+func (m *userEmailServiceMock) SendGroupAssignment(ctx context.Context, userID, to string, notice models.GroupAssignmentNotice) error {
+	if m.groupErr != nil {
+		return m.groupErr
+	}
+	m.groupTo = append(m.groupTo, to)
+	m.groupNotices = append(m.groupNotices, notice)
+	return nil
+}
+
+func (m *userEmailServiceMock) SendGuestReservation(ctx context.Context, to string, notice models.GuestReservationNotice) error {
+	if m.guestErr != nil {
+		return m.guestErr
+	}
+	m.guestTo = to
+	m.guestNotices = append(m.guestNotices, notice)
+	return nil
+}
+
 func (m *userEmailServiceMock) SendEmailVerification(ctx context.Context, userID, to, token string) error {
 	m.verificationTo = to
 	m.verificationToken = token
@@ -63,6 +102,11 @@ type userTokenStorageMock struct {
 
 	deleteResetCalls int
 	deleteResetErr   error
+
+	deletionTokens   map[string]string
+	deletionTokenTTL time.Duration
+
+	revokedUserID string
 }
 
 func (m *userTokenStorageMock) SaveEmailVerificationToken(ctx context.Context, tokenID, userID string) error {
@@ -92,6 +136,15 @@ func (m *userTokenStorageMock) RevokeAuthTokens(ctx context.Context, tokenID str
 	return nil
 }
 
+func (m *userTokenStorageMock) RevokeUserAuthTokens(ctx context.Context, userID string, issuedBefore time.Time) error {
+	m.revokedUserID = userID
+	return nil
+}
+
+func (m *userTokenStorageMock) GetUserAuthTokensRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
 func (m *userTokenStorageMock) SavePasswordResetToken(ctx context.Context, tokenID string, userID string) error {
 	m.saveResetTokenID = tokenID
 	m.saveResetUserID = userID
@@ -111,6 +164,32 @@ func (m *userTokenStorageMock) DeletePasswordResetToken(ctx context.Context, tok
 	return m.deleteResetErr
 }
 
+func (m *userTokenStorageMock) SaveAccountDeletionToken(ctx context.Context, tokenID, userID string, ttl time.Duration) error {
+	if m.deletionTokens == nil {
+		m.deletionTokens = map[string]string{}
+	}
+	m.deletionTokens[tokenID] = userID
+	m.deletionTokenTTL = ttl
+	return nil
+}
+
+func (m *userTokenStorageMock) GetAccountDeletionToken(ctx context.Context, tokenID string) (string, error) {
+	userID, ok := m.deletionTokens[tokenID]
+	if !ok {
+		return "", errors.New("redis: nil")
+	}
+	return userID, nil
+}
+
+func (m *userTokenStorageMock) DeleteAccountDeletionToken(ctx context.Context, userID string) error {
+	for tokenID, tokenUserID := range m.deletionTokens {
+		if tokenUserID == userID {
+			delete(m.deletionTokens, tokenID)
+		}
+	}
+	return nil
+}
+
 type userStorageServiceMock struct {
 	createErr error
 	updateErr error
@@ -139,7 +218,9 @@ type userStorageServiceMock struct {
 	removeAvatarCalls int
 	removeAvatarErr   error
 
-	deletedUserID uuid.UUID
+	scheduledUserID  uuid.UUID
+	scheduledUntil   time.Time
+	cancelledUserIDs []uuid.UUID
 }
 
 func (m *userStorageServiceMock) CreateUser(ctx context.Context, user models.User) error {
@@ -194,22 +275,34 @@ func (m *userStorageServiceMock) RemoveUserAvatar(ctx context.Context, id uuid.U
 	return m.removeAvatarErr
 }
 
-func (m *userStorageServiceMock) DeleteUserByID(ctx context.Context, id uuid.UUID) error {
-	m.deletedUserID = id
+func (m *userStorageServiceMock) ScheduleUserDeletion(ctx context.Context, id uuid.UUID, deleteAfter time.Time) error {
+	m.scheduledUserID = id
+	m.scheduledUntil = deleteAfter
 	return m.deleteErr
 }
 
+func (m *userStorageServiceMock) CancelUserDeletion(ctx context.Context, id uuid.UUID) error {
+	m.cancelledUserIDs = append(m.cancelledUserIDs, id)
+	return nil
+}
+
 type userAvatarStorageMock struct {
 	baseURL string
 
 	uploadedObjectName string
 	uploadedType       string
 	uploadedSize       int64
+	uploadedObjects    []string
 	uploadErr          error
 
 	newObjectURL string
 	deletedObj   string
+	deletedObjs  []string
 	deleteErr    error
+
+	presignedObject string
+	stored          map[string]storedObjectMock // Objects uploaded by clients directly
+	copiedObjs      []string                    // Destinations of CopyObject
 }
 
 func (m *userAvatarStorageMock) GetBaseURL() string { return m.baseURL }
@@ -218,6 +311,7 @@ func (m *userAvatarStorageMock) UploadObject(ctx context.Context, objectName str
 	m.uploadedObjectName = objectName
 	m.uploadedType = contentType
 	m.uploadedSize = size
+	m.uploadedObjects = append(m.uploadedObjects, objectName)
 	return m.uploadErr
 }
 
@@ -230,9 +324,42 @@ func (m *userAvatarStorageMock) GetObjectURL(objectName string) string {
 
 func (m *userAvatarStorageMock) DeleteObject(ctx context.Context, objectName string) error {
 	m.deletedObj = objectName
+	m.deletedObjs = append(m.deletedObjs, objectName)
 	return m.deleteErr
 }
 
+func (m *userAvatarStorageMock) CopyObject(ctx context.Context, srcName, dstName string) error {
+	object, ok := m.stored[srcName]
+	if !ok {
+		return svcErr.NotFoundError{Entity: "Object", Field: "key", Value: srcName}
+	}
+	m.stored[dstName] = object
+	m.copiedObjs = append(m.copiedObjs, dstName)
+	return nil
+}
+
+func (m *userAvatarStorageMock) PresignUpload(ctx context.Context, objectName, contentType string, size int64, expires time.Duration) (string, error) {
+	m.presignedObject = objectName
+	return m.baseURL + "/" + objectName + "?X-Amz-Signature=test", nil
+}
+
+func (m *userAvatarStorageMock) StatObject(ctx context.Context, objectName string) (models.BucketObject, error) {
+	object, ok := m.stored[objectName]
+	if !ok {
+		return models.BucketObject{}, svcErr.NotFoundError{Entity: "Upload", Field: "key", Value: objectName}
+	}
+	return models.BucketObject{Key: objectName, Size: int64(len(object.data)), ContentType: object.contentType}, nil
+}
+
+func (m *userAvatarStorageMock) GetObject(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.stored[objectName].data)), nil
+}
+
+type storedObjectMock struct {
+	data        []byte
+	contentType string
+}
+
 type userLoggerMock struct{ calls int }
 
 func (m *userLoggerMock) Error(format string, v ...any) { m.calls++ }
@@ -243,7 +370,7 @@ func TestUserService_Register_WithoutEmail(t *testing.T) {
 	mailer := &userEmailServiceMock{}
 	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
 	log := &userLoggerMock{}
-	svc := NewUserService(mailer, st, tk, s3, log)
+	svc := NewUserService(mailer, st, tk, s3, log, nil, 0)
 
 	user, err := svc.Register(context.Background(), models.RegisterUserRequest{
 		Name:     "John",
@@ -266,7 +393,7 @@ func TestUserService_Register_WithoutEmail(t *testing.T) {
 
 func TestUserService_Register_TrimsUsernameAndPreservesCase(t *testing.T) {
 	st := &userStorageServiceMock{}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	user, err := svc.Register(context.Background(), models.RegisterUserRequest{
 		Name:     "John",
@@ -286,7 +413,7 @@ func TestUserService_Register_TrimsUsernameAndPreservesCase(t *testing.T) {
 
 func TestUserService_Register_InvalidUsername(t *testing.T) {
 	st := &userStorageServiceMock{}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	_, err := svc.Register(context.Background(), models.RegisterUserRequest{
 		Name:     "John",
@@ -313,7 +440,7 @@ func TestUserService_Register_WithEmail_SendsVerification(t *testing.T) {
 	mailer := &userEmailServiceMock{}
 	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
 	log := &userLoggerMock{}
-	svc := NewUserService(mailer, st, tk, s3, log)
+	svc := NewUserService(mailer, st, tk, s3, log, nil, 0)
 
 	user, err := svc.Register(context.Background(), models.RegisterUserRequest{
 		Name:     "John",
@@ -350,7 +477,7 @@ func TestUserService_VerifyEmail_InvalidToken(t *testing.T) {
 	mailer := &userEmailServiceMock{}
 	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
 	log := &userLoggerMock{}
-	svc := NewUserService(mailer, st, tk, s3, log)
+	svc := NewUserService(mailer, st, tk, s3, log, nil, 0)
 
 	err := svc.VerifyEmail(context.Background(), "bad-token")
 	if err == nil {
@@ -375,17 +502,17 @@ func TestUserService_UpdateAvatar_RemovesPreviousAvatar(t *testing.T) {
 		newObjectURL: "http://minio:9000/wishlist/avatars/new-user/new-file",
 	}
 	log := &userLoggerMock{}
-	svc := NewUserService(mailer, st, tk, s3, log)
+	svc := NewUserService(mailer, st, tk, s3, log, nil, 0)
 
-	err := svc.UpdateAvatar(context.Background(), id, strings.NewReader("x"), 1, "image/png")
+	err := svc.UpdateAvatar(context.Background(), id, bytes.NewReader(testPNG(t)))
 	if err != nil {
 		t.Fatalf("UpdateAvatar() error = %v", err)
 	}
 	if st.updatedUserID != id {
 		t.Fatalf("UpdateAvatar() updated user ID = %s, want %s", st.updatedUserID, id)
 	}
-	if st.updatedUserReq.Avatar == nil || *st.updatedUserReq.Avatar != s3.newObjectURL {
-		t.Fatalf("UpdateAvatar() avatar URL not saved")
+	if st.updatedUserReq.Avatar == nil || *st.updatedUserReq.Avatar != s3.uploadedObjects[0] {
+		t.Fatalf("UpdateAvatar() avatar = %v, want key of the original", st.updatedUserReq.Avatar)
 	}
 	if s3.deletedObj != "avatars/old-user/old-file" {
 		t.Fatalf("UpdateAvatar() deleted object = %s, want avatars/old-user/old-file", s3.deletedObj)
@@ -399,7 +526,7 @@ func TestUserService_VerifyEmail_Success(t *testing.T) {
 	mailer := &userEmailServiceMock{}
 	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
 	log := &userLoggerMock{}
-	svc := NewUserService(mailer, st, tk, s3, log)
+	svc := NewUserService(mailer, st, tk, s3, log, nil, 0)
 
 	if err := svc.VerifyEmail(context.Background(), "token"); err != nil {
 		t.Fatalf("VerifyEmail() error = %v", err)
@@ -420,7 +547,7 @@ func TestUserService_LogIn_Success(t *testing.T) {
 	userID := uuid.New()
 	expected := models.User{ID: userID, Username: "johnny", Password: string(hash)}
 	st := &userStorageServiceMock{userByUsername: expected}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	actual, err := svc.LogIn(context.Background(), models.LogInUserRequest{Username: "  JoHnNy  ", Password: "password123"})
 	if err != nil {
@@ -442,7 +569,7 @@ func TestUserService_LogIn_WrongPassword(t *testing.T) {
 	st := &userStorageServiceMock{
 		userByUsername: models.User{ID: uuid.New(), Username: "johnny", Password: string(hash)},
 	}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	err = nil
 	_, err = svc.LogIn(context.Background(), models.LogInUserRequest{Username: "johnny", Password: "bad-pass"})
@@ -455,7 +582,7 @@ func TestUserService_GetAndUpdateByID(t *testing.T) {
 	userID := uuid.New()
 	expected := models.User{ID: userID, Username: "alice"}
 	st := &userStorageServiceMock{userByID: expected}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	user, err := svc.GetUserByID(context.Background(), userID)
 	if err != nil {
@@ -480,7 +607,7 @@ func TestUserService_GetAndUpdateByID(t *testing.T) {
 func TestUserService_UpdateUserByID_TrimsUsernameAndPreservesCase(t *testing.T) {
 	userID := uuid.New()
 	st := &userStorageServiceMock{}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	username := "  АлиСА42  "
 	if err := svc.UpdateUserByID(context.Background(), userID, models.UpdateUserRequest{Username: &username}); err != nil {
@@ -494,7 +621,7 @@ func TestUserService_UpdateUserByID_TrimsUsernameAndPreservesCase(t *testing.T)
 func TestUserService_GetUserByUsername_NormalizesInput(t *testing.T) {
 	expected := models.User{ID: uuid.New(), Username: "таня"}
 	st := &userStorageServiceMock{userByUsername: expected}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	user, err := svc.GetUserByUsername(context.Background(), "  ТанЯ  ")
 	if err != nil {
@@ -510,7 +637,7 @@ func TestUserService_GetUserByUsername_NormalizesInput(t *testing.T) {
 
 func TestUserService_SearchUsersByUsername_NormalizesInput(t *testing.T) {
 	st := &userStorageServiceMock{searchUsers: []models.User{{ID: uuid.New(), Username: "таня"}}}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	users, err := svc.SearchUsersByUsername(context.Background(), "  Тан  ", 8)
 	if err != nil {
@@ -530,7 +657,7 @@ func TestUserService_SearchUsersByUsername_NormalizesInput(t *testing.T) {
 func TestUserService_DeleteAvatar_NoAvatar(t *testing.T) {
 	id := uuid.New()
 	st := &userStorageServiceMock{userByID: models.User{ID: id}}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{baseURL: "http://minio"}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{baseURL: "http://minio"}, &userLoggerMock{}, nil, 0)
 
 	if err := svc.DeleteAvatar(context.Background(), id); err != nil {
 		t.Fatalf("DeleteAvatar() error = %v", err)
@@ -545,7 +672,7 @@ func TestUserService_DeleteAvatar_WithAvatar(t *testing.T) {
 	avatar := "http://minio:9000/wishlist/avatars/user/file"
 	st := &userStorageServiceMock{userByID: models.User{ID: id, Avatar: &avatar}}
 	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, s3, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, s3, &userLoggerMock{}, nil, 0)
 
 	if err := svc.DeleteAvatar(context.Background(), id); err != nil {
 		t.Fatalf("DeleteAvatar() error = %v", err)
@@ -565,7 +692,7 @@ func TestUserService_VerifyPassword(t *testing.T) {
 		t.Fatalf("GenerateFromPassword() error = %v", err)
 	}
 	st := &userStorageServiceMock{userByID: models.User{ID: id, Password: string(hash)}}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	if err = svc.VerifyPassword(context.Background(), id, "secret123"); err != nil {
 		t.Fatalf("VerifyPassword() error = %v", err)
@@ -588,7 +715,7 @@ func TestUserService_ChangePassword(t *testing.T) {
 		t.Fatalf("GenerateFromPassword() error = %v", err)
 	}
 	st := &userStorageServiceMock{userByID: models.User{ID: id, Password: string(oldHash)}}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	err = svc.ChangePassword(context.Background(), id, models.ChangePasswordRequest{OldPassword: "old-pass", NewPassword: "new-pass-123"})
 	if err != nil {
@@ -613,7 +740,7 @@ func TestUserService_RequestPasswordReset(t *testing.T) {
 	st := &userStorageServiceMock{userByEmail: models.User{ID: id, Email: &email}}
 	tk := &userTokenStorageMock{}
 	mailer := &userEmailServiceMock{}
-	svc := NewUserService(mailer, st, tk, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(mailer, st, tk, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	if err := svc.RequestPasswordReset(context.Background(), email); err != nil {
 		t.Fatalf("RequestPasswordReset() error = %v", err)
@@ -637,7 +764,7 @@ func TestUserService_RequestPasswordReset_SilentCases(t *testing.T) {
 	st := &userStorageServiceMock{userByEmailErr: errors.New("not found")}
 	tk := &userTokenStorageMock{}
 	mailer := &userEmailServiceMock{}
-	svc := NewUserService(mailer, st, tk, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(mailer, st, tk, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 	if err := svc.RequestPasswordReset(context.Background(), email); err != nil {
 		t.Fatalf("RequestPasswordReset() error = %v", err)
 	}
@@ -653,7 +780,7 @@ func TestUserService_ResetPassword(t *testing.T) {
 	userID := uuid.New()
 	tk := &userTokenStorageMock{getResetValue: userID.String()}
 	st := &userStorageServiceMock{}
-	svc := NewUserService(&userEmailServiceMock{}, st, tk, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, tk, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 
 	if err := svc.ResetPassword(context.Background(), "token", "new-pass-123"); err != nil {
 		t.Fatalf("ResetPassword() error = %v", err)
@@ -671,7 +798,7 @@ func TestUserService_ResetPassword(t *testing.T) {
 
 func TestUserService_ResetPassword_InvalidToken(t *testing.T) {
 	tk := &userTokenStorageMock{getResetErr: errors.New("missing")}
-	svc := NewUserService(&userEmailServiceMock{}, &userStorageServiceMock{}, tk, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, &userStorageServiceMock{}, tk, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 	err := svc.ResetPassword(context.Background(), "bad", "new-pass-123")
 	if err == nil {
 		t.Fatal("ResetPassword() error = nil, want validation error")
@@ -684,19 +811,98 @@ func TestUserService_ResetPassword_InvalidToken(t *testing.T) {
 
 func TestUserService_Delete(t *testing.T) {
 	id := uuid.New()
-	st := &userStorageServiceMock{}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
-	if err := svc.Delete(context.Background(), id); err != nil {
+	st := &userStorageServiceMock{userByID: models.User{ID: id, Email: new("john@example.com")}}
+	tk := &userTokenStorageMock{}
+	mailer := &userEmailServiceMock{}
+	svc := NewUserService(mailer, st, tk, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 14*24*time.Hour)
+
+	deleteAfter, err := svc.Delete(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if st.scheduledUserID != id || !st.scheduledUntil.Equal(deleteAfter) || time.Until(deleteAfter) < 13*24*time.Hour {
+		t.Fatalf("scheduled %s until %s, returned %s", st.scheduledUserID, st.scheduledUntil, deleteAfter)
+	}
+	if mailer.deletionCalls != 1 || mailer.deletionTo != "john@example.com" || !mailer.deletionDeleteAfter.Equal(deleteAfter) {
+		t.Fatalf("deletion emails = %d to %q", mailer.deletionCalls, mailer.deletionTo)
+	}
+	if tk.deletionTokens[mailer.deletionToken] != id.String() || tk.deletionTokenTTL != 14*24*time.Hour {
+		t.Fatalf("saved tokens = %v for %s, want the emailed one", tk.deletionTokens, tk.deletionTokenTTL)
+	}
+	if tk.revokedUserID != id.String() {
+		t.Fatalf("revoked tokens of %q, want every session of the account ended", tk.revokedUserID)
+	}
+}
+
+func TestUserService_Delete_AlreadyScheduled(t *testing.T) {
+	id := uuid.New()
+	scheduled := time.Now().Add(48 * time.Hour)
+	st := &userStorageServiceMock{userByID: models.User{ID: id, Email: new("john@example.com"), DeleteAfter: &scheduled}}
+	mailer := &userEmailServiceMock{}
+	svc := NewUserService(mailer, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 14*24*time.Hour)
+
+	deleteAfter, err := svc.Delete(context.Background(), id)
+	if err != nil {
 		t.Fatalf("Delete() error = %v", err)
 	}
-	if st.deletedUserID != id {
-		t.Fatalf("Delete() user ID = %s, want %s", st.deletedUserID, id)
+	if !deleteAfter.Equal(scheduled) || st.scheduledUserID != uuid.Nil || mailer.deletionCalls != 0 {
+		t.Fatalf("Delete() = %s, rescheduled %s, sent %d emails", deleteAfter, st.scheduledUserID, mailer.deletionCalls)
+	}
+}
+
+func TestUserService_CancelDeletion(t *testing.T) {
+	id := uuid.New()
+	st := &userStorageServiceMock{}
+	tk := &userTokenStorageMock{deletionTokens: map[string]string{"valid": id.String()}}
+	svc := NewUserService(&userEmailServiceMock{}, st, tk, &userAvatarStorageMock{}, &userLoggerMock{}, nil, time.Hour)
+
+	if err := svc.CancelDeletion(context.Background(), "valid"); err != nil {
+		t.Fatalf("CancelDeletion() error = %v", err)
+	}
+	if len(st.cancelledUserIDs) != 1 || st.cancelledUserIDs[0] != id || len(tk.deletionTokens) != 0 {
+		t.Fatalf("cancelled %v, tokens left %v", st.cancelledUserIDs, tk.deletionTokens)
+	}
+
+	err := svc.CancelDeletion(context.Background(), "valid")
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("CancelDeletion() reused token error = %v, want ValidationError", err)
+	}
+}
+
+func TestUserService_DeactivatedAccount(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+	deactivated := models.User{ID: uuid.New(), Username: "johnny", Password: string(hash), DeleteAfter: new(time.Now().Add(time.Hour))}
+	st := &userStorageServiceMock{userByID: deactivated, userByUsername: deactivated}
+	tk := &userTokenStorageMock{deletionTokens: map[string]string{"emailed": deactivated.ID.String()}}
+	svc := NewUserService(&userEmailServiceMock{}, st, tk, &userAvatarStorageMock{}, &userLoggerMock{}, nil, time.Hour)
+
+	if _, err = svc.GetUserByID(context.Background(), deactivated.ID); err == nil {
+		t.Fatal("GetUserByID() error = nil, want the deactivated account hidden")
+	}
+	if _, err = svc.GetUserByUsername(context.Background(), "johnny"); err == nil {
+		t.Fatal("GetUserByUsername() error = nil, want the deactivated account hidden")
+	}
+
+	user, err := svc.LogIn(context.Background(), models.LogInUserRequest{Username: "johnny", Password: "password123"})
+	if err != nil || !user.IsDeactivated() || len(st.cancelledUserIDs) != 0 {
+		t.Fatalf("LogIn() = %+v, %v, want the account returned as it is", user, err)
+	}
+
+	user, err = svc.LogIn(context.Background(), models.LogInUserRequest{Username: "johnny", Password: "password123", Reactivate: true})
+	if err != nil || user.IsDeactivated() || len(st.cancelledUserIDs) != 1 {
+		t.Fatalf("LogIn(reactivate) = %+v, %v, want the deletion cancelled", user, err)
+	}
+	if len(tk.deletionTokens) != 0 {
+		t.Fatalf("tokens left %v, want the emailed cancel link gone with the deletion", tk.deletionTokens)
 	}
 }
 
 func TestUserService_Register_CreateUserError(t *testing.T) {
 	st := &userStorageServiceMock{createErr: errors.New("duplicate")}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 	_, err := svc.Register(context.Background(), models.RegisterUserRequest{
 		Name:     "John",
 		Username: "johnny",
@@ -708,7 +914,7 @@ func TestUserService_Register_CreateUserError(t *testing.T) {
 }
 
 func TestUserService_VerifyEmail_ParseAndStorageErrors(t *testing.T) {
-	svc := NewUserService(&userEmailServiceMock{}, &userStorageServiceMock{}, &userTokenStorageMock{getEmailValue: "not-uuid"}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, &userStorageServiceMock{}, &userTokenStorageMock{getEmailValue: "not-uuid"}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 	err := svc.VerifyEmail(context.Background(), "token")
 	if err == nil {
 		t.Fatal("VerifyEmail() error = nil, want parse error")
@@ -716,7 +922,7 @@ func TestUserService_VerifyEmail_ParseAndStorageErrors(t *testing.T) {
 
 	userID := uuid.New()
 	st := &userStorageServiceMock{setVerifiedErr: errors.New("db failed")}
-	svc = NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{getEmailValue: userID.String()}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc = NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{getEmailValue: userID.String()}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 	err = svc.VerifyEmail(context.Background(), "token")
 	if err == nil {
 		t.Fatal("VerifyEmail() error = nil, want storage error")
@@ -727,8 +933,8 @@ func TestUserService_UpdateAvatar_UploadError(t *testing.T) {
 	id := uuid.New()
 	st := &userStorageServiceMock{userByID: models.User{ID: id}}
 	s3 := &userAvatarStorageMock{uploadErr: errors.New("s3 unavailable")}
-	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, s3, &userLoggerMock{})
-	err := svc.UpdateAvatar(context.Background(), id, strings.NewReader("x"), 1, "image/png")
+	svc := NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{}, s3, &userLoggerMock{}, nil, 0)
+	err := svc.UpdateAvatar(context.Background(), id, bytes.NewReader(testPNG(t)))
 	if err == nil {
 		t.Fatal("UpdateAvatar() error = nil, want upload error")
 	}
@@ -739,7 +945,7 @@ func TestUserService_RequestPasswordReset_ErrorPaths(t *testing.T) {
 	email := "alice@example.com"
 	st := &userStorageServiceMock{userByEmail: models.User{ID: id, Email: &email}}
 	tk := &userTokenStorageMock{saveResetErr: errors.New("redis down")}
-	svc := NewUserService(&userEmailServiceMock{}, st, tk, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, st, tk, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 	err := svc.RequestPasswordReset(context.Background(), email)
 	if err == nil {
 		t.Fatal("RequestPasswordReset() error = nil, want save token error")
@@ -747,7 +953,7 @@ func TestUserService_RequestPasswordReset_ErrorPaths(t *testing.T) {
 
 	tk = &userTokenStorageMock{}
 	mailer := &userEmailServiceMock{resetErr: errors.New("smtp down")}
-	svc = NewUserService(mailer, st, tk, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc = NewUserService(mailer, st, tk, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 	err = svc.RequestPasswordReset(context.Background(), email)
 	if err == nil {
 		t.Fatal("RequestPasswordReset() error = nil, want email error")
@@ -755,7 +961,7 @@ func TestUserService_RequestPasswordReset_ErrorPaths(t *testing.T) {
 }
 
 func TestUserService_ResetPassword_ErrorPaths(t *testing.T) {
-	svc := NewUserService(&userEmailServiceMock{}, &userStorageServiceMock{}, &userTokenStorageMock{getResetValue: "bad-uuid"}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc := NewUserService(&userEmailServiceMock{}, &userStorageServiceMock{}, &userTokenStorageMock{getResetValue: "bad-uuid"}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 	err := svc.ResetPassword(context.Background(), "token", "new-pass")
 	if err == nil {
 		t.Fatal("ResetPassword() error = nil, want parse error")
@@ -763,7 +969,7 @@ func TestUserService_ResetPassword_ErrorPaths(t *testing.T) {
 
 	userID := uuid.New()
 	st := &userStorageServiceMock{updateErr: errors.New("db failed")}
-	svc = NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{getResetValue: userID.String()}, &userAvatarStorageMock{}, &userLoggerMock{})
+	svc = NewUserService(&userEmailServiceMock{}, st, &userTokenStorageMock{getResetValue: userID.String()}, &userAvatarStorageMock{}, &userLoggerMock{}, nil, 0)
 	err = svc.ResetPassword(context.Background(), "token", "new-pass")
 	if err == nil {
 		t.Fatal("ResetPassword() error = nil, want update error")
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -15,24 +16,44 @@ import (
 	"wishlist/internal/storage"
 )
 
+const maxImportRows = 500 // Wishes one imported file may hold
+
 type WishStorage interface {
 	CreateWish(ctx context.Context, wish models.Wish) error
+	CreateWishes(ctx context.Context, wishes []models.Wish) error
 	GetWishByID(ctx context.Context, wishID uuid.UUID) (models.Wish, error)
-	GetWishesByListID(ctx context.Context, listID uuid.UUID) ([]models.Wish, error)
+	GetWishesByListID(ctx context.Context, listID uuid.UUID, filter models.WishFilter) ([]models.Wish, error)
 	UpdateWishByID(ctx context.Context, wishID uuid.UUID, req models.UpdateWishRequest) error
-	ReserveWish(ctx context.Context, wishID, userID uuid.UUID) error
-	ReleaseWish(ctx context.Context, wishID, userID uuid.UUID) error
+	UpdateWishStatus(ctx context.Context, wishID uuid.UUID, from, to models.WishStatus) error
+	ReorderWishes(ctx context.Context, listID uuid.UUID, wishIDs []uuid.UUID) error
+	ReserveWish(ctx context.Context, reservation models.WishReservation) error
+	ReleaseWish(ctx context.Context, wishID, userID uuid.UUID, units int) error
 	DeleteWishByID(ctx context.Context, wishID uuid.UUID) error
 }
 
+type WishPriceHistoryStorage interface {
+	GetPriceHistory(ctx context.Context, wishID uuid.UUID) ([]models.WishPrice, error)
+}
+
+type LinkScraper interface {
+	Scrape(ctx context.Context, link string) (models.LinkPreview, error)
+	FetchImage(ctx context.Context, imageURL string) ([]byte, string, error)
+}
+
 type WishServiceImpl struct {
 	wishes    WishStorage
 	wishlists ListStorage
+	members   ListMemberStorage
+	follows   FollowStorage
 	s3        AvatarStorage
+	scraper   LinkScraper
+	prices    WishPriceHistoryStorage
+	objects   *ObjectTracker
+	activity  *ActivityRecorder
 }
 
-func NewWishService(ws WishStorage, wl ListStorage, s3 AvatarStorage) *WishServiceImpl {
-	return &WishServiceImpl{wishes: ws, wishlists: wl, s3: s3}
+func NewWishService(ws WishStorage, wl ListStorage, ms ListMemberStorage, fs FollowStorage, s3 AvatarStorage, ls LinkScraper, ph WishPriceHistoryStorage, ot *ObjectTracker, ar *ActivityRecorder) *WishServiceImpl {
+	return &WishServiceImpl{wishes: ws, wishlists: wl, members: ms, follows: fs, s3: s3, scraper: ls, prices: ph, objects: ot, activity: ar}
 }
 
 func (svc *WishServiceImpl) CreateWish(ctx context.Context, listID, userID uuid.UUID, req models.CreateWishRequest) (models.Wish, error) {
@@ -41,29 +62,199 @@ func (svc *WishServiceImpl) CreateWish(ctx context.Context, listID, userID uuid.
 		return models.Wish{}, err
 	}
 
-	if list.UserID != userID {
-		return models.Wish{}, svcErr.ForbiddenError{Message: "you are not the owner of this wishlist"}
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return models.Wish{}, err
 	}
+	if !role.CanEdit() {
+		return models.Wish{}, svcErr.ForbiddenError{Message: "you are not allowed to edit this wishlist"}
+	}
+
+	wishID := uuid.New()
 
-	wish := models.Wish{
-		ID:        uuid.New(),
-		ListID:    listID,
-		Title:     req.Title,
-		Notes:     req.Notes,
-		Link:      req.Link,
-		Price:     req.Price,
-		Currency:  req.Currency,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	var image *string
+	if req.Image != nil && svc.s3 != nil {
+		if key, ok := wishImageKey(*req.Image, svc.s3.GetBaseURL()); ok { // Only images we host, e.g. from a link preview
+			image = &key
+		}
 	}
 
+	if req.Autofill {
+		if req.Link == nil {
+			return models.Wish{}, svcErr.ValidationError{Message: "link is required to fill in the wish"}
+		}
+		preview, err := svc.previewLink(ctx, *req.Link, wishID)
+		if err != nil && strings.TrimSpace(req.Title) == "" {
+			return models.Wish{}, err
+		}
+		// What the user typed wins over what the page says
+		if strings.TrimSpace(req.Title) == "" && preview.Title != nil {
+			req.Title = *preview.Title
+		}
+		if req.Price == nil && preview.Price != nil {
+			req.Price, req.Currency = preview.Price, preview.Currency
+		}
+		if image == nil {
+			image = preview.Image
+		}
+	}
+
+	wish, err := newWish(wishID, listID, req)
+	if err != nil {
+		return models.Wish{}, err
+	}
+	wish.Image = image
+
 	if err = svc.wishes.CreateWish(ctx, wish); err != nil {
 		return models.Wish{}, err
 	}
+	if image != nil {
+		svc.objects.Track(ctx, *image, wishImageOwner(list, wishID))
+	}
+	svc.activity.RecordWish(ctx, userID, models.ActivityWishCreated, wish)
 
 	return wish, nil
 }
 
+// newWish fills the defaults of a wish and checks what binding rules can't: the title and the currency code
+func newWish(wishID, listID uuid.UUID, req models.CreateWishRequest) (models.Wish, error) {
+	quantity := 1 // default
+	if req.Quantity != nil {
+		quantity = *req.Quantity
+	}
+
+	priority := models.WishPriorityNiceToHave // default
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
+
+	if strings.TrimSpace(req.Title) == "" {
+		return models.Wish{}, svcErr.ValidationError{Message: "title is required"}
+	}
+
+	if req.Currency != nil {
+		code, err := normalizeCurrency(*req.Currency)
+		if err != nil {
+			return models.Wish{}, err
+		}
+		req.Currency = nil
+		if code != "" {
+			req.Currency = &code
+		}
+	}
+
+	return models.Wish{
+		ID:         wishID,
+		ListID:     listID,
+		Title:      req.Title,
+		Notes:      req.Notes,
+		Link:       req.Link,
+		Price:      req.Price,
+		Currency:   req.Currency,
+		Quantity:   quantity,
+		Status:     models.WishStatusOpen,
+		Priority:   priority,
+		PriceAlert: req.PriceAlert,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}, nil
+}
+
+// ImportWishes adds the rows of an imported file to the list. Rows that come with an error or fail the checks of CreateWish
+// are reported and skipped, the rest is created in one transaction. Images and link previews are not fetched for imports
+func (svc *WishServiceImpl) ImportWishes(ctx context.Context, listID, userID uuid.UUID, rows []models.WishImportRow) (models.WishImportReport, error) {
+	list, err := svc.wishlists.GetListByID(ctx, listID)
+	if err != nil {
+		return models.WishImportReport{}, err
+	}
+
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return models.WishImportReport{}, err
+	}
+	if !role.CanEdit() {
+		return models.WishImportReport{}, svcErr.ForbiddenError{Message: "you are not allowed to edit this wishlist"}
+	}
+
+	if len(rows) == 0 {
+		return models.WishImportReport{}, svcErr.ValidationError{Message: "nothing to import"}
+	}
+	if len(rows) > maxImportRows {
+		return models.WishImportReport{}, svcErr.ValidationError{Message: fmt.Sprintf("too many wishes in one file (max %d)", maxImportRows)}
+	}
+
+	report := models.WishImportReport{Rows: make([]models.WishImportResult, 0, len(rows))}
+	var wishes []models.Wish
+	for _, row := range rows {
+		result := models.WishImportResult{Row: row.Row, Title: row.Request.Title, Error: row.Error}
+		if result.Error == "" {
+			row.Request.Image, row.Request.Autofill = nil, false
+			wish, err := newWish(uuid.New(), listID, row.Request)
+			if err != nil {
+				validationErr, ok := errors.AsType[svcErr.ValidationError](err)
+				if !ok {
+					return models.WishImportReport{}, err
+				}
+				result.Error = validationErr.Message
+			} else {
+				wishes = append(wishes, wish)
+				result.WishID = &wish.ID
+			}
+		}
+
+		if result.Error != "" {
+			report.Rejected++
+		} else {
+			report.Created++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if len(wishes) > 0 {
+		if err = svc.wishes.CreateWishes(ctx, wishes); err != nil {
+			return models.WishImportReport{}, err
+		}
+	}
+	for _, wish := range wishes {
+		svc.activity.RecordWish(ctx, userID, models.ActivityWishCreated, wish)
+	}
+
+	return report, nil
+}
+
+// PreviewLink reads title, price and image of a product page, the image is copied to S3 so it can be used for a new wish
+func (svc *WishServiceImpl) PreviewLink(ctx context.Context, link string) (models.LinkPreview, error) {
+	return svc.previewLink(ctx, link, uuid.New())
+}
+
+func (svc *WishServiceImpl) previewLink(ctx context.Context, link string, wishID uuid.UUID) (models.LinkPreview, error) {
+	preview, err := svc.scraper.Scrape(ctx, link)
+	if err != nil {
+		return models.LinkPreview{}, svcErr.ValidationError{Message: "could not load a preview for this link"}
+	}
+
+	if preview.ImageURL == nil {
+		return preview, nil
+	}
+
+	data, _, err := svc.scraper.FetchImage(ctx, *preview.ImageURL)
+	if err != nil {
+		return preview, nil // The rest of the preview is still useful
+	}
+
+	imageKey, err := uploadImage(ctx, svc.s3, fmt.Sprintf(storage.WishImagePrefix, wishID, uuid.NewString()), bytes.NewReader(data))
+	if err != nil {
+		if _, ok := errors.AsType[svcErr.ValidationError](err); ok {
+			return preview, nil // Shops sometimes serve something we can't read, the wish does fine without a picture
+		}
+		return models.LinkPreview{}, fmt.Errorf("failed to upload wish image: %w", err)
+	}
+	svc.objects.Track(ctx, imageKey, models.ObjectOwner{}) // Claimed by the wish it ends up on, the sweep takes it otherwise
+	preview.Image = &imageKey
+
+	return preview, nil
+}
+
 func (svc *WishServiceImpl) GetWishByID(ctx context.Context, wishID uuid.UUID) (models.Wish, error) {
 	return svc.wishes.GetWishByID(ctx, wishID)
 }
@@ -83,14 +274,54 @@ func (svc *WishServiceImpl) UpdateWish(ctx context.Context, listID, wishID, user
 		return err
 	}
 
-	if list.UserID != userID {
-		return svcErr.ForbiddenError{Message: "you are not the owner of this wish"}
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return svcErr.ForbiddenError{Message: "you are not allowed to edit this wish"}
+	}
+
+	// Storage checks it again with the wish locked, this only spares the transaction when the answer is already clear
+	if req.Quantity != nil && *req.Quantity < wish.ReservedUnits() {
+		return svcErr.ValidationError{Message: fmt.Sprintf("quantity cannot be less than %d already reserved units", wish.ReservedUnits())}
 	}
 
-	return svc.wishes.UpdateWishByID(ctx, wishID, req)
+	if req.Currency != nil {
+		code, err := normalizeCurrency(*req.Currency)
+		if err != nil {
+			return err
+		}
+		req.Currency = &code // Empty clears it
+	}
+
+	if req.Image != nil && *req.Image != "" && svc.s3 != nil {
+		// Clients send back the URL they were given, links to other sites are kept as they are
+		if storedObjectName(*req.Image, svc.s3.GetBaseURL()) != "" {
+			key, ok := wishImageKey(*req.Image, svc.s3.GetBaseURL())
+			if !ok {
+				return svcErr.ValidationError{Message: "image is not a wish image"}
+			}
+			req.Image = &key
+		}
+	}
+
+	if err = svc.wishes.UpdateWishByID(ctx, wishID, req); err != nil {
+		return err
+	}
+	if req.Image != nil {
+		svc.objects.Track(ctx, *req.Image, wishImageOwner(list, wishID))
+		svc.objects.Release(ctx, models.ObjectOwner{WishID: &wishID}) // The previous image, unless another wish uses it too
+	}
+	if req.Title != nil {
+		wish.Title = *req.Title
+	}
+	svc.activity.RecordWish(ctx, userID, models.ActivityWishUpdated, wish)
+
+	return nil
 }
 
-func (svc *WishServiceImpl) UpdateWishImage(ctx context.Context, listID, wishID, userID uuid.UUID, reader io.Reader, size int64, contentType string) error {
+func (svc *WishServiceImpl) UpdateWishImage(ctx context.Context, listID, wishID, userID uuid.UUID, reader io.Reader) error {
 	wish, err := svc.wishes.GetWishByID(ctx, wishID)
 	if err != nil {
 		return err
@@ -105,20 +336,66 @@ func (svc *WishServiceImpl) UpdateWishImage(ctx context.Context, listID, wishID,
 		return err
 	}
 
-	if list.UserID != userID {
-		return svcErr.ForbiddenError{Message: "you are not the owner of this wish"}
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return svcErr.ForbiddenError{Message: "you are not allowed to edit this wish"}
+	}
+
+	imageKey, err := uploadImage(ctx, svc.s3, fmt.Sprintf(storage.WishImagePrefix, wishID, uuid.NewString()), reader)
+	if err != nil {
+		if _, ok := errors.AsType[svcErr.ValidationError](err); ok {
+			return err
+		}
+		return fmt.Errorf("failed to upload wish image: %w", err)
 	}
 
-	objectName := fmt.Sprintf(storage.WishImagePrefix, wishID, uuid.NewString())
+	if err = svc.wishes.UpdateWishByID(ctx, wishID, models.UpdateWishRequest{Image: &imageKey}); err != nil {
+		return err
+	}
+	svc.objects.Track(ctx, imageKey, wishImageOwner(list, wishID))
+	svc.objects.Release(ctx, models.ObjectOwner{WishID: &wishID})
+	svc.activity.RecordWish(ctx, userID, models.ActivityWishUpdated, wish)
 
-	if err = svc.s3.UploadObject(ctx, objectName, reader, size, contentType); err != nil {
-		return fmt.Errorf("failed to upload wish image: %w", err)
+	return nil
+}
+
+// CreateWishImageUploadURL lets an editor upload the image straight to S3, ConfirmWishImageUpload then sets it
+func (svc *WishServiceImpl) CreateWishImageUploadURL(ctx context.Context, listID, wishID, userID uuid.UUID, req models.UploadURLRequest) (models.UploadURLResponse, error) {
+	wish, err := svc.wishes.GetWishByID(ctx, wishID)
+	if err != nil {
+		return models.UploadURLResponse{}, err
+	}
+
+	if wish.ListID != listID {
+		return models.UploadURLResponse{}, svcErr.ValidationError{Message: "wish does not belong to this list"}
+	}
+
+	list, err := svc.wishlists.GetListByID(ctx, wish.ListID)
+	if err != nil {
+		return models.UploadURLResponse{}, err
+	}
+
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return models.UploadURLResponse{}, err
+	}
+	if !role.CanEdit() {
+		return models.UploadURLResponse{}, svcErr.ForbiddenError{Message: "you are not allowed to edit this wish"}
 	}
 
-	return svc.wishes.UpdateWishByID(ctx, wishID, models.UpdateWishRequest{Image: new(svc.s3.GetObjectURL(objectName))})
+	return newUploadURL(ctx, svc.s3, userID, req)
 }
 
-func (svc *WishServiceImpl) ReserveWish(ctx context.Context, listID, wishID, userID uuid.UUID) error {
+func (svc *WishServiceImpl) ConfirmWishImageUpload(ctx context.Context, listID, wishID, userID uuid.UUID, objectKey string, maxSize int64) error {
+	return confirmUpload(ctx, svc.s3, svc.objects, userID, objectKey, maxSize, func(reader io.Reader) error {
+		return svc.UpdateWishImage(ctx, listID, wishID, userID, reader)
+	})
+}
+
+func (svc *WishServiceImpl) ReserveWish(ctx context.Context, listID, wishID, userID uuid.UUID, req models.ReserveWishRequest) error {
 	wish, err := svc.wishes.GetWishByID(ctx, wishID)
 	if err != nil {
 		return err
@@ -133,11 +410,41 @@ func (svc *WishServiceImpl) ReserveWish(ctx context.Context, listID, wishID, use
 		return err
 	}
 
-	if list.UserID == userID {
+	role, err := resolveReadableListRole(ctx, svc.members, svc.follows, list, userID)
+	if err != nil {
+		return err
+	}
+	if role.CanEdit() {
 		return svcErr.ValidationError{Message: "you cannot reserve your own wish"}
 	}
 
-	if err = svc.wishes.ReserveWish(ctx, wishID, userID); err != nil {
+	if !wish.Status.AcceptsReservations() {
+		return svcErr.ValidationError{Message: "wish is no longer available"}
+	}
+
+	if wish.Contributed > 0 {
+		return svcErr.ValidationError{Message: "wish is already being chipped in for"}
+	}
+
+	if req.ReservedUntil != nil && !req.ReservedUntil.After(time.Now()) {
+		return svcErr.ValidationError{Message: "reservation deadline must be in the future"}
+	}
+
+	units := 1 // default
+	if req.Units > 0 {
+		units = req.Units
+	}
+
+	reservation := models.WishReservation{
+		WishID:        wishID,
+		UserID:        userID,
+		Units:         units,
+		ReservedUntil: req.ReservedUntil,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if err = svc.wishes.ReserveWish(ctx, reservation); err != nil {
 		if _, ok := errors.AsType[svcErr.ValidationError](err); ok {
 			return err
 		}
@@ -146,11 +453,13 @@ func (svc *WishServiceImpl) ReserveWish(ctx context.Context, listID, wishID, use
 		}
 		return err
 	}
+	svc.activity.RecordWish(ctx, userID, models.ActivityWishReserved, wish)
 
 	return nil
 }
 
-func (svc *WishServiceImpl) ReleaseWish(ctx context.Context, listID, wishID, userID uuid.UUID) error {
+// ReleaseWish releases units reserved by the user, zero units releases all of them
+func (svc *WishServiceImpl) ReleaseWish(ctx context.Context, listID, wishID, userID uuid.UUID, units int) error {
 	wish, err := svc.wishes.GetWishByID(ctx, wishID)
 	if err != nil {
 		return err
@@ -160,7 +469,11 @@ func (svc *WishServiceImpl) ReleaseWish(ctx context.Context, listID, wishID, use
 		return svcErr.ValidationError{Message: "wish does not belong to this list"}
 	}
 
-	if err = svc.wishes.ReleaseWish(ctx, wishID, userID); err != nil {
+	if !wish.Status.AcceptsReservations() {
+		return svcErr.ValidationError{Message: "wish is already " + string(wish.Status)}
+	}
+
+	if err = svc.wishes.ReleaseWish(ctx, wishID, userID, units); err != nil {
 		if _, ok := errors.AsType[svcErr.ValidationError](err); ok {
 			return err
 		}
@@ -169,10 +482,125 @@ func (svc *WishServiceImpl) ReleaseWish(ctx context.Context, listID, wishID, use
 		}
 		return err
 	}
+	svc.activity.RecordWish(ctx, userID, models.ActivityWishReleased, wish)
+
+	return nil
+}
+
+// ChangeWishStatus moves the wish along its lifecycle, only the reserver marks it purchased and only editors mark it received or archived
+func (svc *WishServiceImpl) ChangeWishStatus(ctx context.Context, listID, wishID, userID uuid.UUID, status models.WishStatus) error {
+	wish, err := svc.wishes.GetWishByID(ctx, wishID)
+	if err != nil {
+		return err
+	}
+
+	if wish.ListID != listID {
+		return svcErr.ValidationError{Message: "wish does not belong to this list"}
+	}
+
+	list, err := svc.wishlists.GetListByID(ctx, wish.ListID)
+	if err != nil {
+		return err
+	}
+
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case models.WishStatusPurchased:
+		// Marking it purchased ends every reservation of the wish, so the caller has to hold all of them
+		reservation := wish.ReservationOf(&userID)
+		if reservation == nil || reservation.Units != wish.ReservedUnits() {
+			return svcErr.ForbiddenError{Message: "only the one who reserved this wish can mark it as purchased"}
+		}
+		if !wish.IsFullyReserved() {
+			return svcErr.ValidationError{Message: fmt.Sprintf("reserve the remaining %d units before marking the wish as purchased", wish.RemainingUnits())}
+		}
+	case models.WishStatusReceived, models.WishStatusArchived:
+		if !role.CanEdit() {
+			return svcErr.ForbiddenError{Message: "you are not allowed to edit this wish"}
+		}
+	default:
+		return svcErr.ValidationError{Message: fmt.Sprintf("wish cannot be marked as %s", status)}
+	}
+
+	if !wish.Status.CanTransitionTo(status) {
+		return svcErr.ValidationError{Message: fmt.Sprintf("wish cannot go from %s to %s", wish.Status, status)}
+	}
+
+	if err = svc.wishes.UpdateWishStatus(ctx, wishID, wish.Status, status); err != nil {
+		return err
+	}
+	svc.activity.RecordWish(ctx, userID, statusActivity[status], wish)
 
 	return nil
 }
 
+// statusActivity names the feed event of each status a wish can be moved to
+var statusActivity = map[models.WishStatus]models.ActivityKind{
+	models.WishStatusPurchased: models.ActivityWishPurchased,
+	models.WishStatusReceived:  models.ActivityWishReceived,
+	models.WishStatusArchived:  models.ActivityWishArchived,
+}
+
+// GetPriceHistory returns the price changes seen by the price tracking job, oldest first, to anyone who can read the list
+func (svc *WishServiceImpl) GetPriceHistory(ctx context.Context, listID, wishID, userID uuid.UUID) ([]models.WishPrice, error) {
+	wish, err := svc.wishes.GetWishByID(ctx, wishID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wish.ListID != listID {
+		return nil, svcErr.ValidationError{Message: "wish does not belong to this list"}
+	}
+
+	list, err := svc.wishlists.GetListByID(ctx, wish.ListID)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.Role, err = resolveListRole(ctx, svc.members, list, userID); err != nil {
+		return nil, err
+	}
+	following, err := followsListOwner(ctx, svc.follows, list, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReadList(list, false, following) {
+		return nil, svcErr.ForbiddenError{Message: "this wishlist is private"}
+	}
+
+	return svc.prices.GetPriceHistory(ctx, wishID)
+}
+
+// ReorderWishes puts the wishes of the list in the given order, the request has to name each of them exactly once
+func (svc *WishServiceImpl) ReorderWishes(ctx context.Context, listID, userID uuid.UUID, req models.ReorderWishesRequest) error {
+	list, err := svc.wishlists.GetListByID(ctx, listID)
+	if err != nil {
+		return err
+	}
+
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return svcErr.ForbiddenError{Message: "you are not allowed to edit this wishlist"}
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(req.WishIDs))
+	for _, id := range req.WishIDs {
+		if _, ok := seen[id]; ok {
+			return svcErr.ValidationError{Message: fmt.Sprintf("wish '%s' is listed more than once", id)}
+		}
+		seen[id] = struct{}{}
+	}
+
+	return svc.wishes.ReorderWishes(ctx, listID, req.WishIDs)
+}
+
 func (svc *WishServiceImpl) DeleteWish(ctx context.Context, listID, wishID, userID uuid.UUID) error {
 	wish, err := svc.wishes.GetWishByID(ctx, wishID)
 	if err != nil {
@@ -188,9 +616,24 @@ func (svc *WishServiceImpl) DeleteWish(ctx context.Context, listID, wishID, user
 		return err
 	}
 
-	if list.UserID != userID {
-		return svcErr.ForbiddenError{Message: "you are not the owner of this wish"}
+	role, err := resolveListRole(ctx, svc.members, list, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return svcErr.ForbiddenError{Message: "you are not allowed to edit this wish"}
 	}
 
-	return svc.wishes.DeleteWishByID(ctx, wishID)
+	// The wish goes to the trash, TrashPurgeJob removes it and its image after the retention period
+	if err = svc.wishes.DeleteWishByID(ctx, wishID); err != nil {
+		return err
+	}
+	svc.activity.RecordWish(ctx, userID, models.ActivityWishDeleted, wish)
+
+	return nil
+}
+
+// wishImageOwner makes the list owner the owner of the image, so it goes away with their account
+func wishImageOwner(list models.List, wishID uuid.UUID) models.ObjectOwner {
+	return models.ObjectOwner{UserID: &list.UserID, ListID: &list.ID, WishID: &wishID}
 }
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -15,20 +16,34 @@ type wishSvcWishStorageMock struct {
 	createErr  error
 	getErr     error
 	updateErr  error
+	statusErr  error
 	reserveErr error
 	releaseErr error
 	deleteErr  error
 
-	wishToReturn models.Wish
-	deletedID    uuid.UUID
-	reservedID   uuid.UUID
-	reservedBy   uuid.UUID
+	wishToReturn  models.Wish
+	deletedID     uuid.UUID
+	reservedID    uuid.UUID
+	reservedBy    uuid.UUID
+	reservedUnits int
+	statusFrom    models.WishStatus
+	statusTo      models.WishStatus
+	reorderedIDs  []uuid.UUID
+	created       []models.Wish
 }
 
 func (m *wishSvcWishStorageMock) CreateWish(ctx context.Context, wish models.Wish) error {
 	return m.createErr
 }
 
+func (m *wishSvcWishStorageMock) CreateWishes(ctx context.Context, wishes []models.Wish) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.created = append(m.created, wishes...)
+	return nil
+}
+
 func (m *wishSvcWishStorageMock) GetWishByID(ctx context.Context, wishID uuid.UUID) (models.Wish, error) {
 	if m.getErr != nil {
 		return models.Wish{}, m.getErr
@@ -36,7 +51,7 @@ func (m *wishSvcWishStorageMock) GetWishByID(ctx context.Context, wishID uuid.UU
 	return m.wishToReturn, nil
 }
 
-func (m *wishSvcWishStorageMock) GetWishesByListID(ctx context.Context, listID uuid.UUID) ([]models.Wish, error) {
+func (m *wishSvcWishStorageMock) GetWishesByListID(ctx context.Context, listID uuid.UUID, filter models.WishFilter) ([]models.Wish, error) {
 	return nil, nil
 }
 
@@ -44,13 +59,24 @@ func (m *wishSvcWishStorageMock) UpdateWishByID(ctx context.Context, wishID uuid
 	return m.updateErr
 }
 
-func (m *wishSvcWishStorageMock) ReserveWish(ctx context.Context, wishID, userID uuid.UUID) error {
-	m.reservedID = wishID
-	m.reservedBy = userID
+func (m *wishSvcWishStorageMock) UpdateWishStatus(ctx context.Context, wishID uuid.UUID, from, to models.WishStatus) error {
+	m.statusFrom, m.statusTo = from, to
+	return m.statusErr
+}
+
+func (m *wishSvcWishStorageMock) ReorderWishes(ctx context.Context, listID uuid.UUID, wishIDs []uuid.UUID) error {
+	m.reorderedIDs = wishIDs
+	return m.updateErr
+}
+
+func (m *wishSvcWishStorageMock) ReserveWish(ctx context.Context, reservation models.WishReservation) error {
+	m.reservedID = reservation.WishID
+	m.reservedBy = reservation.UserID
+	m.reservedUnits = reservation.Units
 	return m.reserveErr
 }
 
-func (m *wishSvcWishStorageMock) ReleaseWish(ctx context.Context, wishID, userID uuid.UUID) error {
+func (m *wishSvcWishStorageMock) ReleaseWish(ctx context.Context, wishID, userID uuid.UUID, units int) error {
 	return m.releaseErr
 }
 
@@ -68,6 +94,10 @@ func (m *wishSvcListStorageMock) CreateList(ctx context.Context, list models.Lis
 	return nil
 }
 
+func (m *wishSvcListStorageMock) CreateListWithWishes(ctx context.Context, list models.List, wishes []models.Wish) error {
+	return nil
+}
+
 func (m *wishSvcListStorageMock) GetListByID(ctx context.Context, id uuid.UUID) (models.List, error) {
 	if m.getErr != nil {
 		return models.List{}, m.getErr
@@ -83,7 +113,7 @@ func (m *wishSvcListStorageMock) GetListsByUserID(ctx context.Context, userID uu
 	return nil, nil
 }
 
-func (m *wishSvcListStorageMock) GetPublicListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
+func (m *wishSvcListStorageMock) GetPublicListsByUserID(ctx context.Context, userID uuid.UUID, withFollowers bool) ([]models.List, error) {
 	return nil, nil
 }
 
@@ -105,7 +135,7 @@ func TestWishService_CreateWish_NotOwner(t *testing.T) {
 	callerID := uuid.New()
 	wishStorage := &wishSvcWishStorageMock{}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
 	_, err := svc.CreateWish(context.Background(), listID, callerID, models.CreateWishRequest{Title: "PS5"})
 	if err == nil {
@@ -127,7 +157,7 @@ func TestWishService_UpdateWish_ListMismatch(t *testing.T) {
 		wishToReturn: models.Wish{ID: wishID, ListID: actualListID},
 	}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: actualListID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
 	err := svc.UpdateWish(context.Background(), givenListID, wishID, ownerID, models.UpdateWishRequest{})
 	if err == nil {
@@ -146,10 +176,10 @@ func TestWishService_UpdateWish_NotOwner(t *testing.T) {
 	callerID := uuid.New()
 
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 	}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
 	err := svc.UpdateWish(context.Background(), listID, wishID, callerID, models.UpdateWishRequest{})
 	if err == nil {
@@ -167,12 +197,12 @@ func TestWishService_ReserveWish_OwnWish(t *testing.T) {
 	ownerID := uuid.New()
 
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 	}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
-	err := svc.ReserveWish(context.Background(), listID, wishID, ownerID)
+	err := svc.ReserveWish(context.Background(), listID, wishID, ownerID, models.ReserveWishRequest{})
 	if err == nil {
 		t.Fatal("ReserveWish() error = nil, want validation error")
 	}
@@ -189,19 +219,39 @@ func TestWishService_ReserveWish_Success(t *testing.T) {
 	callerID := uuid.New()
 
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 	}
-	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID, Visibility: models.ListVisibilityPublic}}
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
-	if err := svc.ReserveWish(context.Background(), listID, wishID, callerID); err != nil {
+	if err := svc.ReserveWish(context.Background(), listID, wishID, callerID, models.ReserveWishRequest{}); err != nil {
 		t.Fatalf("ReserveWish() error = %v", err)
 	}
-	if wishStorage.reservedID != wishID || wishStorage.reservedBy != callerID {
+	if wishStorage.reservedID != wishID || wishStorage.reservedBy != callerID || wishStorage.reservedUnits != 1 {
 		t.Fatalf("ReserveWish() forwarded wrong params")
 	}
 }
 
+func TestWishService_UpdateWish_QuantityBelowReserved(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	ownerID := uuid.New()
+	wishStorage := &wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID, Quantity: 4, Reservations: []models.WishReservation{
+		{WishID: wishID, UserID: uuid.New(), Units: 2},
+		{WishID: wishID, UserID: uuid.New(), Units: 1},
+	}}}
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+	var validation svcErr.ValidationError
+	if err := svc.UpdateWish(context.Background(), listID, wishID, ownerID, models.UpdateWishRequest{Quantity: new(2)}); !errors.As(err, &validation) {
+		t.Fatalf("UpdateWish() error = %T, want ValidationError", err)
+	}
+	if err := svc.UpdateWish(context.Background(), listID, wishID, ownerID, models.UpdateWishRequest{Quantity: new(3)}); err != nil {
+		t.Fatalf("UpdateWish() error = %v", err)
+	}
+}
+
 func TestWishService_ReserveWish_AlreadyReserved(t *testing.T) {
 	listID := uuid.New()
 	wishID := uuid.New()
@@ -209,13 +259,13 @@ func TestWishService_ReserveWish_AlreadyReserved(t *testing.T) {
 	callerID := uuid.New()
 
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 		reserveErr:   errors.New("failed to reserve wish with ID 'x': already reserved or not found"),
 	}
-	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID, Visibility: models.ListVisibilityPublic}}
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
-	err := svc.ReserveWish(context.Background(), listID, wishID, callerID)
+	err := svc.ReserveWish(context.Background(), listID, wishID, callerID, models.ReserveWishRequest{})
 	if err == nil {
 		t.Fatal("ReserveWish() error = nil, want validation error")
 	}
@@ -235,10 +285,12 @@ func TestWishService_DeleteWish_Success(t *testing.T) {
 	ownerID := uuid.New()
 
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 	}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+	records := &objectRecordStorageMock{released: []models.StoredObject{{ObjectName: "wishes/1/2", URL: s3.baseURL + "/wishes/1/2/original"}}}
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, s3, nil, nil, NewObjectTracker(records, s3, &userLoggerMock{}), nil)
 
 	if err := svc.DeleteWish(context.Background(), listID, wishID, ownerID); err != nil {
 		t.Fatalf("DeleteWish() error = %v", err)
@@ -246,6 +298,9 @@ func TestWishService_DeleteWish_Success(t *testing.T) {
 	if wishStorage.deletedID != wishID {
 		t.Fatalf("DeleteWish() deleted ID = %s, want %s", wishStorage.deletedID, wishID)
 	}
+	if records.owner.WishID != nil || len(s3.deletedObjs) != 0 {
+		t.Fatalf("released %+v and deleted %v, want the image kept while the wish is in the trash", records.owner, s3.deletedObjs)
+	}
 }
 
 func TestWishService_CreateWish_Success(t *testing.T) {
@@ -253,7 +308,7 @@ func TestWishService_CreateWish_Success(t *testing.T) {
 	ownerID := uuid.New()
 	wishStorage := &wishSvcWishStorageMock{}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
 	price := int64(5000)
 	currency := "RUB"
@@ -268,13 +323,132 @@ func TestWishService_CreateWish_Success(t *testing.T) {
 	if wish.ID == uuid.Nil || wish.ListID != listID || wish.Title != "Headphones" {
 		t.Fatalf("CreateWish() returned invalid wish: %+v", wish)
 	}
+	if wish.Priority != models.WishPriorityNiceToHave {
+		t.Fatalf("CreateWish() priority = %q, want %q", wish.Priority, models.WishPriorityNiceToHave)
+	}
+}
+
+func TestWishService_CreateWish_Currency(t *testing.T) {
+	listID := uuid.New()
+	ownerID := uuid.New()
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
+	svc := NewWishService(&wishSvcWishStorageMock{}, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+	wish, err := svc.CreateWish(context.Background(), listID, ownerID, models.CreateWishRequest{Title: "Book", Currency: new("usd")})
+	if err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+	if wish.Currency == nil || *wish.Currency != "USD" {
+		t.Fatalf("CreateWish() currency = %v, want USD", wish.Currency)
+	}
+
+	_, err = svc.CreateWish(context.Background(), listID, ownerID, models.CreateWishRequest{Title: "Book", Currency: new("руб")})
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("CreateWish() error = %v, want ValidationError", err)
+	}
+}
+
+func TestWishService_ImportWishes(t *testing.T) {
+	listID := uuid.New()
+	ownerID := uuid.New()
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
+	rows := []models.WishImportRow{
+		{Row: 2, Request: models.CreateWishRequest{Title: "Kettle", Currency: new("eur"), Image: new("wishes/other/w/original")}},
+		{Row: 3, Request: models.CreateWishRequest{Title: "Socks"}, Error: "link is invalid"},
+		{Row: 4, Request: models.CreateWishRequest{Title: "Book", Currency: new("руб")}},
+		{Row: 5, Request: models.CreateWishRequest{Title: "Mug"}},
+	}
+
+	t.Run("owner", func(t *testing.T) {
+		wishStorage := &wishSvcWishStorageMock{}
+		svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+		report, err := svc.ImportWishes(context.Background(), listID, ownerID, rows)
+		if err != nil {
+			t.Fatalf("ImportWishes() error = %v", err)
+		}
+		if report.Created != 2 || report.Rejected != 2 || len(report.Rows) != 4 {
+			t.Fatalf("ImportWishes() report = %+v", report)
+		}
+		if report.Rows[1].Error != "link is invalid" || report.Rows[2].Error == "" || report.Rows[2].WishID != nil {
+			t.Fatalf("ImportWishes() rejected rows = %+v", report.Rows[1:3])
+		}
+		if len(wishStorage.created) != 2 || wishStorage.created[0].Title != "Kettle" || wishStorage.created[1].Title != "Mug" {
+			t.Fatalf("created = %+v, want Kettle and Mug in one call", wishStorage.created)
+		}
+		if *wishStorage.created[0].Currency != "EUR" || wishStorage.created[0].Image != nil || *report.Rows[0].WishID != wishStorage.created[0].ID {
+			t.Fatalf("created[0] = %+v", wishStorage.created[0])
+		}
+	})
+
+	t.Run("not an editor", func(t *testing.T) {
+		wishStorage := &wishSvcWishStorageMock{}
+		svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+		_, err := svc.ImportWishes(context.Background(), listID, uuid.New(), rows)
+		if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+			t.Fatalf("ImportWishes() error = %v, want ForbiddenError", err)
+		}
+		if len(wishStorage.created) != 0 {
+			t.Fatal("wishes were created for a stranger")
+		}
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		svc := NewWishService(&wishSvcWishStorageMock{createErr: errors.New("db error")}, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+		if _, err := svc.ImportWishes(context.Background(), listID, ownerID, rows); err == nil {
+			t.Fatal("ImportWishes() error = nil, want storage error")
+		}
+	})
+}
+
+func TestWishService_ReorderWishes(t *testing.T) {
+	listID := uuid.New()
+	ownerID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
+
+	t.Run("owner", func(t *testing.T) {
+		wishStorage := &wishSvcWishStorageMock{}
+		svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+		if err := svc.ReorderWishes(context.Background(), listID, ownerID, models.ReorderWishesRequest{WishIDs: []uuid.UUID{second, first}}); err != nil {
+			t.Fatalf("ReorderWishes() error = %v", err)
+		}
+		if len(wishStorage.reorderedIDs) != 2 || wishStorage.reorderedIDs[0] != second {
+			t.Fatalf("ReorderWishes() stored order = %v, want [%s %s]", wishStorage.reorderedIDs, second, first)
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		wishStorage := &wishSvcWishStorageMock{}
+		svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+		err := svc.ReorderWishes(context.Background(), listID, ownerID, models.ReorderWishesRequest{WishIDs: []uuid.UUID{first, first}})
+		if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+			t.Fatalf("ReorderWishes() error = %v, want ValidationError", err)
+		}
+		if wishStorage.reorderedIDs != nil {
+			t.Fatal("ReorderWishes() reached storage with duplicates")
+		}
+	})
+
+	t.Run("not editor", func(t *testing.T) {
+		svc := NewWishService(&wishSvcWishStorageMock{}, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+		err := svc.ReorderWishes(context.Background(), listID, uuid.New(), models.ReorderWishesRequest{WishIDs: []uuid.UUID{first}})
+		if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+			t.Fatalf("ReorderWishes() error = %v, want ForbiddenError", err)
+		}
+	})
 }
 
 func TestWishService_GetWishByID(t *testing.T) {
 	wishID := uuid.New()
 	expected := models.Wish{ID: wishID, Title: "Keyboard"}
 	wishStorage := &wishSvcWishStorageMock{wishToReturn: expected}
-	svc := NewWishService(wishStorage, &wishSvcListStorageMock{}, nil)
+	svc := NewWishService(wishStorage, &wishSvcListStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
 	actual, err := svc.GetWishByID(context.Background(), wishID)
 	if err != nil {
@@ -291,27 +465,55 @@ func TestWishService_UpdateWish_Success(t *testing.T) {
 	ownerID := uuid.New()
 
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 	}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
 	if err := svc.UpdateWish(context.Background(), listID, wishID, ownerID, models.UpdateWishRequest{Title: ptr("New Title")}); err != nil {
 		t.Fatalf("UpdateWish() error = %v", err)
 	}
 }
 
+func TestWishService_UpdateWish_ForeignObject(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	ownerID := uuid.New()
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+	svc := NewWishService(&wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID}}, listStorage, &listMemberStorageMock{}, nil, s3, nil, nil, nil, nil)
+
+	for _, image := range []string{
+		"avatars/" + ownerID.String() + "/1/original",
+		"uploads/" + ownerID.String() + "/1",
+		"http://minio:9000/wishlist/exports/" + uuid.NewString() + "/archive.zip",
+		"wishes/../exports/" + uuid.NewString() + "/archive.zip",
+	} {
+		t.Run(image, func(t *testing.T) {
+			var validation svcErr.ValidationError
+			if err := svc.UpdateWish(context.Background(), listID, wishID, ownerID, models.UpdateWishRequest{Image: &image}); !errors.As(err, &validation) {
+				t.Fatalf("UpdateWish() error = %T, want ValidationError", err)
+			}
+		})
+	}
+
+	external := "https://shop.example/socks.jpg"
+	if err := svc.UpdateWish(context.Background(), listID, wishID, ownerID, models.UpdateWishRequest{Image: &external}); err != nil {
+		t.Fatalf("UpdateWish() with a link to another site error = %v", err)
+	}
+}
+
 func TestWishService_ReleaseWish(t *testing.T) {
 	listID := uuid.New()
 	wishID := uuid.New()
 	userID := uuid.New()
 
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 	}
-	svc := NewWishService(wishStorage, &wishSvcListStorageMock{}, nil)
+	svc := NewWishService(wishStorage, &wishSvcListStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
-	err := svc.ReleaseWish(context.Background(), uuid.New(), wishID, userID)
+	err := svc.ReleaseWish(context.Background(), uuid.New(), wishID, userID, 0)
 	if err == nil {
 		t.Fatal("ReleaseWish() error = nil, want validation error")
 	}
@@ -320,7 +522,7 @@ func TestWishService_ReleaseWish(t *testing.T) {
 		t.Fatalf("ReleaseWish() error = %T, want ValidationError", err)
 	}
 
-	if err = svc.ReleaseWish(context.Background(), listID, wishID, userID); err != nil {
+	if err = svc.ReleaseWish(context.Background(), listID, wishID, userID, 0); err != nil {
 		t.Fatalf("ReleaseWish() success error = %v", err)
 	}
 }
@@ -331,12 +533,12 @@ func TestWishService_ReleaseWish_NotReservedByUser(t *testing.T) {
 	userID := uuid.New()
 
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 		releaseErr:   errors.New("failed to release wish with ID 'x': not reserved by you or not found"),
 	}
-	svc := NewWishService(wishStorage, &wishSvcListStorageMock{}, nil)
+	svc := NewWishService(wishStorage, &wishSvcListStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
-	err := svc.ReleaseWish(context.Background(), listID, wishID, userID)
+	err := svc.ReleaseWish(context.Background(), listID, wishID, userID, 0)
 	if err == nil {
 		t.Fatal("ReleaseWish() error = nil, want validation error")
 	}
@@ -357,10 +559,10 @@ func TestWishService_DeleteWish_NotOwner(t *testing.T) {
 	callerID := uuid.New()
 
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 	}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
 	err := svc.DeleteWish(context.Background(), listID, wishID, callerID)
 	if err == nil {
@@ -377,7 +579,7 @@ func TestWishService_CreateWish_StorageError(t *testing.T) {
 	ownerID := uuid.New()
 	wishStorage := &wishSvcWishStorageMock{createErr: errors.New("db error")}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
 	_, err := svc.CreateWish(context.Background(), listID, ownerID, models.CreateWishRequest{Title: "PS5"})
 	if err == nil {
@@ -390,11 +592,11 @@ func TestWishService_UpdateWish_StorageError(t *testing.T) {
 	wishID := uuid.New()
 	ownerID := uuid.New()
 	wishStorage := &wishSvcWishStorageMock{
-		wishToReturn: models.Wish{ID: wishID, ListID: listID},
+		wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusOpen},
 		updateErr:    errors.New("db error"),
 	}
 	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
-	svc := NewWishService(wishStorage, listStorage, nil)
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
 	err := svc.UpdateWish(context.Background(), listID, wishID, ownerID, models.UpdateWishRequest{})
 	if err == nil {
@@ -409,9 +611,9 @@ func TestWishService_ReserveWish_ListMismatch(t *testing.T) {
 	wishStorage := &wishSvcWishStorageMock{
 		wishToReturn: models.Wish{ID: wishID, ListID: uuid.New()},
 	}
-	svc := NewWishService(wishStorage, &wishSvcListStorageMock{}, nil)
+	svc := NewWishService(wishStorage, &wishSvcListStorageMock{}, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
 
-	err := svc.ReserveWish(context.Background(), listID, wishID, userID)
+	err := svc.ReserveWish(context.Background(), listID, wishID, userID, models.ReserveWishRequest{})
 	if err == nil {
 		t.Fatal("ReserveWish() error = nil, want validation error")
 	}
@@ -420,3 +622,169 @@ func TestWishService_ReserveWish_ListMismatch(t *testing.T) {
 func ptr(v string) *string {
 	return &v
 }
+
+func TestWishService_ChangeWishStatus(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	ownerID := uuid.New()
+	reserverID := uuid.New()
+	reserved := []models.WishReservation{{WishID: wishID, UserID: reserverID, Units: 1}}
+
+	tests := []struct {
+		name    string
+		wish    models.Wish
+		userID  uuid.UUID
+		status  models.WishStatus
+		wantErr error
+	}{
+		{name: "reserver purchases", wish: models.Wish{Status: models.WishStatusReserved, Reservations: reserved}, userID: reserverID, status: models.WishStatusPurchased},
+		{name: "owner receives", wish: models.Wish{Status: models.WishStatusPurchased, Reservations: reserved}, userID: ownerID, status: models.WishStatusReceived},
+		{name: "owner archives open wish", wish: models.Wish{Status: models.WishStatusOpen}, userID: ownerID, status: models.WishStatusArchived},
+		{name: "owner cannot purchase", wish: models.Wish{Status: models.WishStatusReserved, Reservations: reserved}, userID: ownerID, status: models.WishStatusPurchased, wantErr: svcErr.ForbiddenError{}},
+		{name: "partial reserver cannot purchase", wish: models.Wish{Quantity: 2, Status: models.WishStatusReserved, Reservations: append([]models.WishReservation{{WishID: wishID, UserID: uuid.New(), Units: 1}}, reserved...)}, userID: reserverID, status: models.WishStatusPurchased, wantErr: svcErr.ForbiddenError{}},
+		{name: "units left to reserve", wish: models.Wish{Quantity: 2, Status: models.WishStatusOpen, Reservations: reserved}, userID: reserverID, status: models.WishStatusPurchased, wantErr: svcErr.ValidationError{}},
+		{name: "reserver cannot receive", wish: models.Wish{Status: models.WishStatusPurchased, Reservations: reserved}, userID: reserverID, status: models.WishStatusReceived, wantErr: svcErr.ForbiddenError{}},
+		{name: "open cannot be received", wish: models.Wish{Status: models.WishStatusOpen}, userID: ownerID, status: models.WishStatusReceived, wantErr: svcErr.ValidationError{}},
+		{name: "archived stays archived", wish: models.Wish{Status: models.WishStatusArchived}, userID: ownerID, status: models.WishStatusArchived, wantErr: svcErr.ValidationError{}},
+		{name: "reserved is not a target", wish: models.Wish{Status: models.WishStatusOpen}, userID: ownerID, status: models.WishStatusReserved, wantErr: svcErr.ValidationError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wish.ID, tt.wish.ListID = wishID, listID
+			wishStorage := &wishSvcWishStorageMock{wishToReturn: tt.wish}
+			listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
+			svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+			err := svc.ChangeWishStatus(context.Background(), listID, wishID, tt.userID, tt.status)
+			switch tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Fatalf("ChangeWishStatus() error = %v", err)
+				}
+				if wishStorage.statusFrom != tt.wish.Status || wishStorage.statusTo != tt.status {
+					t.Fatalf("UpdateWishStatus() got %s -> %s, want %s -> %s", wishStorage.statusFrom, wishStorage.statusTo, tt.wish.Status, tt.status)
+				}
+			case svcErr.ForbiddenError:
+				if _, ok := errors.AsType[svcErr.ForbiddenError](err); !ok {
+					t.Fatalf("ChangeWishStatus() error = %v, want ForbiddenError", err)
+				}
+			case svcErr.ValidationError:
+				if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+					t.Fatalf("ChangeWishStatus() error = %v, want ValidationError", err)
+				}
+			}
+			if tt.wantErr != nil && wishStorage.statusTo != "" {
+				t.Fatal("UpdateWishStatus() called on rejected transition")
+			}
+		})
+	}
+}
+
+func TestWishService_ReserveWish_NotAvailable(t *testing.T) {
+	listID := uuid.New()
+	wishID := uuid.New()
+	wishStorage := &wishSvcWishStorageMock{wishToReturn: models.Wish{ID: wishID, ListID: listID, Status: models.WishStatusPurchased}}
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: uuid.New(), Visibility: models.ListVisibilityPublic}}
+	svc := NewWishService(wishStorage, listStorage, &listMemberStorageMock{}, nil, nil, nil, nil, nil, nil)
+
+	err := svc.ReserveWish(context.Background(), listID, wishID, uuid.New(), models.ReserveWishRequest{})
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("ReserveWish() error = %v, want ValidationError", err)
+	}
+	if wishStorage.reservedID != uuid.Nil {
+		t.Fatal("ReserveWish() reached storage for a purchased wish")
+	}
+}
+
+type wishSvcScraperMock struct {
+	preview   models.LinkPreview
+	image     []byte
+	scrapeErr error
+	imageErr  error
+}
+
+func (m *wishSvcScraperMock) Scrape(ctx context.Context, link string) (models.LinkPreview, error) {
+	if m.scrapeErr != nil {
+		return models.LinkPreview{}, m.scrapeErr
+	}
+	preview := m.preview
+	preview.Link = link
+	return preview, nil
+}
+
+func (m *wishSvcScraperMock) FetchImage(ctx context.Context, imageURL string) ([]byte, string, error) {
+	if m.imageErr != nil {
+		return nil, "", m.imageErr
+	}
+	return m.image, "image/png", nil
+}
+
+func TestWishService_PreviewLink_UploadsImage(t *testing.T) {
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+	scraper := &wishSvcScraperMock{preview: models.LinkPreview{Title: new("Kettle"), ImageURL: new("https://shop.example.com/kettle.jpg")}, image: testPNG(t)}
+	svc := NewWishService(&wishSvcWishStorageMock{}, &wishSvcListStorageMock{}, &listMemberStorageMock{}, nil, s3, scraper, nil, nil, nil)
+
+	preview, err := svc.PreviewLink(context.Background(), "https://shop.example.com/kettle")
+	if err != nil {
+		t.Fatalf("PreviewLink() error = %v", err)
+	}
+	if len(s3.uploadedObjects) != 3 || !strings.HasPrefix(s3.uploadedObjectName, "wishes/") || s3.uploadedType != "image/png" {
+		t.Fatalf("uploaded %v (%s), want every variant of the wish image", s3.uploadedObjects, s3.uploadedType)
+	}
+	if preview.Image == nil || *preview.Image != s3.uploadedObjects[0] || !strings.HasSuffix(*preview.Image, "/original") {
+		t.Fatalf("preview image = %v, want key of the original", preview.Image)
+	}
+
+	scraper.image = []byte("<html>not a picture</html>")
+	if preview, err = svc.PreviewLink(context.Background(), "https://shop.example.com/kettle"); err != nil || preview.Image != nil {
+		t.Fatalf("PreviewLink() = %+v, %v, want preview without an unreadable image", preview, err)
+	}
+}
+
+func TestWishService_PreviewLink_Errors(t *testing.T) {
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+
+	svc := NewWishService(&wishSvcWishStorageMock{}, &wishSvcListStorageMock{}, &listMemberStorageMock{}, nil, s3, &wishSvcScraperMock{scrapeErr: errors.New("timeout")}, nil, nil, nil)
+	if _, err := svc.PreviewLink(context.Background(), "https://shop.example.com/x"); !errors.As(err, new(svcErr.ValidationError)) {
+		t.Fatalf("PreviewLink() error = %v, want ValidationError", err)
+	}
+
+	scraper := &wishSvcScraperMock{preview: models.LinkPreview{Title: new("Kettle"), ImageURL: new("https://shop.example.com/x.jpg")}, imageErr: errors.New("too large")}
+	svc = NewWishService(&wishSvcWishStorageMock{}, &wishSvcListStorageMock{}, &listMemberStorageMock{}, nil, s3, scraper, nil, nil, nil)
+	preview, err := svc.PreviewLink(context.Background(), "https://shop.example.com/x")
+	if err != nil || preview.Image != nil || *preview.Title != "Kettle" {
+		t.Fatalf("PreviewLink() = %+v, %v, want preview without image", preview, err)
+	}
+}
+
+func TestWishService_CreateWish_Autofill(t *testing.T) {
+	listID := uuid.New()
+	ownerID := uuid.New()
+	s3 := &userAvatarStorageMock{baseURL: "http://minio:9000/wishlist"}
+	scraper := &wishSvcScraperMock{preview: models.LinkPreview{Title: new("Kettle"), Price: new(int64(20)), Currency: new("USD"), ImageURL: new("https://shop.example.com/kettle.jpg")}, image: testPNG(t)}
+	listStorage := &wishSvcListStorageMock{list: models.List{ID: listID, UserID: ownerID}}
+	svc := NewWishService(&wishSvcWishStorageMock{}, listStorage, &listMemberStorageMock{}, nil, s3, scraper, nil, nil, nil)
+
+	wish, err := svc.CreateWish(context.Background(), listID, ownerID, models.CreateWishRequest{Title: "My kettle", Link: new("https://shop.example.com/kettle"), Autofill: true})
+	if err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+	if wish.Title != "My kettle" {
+		t.Fatalf("title = %s, want the one sent by user", wish.Title)
+	}
+	if wish.Price == nil || *wish.Price != 20 || wish.Currency == nil || *wish.Currency != "USD" {
+		t.Fatalf("price = %v %v, want 20 USD from the page", wish.Price, wish.Currency)
+	}
+	if wish.Image == nil || !strings.HasPrefix(*wish.Image, "wishes/"+wish.ID.String()+"/") {
+		t.Fatalf("image = %v, want uploaded under the wish ID", wish.Image)
+	}
+
+	scraper.scrapeErr = errors.New("timeout")
+	if _, err = svc.CreateWish(context.Background(), listID, ownerID, models.CreateWishRequest{Link: new("https://shop.example.com/kettle"), Autofill: true}); !errors.As(err, new(svcErr.ValidationError)) {
+		t.Fatalf("CreateWish() without title and preview error = %v, want ValidationError", err)
+	}
+	if _, err = svc.CreateWish(context.Background(), listID, ownerID, models.CreateWishRequest{Title: "Kettle", Link: new("https://shop.example.com/kettle"), Autofill: true}); err != nil {
+		t.Fatalf("CreateWish() with title and failed preview error = %v, want wish created", err)
+	}
+}
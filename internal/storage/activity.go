@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+)
+
+type ActivityStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewActivityStorage(pool *pgxpool.Pool) *ActivityStorageImpl {
+	return &ActivityStorageImpl{pool: pool}
+}
+
+func (s *ActivityStorageImpl) RecordActivity(ctx context.Context, event models.ActivityEvent) error {
+	if _, err := s.pool.Exec(ctx,
+		`INSERT INTO activity_events (id, actor_id, list_id, wish_id, kind, title, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		event.ID, event.ActorID, event.ListID, event.WishID, event.Kind, event.Title, event.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to record %s activity of list with ID '%s': %w", event.Kind, event.ListID, err)
+	}
+
+	return nil
+}
+
+// GetFeed returns what others did in the lists the viewer can read: their own and shared ones, and lists of the users
+// they follow unless those are private or link-only. Events come newest first, starting after the cursor if there is one.
+// Reservation activity is left out for everyone, the lists never tell one viewer who reserved a wish and neither does the feed
+func (s *ActivityStorageImpl) GetFeed(ctx context.Context, viewerID uuid.UUID, cursor *models.FeedCursor, limit int) ([]models.ActivityEvent, error) {
+	var after models.FeedCursor // Ignored without a cursor
+	if cursor != nil {
+		after = *cursor
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT e.id, e.actor_id, u.username, e.list_id, l.title, e.wish_id, e.kind, e.title, e.created_at
+		FROM activity_events e
+		JOIN lists l ON l.id = e.list_id AND l.deleted_at IS NULL
+		JOIN users u ON u.id = e.actor_id
+		LEFT JOIN list_members m ON m.list_id = l.id AND m.user_id = $1 AND m.accepted_at IS NOT NULL
+		WHERE e.actor_id <> $1
+			AND (NOT $2 OR (e.created_at, e.id) < ($3, $4))
+			AND NOT EXISTS (SELECT 1 FROM users o WHERE o.id = l.user_id AND o.delete_after IS NOT NULL)
+			AND (
+				l.user_id = $1
+				OR m.user_id IS NOT NULL
+				OR (l.visibility IN ('public', 'followers') AND EXISTS (
+					SELECT 1 FROM follows f WHERE f.follower_id = $1 AND f.followee_id = l.user_id AND f.accepted_at IS NOT NULL
+				))
+			)
+			AND e.kind NOT IN ('wish_reserved', 'wish_released', 'wish_purchased')
+		ORDER BY e.created_at DESC, e.id DESC
+		LIMIT $5
+	`, viewerID, cursor != nil, after.CreatedAt, after.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed of user with ID '%s': %w", viewerID, err)
+	}
+	defer rows.Close()
+
+	var events []models.ActivityEvent
+	for rows.Next() {
+		var event models.ActivityEvent
+		if err = rows.Scan(&event.ID, &event.ActorID, &event.ActorUsername, &event.ListID, &event.ListTitle, &event.WishID, &event.Kind, &event.Title, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan activity event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
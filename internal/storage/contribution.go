@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type WishContributionStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewWishContributionStorage(pool *pgxpool.Pool) *WishContributionStorageImpl {
+	return &WishContributionStorageImpl{pool: pool}
+}
+
+// SaveContribution creates or replaces the contribution of the user, the wish row is locked so concurrent pledges cannot overfund it
+func (s *WishContributionStorageImpl) SaveContribution(ctx context.Context, contribution models.WishContribution) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var price *int64
+	var status models.WishStatus
+	var reserved bool
+	if err = tx.QueryRow(ctx, `SELECT price, status, EXISTS (SELECT 1 FROM wish_reservations WHERE wish_id = $1 AND (reserved_until IS NULL OR reserved_until > now())) FROM wishes WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, contribution.WishID).Scan(&price, &status, &reserved); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return svcErr.NotFoundError{Entity: "wish", Field: "id", Value: contribution.WishID.String()}
+		}
+		return fmt.Errorf("failed to lock wish with ID '%s': %w", contribution.WishID, err)
+	}
+	if status != models.WishStatusOpen {
+		return svcErr.ValidationError{Message: "wish is no longer available"}
+	}
+	if reserved {
+		return svcErr.ValidationError{Message: "wish is already reserved"}
+	}
+	if price == nil || *price <= 0 {
+		return svcErr.ValidationError{Message: "wish has no price to chip in for"}
+	}
+
+	var others int64
+	if err = tx.QueryRow(ctx, `SELECT COALESCE(SUM(amount), 0) FROM wish_contributions WHERE wish_id = $1 AND user_id <> $2`, contribution.WishID, contribution.UserID).Scan(&others); err != nil {
+		return fmt.Errorf("failed to sum contributions for wish with ID '%s': %w", contribution.WishID, err)
+	}
+	if others+contribution.Amount > *price {
+		return svcErr.ValidationError{Message: fmt.Sprintf("contribution exceeds the remaining amount of %d", *price-others)}
+	}
+
+	if _, err = tx.Exec(ctx, `INSERT INTO wish_contributions (wish_id, user_id, amount, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (wish_id, user_id) DO UPDATE SET amount = EXCLUDED.amount, updated_at = now()`,
+		contribution.WishID, contribution.UserID, contribution.Amount, contribution.CreatedAt, contribution.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save contribution for wish with ID '%s': %w", contribution.WishID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit contribution for wish with ID '%s': %w", contribution.WishID, err)
+	}
+
+	return nil
+}
+
+func (s *WishContributionStorageImpl) DeleteContribution(ctx context.Context, wishID, userID uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, `DELETE FROM wish_contributions WHERE wish_id = $1 AND user_id = $2`, wishID, userID); err != nil {
+		return fmt.Errorf("failed to delete contribution for wish with ID '%s': %w", wishID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "contribution", Field: "wish_id", Value: wishID.String()}
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"wishlist/internal/config"
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+const (
+	filesRoute = "/files"   // Path of the public URL FilesController serves objects from
+	tempDir    = ".uploads" // Files being written, renamed into place once complete so readers never see half a file
+)
+
+// FilesystemStorageImpl keeps objects as files under a directory and implements the same methods as MinioServiceImpl,
+// files are served by FilesController. Signed URLs work like presigned S3 URLs: an expiry and an HMAC in the query
+type FilesystemStorageImpl struct {
+	root    *os.Root
+	baseURL string
+	secret  []byte
+	private bool
+	urlTTL  time.Duration
+}
+
+func NewFilesystemStorage() (*FilesystemStorageImpl, error) {
+	dir := viper.GetString(config.StorageFilesystemRoot)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory '%s': %w", dir, err)
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage directory '%s': %w", dir, err)
+	}
+
+	secret := viper.GetString(config.StorageFilesystemSigningSecret)
+	if secret == "" {
+		secret = viper.GetString(config.AccessTokenSecret)
+	}
+	key := sha256.Sum256([]byte("files:" + secret)) // Never the JWT secret itself, a file signature can't be turned into a token
+
+	return &FilesystemStorageImpl{
+		root:    root,
+		baseURL: strings.TrimSuffix(viper.GetString(config.StorageFilesystemPublicURL), "/") + filesRoute,
+		secret:  key[:],
+		private: viper.GetBool(config.StorageFilesystemPrivate),
+		urlTTL:  viper.GetDuration(config.StorageFilesystemURLTTL),
+	}, nil
+}
+
+func (svc *FilesystemStorageImpl) GetBaseURL() string {
+	return svc.baseURL
+}
+
+func (svc *FilesystemStorageImpl) UploadObject(_ context.Context, objectName string, reader io.Reader, _ int64, _ string) error {
+	if err := validObjectName(objectName); err != nil {
+		return err
+	}
+
+	if err := svc.root.MkdirAll(tempDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	tempName := path.Join(tempDir, uuid.NewString())
+	file, err := svc.root.Create(tempName)
+	if err != nil {
+		return fmt.Errorf("failed to create file for '%s': %w", objectName, err)
+	}
+	if _, err = io.Copy(file, reader); err != nil {
+		_ = file.Close()
+		_ = svc.root.Remove(tempName)
+		return fmt.Errorf("failed to write '%s': %w", objectName, err)
+	}
+	if err = file.Close(); err != nil {
+		_ = svc.root.Remove(tempName)
+		return fmt.Errorf("failed to write '%s': %w", objectName, err)
+	}
+
+	if err = svc.root.MkdirAll(path.Dir(objectName), 0o750); err != nil {
+		_ = svc.root.Remove(tempName)
+		return fmt.Errorf("failed to create directory for '%s': %w", objectName, err)
+	}
+	if err = svc.root.Rename(tempName, objectName); err != nil {
+		_ = svc.root.Remove(tempName)
+		return fmt.Errorf("failed to store '%s': %w", objectName, err)
+	}
+
+	return nil
+}
+
+func (svc *FilesystemStorageImpl) GetObjectURL(objectName string) string {
+	return fmt.Sprintf("%s/%s", svc.baseURL, objectName)
+}
+
+// MediaURL is what clients load the object from: a signed URL when files are private, the plain URL otherwise
+func (svc *FilesystemStorageImpl) MediaURL(objectName string) string {
+	if !svc.private {
+		return svc.GetObjectURL(objectName)
+	}
+
+	expires := time.Now().Add(svc.urlTTL).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", svc.sign(http.MethodGet, objectName, expires, "", 0))
+	return svc.GetObjectURL(objectName) + "?" + query.Encode()
+}
+
+// CopyObject writes a copy of the file under another key, the same way an upload is written
+func (svc *FilesystemStorageImpl) CopyObject(ctx context.Context, srcName, dstName string) error {
+	src, err := svc.GetObject(ctx, srcName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	if err = svc.UploadObject(ctx, dstName, src, -1, ""); err != nil {
+		return fmt.Errorf("failed to copy object '%s' to '%s': %w", srcName, dstName, err)
+	}
+
+	return nil
+}
+
+// DeleteObject removes the file and the directories it leaves empty, a missing file is not an error, same as in S3
+func (svc *FilesystemStorageImpl) DeleteObject(_ context.Context, objectName string) error {
+	if err := validObjectName(objectName); err != nil {
+		return err
+	}
+
+	if err := svc.root.Remove(objectName); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	for dir := path.Dir(objectName); dir != "."; dir = path.Dir(dir) {
+		if svc.root.Remove(dir) != nil { // Not empty
+			break
+		}
+	}
+
+	return nil
+}
+
+// PresignUpload returns a URL of FilesController the client can PUT the object to, the signature covers the content type
+// and size, so a body of another type or size is refused
+func (svc *FilesystemStorageImpl) PresignUpload(_ context.Context, objectName, contentType string, size int64, expires time.Duration) (string, error) {
+	if err := validObjectName(objectName); err != nil {
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(expires).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", svc.sign(http.MethodPut, objectName, expiresAt, contentType, size))
+	return svc.GetObjectURL(objectName) + "?" + query.Encode(), nil
+}
+
+// StatObject reports the size and modification time of the file, the content type is sniffed from its first bytes
+func (svc *FilesystemStorageImpl) StatObject(_ context.Context, objectName string) (models.BucketObject, error) {
+	if validObjectName(objectName) != nil {
+		return models.BucketObject{}, svcErr.NotFoundError{Entity: "Upload", Field: "key", Value: objectName}
+	}
+
+	file, err := svc.root.Open(objectName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return models.BucketObject{}, svcErr.NotFoundError{Entity: "Upload", Field: "key", Value: objectName}
+		}
+		return models.BucketObject{}, fmt.Errorf("failed to stat object '%s': %w", objectName, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return models.BucketObject{}, fmt.Errorf("failed to stat object '%s': %w", objectName, err)
+	}
+	if info.IsDir() {
+		return models.BucketObject{}, svcErr.NotFoundError{Entity: "Upload", Field: "key", Value: objectName}
+	}
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return models.BucketObject{}, fmt.Errorf("failed to read object '%s': %w", objectName, err)
+	}
+
+	return models.BucketObject{Key: objectName, Size: info.Size(), ContentType: http.DetectContentType(head[:n]), LastModified: info.ModTime()}, nil
+}
+
+func (svc *FilesystemStorageImpl) GetObject(_ context.Context, objectName string) (io.ReadCloser, error) {
+	if err := validObjectName(objectName); err != nil {
+		return nil, err
+	}
+
+	file, err := svc.root.Open(objectName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, svcErr.NotFoundError{Entity: "Object", Field: "key", Value: objectName}
+		}
+		return nil, fmt.Errorf("failed to get object '%s': %w", objectName, err)
+	}
+
+	return file, nil
+}
+
+// ListObjects returns every stored file, files still being uploaded are skipped
+func (svc *FilesystemStorageImpl) ListObjects(_ context.Context) ([]models.BucketObject, error) {
+	var objects []models.BucketObject
+	err := fs.WalkDir(svc.root.FS(), ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name == tempDir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, models.BucketObject{Key: name, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	return objects, nil
+}
+
+// VerifyDownload checks the signature of a MediaURL, any URL works when files are public
+func (svc *FilesystemStorageImpl) VerifyDownload(objectName string, query url.Values) error {
+	if !svc.private {
+		return nil
+	}
+	return svc.verify(http.MethodGet, objectName, "", 0, query)
+}
+
+// VerifyUpload checks the signature of a PresignUpload URL against the content type and size of the request
+func (svc *FilesystemStorageImpl) VerifyUpload(objectName, contentType string, size int64, query url.Values) error {
+	return svc.verify(http.MethodPut, objectName, contentType, size, query)
+}
+
+func (svc *FilesystemStorageImpl) verify(method, objectName, contentType string, size int64, query url.Values) error {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return svcErr.ForbiddenError{Message: "missing or invalid signature"}
+	}
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(svc.sign(method, objectName, expires, contentType, size))) {
+		return svcErr.ForbiddenError{Message: "missing or invalid signature"}
+	}
+	if time.Now().Unix() > expires {
+		return svcErr.ForbiddenError{Message: "link has expired"}
+	}
+
+	return nil
+}
+
+func (svc *FilesystemStorageImpl) sign(method, objectName string, expires int64, contentType string, size int64) string {
+	mac := hmac.New(sha256.New, svc.secret)
+	_, _ = fmt.Fprintf(mac, "%s\n%s\n%d\n%s\n%d", method, objectName, expires, contentType, size)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validObjectName only lets through keys the services build, e.g. "avatars/<id>/<id>/original": relative, clean and
+// outside the directory of unfinished uploads. os.Root keeps anything else inside the directory anyway
+func validObjectName(objectName string) error {
+	if objectName == "" || path.IsAbs(objectName) || path.Clean(objectName) != objectName ||
+		objectName == "." || objectName == ".." || strings.HasPrefix(objectName, "../") ||
+		objectName == tempDir || strings.HasPrefix(objectName, tempDir+"/") {
+		return svcErr.ValidationError{Message: fmt.Sprintf("invalid object key '%s'", objectName)}
+	}
+	return nil
+}
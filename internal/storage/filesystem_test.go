@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"wishlist/internal/config"
+	"wishlist/internal/services/errors"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newTestFilesystemStorage(t *testing.T, private bool) (*FilesystemStorageImpl, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	viper.Set(config.StorageFilesystemRoot, dir)
+	viper.Set(config.StorageFilesystemPublicURL, "https://wishlist.example/")
+	viper.Set(config.StorageFilesystemPrivate, private)
+	viper.Set(config.StorageFilesystemURLTTL, "1h")
+	viper.Set(config.StorageFilesystemSigningSecret, "secret")
+
+	svc, err := NewFilesystemStorage()
+	if err != nil {
+		t.Fatalf("NewFilesystemStorage() error = %v", err)
+	}
+	t.Cleanup(func() { _ = svc.root.Close() })
+	return svc, dir
+}
+
+func signedQuery(t *testing.T, signedURL string) url.Values {
+	t.Helper()
+
+	parsed, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", signedURL, err)
+	}
+	return parsed.Query()
+}
+
+func TestFilesystemStorage_Objects(t *testing.T) {
+	ctx := context.Background()
+	svc, dir := newTestFilesystemStorage(t, false)
+	name := "avatars/u/1/original"
+
+	if err := svc.UploadObject(ctx, name, bytes.NewReader(pngHeader), int64(len(pngHeader)), "image/png"); err != nil {
+		t.Fatalf("UploadObject() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "avatars", "u", "1", "original")); err != nil {
+		t.Fatalf("file was not written: %v", err)
+	}
+
+	info, err := svc.StatObject(ctx, name)
+	if err != nil {
+		t.Fatalf("StatObject() error = %v", err)
+	}
+	if info.Size != int64(len(pngHeader)) || info.ContentType != "image/png" {
+		t.Fatalf("StatObject() = %+v, want %d bytes of image/png", info, len(pngHeader))
+	}
+
+	object, err := svc.GetObject(ctx, name)
+	if err != nil {
+		t.Fatalf("GetObject() error = %v", err)
+	}
+	data, _ := io.ReadAll(object)
+	_ = object.Close()
+	if !bytes.Equal(data, pngHeader) {
+		t.Fatalf("GetObject() = %q", data)
+	}
+
+	// Half-written uploads are not objects yet
+	if err = os.MkdirAll(filepath.Join(dir, tempDir), 0o750); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, tempDir, "partial"), []byte("x"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	objects, err := svc.ListObjects(ctx)
+	if err != nil {
+		t.Fatalf("ListObjects() error = %v", err)
+	}
+	if len(objects) != 1 || objects[0].Key != name {
+		t.Fatalf("ListObjects() = %+v, want only %s", objects, name)
+	}
+
+	copyName := "wishes/w/2/original"
+	if err = svc.CopyObject(ctx, name, copyName); err != nil {
+		t.Fatalf("CopyObject() error = %v", err)
+	}
+	if copied, err := os.ReadFile(filepath.Join(dir, "wishes", "w", "2", "original")); err != nil || !bytes.Equal(copied, pngHeader) {
+		t.Fatalf("copy = %q, %v", copied, err)
+	}
+	if err = svc.CopyObject(ctx, "wishes/missing/original", copyName); !isNotFound(err) {
+		t.Fatalf("CopyObject(missing) error = %v, want NotFoundError", err)
+	}
+	if err = svc.DeleteObject(ctx, copyName); err != nil {
+		t.Fatalf("DeleteObject(copy) error = %v", err)
+	}
+
+	if got := svc.GetObjectURL(name); got != "https://wishlist.example/files/"+name || svc.MediaURL(name) != got {
+		t.Fatalf("GetObjectURL() = %s, MediaURL() = %s", got, svc.MediaURL(name))
+	}
+
+	if err = svc.DeleteObject(ctx, name); err != nil {
+		t.Fatalf("DeleteObject() error = %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "avatars")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("empty directories were left behind: %v", err)
+	}
+	if err = svc.DeleteObject(ctx, name); err != nil {
+		t.Fatalf("DeleteObject(missing) error = %v", err)
+	}
+	if _, err = svc.StatObject(ctx, name); !isNotFound(err) {
+		t.Fatalf("StatObject(deleted) error = %v, want NotFoundError", err)
+	}
+}
+
+func TestFilesystemStorage_RejectsKeysOutsideRoot(t *testing.T) {
+	ctx := context.Background()
+	svc, dir := newTestFilesystemStorage(t, false)
+
+	for _, name := range []string{"../escape", "/etc/passwd", "avatars/../../escape", "", ".", tempDir + "/x"} {
+		if err := svc.UploadObject(ctx, name, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Fatalf("UploadObject(%q) error = nil", name)
+		}
+		if _, err := svc.StatObject(ctx, name); !isNotFound(err) {
+			t.Fatalf("StatObject(%q) error = %v, want NotFoundError", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatal("a file was written outside the storage directory")
+	}
+}
+
+func TestFilesystemStorage_SignedURLs(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestFilesystemStorage(t, true)
+	name := "wishes/l/w/1/original"
+
+	query := signedQuery(t, svc.MediaURL(name))
+	if err := svc.VerifyDownload(name, query); err != nil {
+		t.Fatalf("VerifyDownload(MediaURL) error = %v", err)
+	}
+	if err := svc.VerifyDownload("wishes/l/w/2/original", query); !isForbidden(err) {
+		t.Fatalf("VerifyDownload(other key) error = %v, want ForbiddenError", err)
+	}
+	if err := svc.VerifyDownload(name, url.Values{}); !isForbidden(err) {
+		t.Fatalf("VerifyDownload(unsigned) error = %v, want ForbiddenError", err)
+	}
+
+	expired := time.Now().Add(-time.Minute).Unix()
+	old := url.Values{"expires": {strconv.FormatInt(expired, 10)}, "signature": {svc.sign("GET", name, expired, "", 0)}}
+	if err := svc.VerifyDownload(name, old); !isForbidden(err) {
+		t.Fatalf("VerifyDownload(expired) error = %v, want ForbiddenError", err)
+	}
+
+	uploadURL, err := svc.PresignUpload(ctx, "uploads/u/1", "image/png", 100, time.Minute)
+	if err != nil {
+		t.Fatalf("PresignUpload() error = %v", err)
+	}
+	query = signedQuery(t, uploadURL)
+	if err = svc.VerifyUpload("uploads/u/1", "image/png", 100, query); err != nil {
+		t.Fatalf("VerifyUpload() error = %v", err)
+	}
+	if err = svc.VerifyUpload("uploads/u/1", "image/png", 5000, query); !isForbidden(err) {
+		t.Fatalf("VerifyUpload(other size) error = %v, want ForbiddenError", err)
+	}
+	if err = svc.VerifyUpload("uploads/u/1", "text/html", 100, query); !isForbidden(err) {
+		t.Fatalf("VerifyUpload(other type) error = %v, want ForbiddenError", err)
+	}
+}
+
+func isNotFound(err error) bool {
+	_, ok := errors.AsType[svcErr.NotFoundError](err)
+	return ok
+}
+
+func isForbidden(err error) bool {
+	_, ok := errors.AsType[svcErr.ForbiddenError](err)
+	return ok
+}
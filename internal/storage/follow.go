@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type FollowStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewFollowStorage(pool *pgxpool.Pool) *FollowStorageImpl {
+	return &FollowStorageImpl{pool: pool}
+}
+
+func (s *FollowStorageImpl) CreateFollow(ctx context.Context, follow models.Follow) error {
+	if _, err := s.pool.Exec(ctx,
+		`INSERT INTO follows (follower_id, followee_id, accepted_at, created_at) VALUES ($1, $2, $3, $4)`,
+		follow.FollowerID, follow.FolloweeID, follow.AcceptedAt, follow.CreatedAt,
+	); err != nil {
+		if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok {
+			switch pgErr.Code {
+			case "23505":
+				return svcErr.ConflictError{Message: "you already follow this user or asked to"}
+			case "23503":
+				return svcErr.NotFoundError{Entity: "user", Field: "id", Value: follow.FolloweeID.String()}
+			}
+		}
+		return fmt.Errorf("failed to follow user with ID '%s': %w", follow.FolloweeID, err)
+	}
+
+	return nil
+}
+
+func (s *FollowStorageImpl) GetFollow(ctx context.Context, followerID, followeeID uuid.UUID) (models.Follow, error) {
+	var follow models.Follow
+
+	if err := s.pool.QueryRow(ctx, `SELECT follower_id, followee_id, accepted_at, created_at FROM follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID).Scan(
+		&follow.FollowerID, &follow.FolloweeID, &follow.AcceptedAt, &follow.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Follow{}, svcErr.NotFoundError{Entity: "follow", Field: "user_id", Value: followeeID.String()}
+		}
+		return models.Follow{}, fmt.Errorf("failed to get follow of user '%s' by '%s': %w", followeeID, followerID, err)
+	}
+
+	return follow, nil
+}
+
+// GetFollowers returns followers of the user together with the requests waiting for an answer
+func (s *FollowStorageImpl) GetFollowers(ctx context.Context, userID uuid.UUID) ([]models.Follow, error) {
+	rows, err := s.pool.Query(ctx, `SELECT follower_id, followee_id, accepted_at, created_at FROM follows WHERE followee_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followers of user with ID '%s': %w", userID, err)
+	}
+	defer rows.Close()
+
+	var follows []models.Follow
+	for rows.Next() {
+		var follow models.Follow
+		if err = rows.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.AcceptedAt, &follow.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan follow: %w", err)
+		}
+		follows = append(follows, follow)
+	}
+
+	return follows, rows.Err()
+}
+
+// GetFollowing returns users the user follows together with the requests not accepted yet
+func (s *FollowStorageImpl) GetFollowing(ctx context.Context, userID uuid.UUID) ([]models.Follow, error) {
+	rows, err := s.pool.Query(ctx, `SELECT follower_id, followee_id, accepted_at, created_at FROM follows WHERE follower_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users followed by user with ID '%s': %w", userID, err)
+	}
+	defer rows.Close()
+
+	var follows []models.Follow
+	for rows.Next() {
+		var follow models.Follow
+		if err = rows.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.AcceptedAt, &follow.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan follow: %w", err)
+		}
+		follows = append(follows, follow)
+	}
+
+	return follows, rows.Err()
+}
+
+func (s *FollowStorageImpl) AcceptFollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, `UPDATE follows SET accepted_at = now() WHERE follower_id = $1 AND followee_id = $2 AND accepted_at IS NULL`, followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to accept follow request of user with ID '%s': %w", followerID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "follow request", Field: "user_id", Value: followerID.String()}
+	}
+
+	return nil
+}
+
+func (s *FollowStorageImpl) DeleteFollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to delete follow of user '%s' by '%s': %w", followeeID, followerID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "follow", Field: "user_id", Value: followeeID.String()}
+	}
+
+	return nil
+}
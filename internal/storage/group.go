@@ -0,0 +1,329 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type GroupStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewGroupStorage(pool *pgxpool.Pool) *GroupStorageImpl { return &GroupStorageImpl{pool: pool} }
+
+// groupQuery selects groups with their accepted member count and the time of the latest draw
+const groupQuery = `
+	SELECT g.id, g.organizer_id, g.title, g.notes, g.created_at, g.updated_at,
+	       (SELECT COUNT(*) FROM gift_group_members c WHERE c.group_id = g.id AND c.accepted_at IS NOT NULL) AS members_count,
+	       (SELECT MAX(d.created_at) FROM gift_group_draws d WHERE d.group_id = g.id) AS drawn_at
+	FROM gift_groups g`
+
+const insertGroupMemberQuery = `INSERT INTO gift_group_members (group_id, user_id, list_id, invited_by, accepted_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+
+// CreateGroup adds the group together with its organizer as the first member
+func (s *GroupStorageImpl) CreateGroup(ctx context.Context, group models.Group, organizer models.GroupMember) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, `INSERT INTO gift_groups (id, organizer_id, title, notes, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		group.ID, group.OrganizerID, group.Title, group.Notes, group.CreatedAt, group.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+	if _, err = tx.Exec(ctx, insertGroupMemberQuery,
+		organizer.GroupID, organizer.UserID, organizer.ListID, organizer.InvitedBy, organizer.AcceptedAt, organizer.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to add organizer to group: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit new group: %w", err)
+	}
+
+	return nil
+}
+
+func (s *GroupStorageImpl) GetGroupByID(ctx context.Context, id uuid.UUID) (models.Group, error) {
+	var group models.Group
+
+	if err := s.pool.QueryRow(ctx, groupQuery+` WHERE g.id = $1`, id).Scan(
+		&group.ID, &group.OrganizerID, &group.Title, &group.Notes, &group.CreatedAt, &group.UpdatedAt, &group.MembersCount, &group.DrawnAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Group{}, svcErr.NotFoundError{Entity: "group", Field: "id", Value: id.String()}
+		}
+		return models.Group{}, fmt.Errorf("failed to get group with ID '%s': %w", id, err)
+	}
+
+	return group, nil
+}
+
+// GetGroupsByUserID returns the groups the user joined, accepted is false for the ones they are only invited to
+func (s *GroupStorageImpl) GetGroupsByUserID(ctx context.Context, userID uuid.UUID, accepted bool) ([]models.Group, error) {
+	rows, err := s.pool.Query(ctx, groupQuery+`
+		JOIN gift_group_members m ON m.group_id = g.id AND m.user_id = $1
+		WHERE (m.accepted_at IS NOT NULL) = $2
+		ORDER BY g.created_at DESC`, userID, accepted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups of user with ID '%s': %w", userID, err)
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		var group models.Group
+		if err = rows.Scan(&group.ID, &group.OrganizerID, &group.Title, &group.Notes, &group.CreatedAt, &group.UpdatedAt, &group.MembersCount, &group.DrawnAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
+func (s *GroupStorageImpl) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, `DELETE FROM gift_groups WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete group with ID '%s': %w", id, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "group", Field: "id", Value: id.String()}
+	}
+
+	return nil
+}
+
+func (s *GroupStorageImpl) AddGroupMember(ctx context.Context, member models.GroupMember) error {
+	if _, err := s.pool.Exec(ctx, insertGroupMemberQuery,
+		member.GroupID, member.UserID, member.ListID, member.InvitedBy, member.AcceptedAt, member.CreatedAt,
+	); err != nil {
+		if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok {
+			switch pgErr.Code {
+			case "23505":
+				return svcErr.ConflictError{Message: "user is already a member of this group"}
+			case "23503":
+				return svcErr.NotFoundError{Entity: "user", Field: "id", Value: member.UserID.String()}
+			}
+		}
+		return fmt.Errorf("failed to add member to group with ID '%s': %w", member.GroupID, err)
+	}
+
+	return nil
+}
+
+func (s *GroupStorageImpl) GetGroupMember(ctx context.Context, groupID, userID uuid.UUID) (models.GroupMember, error) {
+	var member models.GroupMember
+
+	if err := s.pool.QueryRow(ctx, `
+		SELECT m.group_id, m.user_id, u.username, u.name, u.email, m.list_id, m.invited_by, m.accepted_at, m.created_at
+		FROM gift_group_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.group_id = $1 AND m.user_id = $2`, groupID, userID).Scan(
+		&member.GroupID, &member.UserID, &member.Username, &member.Name, &member.Email, &member.ListID, &member.InvitedBy, &member.AcceptedAt, &member.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.GroupMember{}, svcErr.NotFoundError{Entity: "member", Field: "user_id", Value: userID.String()}
+		}
+		return models.GroupMember{}, fmt.Errorf("failed to get member '%s' of group with ID '%s': %w", userID, groupID, err)
+	}
+
+	return member, nil
+}
+
+func (s *GroupStorageImpl) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]models.GroupMember, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT m.group_id, m.user_id, u.username, u.name, u.email, m.list_id, m.invited_by, m.accepted_at, m.created_at
+		FROM gift_group_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.group_id = $1
+		ORDER BY m.created_at ASC`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members of group with ID '%s': %w", groupID, err)
+	}
+	defer rows.Close()
+
+	var members []models.GroupMember
+	for rows.Next() {
+		var member models.GroupMember
+		if err = rows.Scan(&member.GroupID, &member.UserID, &member.Username, &member.Name, &member.Email, &member.ListID, &member.InvitedBy, &member.AcceptedAt, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+func (s *GroupStorageImpl) AcceptGroupMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, `UPDATE gift_group_members SET accepted_at = now() WHERE group_id = $1 AND user_id = $2 AND accepted_at IS NULL`, groupID, userID); err != nil {
+		return fmt.Errorf("failed to accept invitation to group with ID '%s': %w", groupID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "invitation", Field: "group_id", Value: groupID.String()}
+	}
+
+	return nil
+}
+
+func (s *GroupStorageImpl) SetGroupMemberList(ctx context.Context, groupID, userID uuid.UUID, listID *uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, `UPDATE gift_group_members SET list_id = $1 WHERE group_id = $2 AND user_id = $3`, listID, groupID, userID); err != nil {
+		return fmt.Errorf("failed to set list of member '%s' of group with ID '%s': %w", userID, groupID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "member", Field: "user_id", Value: userID.String()}
+	}
+
+	return nil
+}
+
+// DeleteGroupMember removes the member and marks the latest draw stale when they took part in it
+func (s *GroupStorageImpl) DeleteGroupMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if result, err := tx.Exec(ctx, `DELETE FROM gift_group_members WHERE group_id = $1 AND user_id = $2`, groupID, userID); err != nil {
+		return fmt.Errorf("failed to remove member '%s' from group with ID '%s': %w", userID, groupID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "member", Field: "user_id", Value: userID.String()}
+	}
+
+	if _, err = tx.Exec(ctx, `
+		UPDATE gift_group_draws d SET stale_at = now()
+		WHERE d.id = (SELECT id FROM gift_group_draws WHERE group_id = $1 ORDER BY created_at DESC LIMIT 1)
+		  AND d.stale_at IS NULL
+		  AND EXISTS (SELECT 1 FROM gift_group_assignments a WHERE a.draw_id = d.id AND (a.giver_id = $2 OR a.receiver_id = $2))`, groupID, userID); err != nil {
+		return fmt.Errorf("failed to mark draw of group with ID '%s' stale: %w", groupID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddGroupExclusions saves the rules, the ones that already exist are left as they are
+func (s *GroupStorageImpl) AddGroupExclusions(ctx context.Context, exclusions []models.GroupExclusion) error {
+	batch := &pgx.Batch{}
+	for _, e := range exclusions {
+		batch.Queue(`INSERT INTO gift_group_exclusions (group_id, giver_id, receiver_id, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+			e.GroupID, e.GiverID, e.ReceiverID, e.CreatedAt)
+	}
+	if err := s.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to add group exclusions: %w", err)
+	}
+
+	return nil
+}
+
+func (s *GroupStorageImpl) GetGroupExclusions(ctx context.Context, groupID uuid.UUID) ([]models.GroupExclusion, error) {
+	rows, err := s.pool.Query(ctx, `SELECT group_id, giver_id, receiver_id, created_at FROM gift_group_exclusions WHERE group_id = $1 ORDER BY created_at ASC`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exclusions of group with ID '%s': %w", groupID, err)
+	}
+	defer rows.Close()
+
+	var exclusions []models.GroupExclusion
+	for rows.Next() {
+		var e models.GroupExclusion
+		if err = rows.Scan(&e.GroupID, &e.GiverID, &e.ReceiverID, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group exclusion: %w", err)
+		}
+		exclusions = append(exclusions, e)
+	}
+
+	return exclusions, rows.Err()
+}
+
+func (s *GroupStorageImpl) DeleteGroupExclusion(ctx context.Context, groupID, giverID, receiverID uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, `DELETE FROM gift_group_exclusions WHERE group_id = $1 AND giver_id = $2 AND receiver_id = $3`, groupID, giverID, receiverID); err != nil {
+		return fmt.Errorf("failed to delete exclusion of group with ID '%s': %w", groupID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "exclusion", Field: "receiver_id", Value: receiverID.String()}
+	}
+
+	return nil
+}
+
+// CreateGroupDraw saves the draw and its assignments in one transaction, earlier draws stay for the audit trail
+func (s *GroupStorageImpl) CreateGroupDraw(ctx context.Context, draw models.GroupDraw, assignments []models.GroupAssignment) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, `INSERT INTO gift_group_draws (id, group_id, drawn_by, seed, participants, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		draw.ID, draw.GroupID, draw.DrawnBy, draw.Seed, draw.Participants, draw.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create draw: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, a := range assignments {
+		batch.Queue(`INSERT INTO gift_group_assignments (draw_id, giver_id, receiver_id) VALUES ($1, $2, $3)`, a.DrawID, a.GiverID, a.ReceiverID)
+	}
+	if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to create assignments: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit draw: %w", err)
+	}
+
+	return nil
+}
+
+// GetGroupDraws returns the audit trail of the group, newest draw first
+func (s *GroupStorageImpl) GetGroupDraws(ctx context.Context, groupID uuid.UUID) ([]models.GroupDraw, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id, group_id, drawn_by, seed, participants, stale_at, created_at FROM gift_group_draws WHERE group_id = $1 ORDER BY created_at DESC`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draws of group with ID '%s': %w", groupID, err)
+	}
+	defer rows.Close()
+
+	var draws []models.GroupDraw
+	for rows.Next() {
+		var d models.GroupDraw
+		var drawnBy *uuid.UUID // Gone with a deleted account
+		if err = rows.Scan(&d.ID, &d.GroupID, &drawnBy, &d.Seed, &d.Participants, &d.StaleAt, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group draw: %w", err)
+		}
+		if drawnBy != nil {
+			d.DrawnBy = *drawnBy
+		}
+		draws = append(draws, d)
+	}
+
+	return draws, rows.Err()
+}
+
+// GetGroupAssignee returns who the giver drew in the latest draw of the group
+func (s *GroupStorageImpl) GetGroupAssignee(ctx context.Context, groupID, giverID uuid.UUID) (models.GroupAssignee, error) {
+	var assignee models.GroupAssignee
+
+	if err := s.pool.QueryRow(ctx, `
+		SELECT d.group_id, d.id, u.id, u.username, u.name, m.list_id, d.created_at, d.stale_at
+		FROM (SELECT id, group_id, created_at, stale_at FROM gift_group_draws WHERE group_id = $1 ORDER BY created_at DESC LIMIT 1) d
+		JOIN gift_group_assignments a ON a.draw_id = d.id AND a.giver_id = $2
+		JOIN users u ON u.id = a.receiver_id
+		LEFT JOIN gift_group_members m ON m.group_id = d.group_id AND m.user_id = a.receiver_id`, groupID, giverID).Scan(
+		&assignee.GroupID, &assignee.DrawID, &assignee.UserID, &assignee.Username, &assignee.Name, &assignee.ListID, &assignee.DrawnAt, &assignee.StaleAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.GroupAssignee{}, svcErr.NotFoundError{Entity: "assignment", Field: "group_id", Value: groupID.String()}
+		}
+		return models.GroupAssignee{}, fmt.Errorf("failed to get assignment in group with ID '%s': %w", groupID, err)
+	}
+
+	return assignee, nil
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type GuestReservationStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewGuestReservationStorage(pool *pgxpool.Pool) *GuestReservationStorageImpl {
+	return &GuestReservationStorageImpl{pool: pool}
+}
+
+// CreateGuestReservation holds units for a guest, checked against every other reservation of the wish like ReserveWish.
+// The list stays locked while its unconfirmed guest reservations are counted against the limits
+func (s *GuestReservationStorageImpl) CreateGuestReservation(ctx context.Context, reservation models.GuestReservation, listID uuid.UUID, limits models.GuestReservationLimits) error {
+	wishID := reservation.WishID
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = lockListWishes(ctx, tx, listID); err != nil {
+		return err
+	}
+	var pending, pendingForEmail int
+	if err = tx.QueryRow(ctx, `SELECT COUNT(*), COUNT(*) FILTER (WHERE lower(r.guest_email) = lower($2))
+		FROM wish_reservations r
+		JOIN wishes w ON w.id = r.wish_id
+		WHERE w.list_id = $1 AND r.guest_token IS NOT NULL AND r.confirmed_at IS NULL AND r.reserved_until > now()`, listID, reservation.Email).Scan(&pending, &pendingForEmail); err != nil {
+		return fmt.Errorf("failed to count unconfirmed guest reservations of list with ID '%s': %w", listID, err)
+	}
+	if limits.PerEmail > 0 && pendingForEmail >= limits.PerEmail {
+		return svcErr.ValidationError{Message: "confirm the reservations already sent to this email first"}
+	}
+	if limits.PerList > 0 && pending >= limits.PerList {
+		return svcErr.TooManyRequestsError{Message: "too many reservations on this wishlist are waiting for confirmation, try again later"}
+	}
+
+	if err = lockReservableWish(ctx, tx, wishID, reservation.Units); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `INSERT INTO wish_reservations (wish_id, guest_name, guest_email, guest_token, units, reserved_until, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		wishID, reservation.Name, reservation.Email, reservation.Token, reservation.Units, reservation.ReservedUntil, reservation.CreatedAt, reservation.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to reserve wish with ID '%s' for a guest: %w", wishID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit guest reservation of wish with ID '%s': %w", wishID, err)
+	}
+
+	return nil
+}
+
+// GetGuestReservation finds an active guest reservation by its token, expired ones and those on deleted wishes are gone
+func (s *GuestReservationStorageImpl) GetGuestReservation(ctx context.Context, token string) (models.GuestReservationDetails, error) {
+	var r models.GuestReservationDetails
+
+	if err := s.pool.QueryRow(ctx, `SELECT r.wish_id, r.guest_name, r.guest_email, r.guest_token, r.units, r.reserved_until, r.confirmed_at, r.created_at, r.updated_at, w.title, w.status, l.title, l.slug
+		FROM wish_reservations r
+		JOIN wishes w ON w.id = r.wish_id
+		JOIN lists l ON l.id = w.list_id
+		WHERE r.guest_token = $1 AND (r.reserved_until IS NULL OR r.reserved_until > now()) AND w.deleted_at IS NULL AND l.deleted_at IS NULL`, token).Scan(
+		&r.WishID, &r.Name, &r.Email, &r.Token, &r.Units, &r.ReservedUntil, &r.ConfirmedAt, &r.CreatedAt, &r.UpdatedAt, &r.WishTitle, &r.WishStatus, &r.ListTitle, &r.ListSlug,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.GuestReservationDetails{}, svcErr.NotFoundError{Entity: "reservation", Field: "token", Value: token}
+		}
+		return models.GuestReservationDetails{}, fmt.Errorf("failed to get guest reservation: %w", err)
+	}
+
+	return r, nil
+}
+
+// ConfirmGuestReservation keeps the reservation for good, the wish is then held like one reserved by a user without a deadline
+func (s *GuestReservationStorageImpl) ConfirmGuestReservation(ctx context.Context, token string) error {
+	if result, err := s.pool.Exec(ctx, `UPDATE wish_reservations SET confirmed_at = COALESCE(confirmed_at, now()), reserved_until = NULL, updated_at = now()
+		WHERE guest_token = $1 AND (reserved_until IS NULL OR reserved_until > now())`, token); err != nil {
+		return fmt.Errorf("failed to confirm guest reservation: %w", err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "reservation", Field: "token", Value: token}
+	}
+
+	return nil
+}
+
+func (s *GuestReservationStorageImpl) DeleteGuestReservation(ctx context.Context, token string) error {
+	if result, err := s.pool.Exec(ctx, `DELETE FROM wish_reservations WHERE guest_token = $1`, token); err != nil {
+		return fmt.Errorf("failed to release guest reservation: %w", err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "reservation", Field: "token", Value: token}
+	}
+
+	return nil
+}
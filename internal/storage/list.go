@@ -18,22 +18,58 @@ type ListStorageImpl struct{ pool *pgxpool.Pool }
 
 func NewListStorage(pool *pgxpool.Pool) *ListStorageImpl { return &ListStorageImpl{pool: pool} }
 
+const insertListQuery = `INSERT INTO lists (id, user_id, image, title, notes, visibility, slug, occasion_date, occasion_recurs_yearly, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
 func (s *ListStorageImpl) CreateList(ctx context.Context, list models.List) error {
-	if _, err := s.pool.Exec(ctx,
-		`INSERT INTO lists (id, user_id, image, title, notes, is_public, slug, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
-		list.ID, list.UserID, list.Image, list.Title, list.Notes, list.IsPublic, list.Slug, list.CreatedAt, list.UpdatedAt,
+	if _, err := s.pool.Exec(ctx, insertListQuery,
+		list.ID, list.UserID, list.Image, list.Title, list.Notes, list.Visibility, list.Slug, list.OccasionDate, list.OccasionRecursYearly, list.CreatedAt, list.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create list: %w", err)
+	}
+
+	return nil
+}
+
+// CreateListWithWishes adds the list and its wishes in one transaction, the wishes in the order given
+func (s *ListStorageImpl) CreateListWithWishes(ctx context.Context, list models.List, wishes []models.Wish) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, insertListQuery,
+		list.ID, list.UserID, list.Image, list.Title, list.Notes, list.Visibility, list.Slug, list.OccasionDate, list.OccasionRecursYearly, list.CreatedAt, list.UpdatedAt,
 	); err != nil {
 		return fmt.Errorf("failed to create list: %w", err)
 	}
 
+	if len(wishes) > 0 {
+		batch := &pgx.Batch{}
+		for _, wish := range wishes {
+			batch.Queue(insertWishQuery,
+				wish.ID, wish.ListID, wish.Image, wish.Title, wish.Notes, wish.Link, wish.Price, wish.Currency, wish.Quantity, wish.Status.Stored(), wish.Priority, wish.PriceAlert, wish.CreatedAt, wish.UpdatedAt,
+			)
+		}
+		if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("failed to create wishes: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit new list: %w", err)
+	}
+
 	return nil
 }
 
+// GetListByID finds a list that is not in the trash, lists of accounts scheduled for deletion are not found either
 func (s *ListStorageImpl) GetListByID(ctx context.Context, id uuid.UUID) (models.List, error) {
 	var list models.List
 
-	if err := s.pool.QueryRow(ctx, `SELECT id, user_id, image, title, notes, is_public, slug, created_at, updated_at FROM lists WHERE id = $1`, id).Scan(
-		&list.ID, &list.UserID, &list.Image, &list.Title, &list.Notes, &list.IsPublic, &list.Slug, &list.CreatedAt, &list.UpdatedAt,
+	if err := s.pool.QueryRow(ctx, `SELECT id, user_id, image, title, notes, visibility, slug, occasion_date, occasion_recurs_yearly, created_at, updated_at FROM lists l WHERE l.id = $1 AND l.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = l.user_id AND u.delete_after IS NOT NULL)`, id).Scan(
+		&list.ID, &list.UserID, &list.Image, &list.Title, &list.Notes, &list.Visibility, &list.Slug, &list.OccasionDate, &list.OccasionRecursYearly, &list.CreatedAt, &list.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.List{}, svcErr.NotFoundError{Entity: "list", Field: "id", Value: id.String()}
@@ -44,11 +80,13 @@ func (s *ListStorageImpl) GetListByID(ctx context.Context, id uuid.UUID) (models
 	return list, nil
 }
 
+// GetListBySharedLink finds a list by its link the same way GetListByID does
 func (s *ListStorageImpl) GetListBySharedLink(ctx context.Context, slug string) (models.List, error) {
 	var list models.List
 
-	if err := s.pool.QueryRow(ctx, `SELECT id, user_id, image, title, notes, is_public, slug, created_at, updated_at FROM lists WHERE slug = $1`, slug).Scan(
-		&list.ID, &list.UserID, &list.Image, &list.Title, &list.Notes, &list.IsPublic, &list.Slug, &list.CreatedAt, &list.UpdatedAt,
+	if err := s.pool.QueryRow(ctx, `SELECT id, user_id, image, title, notes, visibility, slug, occasion_date, occasion_recurs_yearly, created_at, updated_at FROM lists l WHERE l.slug = $1 AND l.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = l.user_id AND u.delete_after IS NOT NULL)`, slug).Scan(
+		&list.ID, &list.UserID, &list.Image, &list.Title, &list.Notes, &list.Visibility, &list.Slug, &list.OccasionDate, &list.OccasionRecursYearly, &list.CreatedAt, &list.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.List{}, svcErr.NotFoundError{Entity: "list", Field: "slug", Value: slug}
@@ -62,14 +100,17 @@ func (s *ListStorageImpl) GetListBySharedLink(ctx context.Context, slug string)
 // noinspection DuplicatedCode
 func (s *ListStorageImpl) GetListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT l.id, l.user_id, l.image, l.title, l.notes, l.is_public, l.slug, l.created_at, l.updated_at, COALESCE(w.wishes_count, 0) AS wishes_count
+		SELECT l.id, l.user_id, l.image, l.title, l.notes, l.visibility, l.slug, l.occasion_date, l.occasion_recurs_yearly, l.created_at, l.updated_at, COALESCE(w.wishes_count, 0) AS wishes_count,
+		       CASE WHEN l.user_id = $1 THEN 'owner' ELSE m.role END AS role
 		FROM lists l
+		LEFT JOIN list_members m ON m.list_id = l.id AND m.user_id = $1 AND m.accepted_at IS NOT NULL
 		LEFT JOIN (
 			SELECT list_id, COUNT(*) AS wishes_count
 			FROM wishes
+			WHERE status <> 'archived' AND deleted_at IS NULL
 			GROUP BY list_id
 		) w ON w.list_id = l.id
-		WHERE l.user_id = $1
+		WHERE (l.user_id = $1 OR m.user_id IS NOT NULL) AND l.deleted_at IS NULL
 		ORDER BY l.created_at DESC
 	`, userID)
 	if err != nil {
@@ -80,7 +121,7 @@ func (s *ListStorageImpl) GetListsByUserID(ctx context.Context, userID uuid.UUID
 	var lists []models.List
 	for rows.Next() {
 		var list models.List
-		if err = rows.Scan(&list.ID, &list.UserID, &list.Image, &list.Title, &list.Notes, &list.IsPublic, &list.Slug, &list.CreatedAt, &list.UpdatedAt, &list.WishesCount); err != nil {
+		if err = rows.Scan(&list.ID, &list.UserID, &list.Image, &list.Title, &list.Notes, &list.Visibility, &list.Slug, &list.OccasionDate, &list.OccasionRecursYearly, &list.CreatedAt, &list.UpdatedAt, &list.WishesCount, &list.Role); err != nil {
 			return nil, fmt.Errorf("failed to scan list: %w", err)
 		}
 		lists = append(lists, list)
@@ -89,19 +130,22 @@ func (s *ListStorageImpl) GetListsByUserID(ctx context.Context, userID uuid.UUID
 	return lists, rows.Err()
 }
 
+// GetPublicListsByUserID returns the lists shown on the user's profile, withFollowers adds the followers-only ones
 // noinspection DuplicatedCode
-func (s *ListStorageImpl) GetPublicListsByUserID(ctx context.Context, userID uuid.UUID) ([]models.List, error) {
+func (s *ListStorageImpl) GetPublicListsByUserID(ctx context.Context, userID uuid.UUID, withFollowers bool) ([]models.List, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT l.id, l.user_id, l.image, l.title, l.notes, l.is_public, l.slug, l.created_at, l.updated_at, COALESCE(w.wishes_count, 0) AS wishes_count
+		SELECT l.id, l.user_id, l.image, l.title, l.notes, l.visibility, l.slug, l.occasion_date, l.occasion_recurs_yearly, l.created_at, l.updated_at, COALESCE(w.wishes_count, 0) AS wishes_count
 		FROM lists l
 		LEFT JOIN (
 			SELECT list_id, COUNT(*) AS wishes_count
 			FROM wishes
+			WHERE status <> 'archived' AND deleted_at IS NULL
 			GROUP BY list_id
 		) w ON w.list_id = l.id
-		WHERE l.user_id = $1 AND l.is_public = true
+		WHERE l.user_id = $1 AND (l.visibility = 'public' OR ($2 AND l.visibility = 'followers')) AND l.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = l.user_id AND u.delete_after IS NOT NULL)
 		ORDER BY l.created_at DESC
-	`, userID)
+	`, userID, withFollowers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get public lists for user with ID '%s': %w", userID, err)
 	}
@@ -110,7 +154,7 @@ func (s *ListStorageImpl) GetPublicListsByUserID(ctx context.Context, userID uui
 	var lists []models.List
 	for rows.Next() {
 		var list models.List
-		if err = rows.Scan(&list.ID, &list.UserID, &list.Image, &list.Title, &list.Notes, &list.IsPublic, &list.Slug, &list.CreatedAt, &list.UpdatedAt, &list.WishesCount); err != nil {
+		if err = rows.Scan(&list.ID, &list.UserID, &list.Image, &list.Title, &list.Notes, &list.Visibility, &list.Slug, &list.OccasionDate, &list.OccasionRecursYearly, &list.CreatedAt, &list.UpdatedAt, &list.WishesCount); err != nil {
 			return nil, fmt.Errorf("failed to scan list: %w", err)
 		}
 		lists = append(lists, list)
@@ -140,19 +184,36 @@ func (s *ListStorageImpl) UpdateListByID(ctx context.Context, listID uuid.UUID,
 		args = append(args, *req.Notes)
 		index++
 	}
-	if req.IsPublic != nil {
-		clauses = append(clauses, fmt.Sprintf("is_public = $%d", index))
-		args = append(args, *req.IsPublic)
+	if req.Visibility != nil {
+		clauses = append(clauses, fmt.Sprintf("visibility = $%d", index))
+		args = append(args, *req.Visibility)
+		index++
+	}
+	if req.OccasionDate != nil {
+		clauses = append(clauses, fmt.Sprintf("occasion_date = $%d", index))
+		var date *string // An empty string removes the occasion
+		if *req.OccasionDate != "" {
+			date = req.OccasionDate
+		}
+		args = append(args, date)
+		index++
+	}
+	if req.OccasionRecursYearly != nil {
+		clauses = append(clauses, fmt.Sprintf("occasion_recurs_yearly = $%d", index))
+		args = append(args, *req.OccasionRecursYearly)
 		index++
 	}
 	if len(args) == 0 {
 		return nil
 	}
 
+	if req.OccasionDate != nil || req.OccasionRecursYearly != nil {
+		clauses = append(clauses, "occasion_reminded_for = NULL") // A moved occasion is reminded again
+	}
 	clauses = append(clauses, "updated_at = now()")
 	args = append(args, listID)
 
-	if result, err := s.pool.Exec(ctx, fmt.Sprintf("UPDATE lists SET"+" %s WHERE id = $%d", strings.Join(clauses, ", "), index), args...); err != nil {
+	if result, err := s.pool.Exec(ctx, fmt.Sprintf("UPDATE lists SET"+" %s WHERE id = $%d AND deleted_at IS NULL", strings.Join(clauses, ", "), index), args...); err != nil {
 		return fmt.Errorf("failed to update list with ID '%s': %w", listID, err)
 	} else if result.RowsAffected() == 0 {
 		return svcErr.NotFoundError{Entity: "list", Field: "id", Value: listID.String()}
@@ -162,7 +223,7 @@ func (s *ListStorageImpl) UpdateListByID(ctx context.Context, listID uuid.UUID,
 }
 
 func (s *ListStorageImpl) RotateSharedLink(ctx context.Context, listID uuid.UUID, slug string) error {
-	if result, err := s.pool.Exec(ctx, "UPDATE lists SET slug = $1, updated_at = now() WHERE id = $2", slug, listID); err != nil {
+	if result, err := s.pool.Exec(ctx, "UPDATE lists SET slug = $1, updated_at = now() WHERE id = $2 AND deleted_at IS NULL", slug, listID); err != nil {
 		return fmt.Errorf("failed to update slug for list with ID '%s': %w", listID, err)
 	} else if result.RowsAffected() == 0 {
 		return svcErr.NotFoundError{Entity: "list", Field: "id", Value: listID.String()}
@@ -171,8 +232,9 @@ func (s *ListStorageImpl) RotateSharedLink(ctx context.Context, listID uuid.UUID
 	return nil
 }
 
+// DeleteListByID moves the list to the trash, its wishes go with it and come back when it is restored
 func (s *ListStorageImpl) DeleteListByID(ctx context.Context, listID uuid.UUID) error {
-	if result, err := s.pool.Exec(ctx, "DELETE FROM lists WHERE id = $1", listID); err != nil {
+	if result, err := s.pool.Exec(ctx, "UPDATE lists SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL", listID); err != nil {
 		return fmt.Errorf("failed to delete list with ID '%s': %w", listID, err)
 	} else if result.RowsAffected() == 0 {
 		return svcErr.NotFoundError{Entity: "list", Field: "id", Value: listID.String()}
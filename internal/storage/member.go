@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type ListMemberStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewListMemberStorage(pool *pgxpool.Pool) *ListMemberStorageImpl {
+	return &ListMemberStorageImpl{pool: pool}
+}
+
+func (s *ListMemberStorageImpl) AddListMember(ctx context.Context, member models.ListMember) error {
+	if _, err := s.pool.Exec(ctx,
+		`INSERT INTO list_members (list_id, user_id, role, invited_by, accepted_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		member.ListID, member.UserID, member.Role, member.InvitedBy, member.AcceptedAt, member.CreatedAt, member.UpdatedAt,
+	); err != nil {
+		if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok {
+			switch pgErr.Code {
+			case "23505":
+				return svcErr.ConflictError{Message: "user is already a member of this wishlist"}
+			case "23503":
+				return svcErr.NotFoundError{Entity: "user", Field: "id", Value: member.UserID.String()}
+			}
+		}
+		return fmt.Errorf("failed to add member to list with ID '%s': %w", member.ListID, err)
+	}
+
+	return nil
+}
+
+func (s *ListMemberStorageImpl) GetListMember(ctx context.Context, listID, userID uuid.UUID) (models.ListMember, error) {
+	var member models.ListMember
+
+	if err := s.pool.QueryRow(ctx, `SELECT list_id, user_id, role, invited_by, accepted_at, created_at, updated_at FROM list_members WHERE list_id = $1 AND user_id = $2`, listID, userID).Scan(
+		&member.ListID, &member.UserID, &member.Role, &member.InvitedBy, &member.AcceptedAt, &member.CreatedAt, &member.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ListMember{}, svcErr.NotFoundError{Entity: "member", Field: "user_id", Value: userID.String()}
+		}
+		return models.ListMember{}, fmt.Errorf("failed to get member '%s' of list with ID '%s': %w", userID, listID, err)
+	}
+
+	return member, nil
+}
+
+func (s *ListMemberStorageImpl) GetListMembers(ctx context.Context, listID uuid.UUID) ([]models.ListMember, error) {
+	//noinspection SqlRedundantOrderingDirection
+	rows, err := s.pool.Query(ctx, `SELECT list_id, user_id, role, invited_by, accepted_at, created_at, updated_at FROM list_members WHERE list_id = $1 ORDER BY created_at ASC`, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members of list with ID '%s': %w", listID, err)
+	}
+	defer rows.Close()
+
+	var members []models.ListMember
+	for rows.Next() {
+		var member models.ListMember
+		if err = rows.Scan(&member.ListID, &member.UserID, &member.Role, &member.InvitedBy, &member.AcceptedAt, &member.CreatedAt, &member.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan list member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+func (s *ListMemberStorageImpl) GetPendingInvitations(ctx context.Context, userID uuid.UUID) ([]models.ListMember, error) {
+	rows, err := s.pool.Query(ctx, `SELECT list_id, user_id, role, invited_by, accepted_at, created_at, updated_at FROM list_members WHERE user_id = $1 AND accepted_at IS NULL ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending invitations for user with ID '%s': %w", userID, err)
+	}
+	defer rows.Close()
+
+	var members []models.ListMember
+	for rows.Next() {
+		var member models.ListMember
+		if err = rows.Scan(&member.ListID, &member.UserID, &member.Role, &member.InvitedBy, &member.AcceptedAt, &member.CreatedAt, &member.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan list member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+func (s *ListMemberStorageImpl) AcceptListMember(ctx context.Context, listID, userID uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, `UPDATE list_members SET accepted_at = now(), updated_at = now() WHERE list_id = $1 AND user_id = $2 AND accepted_at IS NULL`, listID, userID); err != nil {
+		return fmt.Errorf("failed to accept invitation to list with ID '%s': %w", listID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "invitation", Field: "list_id", Value: listID.String()}
+	}
+
+	return nil
+}
+
+func (s *ListMemberStorageImpl) DeleteListMember(ctx context.Context, listID, userID uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, `DELETE FROM list_members WHERE list_id = $1 AND user_id = $2`, listID, userID); err != nil {
+		return fmt.Errorf("failed to remove member '%s' from list with ID '%s': %w", userID, listID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "member", Field: "user_id", Value: userID.String()}
+	}
+
+	return nil
+}
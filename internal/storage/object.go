@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+)
+
+type ObjectStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewObjectStorage(pool *pgxpool.Pool) *ObjectStorageImpl {
+	return &ObjectStorageImpl{pool: pool}
+}
+
+// RecordObject saves who owns the object, recording it again moves it to the new owner
+func (s *ObjectStorageImpl) RecordObject(ctx context.Context, object models.StoredObject) error {
+	if _, err := s.pool.Exec(ctx, `INSERT INTO storage_objects (object_name, url, user_id, list_id, wish_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (object_name) DO UPDATE SET url = EXCLUDED.url, user_id = EXCLUDED.user_id, list_id = EXCLUDED.list_id, wish_id = EXCLUDED.wish_id`,
+		object.ObjectName, object.URL, object.Owner.UserID, object.Owner.ListID, object.Owner.WishID, object.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to record object '%s': %w", object.ObjectName, err)
+	}
+
+	return nil
+}
+
+// ReleaseObjects forgets the objects of the owner that no avatar or wish image uses anymore and returns them,
+// so the caller can remove them from S3
+func (s *ObjectStorageImpl) ReleaseObjects(ctx context.Context, owner models.ObjectOwner) ([]models.StoredObject, error) {
+	rows, err := s.pool.Query(ctx, `DELETE FROM storage_objects o
+		WHERE (o.user_id = $1 OR o.list_id = $2 OR o.wish_id = $3)
+			AND NOT EXISTS (SELECT 1 FROM users u WHERE u.avatar = o.url)
+			AND NOT EXISTS (SELECT 1 FROM wishes w WHERE w.image = o.url)
+		RETURNING o.object_name, o.url, o.user_id, o.list_id, o.wish_id, o.created_at`,
+		owner.UserID, owner.ListID, owner.WishID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release objects: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []models.StoredObject
+	for rows.Next() {
+		var object models.StoredObject
+		if err = rows.Scan(&object.ObjectName, &object.URL, &object.Owner.UserID, &object.Owner.ListID, &object.Owner.WishID, &object.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan object: %w", err)
+		}
+		objects = append(objects, object)
+	}
+
+	return objects, rows.Err()
+}
+
+// GetReferencedURLs returns every avatar and wish image saved in the database, object keys and older URLs alike
+func (s *ObjectStorageImpl) GetReferencedURLs(ctx context.Context) ([]string, error) {
+	rows, err := s.pool.Query(ctx, `SELECT avatar FROM users WHERE avatar IS NOT NULL
+		UNION SELECT image FROM wishes WHERE image IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referenced objects: %w", err)
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err = rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan object URL: %w", err)
+		}
+		urls = append(urls, url)
+	}
+
+	return urls, rows.Err()
+}
+
+// DeleteObjectRecords forgets objects removed from S3 by the sweep
+func (s *ObjectStorageImpl) DeleteObjectRecords(ctx context.Context, objectNames []string) error {
+	if len(objectNames) == 0 {
+		return nil
+	}
+
+	if _, err := s.pool.Exec(ctx, `DELETE FROM storage_objects WHERE object_name = ANY($1)`, objectNames); err != nil {
+		return fmt.Errorf("failed to delete object records: %w", err)
+	}
+
+	return nil
+}
+
+const storedURLsMigration = "stored_urls_to_keys"
+
+// ConvertStoredURLs rewrites avatars, wish images and object records that still hold a URL of the bucket into what toKey
+// makes of it. It runs at startup as a data migration that is recorded in data_migrations, so only the first start does
+// the work. Rows that already hold keys or links to other sites are left alone
+func (s *ObjectStorageImpl) ConvertStoredURLs(ctx context.Context, baseURL string, toKey func(string) string) (int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Another instance starting at the same time waits here until the first one commits
+	tag, err := tx.Exec(ctx, `INSERT INTO data_migrations (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`, storedURLsMigration)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record data migration '%s': %w", storedURLsMigration, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, nil // Ran already
+	}
+
+	var converted int64
+	for _, target := range []struct{ table, id, column string }{
+		{"users", "id", "avatar"},
+		{"wishes", "id", "image"},
+		{"storage_objects", "object_name", "url"},
+	} {
+		rows, err := tx.Query(ctx, fmt.Sprintf(`SELECT %s::text, %s FROM %s WHERE starts_with(%s, $1) FOR UPDATE`, target.id, target.column, target.table, target.column), baseURL+"/")
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s.%s: %w", target.table, target.column, err)
+		}
+		keys := map[string]string{}
+		for rows.Next() {
+			var id, url string
+			if err = rows.Scan(&id, &url); err != nil {
+				rows.Close()
+				return 0, fmt.Errorf("failed to scan %s.%s: %w", target.table, target.column, err)
+			}
+			keys[id] = toKey(url)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return 0, fmt.Errorf("failed to read %s.%s: %w", target.table, target.column, err)
+		}
+
+		for id, key := range keys {
+			if _, err = tx.Exec(ctx, fmt.Sprintf(`UPDATE %s SET %s = $2 WHERE %s::text = $1`, target.table, target.column, target.id), id, key); err != nil {
+				return 0, fmt.Errorf("failed to convert %s.%s of '%s': %w", target.table, target.column, id, err)
+			}
+		}
+		converted += int64(len(keys))
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return converted, nil
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+)
+
+type OccasionStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewOccasionStorage(pool *pgxpool.Pool) *OccasionStorageImpl {
+	return &OccasionStorageImpl{pool: pool}
+}
+
+// GetOccasionLists returns the lists with an occasion still ahead that the viewer can read: their own and shared ones,
+// and lists of the users they follow unless those are private or link-only
+// noinspection DuplicatedCode
+func (s *OccasionStorageImpl) GetOccasionLists(ctx context.Context, viewerID uuid.UUID, from time.Time) ([]models.List, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT l.id, l.user_id, l.image, l.title, l.notes, l.visibility, l.slug, l.occasion_date, l.occasion_recurs_yearly, l.created_at, l.updated_at, COALESCE(w.wishes_count, 0) AS wishes_count,
+		       CASE WHEN l.user_id = $1 THEN 'owner' ELSE COALESCE(m.role, '') END AS role
+		FROM lists l
+		LEFT JOIN list_members m ON m.list_id = l.id AND m.user_id = $1 AND m.accepted_at IS NOT NULL
+		LEFT JOIN (
+			SELECT list_id, COUNT(*) AS wishes_count
+			FROM wishes
+			WHERE status <> 'archived' AND deleted_at IS NULL
+			GROUP BY list_id
+		) w ON w.list_id = l.id
+		WHERE l.occasion_date IS NOT NULL AND (l.occasion_recurs_yearly OR l.occasion_date >= $2) AND l.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM users o WHERE o.id = l.user_id AND o.delete_after IS NOT NULL)
+			AND (
+				l.user_id = $1
+				OR m.user_id IS NOT NULL
+				OR (l.visibility IN ('public', 'followers') AND EXISTS (
+					SELECT 1 FROM follows f WHERE f.follower_id = $1 AND f.followee_id = l.user_id AND f.accepted_at IS NOT NULL
+				))
+			)
+	`, viewerID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get occasions for user with ID '%s': %w", viewerID, err)
+	}
+	defer rows.Close()
+
+	var lists []models.List
+	for rows.Next() {
+		var list models.List
+		if err = rows.Scan(&list.ID, &list.UserID, &list.Image, &list.Title, &list.Notes, &list.Visibility, &list.Slug, &list.OccasionDate, &list.OccasionRecursYearly, &list.CreatedAt, &list.UpdatedAt, &list.WishesCount, &list.Role); err != nil {
+			return nil, fmt.Errorf("failed to scan list: %w", err)
+		}
+		lists = append(lists, list)
+	}
+
+	return lists, rows.Err()
+}
+
+// GetOccasions returns the occasions of all lists that may still need a reminder, one-off dates before from are left out
+func (s *OccasionStorageImpl) GetOccasions(ctx context.Context, from time.Time) ([]models.Occasion, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT l.id, l.title, l.occasion_date, l.occasion_recurs_yearly, l.occasion_reminded_for
+		FROM lists l
+		JOIN users u ON u.id = l.user_id AND u.delete_after IS NULL
+		WHERE l.occasion_date IS NOT NULL AND (l.occasion_recurs_yearly OR l.occasion_date >= $1) AND l.deleted_at IS NULL
+	`, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get occasions: %w", err)
+	}
+	defer rows.Close()
+
+	var occasions []models.Occasion
+	for rows.Next() {
+		var o models.Occasion
+		if err = rows.Scan(&o.ListID, &o.ListTitle, &o.Date, &o.RecursYearly, &o.RemindedFor); err != nil {
+			return nil, fmt.Errorf("failed to scan occasion: %w", err)
+		}
+		occasions = append(occasions, o)
+	}
+
+	return occasions, rows.Err()
+}
+
+// GetOccasionRecipients returns the owner, the members and everyone holding an active reservation of a wish of the list.
+// Users without an email or with a deactivated account are skipped, a member who also reserved is reminded as a member
+func (s *OccasionStorageImpl) GetOccasionRecipients(ctx context.Context, listID uuid.UUID) ([]models.OccasionRecipient, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT DISTINCT ON (u.id) u.id, u.email, r.is_reserver
+		FROM (
+			SELECT l.user_id, FALSE AS is_reserver FROM lists l WHERE l.id = $1
+			UNION ALL
+			SELECT m.user_id, FALSE FROM list_members m WHERE m.list_id = $1 AND m.accepted_at IS NOT NULL
+			UNION ALL
+			SELECT rv.user_id, TRUE
+			FROM wish_reservations rv
+			JOIN wishes w ON w.id = rv.wish_id AND w.deleted_at IS NULL
+			WHERE w.list_id = $1 AND (rv.reserved_until IS NULL OR rv.reserved_until > now())
+		) r
+		JOIN users u ON u.id = r.user_id
+		WHERE u.email IS NOT NULL AND u.delete_after IS NULL
+		ORDER BY u.id, r.is_reserver
+	`, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get occasion recipients of list with ID '%s': %w", listID, err)
+	}
+	defer rows.Close()
+
+	var recipients []models.OccasionRecipient
+	for rows.Next() {
+		var r models.OccasionRecipient
+		if err = rows.Scan(&r.UserID, &r.Email, &r.IsReserver); err != nil {
+			return nil, fmt.Errorf("failed to scan occasion recipient: %w", err)
+		}
+		recipients = append(recipients, r)
+	}
+
+	return recipients, rows.Err()
+}
+
+// MarkOccasionReminded remembers that the occurrence on date was reminded, so the next run skips it
+func (s *OccasionStorageImpl) MarkOccasionReminded(ctx context.Context, listID uuid.UUID, date time.Time) error {
+	if _, err := s.pool.Exec(ctx, `UPDATE lists SET occasion_reminded_for = $1 WHERE id = $2`, date, listID); err != nil {
+		return fmt.Errorf("failed to mark occasion of list with ID '%s' as reminded: %w", listID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+)
+
+type WishPriceStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewWishPriceStorage(pool *pgxpool.Pool) *WishPriceStorageImpl {
+	return &WishPriceStorageImpl{pool: pool}
+}
+
+// GetWishesToTrack returns open wishes with a link and a price that were not checked since the given time, the longest unchecked first
+func (s *WishPriceStorageImpl) GetWishesToTrack(ctx context.Context, checkedBefore time.Time, limit int) ([]models.Wish, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id, list_id, title, link, price, current_price, currency, status, price_alert_below, created_at, updated_at
+		FROM wishes
+		WHERE link IS NOT NULL AND price IS NOT NULL AND status = 'open' AND (price_checked_at IS NULL OR price_checked_at < $1)
+			AND deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM lists l WHERE l.id = wishes.list_id AND l.deleted_at IS NOT NULL)
+		ORDER BY price_checked_at ASC NULLS FIRST
+		LIMIT $2`, checkedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishes to track: %w", err)
+	}
+	defer rows.Close()
+
+	var wishes []models.Wish
+	for rows.Next() {
+		var wish models.Wish
+		if err = rows.Scan(&wish.ID, &wish.ListID, &wish.Title, &wish.Link, &wish.Price, &wish.CurrentPrice, &wish.Currency, &wish.Status, &wish.PriceAlert, &wish.CreatedAt, &wish.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan wish: %w", err)
+		}
+		wishes = append(wishes, wish)
+	}
+
+	return wishes, rows.Err()
+}
+
+// RecordWishPrice keeps the price as the current price of the wish and adds it to the history when it changed,
+// the price the owner set is left alone since guests and chip-ins go by it
+func (s *WishPriceStorageImpl) RecordWishPrice(ctx context.Context, price models.WishPrice) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, `INSERT INTO wish_price_history (id, wish_id, price, currency, recorded_at)
+		SELECT $1, $2, $3, $4, $5 FROM wishes WHERE id = $2 AND current_price IS DISTINCT FROM $3 FOR UPDATE`,
+		price.ID, price.WishID, price.Price, price.Currency, price.RecordedAt,
+	); err != nil {
+		return fmt.Errorf("failed to record price of wish with ID '%s': %w", price.WishID, err)
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE wishes
+		SET updated_at = CASE WHEN current_price IS DISTINCT FROM $2 THEN now() ELSE updated_at END, current_price = $2, price_checked_at = $3
+		WHERE id = $1`, price.WishID, price.Price, price.RecordedAt,
+	); err != nil {
+		return fmt.Errorf("failed to update price of wish with ID '%s': %w", price.WishID, err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// MarkWishPriceChecked moves the wish to the back of the queue when its page could not be read
+func (s *WishPriceStorageImpl) MarkWishPriceChecked(ctx context.Context, wishID uuid.UUID) error {
+	if _, err := s.pool.Exec(ctx, `UPDATE wishes SET price_checked_at = now() WHERE id = $1`, wishID); err != nil {
+		return fmt.Errorf("failed to mark price of wish with ID '%s' as checked: %w", wishID, err)
+	}
+
+	return nil
+}
+
+// GetPriceDropRecipients returns the list owner and everyone holding an active reservation of the wish, users without an email are skipped
+func (s *WishPriceStorageImpl) GetPriceDropRecipients(ctx context.Context, wishID uuid.UUID) ([]models.PriceDropRecipient, error) {
+	rows, err := s.pool.Query(ctx, `SELECT u.id, u.email, FALSE
+		FROM wishes w
+		JOIN lists l ON l.id = w.list_id
+		JOIN users u ON u.id = l.user_id
+		WHERE w.id = $1 AND u.email IS NOT NULL
+		UNION ALL
+		SELECT u.id, u.email, TRUE
+		FROM wish_reservations r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.wish_id = $1 AND (r.reserved_until IS NULL OR r.reserved_until > now()) AND u.email IS NOT NULL`, wishID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price drop recipients of wish with ID '%s': %w", wishID, err)
+	}
+	defer rows.Close()
+
+	var recipients []models.PriceDropRecipient
+	for rows.Next() {
+		var r models.PriceDropRecipient
+		if err = rows.Scan(&r.UserID, &r.Email, &r.IsReserver); err != nil {
+			return nil, fmt.Errorf("failed to scan price drop recipient: %w", err)
+		}
+		recipients = append(recipients, r)
+	}
+
+	return recipients, rows.Err()
+}
+
+func (s *WishPriceStorageImpl) GetPriceHistory(ctx context.Context, wishID uuid.UUID) ([]models.WishPrice, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id, wish_id, price, currency, recorded_at FROM wish_price_history WHERE wish_id = $1 ORDER BY recorded_at ASC`, wishID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history of wish with ID '%s': %w", wishID, err)
+	}
+	defer rows.Close()
+
+	var prices []models.WishPrice
+	for rows.Next() {
+		var p models.WishPrice
+		if err = rows.Scan(&p.ID, &p.WishID, &p.Price, &p.Currency, &p.RecordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan wish price: %w", err)
+		}
+		prices = append(prices, p)
+	}
+
+	return prices, rows.Err()
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const rateLimitPrefix = projectPrefix + ":" + "rate_limit:"
+
+// RateLimitStorageImpl counts attempts in fixed windows, shared by every instance of the API
+type RateLimitStorageImpl struct{ client *redis.Client }
+
+func NewRateLimitStorage(client *redis.Client) *RateLimitStorageImpl {
+	return &RateLimitStorageImpl{client: client}
+}
+
+// Hit counts one attempt for the key and reports whether it is still within the limit of the current window.
+// The window starts with the first attempt and its counter is gone when it ends
+func (s *RateLimitStorageImpl) Hit(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	var count *redis.IntCmd
+	if _, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, rateLimitPrefix+key)
+		pipe.ExpireNX(ctx, rateLimitPrefix+key, window)
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return count.Val() <= int64(limit), nil
+}
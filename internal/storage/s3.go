@@ -4,25 +4,39 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/spf13/viper"
 
 	"wishlist/internal/config"
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
 )
 
 const (
 	AvatarPrefix    = "avatars/%s/%s"
 	WishImagePrefix = "wishes/%s/%s"
+	UploadPrefix    = "uploads/%s/%s" // Direct uploads of a user waiting to be confirmed
+	ExportPrefix    = "exports/%s/%s" // Data export archives waiting to be downloaded
 )
 
 type MinioServiceImpl struct {
-	client *minio.Client
-	bucket string
+	client  *minio.Client
+	bucket  string
+	private bool
+	urlTTL  time.Duration
 }
 
 func NewMinioService(c *minio.Client) *MinioServiceImpl {
-	return &MinioServiceImpl{client: c, bucket: viper.GetString(config.MinioBucketName)}
+	return &MinioServiceImpl{
+		client:  c,
+		bucket:  viper.GetString(config.MinioBucketName),
+		private: viper.GetBool(config.MinioPrivateBucket),
+		urlTTL:  viper.GetDuration(config.MinioURLTTL),
+	}
 }
 
 func (svc *MinioServiceImpl) GetBaseURL() string {
@@ -42,6 +56,36 @@ func (svc *MinioServiceImpl) GetObjectURL(objectName string) string {
 	return fmt.Sprintf("%s/%s/%s", svc.client.EndpointURL().String(), svc.bucket, objectName)
 }
 
+// MediaURL is what clients load the object from: a presigned GET URL when the bucket is private, the plain URL otherwise
+func (svc *MinioServiceImpl) MediaURL(objectName string) string {
+	if !svc.private {
+		return svc.GetObjectURL(objectName)
+	}
+
+	u, err := svc.client.Presign(context.Background(), http.MethodGet, svc.bucket, objectName, svc.urlTTL, nil)
+	if err != nil {
+		return "" // Only fails for an invalid key, the client shows a placeholder
+	}
+
+	return u.String()
+}
+
+// CopyObject copies the object inside the bucket with a server-side copy, the data never goes through the API
+func (svc *MinioServiceImpl) CopyObject(ctx context.Context, srcName, dstName string) error {
+	_, err := svc.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: svc.bucket, Object: dstName},
+		minio.CopySrcOptions{Bucket: svc.bucket, Object: srcName},
+	)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return svcErr.NotFoundError{Entity: "Object", Field: "key", Value: srcName}
+		}
+		return fmt.Errorf("failed to copy object '%s' to '%s': %w", srcName, dstName, err)
+	}
+
+	return nil
+}
+
 func (svc *MinioServiceImpl) DeleteObject(ctx context.Context, objectName string) error {
 	if err := svc.client.RemoveObject(ctx, svc.bucket, objectName, minio.RemoveObjectOptions{}); err != nil {
 		return fmt.Errorf("failed to delete object: %w", err)
@@ -49,3 +93,52 @@ func (svc *MinioServiceImpl) DeleteObject(ctx context.Context, objectName string
 
 	return nil
 }
+
+// PresignUpload returns a URL the client can PUT the object to without going through the API. It is a PresignedPutObject URL
+// that also signs Content-Type and Content-Length, so S3 refuses a body of another type or size
+func (svc *MinioServiceImpl) PresignUpload(ctx context.Context, objectName, contentType string, size int64, expires time.Duration) (string, error) {
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Content-Length", strconv.FormatInt(size, 10))
+
+	u, err := svc.client.PresignHeader(ctx, http.MethodPut, svc.bucket, objectName, expires, nil, headers)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign upload of '%s': %w", objectName, err)
+	}
+
+	return u.String(), nil
+}
+
+func (svc *MinioServiceImpl) StatObject(ctx context.Context, objectName string) (models.BucketObject, error) {
+	info, err := svc.client.StatObject(ctx, svc.bucket, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return models.BucketObject{}, svcErr.NotFoundError{Entity: "Upload", Field: "key", Value: objectName}
+		}
+		return models.BucketObject{}, fmt.Errorf("failed to stat object '%s': %w", objectName, err)
+	}
+
+	return models.BucketObject{Key: info.Key, Size: info.Size, ContentType: info.ContentType, LastModified: info.LastModified}, nil
+}
+
+func (svc *MinioServiceImpl) GetObject(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	object, err := svc.client.GetObject(ctx, svc.bucket, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object '%s': %w", objectName, err)
+	}
+
+	return object, nil
+}
+
+// ListObjects returns every key in the bucket, the whole bucket is read at once so the sweep can compare it with the database
+func (svc *MinioServiceImpl) ListObjects(ctx context.Context) ([]models.BucketObject, error) {
+	var objects []models.BucketObject
+	for info := range svc.client.ListObjects(ctx, svc.bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if info.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", info.Err)
+		}
+		objects = append(objects, models.BucketObject{Key: info.Key, Size: info.Size, LastModified: info.LastModified})
+	}
+
+	return objects, nil
+}
@@ -7,8 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -21,6 +25,7 @@ import (
 
 	"wishlist/internal/config"
 	"wishlist/internal/models"
+	svcErr "wishlist/internal/services/errors"
 	minioPkg "wishlist/pkg/minio"
 	"wishlist/pkg/postgres"
 	redisPkg "wishlist/pkg/redis"
@@ -71,6 +76,7 @@ func ensureSchema(t *testing.T, pool *pgxpool.Pool) {
 			email TEXT UNIQUE,
 			email_verified BOOLEAN NOT NULL DEFAULT FALSE,
 			password TEXT NOT NULL,
+			preferred_currency VARCHAR(3),
 			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 		);`,
@@ -82,7 +88,7 @@ func ensureSchema(t *testing.T, pool *pgxpool.Pool) {
 			image TEXT,
 			title TEXT NOT NULL,
 			notes TEXT,
-			is_public BOOLEAN NOT NULL DEFAULT TRUE,
+			visibility VARCHAR(16) NOT NULL DEFAULT 'public',
 			slug VARCHAR(32) NOT NULL UNIQUE,
 			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -98,13 +104,138 @@ func ensureSchema(t *testing.T, pool *pgxpool.Pool) {
 			link TEXT,
 			price BIGINT,
 			currency VARCHAR(8),
-			reserved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			quantity INT NOT NULL DEFAULT 1,
+			status VARCHAR(16) NOT NULL DEFAULT 'open',
+			priority VARCHAR(16) NOT NULL DEFAULT 'nice_to_have',
+			position INT NOT NULL DEFAULT 0,
+			price_alert_below BIGINT,
+			price_checked_at TIMESTAMPTZ,
+			current_price BIGINT,
 			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-			CONSTRAINT wishes_price_non_negative CHECK (price IS NULL OR price >= 0)
+			CONSTRAINT wishes_price_non_negative CHECK (price IS NULL OR price >= 0),
+			CONSTRAINT wishes_quantity_positive CHECK (quantity > 0),
+			CONSTRAINT wishes_status_check CHECK (status IN ('open', 'purchased', 'received', 'archived'))
 		);`,
 		`CREATE INDEX IF NOT EXISTS idx_wishes_list_id_created_at_asc ON wishes (list_id, created_at ASC);`,
-		`CREATE INDEX IF NOT EXISTS idx_wishes_reserved_by ON wishes (reserved_by);`,
+		`CREATE TABLE IF NOT EXISTS wish_reservations (
+			wish_id UUID NOT NULL REFERENCES wishes(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			units INT NOT NULL,
+			reserved_until TIMESTAMPTZ,
+			reminded_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (wish_id, user_id),
+			CONSTRAINT wish_reservations_units_positive CHECK (units > 0)
+		);`,
+		`ALTER TABLE wish_reservations DROP CONSTRAINT IF EXISTS wish_reservations_pkey;`,
+		`ALTER TABLE wish_reservations ALTER COLUMN user_id DROP NOT NULL;`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS wish_reservations_wish_id_user_id_key ON wish_reservations (wish_id, user_id);`,
+		`ALTER TABLE wish_reservations ADD COLUMN IF NOT EXISTS guest_name TEXT;`,
+		`ALTER TABLE wish_reservations ADD COLUMN IF NOT EXISTS guest_email TEXT;`,
+		`ALTER TABLE wish_reservations ADD COLUMN IF NOT EXISTS guest_token VARCHAR(64);`,
+		`ALTER TABLE wish_reservations ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMPTZ;`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_wish_reservations_guest_token ON wish_reservations (guest_token) WHERE guest_token IS NOT NULL;`,
+		`CREATE TABLE IF NOT EXISTS list_members (
+			list_id UUID NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			role VARCHAR(16) NOT NULL,
+			invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			accepted_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (list_id, user_id),
+			CONSTRAINT list_members_role_check CHECK (role IN ('owner', 'editor', 'viewer'))
+		);`,
+		`CREATE TABLE IF NOT EXISTS follows (
+			follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			accepted_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (follower_id, followee_id),
+			CONSTRAINT follows_not_self_check CHECK (follower_id <> followee_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS activity_events (
+			id UUID PRIMARY KEY,
+			actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			list_id UUID NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+			wish_id UUID REFERENCES wishes(id) ON DELETE CASCADE,
+			kind VARCHAR(32) NOT NULL,
+			title TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		`CREATE TABLE IF NOT EXISTS wish_contributions (
+			wish_id UUID NOT NULL REFERENCES wishes(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			amount BIGINT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (wish_id, user_id),
+			CONSTRAINT wish_contributions_amount_check CHECK (amount > 0)
+		);`,
+		`CREATE TABLE IF NOT EXISTS wish_price_history (
+			id UUID PRIMARY KEY,
+			wish_id UUID NOT NULL REFERENCES wishes(id) ON DELETE CASCADE,
+			price BIGINT NOT NULL,
+			currency VARCHAR(8),
+			recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		`CREATE TABLE IF NOT EXISTS gift_groups (
+			id UUID PRIMARY KEY,
+			organizer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			notes TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		`CREATE TABLE IF NOT EXISTS gift_group_members (
+			group_id UUID NOT NULL REFERENCES gift_groups(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			list_id UUID REFERENCES lists(id) ON DELETE SET NULL,
+			invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			accepted_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (group_id, user_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS gift_group_exclusions (
+			group_id UUID NOT NULL,
+			giver_id UUID NOT NULL,
+			receiver_id UUID NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (group_id, giver_id, receiver_id),
+			FOREIGN KEY (group_id, giver_id) REFERENCES gift_group_members(group_id, user_id) ON DELETE CASCADE,
+			FOREIGN KEY (group_id, receiver_id) REFERENCES gift_group_members(group_id, user_id) ON DELETE CASCADE,
+			CONSTRAINT gift_group_exclusions_not_self_check CHECK (giver_id <> receiver_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS gift_group_draws (
+			id UUID PRIMARY KEY,
+			group_id UUID NOT NULL REFERENCES gift_groups(id) ON DELETE CASCADE,
+			drawn_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			seed BIGINT NOT NULL,
+			participants INT NOT NULL,
+			stale_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		`CREATE TABLE IF NOT EXISTS gift_group_assignments (
+			draw_id UUID NOT NULL REFERENCES gift_group_draws(id) ON DELETE CASCADE,
+			giver_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			receiver_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			PRIMARY KEY (draw_id, giver_id),
+			UNIQUE (draw_id, receiver_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS storage_objects (
+			object_name VARCHAR(512) PRIMARY KEY,
+			url TEXT NOT NULL,
+			user_id UUID,
+			list_id UUID,
+			wish_id UUID,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		`CREATE TABLE IF NOT EXISTS data_migrations (
+			name VARCHAR(64) PRIMARY KEY,
+			ran_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
 	}
 
 	for _, stmt := range stmts {
@@ -118,7 +249,7 @@ func resetDB(t *testing.T, pool *pgxpool.Pool) {
 	t.Helper()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if _, err := pool.Exec(ctx, "TRUNCATE TABLE wishes, lists, users CASCADE"); err != nil {
+	if _, err := pool.Exec(ctx, "TRUNCATE TABLE data_migrations, gift_group_assignments, gift_group_draws, gift_group_exclusions, gift_group_members, gift_groups, storage_objects, wish_price_history, wish_reservations, wish_contributions, list_members, follows, activity_events, wishes, lists, users CASCADE"); err != nil {
 		t.Fatalf("truncate failed: %v", err)
 	}
 }
@@ -260,6 +391,80 @@ func TestUserStorage_Integration(t *testing.T) {
 	}
 }
 
+func TestUserStorage_AccountDeletion_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+
+	ctx := context.Background()
+	leaving := models.User{ID: uuid.New(), Name: "Carol", Username: "carol", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	staying := models.User{ID: uuid.New(), Name: "Carl", Username: "carl", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, user := range []models.User{leaving, staying} {
+		if err := users.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+	}
+	list := models.List{ID: uuid.New(), UserID: leaving.ID, Title: "Public", Visibility: models.ListVisibilityPublic, Slug: "acc0unt0de1et10n0000000000000000", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+
+	deleteAfter := time.Now().Add(time.Hour)
+	if err := users.ScheduleUserDeletion(ctx, leaving.ID, deleteAfter); err != nil {
+		t.Fatalf("ScheduleUserDeletion() error = %v", err)
+	}
+	got, err := users.GetUserByID(ctx, leaving.ID)
+	if err != nil || !got.IsDeactivated() {
+		t.Fatalf("GetUserByID() error=%v user=%+v, want it deactivated", err, got)
+	}
+	found, err := users.SearchUsersByUsername(ctx, "car", 10)
+	if err != nil || len(found) != 1 || found[0].ID != staying.ID {
+		t.Fatalf("SearchUsersByUsername() error=%v users=%+v, want only the active one", err, found)
+	}
+	public, err := lists.GetPublicListsByUserID(ctx, leaving.ID, false)
+	if err != nil || len(public) != 0 {
+		t.Fatalf("GetPublicListsByUserID() error=%v lists=%d, want none", err, len(public))
+	}
+	if _, err = lists.GetListByID(ctx, list.ID); err == nil {
+		t.Fatal("expected the list of a deactivated account not found by ID")
+	}
+	if _, err = lists.GetListBySharedLink(ctx, list.Slug); err == nil {
+		t.Fatal("expected the list of a deactivated account not found by its link")
+	}
+
+	if err = users.CancelUserDeletion(ctx, leaving.ID); err != nil {
+		t.Fatalf("CancelUserDeletion() error = %v", err)
+	}
+	if got, _ = users.GetUserByID(ctx, leaving.ID); got.IsDeactivated() {
+		t.Fatal("account still deactivated after cancel")
+	}
+	if _, err = lists.GetListBySharedLink(ctx, list.Slug); err != nil {
+		t.Fatalf("GetListBySharedLink() after cancel error = %v", err)
+	}
+
+	if err = users.ScheduleUserDeletion(ctx, leaving.ID, deleteAfter); err != nil {
+		t.Fatalf("ScheduleUserDeletion() error = %v", err)
+	}
+	deleted, err := users.DeleteScheduledUsers(ctx, time.Now())
+	if err != nil || len(deleted) != 0 {
+		t.Fatalf("DeleteScheduledUsers(now) error=%v deleted=%v, want the grace period respected", err, deleted)
+	}
+	deleted, err = users.DeleteScheduledUsers(ctx, deleteAfter.Add(time.Minute))
+	if err != nil || len(deleted) != 1 || deleted[0] != leaving.ID {
+		t.Fatalf("DeleteScheduledUsers() error=%v deleted=%v", err, deleted)
+	}
+	if _, err = users.GetUserByID(ctx, leaving.ID); err == nil {
+		t.Fatal("expected not found after the grace period")
+	}
+	if _, err = lists.GetListByID(ctx, list.ID); err == nil {
+		t.Fatal("expected the lists deleted with the account")
+	}
+	if _, err = users.GetUserByID(ctx, staying.ID); err != nil {
+		t.Fatalf("GetUserByID(staying) error = %v", err)
+	}
+}
+
 func TestListStorage_Integration(t *testing.T) {
 	pool := mustPostgres(t)
 	resetDB(t, pool)
@@ -274,14 +479,14 @@ func TestListStorage_Integration(t *testing.T) {
 	}
 
 	list := models.List{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Title:     "List",
-		Notes:     nil,
-		IsPublic:  true,
-		Slug:      "12345678901234567890123456789012",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:         uuid.New(),
+		UserID:     userID,
+		Title:      "List",
+		Notes:      nil,
+		Visibility: models.ListVisibilityPublic,
+		Slug:       "12345678901234567890123456789012",
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
 	}
 	if err := lists.CreateList(ctx, list); err != nil {
 		t.Fatalf("CreateList() error = %v", err)
@@ -302,7 +507,7 @@ func TestListStorage_Integration(t *testing.T) {
 		t.Fatalf("GetListsByUserID() error=%v len=%d", err, len(all))
 	}
 
-	public, err := lists.GetPublicListsByUserID(ctx, userID)
+	public, err := lists.GetPublicListsByUserID(ctx, userID, false)
 	if err != nil || len(public) != 1 {
 		t.Fatalf("GetPublicListsByUserID() error=%v len=%d", err, len(public))
 	}
@@ -328,227 +533,1602 @@ func TestListStorage_Integration(t *testing.T) {
 	}
 }
 
-func TestWishStorage_Integration(t *testing.T) {
+func TestListMemberStorage_Integration(t *testing.T) {
 	pool := mustPostgres(t)
 	resetDB(t, pool)
 	users := NewUserStorage(pool)
 	lists := NewListStorage(pool)
-	wishes := NewWishStorage(pool)
+	members := NewListMemberStorage(pool)
 
 	ctx := context.Background()
-	ownerID := uuid.New()
-	otherID := uuid.New()
-
-	if err := users.CreateUser(ctx, models.User{ID: ownerID, Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
-		t.Fatalf("CreateUser(owner) error = %v", err)
-	}
-	if err := users.CreateUser(ctx, models.User{ID: otherID, Name: "Other", Username: "other", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
-		t.Fatalf("CreateUser(other) error = %v", err)
+	owner := models.User{ID: uuid.New(), Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	editor := models.User{ID: uuid.New(), Name: "Editor", Username: "editor", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, u := range []models.User{owner, editor} {
+		if err := users.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
 	}
 
-	list := models.List{
-		ID:        uuid.New(),
-		UserID:    ownerID,
-		Title:     "List",
-		IsPublic:  true,
-		Slug:      "12345678901234567890123456789012",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
+	list := models.List{ID: uuid.New(), UserID: owner.ID, Title: "Household", Visibility: models.ListVisibilityPrivate, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
 	if err := lists.CreateList(ctx, list); err != nil {
 		t.Fatalf("CreateList() error = %v", err)
 	}
 
-	price := int64(100)
-	currency := "USD"
-	wish := models.Wish{
-		ID:        uuid.New(),
-		ListID:    list.ID,
-		Title:     "Wish",
-		Price:     &price,
-		Currency:  &currency,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	member := models.ListMember{ListID: list.ID, UserID: editor.ID, Role: models.ListRoleEditor, InvitedBy: owner.ID, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := members.AddListMember(ctx, member); err != nil {
+		t.Fatalf("AddListMember() error = %v", err)
 	}
-	if err := wishes.CreateWish(ctx, wish); err != nil {
-		t.Fatalf("CreateWish() error = %v", err)
-	}
-
-	got, err := wishes.GetWishByID(ctx, wish.ID)
-	if err != nil || got.Title != "Wish" {
-		t.Fatalf("GetWishByID() error=%v wish=%+v", err, got)
+	if err := members.AddListMember(ctx, member); err == nil {
+		t.Fatal("AddListMember() duplicate error = nil, want conflict")
 	}
 
-	listWishes, err := wishes.GetWishesByListID(ctx, list.ID)
-	if err != nil || len(listWishes) != 1 {
-		t.Fatalf("GetWishesByListID() error=%v len=%d", err, len(listWishes))
+	pending, err := members.GetPendingInvitations(ctx, editor.ID)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("GetPendingInvitations() error=%v len=%d", err, len(pending))
 	}
-
-	newTitle := "Updated"
-	if err = wishes.UpdateWishByID(ctx, wish.ID, models.UpdateWishRequest{Title: &newTitle}); err != nil {
-		t.Fatalf("UpdateWishByID() error = %v", err)
+	if shared, _ := lists.GetListsByUserID(ctx, editor.ID); len(shared) != 0 {
+		t.Fatalf("GetListsByUserID() before accept len=%d, want 0", len(shared))
 	}
 
-	if err = wishes.ReserveWish(ctx, wish.ID, otherID); err != nil {
-		t.Fatalf("ReserveWish() error = %v", err)
+	if err = members.AcceptListMember(ctx, list.ID, editor.ID); err != nil {
+		t.Fatalf("AcceptListMember() error = %v", err)
 	}
-	if err = wishes.ReserveWish(ctx, wish.ID, ownerID); err == nil {
-		t.Fatal("expected reserve error on already reserved")
+	got, err := members.GetListMember(ctx, list.ID, editor.ID)
+	if err != nil || !got.IsAccepted() || got.Role != models.ListRoleEditor {
+		t.Fatalf("GetListMember() error=%v member=%+v", err, got)
 	}
 
-	if err = wishes.ReleaseWish(ctx, wish.ID, ownerID); err == nil {
-		t.Fatal("expected release error for wrong user")
-	}
-	if err = wishes.ReleaseWish(ctx, wish.ID, otherID); err != nil {
-		t.Fatalf("ReleaseWish() error = %v", err)
+	shared, err := lists.GetListsByUserID(ctx, editor.ID)
+	if err != nil || len(shared) != 1 || shared[0].Role != models.ListRoleEditor {
+		t.Fatalf("GetListsByUserID() error=%v lists=%+v", err, shared)
 	}
 
-	if err = wishes.DeleteWishByID(ctx, wish.ID); err != nil {
-		t.Fatalf("DeleteWishByID() error = %v", err)
+	if err = members.DeleteListMember(ctx, list.ID, editor.ID); err != nil {
+		t.Fatalf("DeleteListMember() error = %v", err)
 	}
-	if _, err = wishes.GetWishByID(ctx, wish.ID); err == nil {
+	if _, err = members.GetListMember(ctx, list.ID, editor.ID); err == nil {
 		t.Fatal("expected not found after delete")
 	}
 }
 
-func TestWishStorage_InvalidListID_Integration(t *testing.T) {
+func TestFollowStorage_Integration(t *testing.T) {
 	pool := mustPostgres(t)
 	resetDB(t, pool)
-	wishes := NewWishStorage(pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	follows := NewFollowStorage(pool)
 
 	ctx := context.Background()
-	badListID := uuid.New()
-	price := int64(10)
-	wish := models.Wish{
-		ID:        uuid.New(),
-		ListID:    badListID,
-		Title:     "Invalid",
-		Price:     &price,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	owner := models.User{ID: uuid.New(), Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	friend := models.User{ID: uuid.New(), Name: "Friend", Username: "friend", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, u := range []models.User{owner, friend} {
+		if err := users.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
 	}
-	if err := wishes.CreateWish(ctx, wish); err == nil {
-		t.Fatal("expected error on CreateWish with invalid list ID")
+	for i, visibility := range []models.ListVisibility{models.ListVisibilityPublic, models.ListVisibilityFollowers, models.ListVisibilityPrivate} {
+		list := models.List{ID: uuid.New(), UserID: owner.ID, Title: string(visibility), Visibility: visibility, Slug: fmt.Sprintf("f0110w%026d", i), CreatedAt: time.Now(), UpdatedAt: time.Now()}
+		if err := lists.CreateList(ctx, list); err != nil {
+			t.Fatalf("CreateList() error = %v", err)
+		}
+	}
+
+	follow := models.Follow{FollowerID: friend.ID, FolloweeID: owner.ID, CreatedAt: time.Now()}
+	if err := follows.CreateFollow(ctx, follow); err != nil {
+		t.Fatalf("CreateFollow() error = %v", err)
+	}
+	if err := follows.CreateFollow(ctx, follow); err == nil {
+		t.Fatal("CreateFollow() duplicate error = nil, want conflict")
+	}
+	if err := follows.CreateFollow(ctx, models.Follow{FollowerID: friend.ID, FolloweeID: uuid.New(), CreatedAt: time.Now()}); err == nil {
+		t.Fatal("CreateFollow() unknown user error = nil, want not found")
+	}
+
+	followers, err := follows.GetFollowers(ctx, owner.ID)
+	if err != nil || len(followers) != 1 || followers[0].IsAccepted() {
+		t.Fatalf("GetFollowers() error=%v follows=%+v, want one pending request", err, followers)
+	}
+
+	if err = follows.AcceptFollow(ctx, friend.ID, owner.ID); err != nil {
+		t.Fatalf("AcceptFollow() error = %v", err)
+	}
+	if err = follows.AcceptFollow(ctx, friend.ID, owner.ID); err == nil {
+		t.Fatal("AcceptFollow() twice error = nil, want not found")
+	}
+	got, err := follows.GetFollow(ctx, friend.ID, owner.ID)
+	if err != nil || !got.IsAccepted() {
+		t.Fatalf("GetFollow() error=%v follow=%+v", err, got)
+	}
+	following, err := follows.GetFollowing(ctx, friend.ID)
+	if err != nil || len(following) != 1 || following[0].FolloweeID != owner.ID {
+		t.Fatalf("GetFollowing() error=%v follows=%+v", err, following)
+	}
+
+	if public, err := lists.GetPublicListsByUserID(ctx, owner.ID, false); err != nil || len(public) != 1 {
+		t.Fatalf("GetPublicListsByUserID(false) error=%v len=%d, want 1", err, len(public))
+	}
+	if visible, err := lists.GetPublicListsByUserID(ctx, owner.ID, true); err != nil || len(visible) != 2 {
+		t.Fatalf("GetPublicListsByUserID(true) error=%v len=%d, want 2", err, len(visible))
+	}
+
+	if err = follows.DeleteFollow(ctx, friend.ID, owner.ID); err != nil {
+		t.Fatalf("DeleteFollow() error = %v", err)
+	}
+	if _, err = follows.GetFollow(ctx, friend.ID, owner.ID); err == nil {
+		t.Fatal("expected not found after delete")
 	}
 }
 
-func TestCascadeDelete_Integration(t *testing.T) {
+func TestActivityStorage_Feed_Integration(t *testing.T) {
 	pool := mustPostgres(t)
 	resetDB(t, pool)
 	users := NewUserStorage(pool)
 	lists := NewListStorage(pool)
-	wishes := NewWishStorage(pool)
+	members := NewListMemberStorage(pool)
+	follows := NewFollowStorage(pool)
+	activity := NewActivityStorage(pool)
 
 	ctx := context.Background()
-	userID := uuid.New()
-	if err := users.CreateUser(ctx, models.User{ID: userID, Name: "Cascade", Username: "cascade", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
-		t.Fatalf("CreateUser() error = %v", err)
+	owner := models.User{ID: uuid.New(), Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	follower := models.User{ID: uuid.New(), Name: "Follower", Username: "follower", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	stranger := models.User{ID: uuid.New(), Name: "Stranger", Username: "stranger", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	editor := models.User{ID: uuid.New(), Name: "Editor", Username: "editor", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	fan := models.User{ID: uuid.New(), Name: "Fan", Username: "fan", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, u := range []models.User{owner, follower, stranger, editor, fan} {
+		if err := users.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
 	}
 
-	list := models.List{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Title:     "Cascade list",
-		IsPublic:  true,
-		Slug:      "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	public := models.List{ID: uuid.New(), UserID: owner.ID, Title: "Birthday", Visibility: models.ListVisibilityPublic, Slug: "act1v1ty000000000000000000000001", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	private := models.List{ID: uuid.New(), UserID: owner.ID, Title: "Secret", Visibility: models.ListVisibilityPrivate, Slug: "act1v1ty000000000000000000000002", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, list := range []models.List{public, private} {
+		if err := lists.CreateList(ctx, list); err != nil {
+			t.Fatalf("CreateList() error = %v", err)
+		}
 	}
-	if err := lists.CreateList(ctx, list); err != nil {
-		t.Fatalf("CreateList() error = %v", err)
+	for _, u := range []models.User{follower, fan} {
+		if err := follows.CreateFollow(ctx, models.Follow{FollowerID: u.ID, FolloweeID: owner.ID, AcceptedAt: new(time.Now()), CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("CreateFollow() error = %v", err)
+		}
+	}
+	if err := members.AddListMember(ctx, models.ListMember{ListID: public.ID, UserID: editor.ID, Role: models.ListRoleEditor, InvitedBy: owner.ID, AcceptedAt: new(time.Now()), CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddListMember() error = %v", err)
 	}
 
-	wish := models.Wish{
-		ID:        uuid.New(),
-		ListID:    list.ID,
-		Title:     "Cascade wish",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	start := time.Now().Add(-time.Hour)
+	events := []models.ActivityEvent{
+		{ID: uuid.New(), ActorID: owner.ID, ListID: public.ID, Kind: models.ActivityListCreated, Title: public.Title, CreatedAt: start},
+		{ID: uuid.New(), ActorID: owner.ID, ListID: private.ID, Kind: models.ActivityListCreated, Title: private.Title, CreatedAt: start.Add(time.Minute)},
+		{ID: uuid.New(), ActorID: follower.ID, ListID: public.ID, Kind: models.ActivityWishReserved, Title: "Mug", CreatedAt: start.Add(2 * time.Minute)},
+		{ID: uuid.New(), ActorID: editor.ID, ListID: public.ID, Kind: models.ActivityListUpdated, Title: public.Title, CreatedAt: start.Add(3 * time.Minute)},
 	}
-	if err := wishes.CreateWish(ctx, wish); err != nil {
-		t.Fatalf("CreateWish() error = %v", err)
+	for _, event := range events {
+		if err := activity.RecordActivity(ctx, event); err != nil {
+			t.Fatalf("RecordActivity() error = %v", err)
+		}
 	}
 
-	if err := users.DeleteUserByID(ctx, userID); err != nil {
-		t.Fatalf("DeleteUserByID() error = %v", err)
+	feed, err := activity.GetFeed(ctx, fan.ID, nil, 10)
+	if err != nil || len(feed) != 2 {
+		t.Fatalf("GetFeed(fan) error=%v len=%d, want 2 events of the public list", err, len(feed))
 	}
-	if _, err := lists.GetListByID(ctx, list.ID); err == nil {
-		t.Fatal("expected list deleted on user delete")
+	for _, event := range feed {
+		if event.Kind.IsReservation() {
+			t.Fatalf("GetFeed(fan) returned the follower's reservation %+v", event)
+		}
 	}
-	if _, err := wishes.GetWishByID(ctx, wish.ID); err == nil {
-		t.Fatal("expected wish deleted on user delete")
+
+	feed, err = activity.GetFeed(ctx, follower.ID, nil, 10)
+	if err != nil || len(feed) != 2 {
+		t.Fatalf("GetFeed(follower) error=%v len=%d, want 2 events of the public list", err, len(feed))
+	}
+	if feed[0].ID != events[3].ID || feed[0].ActorUsername != "editor" || feed[0].ListTitle != public.Title {
+		t.Fatalf("GetFeed(follower) first event = %+v, want the newest one", feed[0])
 	}
-}
 
-func TestTokenStorage_RedisIntegration(t *testing.T) {
-	client := mustRedis(t)
-	defer func() { _ = client.Close() }()
+	page, err := activity.GetFeed(ctx, follower.ID, &models.FeedCursor{CreatedAt: feed[0].CreatedAt, ID: feed[0].ID}, 10)
+	if err != nil || len(page) != 1 || page[0].ID != events[0].ID {
+		t.Fatalf("GetFeed(follower, cursor) error=%v events=%+v", err, page)
+	}
 
-	viper.Reset()
-	viper.Set(config.PwdResetTokenTTL, "1h")
-	viper.Set(config.EmailVerifyTokenTTL, "1h")
+	if feed, err = activity.GetFeed(ctx, stranger.ID, nil, 10); err != nil || len(feed) != 0 {
+		t.Fatalf("GetFeed(stranger) error=%v len=%d, want none", err, len(feed))
+	}
+
+	for _, viewer := range []models.User{owner, editor, fan} {
+		feed, err = activity.GetFeed(ctx, viewer.ID, nil, 10)
+		if err != nil {
+			t.Fatalf("GetFeed(%s) error = %v", viewer.Username, err)
+		}
+		for _, event := range feed {
+			if event.Kind.IsReservation() || event.ActorID == viewer.ID {
+				t.Fatalf("GetFeed(%s) returned %+v", viewer.Username, event)
+			}
+		}
+	}
+}
+
+func TestOccasionStorage_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+	follows := NewFollowStorage(pool)
+	occasions := NewOccasionStorage(pool)
 
-	ts := NewTokenStorage(client)
 	ctx := context.Background()
+	owner := models.User{ID: uuid.New(), Name: "Owner", Username: "owner", Email: new("owner@example.com"), Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	follower := models.User{ID: uuid.New(), Name: "Follower", Username: "follower", Email: new("follower@example.com"), Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	stranger := models.User{ID: uuid.New(), Name: "Stranger", Username: "stranger", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, u := range []models.User{owner, follower, stranger} {
+		if err := users.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+	}
 
-	if err := ts.SaveEmailVerificationToken(ctx, "token1", "user1"); err != nil {
-		t.Fatalf("SaveEmailVerificationToken() error = %v", err)
+	today := models.StartOfDay(time.Now())
+	public := models.List{ID: uuid.New(), UserID: owner.ID, Title: "Birthday", Visibility: models.ListVisibilityPublic, Slug: "0ccas10n000000000000000000000001",
+		OccasionDate: new(today.AddDate(-20, 0, 3)), OccasionRecursYearly: true, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	private := models.List{ID: uuid.New(), UserID: owner.ID, Title: "Secret", Visibility: models.ListVisibilityPrivate, Slug: "0ccas10n000000000000000000000002",
+		OccasionDate: new(today.AddDate(0, 0, 10)), CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	passed := models.List{ID: uuid.New(), UserID: owner.ID, Title: "Housewarming", Visibility: models.ListVisibilityPublic, Slug: "0ccas10n000000000000000000000003",
+		OccasionDate: new(today.AddDate(0, 0, -1)), CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, list := range []models.List{public, private, passed} {
+		if err := lists.CreateList(ctx, list); err != nil {
+			t.Fatalf("CreateList() error = %v", err)
+		}
 	}
-	if val, err := ts.GetEmailVerificationToken(ctx, "token1"); err != nil || val != "user1" {
-		t.Fatalf("GetEmailVerificationToken() error=%v val=%s", err, val)
+	if err := follows.CreateFollow(ctx, models.Follow{FollowerID: follower.ID, FolloweeID: owner.ID, AcceptedAt: new(time.Now()), CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateFollow() error = %v", err)
 	}
-	if err := ts.DeleteEmailVerificationToken(ctx, "token1"); err != nil {
-		t.Fatalf("DeleteEmailVerificationToken() error = %v", err)
+
+	stored, err := lists.GetListByID(ctx, public.ID)
+	if err != nil || stored.OccasionDate == nil || !stored.OccasionDate.Equal(*public.OccasionDate) || !stored.OccasionRecursYearly {
+		t.Fatalf("GetListByID() error=%v list=%+v, want the yearly occasion", err, stored)
 	}
 
-	revoked, err := ts.CheckIfAuthTokenRevoked(ctx, "tokenX")
-	if err != nil || revoked {
-		t.Fatalf("CheckIfAuthTokenRevoked() err=%v revoked=%v", err, revoked)
+	visible, err := occasions.GetOccasionLists(ctx, follower.ID, today)
+	if err != nil || len(visible) != 1 || visible[0].ID != public.ID || visible[0].Role != "" {
+		t.Fatalf("GetOccasionLists(follower) error=%v lists=%+v, want the public birthday", err, visible)
 	}
-	if err = ts.RevokeAuthTokens(ctx, "tokenX", time.Minute); err != nil {
-		t.Fatalf("RevokeAuthTokens() error = %v", err)
+	if visible, err = occasions.GetOccasionLists(ctx, owner.ID, today); err != nil || len(visible) != 2 {
+		t.Fatalf("GetOccasionLists(owner) error=%v len=%d, want both lists ahead", err, len(visible))
 	}
-	if revoked, err = ts.CheckIfAuthTokenRevoked(ctx, "tokenX"); err != nil || !revoked {
-		t.Fatalf("CheckIfAuthTokenRevoked() err=%v revoked=%v", err, revoked)
+	if visible, err = occasions.GetOccasionLists(ctx, stranger.ID, today); err != nil || len(visible) != 0 {
+		t.Fatalf("GetOccasionLists(stranger) error=%v len=%d, want none", err, len(visible))
 	}
 
-	if err := ts.SavePasswordResetToken(ctx, "token2", "user2"); err != nil {
-		t.Fatalf("SavePasswordResetToken() error = %v", err)
+	wish := models.Wish{ID: uuid.New(), ListID: public.ID, Title: "Mug", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err = wishes.CreateWish(ctx, wish); err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
 	}
-	if val, err := ts.GetPasswordResetToken(ctx, "token2"); err != nil || val != "user2" {
-		t.Fatalf("GetPasswordResetToken() error=%v val=%s", err, val)
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: follower.ID, Units: 1}); err != nil {
+		t.Fatalf("ReserveWish() error = %v", err)
 	}
-	if err := ts.DeletePasswordResetToken(ctx, "token2"); err != nil {
-		t.Fatalf("DeletePasswordResetToken() error = %v", err)
+	recipients, err := occasions.GetOccasionRecipients(ctx, public.ID)
+	if err != nil || len(recipients) != 2 {
+		t.Fatalf("GetOccasionRecipients() error=%v recipients=%+v, want the owner and the reserver", err, recipients)
 	}
-}
-
-func TestMinioStorage_Integration(t *testing.T) {
-	client, bucket := mustMinio(t)
-	viper.Reset()
-	viper.Set(config.MinioBucketName, bucket)
-
-	svc := NewMinioService(client)
-
-	ctx := context.Background()
-	objectName := fmt.Sprintf("test/%s.txt", uuid.NewString())
-	payload := []byte("hello")
-
-	if err := svc.UploadObject(ctx, objectName, bytesReader(payload), int64(len(payload)), "text/plain"); err != nil {
-		t.Fatalf("UploadObject() error = %v", err)
+	for _, r := range recipients {
+		if r.IsReserver != (r.UserID == follower.ID) {
+			t.Fatalf("recipient %+v, want only the follower marked as reserver", r)
+		}
 	}
 
-	if _, err := client.StatObject(ctx, bucket, objectName, minio.StatObjectOptions{}); err != nil {
-		t.Fatalf("StatObject() error = %v", err)
+	all, err := occasions.GetOccasions(ctx, today)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("GetOccasions() error=%v len=%d, want the two lists ahead", err, len(all))
 	}
-
-	if svc.GetObjectURL(objectName) == "" {
-		t.Fatal("GetObjectURL() empty")
+	next := *models.NextOccasion(*public.OccasionDate, true, time.Now())
+	if err = occasions.MarkOccasionReminded(ctx, public.ID, next); err != nil {
+		t.Fatalf("MarkOccasionReminded() error = %v", err)
+	}
+	if all, err = occasions.GetOccasions(ctx, today); err != nil {
+		t.Fatalf("GetOccasions() error = %v", err)
+	}
+	for _, o := range all {
+		if o.ListID == public.ID && (o.RemindedFor == nil || !o.RemindedFor.Equal(next)) {
+			t.Fatalf("occasion %+v, want it marked as reminded for %v", o, next)
+		}
+	}
+
+	// Moving the date makes it due again
+	if err = lists.UpdateListByID(ctx, public.ID, models.UpdateListRequest{OccasionDate: new(today.AddDate(0, 0, 5).Format(models.OccasionDateLayout))}); err != nil {
+		t.Fatalf("UpdateListByID() error = %v", err)
+	}
+	if all, err = occasions.GetOccasions(ctx, today); err != nil {
+		t.Fatalf("GetOccasions() error = %v", err)
+	}
+	for _, o := range all {
+		if o.ListID == public.ID && o.RemindedFor != nil {
+			t.Fatalf("occasion %+v, want the reminder reset with the new date", o)
+		}
+	}
+	if err = lists.UpdateListByID(ctx, public.ID, models.UpdateListRequest{OccasionDate: new("")}); err != nil {
+		t.Fatalf("UpdateListByID() clear error = %v", err)
+	}
+	if stored, err = lists.GetListByID(ctx, public.ID); err != nil || stored.OccasionDate != nil {
+		t.Fatalf("GetListByID() error=%v date=%v, want the occasion removed", err, stored.OccasionDate)
+	}
+}
+
+func TestGroupStorage_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	groups := NewGroupStorage(pool)
+
+	ctx := context.Background()
+	var members []models.User
+	for _, name := range []string{"organizer", "alice", "bob", "carol"} {
+		u := models.User{ID: uuid.New(), Name: name, Username: name, Email: new(name + "@example.com"), Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+		if err := users.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+		members = append(members, u)
+	}
+	organizer, alice, bob, carol := members[0], members[1], members[2], members[3]
+
+	now := time.Now()
+	group := models.Group{ID: uuid.New(), OrganizerID: organizer.ID, Title: "Office Secret Santa", CreatedAt: now, UpdatedAt: now}
+	if err := groups.CreateGroup(ctx, group, models.GroupMember{GroupID: group.ID, UserID: organizer.ID, InvitedBy: organizer.ID, AcceptedAt: &now, CreatedAt: now}); err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+
+	for _, u := range []models.User{alice, bob, carol} {
+		if err := groups.AddGroupMember(ctx, models.GroupMember{GroupID: group.ID, UserID: u.ID, InvitedBy: organizer.ID, CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("AddGroupMember() error = %v", err)
+		}
+	}
+	err := groups.AddGroupMember(ctx, models.GroupMember{GroupID: group.ID, UserID: alice.ID, InvitedBy: organizer.ID, CreatedAt: time.Now()})
+	if _, ok := errors.AsType[svcErr.ConflictError](err); !ok {
+		t.Fatalf("AddGroupMember() duplicate error = %v, want ConflictError", err)
+	}
+
+	if invitations, err := groups.GetGroupsByUserID(ctx, alice.ID, false); err != nil || len(invitations) != 1 {
+		t.Fatalf("GetGroupsByUserID(invited) error=%v len=%d, want the invitation", err, len(invitations))
+	}
+	for _, u := range []models.User{alice, bob} {
+		if err = groups.AcceptGroupMember(ctx, group.ID, u.ID); err != nil {
+			t.Fatalf("AcceptGroupMember() error = %v", err)
+		}
+	}
+	if err = groups.AcceptGroupMember(ctx, group.ID, alice.ID); err == nil {
+		t.Fatal("AcceptGroupMember() twice error = nil, want not found")
+	}
+
+	stored, err := groups.GetGroupByID(ctx, group.ID)
+	if err != nil || stored.MembersCount != 3 || stored.DrawnAt != nil {
+		t.Fatalf("GetGroupByID() error=%v group=%+v, want 3 accepted members and no draw", err, stored)
+	}
+	joined, err := groups.GetGroupsByUserID(ctx, alice.ID, true)
+	if err != nil || len(joined) != 1 || joined[0].ID != group.ID {
+		t.Fatalf("GetGroupsByUserID(accepted) error=%v groups=%+v", err, joined)
+	}
+
+	list := models.List{ID: uuid.New(), UserID: bob.ID, Title: "Christmas", Visibility: models.ListVisibilityPublic, Slug: "gr0up000000000000000000000000001", CreatedAt: now, UpdatedAt: now}
+	if err = lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	if err = groups.SetGroupMemberList(ctx, group.ID, bob.ID, &list.ID); err != nil {
+		t.Fatalf("SetGroupMemberList() error = %v", err)
+	}
+
+	all, err := groups.GetGroupMembers(ctx, group.ID)
+	if err != nil || len(all) != 4 || all[0].UserID != organizer.ID || all[0].Email == nil || *all[0].Email != "organizer@example.com" {
+		t.Fatalf("GetGroupMembers() error=%v members=%+v, want everyone with the organizer first", err, all)
+	}
+
+	exclusions := []models.GroupExclusion{
+		{GroupID: group.ID, GiverID: alice.ID, ReceiverID: bob.ID, CreatedAt: now},
+		{GroupID: group.ID, GiverID: bob.ID, ReceiverID: alice.ID, CreatedAt: now},
+	}
+	if err = groups.AddGroupExclusions(ctx, exclusions); err != nil {
+		t.Fatalf("AddGroupExclusions() error = %v", err)
+	}
+	if err = groups.AddGroupExclusions(ctx, exclusions[:1]); err != nil {
+		t.Fatalf("AddGroupExclusions() again error = %v, want the existing rule kept", err)
+	}
+	if stored, err := groups.GetGroupExclusions(ctx, group.ID); err != nil || len(stored) != 2 {
+		t.Fatalf("GetGroupExclusions() error=%v len=%d, want 2", err, len(stored))
+	}
+
+	first := models.GroupDraw{ID: uuid.New(), GroupID: group.ID, DrawnBy: organizer.ID, Seed: 1, Participants: 3, CreatedAt: now.Add(-time.Minute)}
+	if err = groups.CreateGroupDraw(ctx, first, []models.GroupAssignment{
+		{DrawID: first.ID, GiverID: organizer.ID, ReceiverID: alice.ID},
+		{DrawID: first.ID, GiverID: alice.ID, ReceiverID: organizer.ID},
+	}); err != nil {
+		t.Fatalf("CreateGroupDraw() error = %v", err)
+	}
+	second := models.GroupDraw{ID: uuid.New(), GroupID: group.ID, DrawnBy: organizer.ID, Seed: 2, Participants: 3, CreatedAt: now}
+	if err = groups.CreateGroupDraw(ctx, second, []models.GroupAssignment{
+		{DrawID: second.ID, GiverID: organizer.ID, ReceiverID: bob.ID},
+		{DrawID: second.ID, GiverID: bob.ID, ReceiverID: organizer.ID},
+	}); err != nil {
+		t.Fatalf("CreateGroupDraw() second error = %v", err)
+	}
+	broken := models.GroupDraw{ID: uuid.New(), GroupID: group.ID, DrawnBy: organizer.ID, Seed: 3, Participants: 2, CreatedAt: now}
+	if err = groups.CreateGroupDraw(ctx, broken, []models.GroupAssignment{
+		{DrawID: broken.ID, GiverID: organizer.ID, ReceiverID: bob.ID},
+		{DrawID: broken.ID, GiverID: alice.ID, ReceiverID: bob.ID},
+	}); err == nil {
+		t.Fatal("CreateGroupDraw() with a receiver drawn twice error = nil, want the draw rolled back")
+	}
+
+	draws, err := groups.GetGroupDraws(ctx, group.ID)
+	if err != nil || len(draws) != 2 || draws[0].ID != second.ID || draws[1].Seed != 1 {
+		t.Fatalf("GetGroupDraws() error=%v draws=%+v, want both draws newest first", err, draws)
+	}
+
+	assignee, err := groups.GetGroupAssignee(ctx, group.ID, organizer.ID)
+	if err != nil || assignee.UserID != bob.ID || assignee.DrawID != second.ID || assignee.ListID == nil || *assignee.ListID != list.ID || assignee.Username != "bob" {
+		t.Fatalf("GetGroupAssignee() error=%v assignee=%+v, want bob from the latest draw with his list", err, assignee)
+	}
+	if _, err = groups.GetGroupAssignee(ctx, group.ID, alice.ID); err == nil {
+		t.Fatal("GetGroupAssignee() for a giver left out of the latest draw error = nil, want not found")
+	}
+
+	// Carol never accepted and took no part in the draw, bob did
+	if err = groups.DeleteGroupMember(ctx, group.ID, carol.ID); err != nil {
+		t.Fatalf("DeleteGroupMember() error = %v", err)
+	}
+	if assignee, err = groups.GetGroupAssignee(ctx, group.ID, organizer.ID); err != nil || assignee.StaleAt != nil {
+		t.Fatalf("GetGroupAssignee() after carol left error=%v assignee=%+v, want the draw intact", err, assignee)
+	}
+	if err = groups.DeleteGroupMember(ctx, group.ID, bob.ID); err != nil {
+		t.Fatalf("DeleteGroupMember() error = %v", err)
+	}
+	if stored, _ := groups.GetGroupExclusions(ctx, group.ID); len(stored) != 0 {
+		t.Fatalf("GetGroupExclusions() after bob left len=%d, want his rules gone", len(stored))
+	}
+	if assignee, err = groups.GetGroupAssignee(ctx, group.ID, organizer.ID); err != nil || assignee.StaleAt == nil {
+		t.Fatalf("GetGroupAssignee() after bob left error=%v assignee=%+v, want the draw stale", err, assignee)
+	}
+	if draws, err = groups.GetGroupDraws(ctx, group.ID); err != nil || draws[0].StaleAt == nil || draws[1].StaleAt != nil {
+		t.Fatalf("GetGroupDraws() error=%v draws=%+v, want only the latest draw stale", err, draws)
+	}
+
+	if err = groups.DeleteGroup(ctx, group.ID); err != nil {
+		t.Fatalf("DeleteGroup() error = %v", err)
+	}
+	if _, err = groups.GetGroupByID(ctx, group.ID); err == nil {
+		t.Fatal("GetGroupByID() after delete error = nil, want not found")
+	}
+}
+
+func TestWishStorage_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	otherID := uuid.New()
+
+	if err := users.CreateUser(ctx, models.User{ID: ownerID, Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser(owner) error = %v", err)
+	}
+	if err := users.CreateUser(ctx, models.User{ID: otherID, Name: "Other", Username: "other", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser(other) error = %v", err)
+	}
+
+	list := models.List{
+		ID:         uuid.New(),
+		UserID:     ownerID,
+		Title:      "List",
+		Visibility: models.ListVisibilityPublic,
+		Slug:       "12345678901234567890123456789012",
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+
+	price := int64(100)
+	currency := "USD"
+	wish := models.Wish{
+		ID:        uuid.New(),
+		ListID:    list.ID,
+		Title:     "Wish",
+		Price:     &price,
+		Currency:  &currency,
+		Quantity:  1,
+		Status:    models.WishStatusOpen,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := wishes.CreateWish(ctx, wish); err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+
+	got, err := wishes.GetWishByID(ctx, wish.ID)
+	if err != nil || got.Title != "Wish" {
+		t.Fatalf("GetWishByID() error=%v wish=%+v", err, got)
+	}
+
+	listWishes, err := wishes.GetWishesByListID(ctx, list.ID, models.WishFilter{})
+	if err != nil || len(listWishes) != 1 {
+		t.Fatalf("GetWishesByListID() error=%v len=%d", err, len(listWishes))
+	}
+
+	newTitle := "Updated"
+	if err = wishes.UpdateWishByID(ctx, wish.ID, models.UpdateWishRequest{Title: &newTitle}); err != nil {
+		t.Fatalf("UpdateWishByID() error = %v", err)
+	}
+
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: otherID, Units: 1}); err != nil {
+		t.Fatalf("ReserveWish() error = %v", err)
+	}
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: ownerID, Units: 1}); err == nil {
+		t.Fatal("expected reserve error on already reserved")
+	}
+
+	if err = wishes.ReleaseWish(ctx, wish.ID, ownerID, 0); err == nil {
+		t.Fatal("expected release error for wrong user")
+	}
+	if err = wishes.ReleaseWish(ctx, wish.ID, otherID, 0); err != nil {
+		t.Fatalf("ReleaseWish() error = %v", err)
+	}
+
+	if err = wishes.DeleteWishByID(ctx, wish.ID); err != nil {
+		t.Fatalf("DeleteWishByID() error = %v", err)
+	}
+	if _, err = wishes.GetWishByID(ctx, wish.ID); err == nil {
+		t.Fatal("expected not found after delete")
+	}
+}
+
+func TestWishContributionStorage_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+	contributions := NewWishContributionStorage(pool)
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	friendID := uuid.New()
+	for _, user := range []models.User{
+		{ID: ownerID, Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: friendID, Name: "Friend", Username: "friend", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	} {
+		if err := users.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser(%s) error = %v", user.Username, err)
+		}
+	}
+
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "List", Visibility: models.ListVisibilityPublic, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	price := int64(1000)
+	wish := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Bike", Price: &price, Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := wishes.CreateWish(ctx, wish); err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+
+	contribution := models.WishContribution{WishID: wish.ID, UserID: friendID, Amount: 300, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := contributions.SaveContribution(ctx, contribution); err != nil {
+		t.Fatalf("SaveContribution() error = %v", err)
+	}
+	contribution.Amount = 400
+	if err := contributions.SaveContribution(ctx, contribution); err != nil {
+		t.Fatalf("SaveContribution() replace error = %v", err)
+	}
+
+	got, err := wishes.GetWishByID(ctx, wish.ID)
+	if err != nil || got.Contributed != 400 {
+		t.Fatalf("GetWishByID() error=%v contributed=%d, want 400", err, got.Contributed)
+	}
+
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: ownerID, Units: 1}); err == nil {
+		t.Fatal("expected reserve error on chipped in wish")
+	}
+
+	contribution.Amount = 1001
+	if err = contributions.SaveContribution(ctx, contribution); err == nil {
+		t.Fatal("expected error on contribution above price")
+	}
+
+	if err = contributions.DeleteContribution(ctx, wish.ID, friendID); err != nil {
+		t.Fatalf("DeleteContribution() error = %v", err)
+	}
+	if err = contributions.DeleteContribution(ctx, wish.ID, friendID); err == nil {
+		t.Fatal("expected not found on second delete")
+	}
+}
+
+func TestWishStorage_ReserveUnits_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	if err := users.CreateUser(ctx, models.User{ID: ownerID, Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser(owner) error = %v", err)
+	}
+	guestIDs := make([]uuid.UUID, 8)
+	for i := range guestIDs {
+		guestIDs[i] = uuid.New()
+		if err := users.CreateUser(ctx, models.User{ID: guestIDs[i], Name: "Guest", Username: fmt.Sprintf("guest%d", i), Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+			t.Fatalf("CreateUser(guest) error = %v", err)
+		}
+	}
+
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "List", Visibility: models.ListVisibilityPublic, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	wish := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Mugs", Quantity: 4, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := wishes.CreateWish(ctx, wish); err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, guestID := range guestIDs {
+		wg.Go(func() {
+			_ = wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: guestID, Units: 1})
+		})
+	}
+	wg.Wait()
+
+	got, err := wishes.GetWishByID(ctx, wish.ID)
+	if err != nil {
+		t.Fatalf("GetWishByID() error = %v", err)
+	}
+	if got.ReservedUnits() != 4 || got.RemainingUnits() != 0 {
+		t.Fatalf("reserved=%d remaining=%d, want 4 and 0", got.ReservedUnits(), got.RemainingUnits())
+	}
+
+	holder := got.Reservations[0].UserID
+	if err = wishes.ReleaseWish(ctx, wish.ID, holder, 0); err != nil {
+		t.Fatalf("ReleaseWish() error = %v", err)
+	}
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: holder, Units: 2}); err == nil {
+		t.Fatal("expected reserve error when asking for more units than left")
+	}
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: holder, Units: 1}); err != nil {
+		t.Fatalf("ReserveWish() error = %v", err)
+	}
+
+	if err = wishes.UpdateWishByID(ctx, wish.ID, models.UpdateWishRequest{Quantity: new(3)}); err == nil {
+		t.Fatal("expected update error when the quantity drops below the reserved units")
+	}
+	if err = wishes.UpdateWishByID(ctx, wish.ID, models.UpdateWishRequest{Quantity: new(5)}); err != nil {
+		t.Fatalf("UpdateWishByID() error = %v", err)
+	}
+}
+
+func TestWishStorage_ReservationExpiry_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+
+	ctx := context.Background()
+	ownerID, soonID, lateID := uuid.New(), uuid.New(), uuid.New()
+	for i, id := range []uuid.UUID{ownerID, soonID, lateID} {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if err := users.CreateUser(ctx, models.User{ID: id, Name: "User", Username: fmt.Sprintf("user%d", i), Email: &email, Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+	}
+
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "List", Visibility: models.ListVisibilityPublic, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	wish := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Mugs", Quantity: 2, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := wishes.CreateWish(ctx, wish); err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+
+	if err := wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: soonID, Units: 1, ReservedUntil: new(time.Now().Add(time.Hour))}); err != nil {
+		t.Fatalf("ReserveWish(soon) error = %v", err)
+	}
+	if err := wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: lateID, Units: 1, ReservedUntil: new(time.Now().Add(72 * time.Hour))}); err != nil {
+		t.Fatalf("ReserveWish(late) error = %v", err)
+	}
+
+	toRemind, err := wishes.GetReservationsToRemind(ctx, time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetReservationsToRemind() error = %v", err)
+	}
+	if len(toRemind) != 1 || toRemind[0].UserID != soonID || toRemind[0].WishTitle != "Mugs" {
+		t.Fatalf("GetReservationsToRemind() = %+v, want only the soon reservation", toRemind)
+	}
+	if err = wishes.MarkReservationReminded(ctx, wish.ID, soonID); err != nil {
+		t.Fatalf("MarkReservationReminded() error = %v", err)
+	}
+	if toRemind, err = wishes.GetReservationsToRemind(ctx, time.Now().Add(24*time.Hour)); err != nil || len(toRemind) != 0 {
+		t.Fatalf("GetReservationsToRemind() after reminder = %v, %v, want none", toRemind, err)
+	}
+
+	held, err := wishes.GetReservationsByUserID(ctx, soonID)
+	if err != nil {
+		t.Fatalf("GetReservationsByUserID() error = %v", err)
+	}
+	if len(held) != 1 || held[0].WishTitle != "Mugs" || held[0].ListID != list.ID || held[0].ListTitle != "List" {
+		t.Fatalf("GetReservationsByUserID() = %+v", held)
+	}
+
+	if _, err = pool.Exec(ctx, "UPDATE wish_reservations SET reserved_until = now() - interval '1 minute' WHERE user_id = $1", soonID); err != nil {
+		t.Fatalf("expire reservation: %v", err)
+	}
+	if held, err = wishes.GetReservationsByUserID(ctx, soonID); err != nil || len(held) != 0 {
+		t.Fatalf("GetReservationsByUserID() after expiry = %v, %v, want none", held, err)
+	}
+	got, err := wishes.GetWishByID(ctx, wish.ID)
+	if err != nil {
+		t.Fatalf("GetWishByID() error = %v", err)
+	}
+	if got.ReservedUnits() != 1 {
+		t.Fatalf("reserved units = %d, want expired reservation to be ignored", got.ReservedUnits())
+	}
+	released, err := wishes.ReleaseExpiredReservations(ctx)
+	if err != nil {
+		t.Fatalf("ReleaseExpiredReservations() error = %v", err)
+	}
+	if released != 1 {
+		t.Fatalf("released = %d, want 1", released)
+	}
+
+	// More units with a deadline must not put one on units reserved without
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: soonID, Units: 1}); err != nil {
+		t.Fatalf("ReserveWish(open-ended) error = %v", err)
+	}
+	if err = wishes.UpdateWishByID(ctx, wish.ID, models.UpdateWishRequest{Quantity: new(3)}); err != nil {
+		t.Fatalf("UpdateWishByID() error = %v", err)
+	}
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: soonID, Units: 1, ReservedUntil: new(time.Now().Add(time.Hour))}); err != nil {
+		t.Fatalf("ReserveWish(with deadline) error = %v", err)
+	}
+	if got, err = wishes.GetWishByID(ctx, wish.ID); err != nil {
+		t.Fatalf("GetWishByID() error = %v", err)
+	}
+	if r := got.ReservationOf(&soonID); r == nil || r.Units != 2 || r.ReservedUntil != nil {
+		t.Fatalf("reservation = %+v, want 2 units kept without a deadline", r)
+	}
+}
+
+func TestGuestReservationStorage_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+	guests := NewGuestReservationStorage(pool)
+
+	ctx := context.Background()
+	ownerID, friendID := uuid.New(), uuid.New()
+	for i, id := range []uuid.UUID{ownerID, friendID} {
+		if err := users.CreateUser(ctx, models.User{ID: id, Name: "User", Username: fmt.Sprintf("user%d", i), Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+	}
+
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "Birthday", Visibility: models.ListVisibilityLinkOnly, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	wish := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Mugs", Quantity: 3, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := wishes.CreateWish(ctx, wish); err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+
+	newGuest := func(token string, units int) models.GuestReservation {
+		return models.GuestReservation{WishID: wish.ID, Name: "Aunt Mary", Email: "mary@example.com", Token: token, Units: units, ReservedUntil: new(time.Now().Add(time.Hour)), CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	}
+	if err := wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: friendID, Units: 1}); err != nil {
+		t.Fatalf("ReserveWish() error = %v", err)
+	}
+	limits := models.GuestReservationLimits{PerList: 10, PerEmail: 10}
+	if err := guests.CreateGuestReservation(ctx, newGuest("first", 1), list.ID, limits); err != nil {
+		t.Fatalf("CreateGuestReservation(first) error = %v", err)
+	}
+	if err := guests.CreateGuestReservation(ctx, newGuest("second", 1), list.ID, limits); err != nil {
+		t.Fatalf("CreateGuestReservation(second) error = %v", err)
+	}
+	if err := guests.CreateGuestReservation(ctx, newGuest("third", 1), list.ID, limits); err == nil {
+		t.Fatal("expected guest reserve error when all units are taken")
+	}
+	err := guests.CreateGuestReservation(ctx, newGuest("third", 1), list.ID, models.GuestReservationLimits{PerEmail: 2})
+	if _, ok := errors.AsType[svcErr.ValidationError](err); !ok {
+		t.Fatalf("CreateGuestReservation() with two pending for the email error = %v, want ValidationError", err)
+	}
+	other := newGuest("third", 1)
+	other.Email = "MARY@example.com"
+	if _, ok := errors.AsType[svcErr.ValidationError](guests.CreateGuestReservation(ctx, other, list.ID, models.GuestReservationLimits{PerEmail: 2})); !ok {
+		t.Fatal("expected the email limit to ignore case")
+	}
+	other.Email = "bob@example.com"
+	err = guests.CreateGuestReservation(ctx, other, list.ID, models.GuestReservationLimits{PerList: 2, PerEmail: 2})
+	if _, ok := errors.AsType[svcErr.TooManyRequestsError](err); !ok {
+		t.Fatalf("CreateGuestReservation() with two pending on the list error = %v, want TooManyRequestsError", err)
+	}
+	if err := wishes.ReserveWish(ctx, models.WishReservation{WishID: wish.ID, UserID: ownerID, Units: 1}); err == nil {
+		t.Fatal("expected reserve error when guests hold the remaining units")
+	}
+
+	got, err := wishes.GetWishByID(ctx, wish.ID)
+	if err != nil {
+		t.Fatalf("GetWishByID() error = %v", err)
+	}
+	var guestHeld int
+	for _, r := range got.Reservations {
+		if r.Guest {
+			guestHeld++
+			if r.UserID != uuid.Nil {
+				t.Fatalf("guest reservation with user %s", r.UserID)
+			}
+		}
+	}
+	if got.ReservedUnits() != 3 || got.Status != models.WishStatusReserved || guestHeld != 2 || got.ReservationOf(&friendID) == nil {
+		t.Fatalf("wish = %+v, want the friend and two guests holding it", got)
+	}
+
+	details, err := guests.GetGuestReservation(ctx, "first")
+	if err != nil {
+		t.Fatalf("GetGuestReservation() error = %v", err)
+	}
+	if details.Name != "Aunt Mary" || details.WishTitle != "Mugs" || details.ListSlug != list.Slug || details.WishStatus != models.WishStatusOpen || details.ConfirmedAt != nil {
+		t.Fatalf("GetGuestReservation() = %+v", details)
+	}
+	if err = guests.ConfirmGuestReservation(ctx, "first"); err != nil {
+		t.Fatalf("ConfirmGuestReservation() error = %v", err)
+	}
+	if details, err = guests.GetGuestReservation(ctx, "first"); err != nil || details.ConfirmedAt == nil || details.ReservedUntil != nil {
+		t.Fatalf("GetGuestReservation() after confirm = %+v, %v, want it kept without a deadline", details, err)
+	}
+
+	if _, err = pool.Exec(ctx, "UPDATE wish_reservations SET reserved_until = now() - interval '1 minute' WHERE guest_token = 'second'"); err != nil {
+		t.Fatalf("expire guest reservation: %v", err)
+	}
+	if _, err = guests.GetGuestReservation(ctx, "second"); err == nil {
+		t.Fatal("expected not found for an unconfirmed reservation past its deadline")
+	}
+	if err = guests.ConfirmGuestReservation(ctx, "second"); err == nil {
+		t.Fatal("expected confirm error for an expired reservation")
+	}
+	if released, err := wishes.ReleaseExpiredReservations(ctx); err != nil || released != 1 {
+		t.Fatalf("ReleaseExpiredReservations() = %d, %v, want the expired guest reservation", released, err)
+	}
+
+	if err = guests.DeleteGuestReservation(ctx, "first"); err != nil {
+		t.Fatalf("DeleteGuestReservation() error = %v", err)
+	}
+	if err = guests.DeleteGuestReservation(ctx, "first"); err == nil {
+		t.Fatal("expected not found when releasing twice")
+	}
+	if got, err = wishes.GetWishByID(ctx, wish.ID); err != nil || got.ReservedUnits() != 1 {
+		t.Fatalf("GetWishByID() after release = %d units, %v, want only the friend's", got.ReservedUnits(), err)
+	}
+}
+
+func TestWishStorage_Status_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+
+	ctx := context.Background()
+	ownerID, friendID := uuid.New(), uuid.New()
+	if err := users.CreateUser(ctx, models.User{ID: ownerID, Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser(owner) error = %v", err)
+	}
+	if err := users.CreateUser(ctx, models.User{ID: friendID, Name: "Friend", Username: "friend", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser(friend) error = %v", err)
+	}
+
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "List", Visibility: models.ListVisibilityPublic, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	open := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Open", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	gift := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Gift", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, wish := range []models.Wish{open, gift} {
+		if err := wishes.CreateWish(ctx, wish); err != nil {
+			t.Fatalf("CreateWish() error = %v", err)
+		}
+	}
+
+	if err := wishes.ReserveWish(ctx, models.WishReservation{WishID: gift.ID, UserID: friendID, Units: 1, ReservedUntil: new(time.Now().Add(time.Hour))}); err != nil {
+		t.Fatalf("ReserveWish() error = %v", err)
+	}
+	got, err := wishes.GetWishByID(ctx, gift.ID)
+	if err != nil || got.Status != models.WishStatusReserved {
+		t.Fatalf("GetWishByID() status = %s, err = %v, want reserved", got.Status, err)
+	}
+
+	if err = wishes.UpdateWishStatus(ctx, gift.ID, models.WishStatusReserved, models.WishStatusPurchased); err != nil {
+		t.Fatalf("UpdateWishStatus(purchased) error = %v", err)
+	}
+	if err = wishes.UpdateWishStatus(ctx, gift.ID, models.WishStatusReserved, models.WishStatusArchived); err == nil {
+		t.Fatal("expected conflict when status has already changed")
+	}
+	if got, err = wishes.GetWishByID(ctx, gift.ID); err != nil || got.Status != models.WishStatusPurchased || got.Reservations[0].ReservedUntil != nil {
+		t.Fatalf("GetWishByID() = %+v, err = %v, want purchased without deadline", got, err)
+	}
+	if err = wishes.UpdateWishStatus(ctx, open.ID, models.WishStatusOpen, models.WishStatusArchived); err != nil {
+		t.Fatalf("UpdateWishStatus(archived) error = %v", err)
+	}
+
+	for status, wantID := range map[models.WishStatus]uuid.UUID{models.WishStatusPurchased: gift.ID, models.WishStatusArchived: open.ID} {
+		filtered, err := wishes.GetWishesByListID(ctx, list.ID, models.WishFilter{Status: &status})
+		if err != nil || len(filtered) != 1 || filtered[0].ID != wantID {
+			t.Fatalf("GetWishesByListID(%s) = %v, err = %v", status, filtered, err)
+		}
+	}
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: open.ID, UserID: friendID, Units: 1}); err == nil {
+		t.Fatal("expected reserve error on archived wish")
+	}
+
+	ownerLists, err := lists.GetListsByUserID(ctx, ownerID)
+	if err != nil || len(ownerLists) != 1 || ownerLists[0].WishesCount != 1 {
+		t.Fatalf("GetListsByUserID() = %+v, err = %v, want one list with one counted wish", ownerLists, err)
+	}
+}
+
+func TestWishStorage_Order_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	if err := users.CreateUser(ctx, models.User{ID: ownerID, Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "List", Visibility: models.ListVisibilityPublic, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+
+	cheap := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Cheap", Price: new(int64(100)), Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	pricey := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Pricey", Price: new(int64(900)), Quantity: 1, Status: models.WishStatusOpen, Priority: models.WishPriorityMustHave, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	free := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Free", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, wish := range []models.Wish{cheap, pricey, free} {
+		if err := wishes.CreateWish(ctx, wish); err != nil {
+			t.Fatalf("CreateWish() error = %v", err)
+		}
+	}
+
+	order := func(filter models.WishFilter) []uuid.UUID {
+		t.Helper()
+		got, err := wishes.GetWishesByListID(ctx, list.ID, filter)
+		if err != nil {
+			t.Fatalf("GetWishesByListID(%+v) error = %v", filter, err)
+		}
+		ids := make([]uuid.UUID, len(got))
+		for i, wish := range got {
+			ids[i] = wish.ID
+		}
+		return ids
+	}
+
+	if got := order(models.WishFilter{}); !slices.Equal(got, []uuid.UUID{cheap.ID, pricey.ID, free.ID}) {
+		t.Fatalf("default order = %v, want the order of creation", got)
+	}
+	if got := order(models.WishFilter{Sort: models.WishSortPrice, Desc: true}); !slices.Equal(got, []uuid.UUID{pricey.ID, cheap.ID, free.ID}) {
+		t.Fatalf("price order = %v, want unpriced last", got)
+	}
+	if got := order(models.WishFilter{Sort: models.WishSortPriority}); got[0] != pricey.ID {
+		t.Fatalf("priority order = %v, want must-have first", got)
+	}
+
+	if err := wishes.ReorderWishes(ctx, list.ID, []uuid.UUID{free.ID, cheap.ID}); err == nil {
+		t.Fatal("expected conflict when a wish is missing from the order")
+	}
+	if err := wishes.ReorderWishes(ctx, list.ID, []uuid.UUID{free.ID, pricey.ID, cheap.ID}); err != nil {
+		t.Fatalf("ReorderWishes() error = %v", err)
+	}
+	if got := order(models.WishFilter{}); !slices.Equal(got, []uuid.UUID{free.ID, pricey.ID, cheap.ID}) {
+		t.Fatalf("order after reorder = %v", got)
+	}
+}
+
+func TestWishStorage_CreateWishes_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	if err := users.CreateUser(ctx, models.User{ID: ownerID, Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "List", Visibility: models.ListVisibilityPublic, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+
+	existing := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Existing", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := wishes.CreateWish(ctx, existing); err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+
+	kettle := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Kettle", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	mug := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Mug", Quantity: 2, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := wishes.CreateWishes(ctx, []models.Wish{kettle, mug}); err != nil {
+		t.Fatalf("CreateWishes() error = %v", err)
+	}
+
+	got, err := wishes.GetWishesByListID(ctx, list.ID, models.WishFilter{})
+	if err != nil {
+		t.Fatalf("GetWishesByListID() error = %v", err)
+	}
+	if len(got) != 3 || got[1].ID != kettle.ID || got[2].ID != mug.ID || got[2].Position != 2 {
+		t.Fatalf("wishes after import = %+v, want them appended in order", got)
+	}
+
+	// A failing row rolls back the whole import
+	broken := models.Wish{ID: kettle.ID, ListID: list.ID, Title: "Duplicate", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	fresh := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Fresh", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err = wishes.CreateWishes(ctx, []models.Wish{fresh, broken}); err == nil {
+		t.Fatal("CreateWishes() error = nil, want duplicate key error")
+	}
+	if _, err = wishes.GetWishByID(ctx, fresh.ID); err == nil {
+		t.Fatal("wish of a failed import was kept")
+	}
+}
+
+func TestListStorage_CreateListWithWishes_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	if err := users.CreateUser(ctx, models.User{ID: ownerID, Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "Copy", Visibility: models.ListVisibilityPrivate, Slug: "d0p1icate00000000000000000000001", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	kettle := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Kettle", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	mug := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Mug", Quantity: 2, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateListWithWishes(ctx, list, []models.Wish{kettle, mug}); err != nil {
+		t.Fatalf("CreateListWithWishes() error = %v", err)
+	}
+
+	got, err := wishes.GetWishesByListID(ctx, list.ID, models.WishFilter{})
+	if err != nil || len(got) != 2 || got[0].ID != kettle.ID || got[1].ID != mug.ID {
+		t.Fatalf("GetWishesByListID() error=%v wishes=%+v, want both in order", err, got)
+	}
+
+	// A failing wish rolls back the list too
+	broken := models.List{ID: uuid.New(), UserID: ownerID, Title: "Broken", Visibility: models.ListVisibilityPrivate, Slug: "d0p1icate00000000000000000000002", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	duplicate := models.Wish{ID: kettle.ID, ListID: broken.ID, Title: "Kettle", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err = lists.CreateListWithWishes(ctx, broken, []models.Wish{duplicate}); err == nil {
+		t.Fatal("CreateListWithWishes() error = nil, want duplicate key error")
+	}
+	if _, err = lists.GetListByID(ctx, broken.ID); err == nil {
+		t.Fatal("list of a failed copy was kept")
+	}
+}
+
+func TestWishPriceStorage_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+	prices := NewWishPriceStorage(pool)
+
+	ctx := context.Background()
+	ownerID, friendID := uuid.New(), uuid.New()
+	for i, id := range []uuid.UUID{ownerID, friendID} {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if err := users.CreateUser(ctx, models.User{ID: id, Name: "User", Username: fmt.Sprintf("user%d", i), Email: &email, Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+	}
+
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "List", Visibility: models.ListVisibilityPublic, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	tracked := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Kettle", Link: new("https://shop.example.com/kettle"), Price: new(int64(100)), Currency: new("EUR"), Quantity: 1, Status: models.WishStatusOpen, PriceAlert: new(int64(90)), CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	untracked := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Hug", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, wish := range []models.Wish{tracked, untracked} {
+		if err := wishes.CreateWish(ctx, wish); err != nil {
+			t.Fatalf("CreateWish() error = %v", err)
+		}
+	}
+
+	toTrack, err := prices.GetWishesToTrack(ctx, time.Now(), 10)
+	if err != nil || len(toTrack) != 1 || toTrack[0].ID != tracked.ID || *toTrack[0].PriceAlert != 90 {
+		t.Fatalf("GetWishesToTrack() = %+v, err = %v, want only the wish with a link", toTrack, err)
+	}
+
+	if err = prices.RecordWishPrice(ctx, models.WishPrice{ID: uuid.New(), WishID: tracked.ID, Price: 80, Currency: new("EUR"), RecordedAt: time.Now()}); err != nil {
+		t.Fatalf("RecordWishPrice() error = %v", err)
+	}
+	if got, err := wishes.GetWishByID(ctx, tracked.ID); err != nil || *got.Price != 100 || got.CurrentPrice == nil || *got.CurrentPrice != 80 {
+		t.Fatalf("GetWishByID() price = %v, current = %v, err = %v, want 100 kept and 80 seen", got.Price, got.CurrentPrice, err)
+	}
+	if toTrack, err = prices.GetWishesToTrack(ctx, time.Now().Add(-time.Hour), 10); err != nil || len(toTrack) != 0 {
+		t.Fatalf("GetWishesToTrack() after check = %v, %v, want none", toTrack, err)
+	}
+	checkedAt := time.Now().Add(time.Minute)
+	if err = prices.RecordWishPrice(ctx, models.WishPrice{ID: uuid.New(), WishID: tracked.ID, Price: 80, Currency: new("EUR"), RecordedAt: checkedAt}); err != nil {
+		t.Fatalf("RecordWishPrice(same) error = %v", err)
+	}
+	if toTrack, err = prices.GetWishesToTrack(ctx, checkedAt.Add(-time.Second), 10); err != nil || len(toTrack) != 0 {
+		t.Fatalf("GetWishesToTrack() after the same price = %v, %v, want it checked again", toTrack, err)
+	}
+	history, err := prices.GetPriceHistory(ctx, tracked.ID)
+	if err != nil || len(history) != 1 || history[0].Price != 80 {
+		t.Fatalf("GetPriceHistory() = %+v, err = %v, want one entry for an unchanged price", history, err)
+	}
+
+	if err = wishes.ReserveWish(ctx, models.WishReservation{WishID: tracked.ID, UserID: friendID, Units: 1}); err != nil {
+		t.Fatalf("ReserveWish() error = %v", err)
+	}
+	recipients, err := prices.GetPriceDropRecipients(ctx, tracked.ID)
+	if err != nil || len(recipients) != 2 {
+		t.Fatalf("GetPriceDropRecipients() = %+v, err = %v, want owner and reserver", recipients, err)
+	}
+	for _, r := range recipients {
+		if r.IsReserver != (r.UserID == friendID) {
+			t.Fatalf("recipient %+v has wrong IsReserver", r)
+		}
+	}
+}
+
+func TestObjectStorage_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+	objects := NewObjectStorage(pool)
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	email := "owner@example.com"
+	if err := users.CreateUser(ctx, models.User{ID: ownerID, Name: "Owner", Username: "owner", Email: &email, Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "List", Visibility: models.ListVisibilityPublic, Slug: "12345678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+
+	shared := "http://minio/wishlist/wishes/a/1/original"
+	first := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "First", Image: &shared, Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	second := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Second", Image: &shared, Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, wish := range []models.Wish{first, second} {
+		if err := wishes.CreateWish(ctx, wish); err != nil {
+			t.Fatalf("CreateWish() error = %v", err)
+		}
+	}
+	owner := models.ObjectOwner{UserID: &ownerID, ListID: &list.ID, WishID: &first.ID}
+	for _, object := range []models.StoredObject{
+		{ObjectName: "wishes/a/1", URL: shared, Owner: owner, CreatedAt: time.Now()},
+		{ObjectName: "wishes/a/0", URL: "http://minio/wishlist/wishes/a/0/original", Owner: owner, CreatedAt: time.Now()},
+	} {
+		if err := objects.RecordObject(ctx, object); err != nil {
+			t.Fatalf("RecordObject() error = %v", err)
+		}
+	}
+
+	urls, err := objects.GetReferencedURLs(ctx)
+	if err != nil || len(urls) != 1 || urls[0] != shared {
+		t.Fatalf("GetReferencedURLs() = %v, err = %v, want the shared image once", urls, err)
+	}
+
+	// The old image is unused, the shared one is still on the second wish after the first is gone
+	if err = wishes.DeleteWishByID(ctx, first.ID); err != nil {
+		t.Fatalf("DeleteWishByID() error = %v", err)
+	}
+	released, err := objects.ReleaseObjects(ctx, models.ObjectOwner{WishID: &first.ID})
+	if err != nil || len(released) != 1 || released[0].ObjectName != "wishes/a/0" {
+		t.Fatalf("ReleaseObjects() = %+v, err = %v, want only the unused image", released, err)
+	}
+
+	if err = users.DeleteUserByID(ctx, ownerID); err != nil {
+		t.Fatalf("DeleteUserByID() error = %v", err)
+	}
+	released, err = objects.ReleaseObjects(ctx, models.ObjectOwner{UserID: &ownerID})
+	if err != nil || len(released) != 1 || released[0].ObjectName != "wishes/a/1" {
+		t.Fatalf("ReleaseObjects() after account deletion = %+v, err = %v", released, err)
+	}
+
+	if err = objects.DeleteObjectRecords(ctx, []string{"wishes/a/1"}); err != nil {
+		t.Fatalf("DeleteObjectRecords() error = %v", err)
+	}
+}
+
+func TestTrashStorage_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+	trash := NewTrashStorage(pool)
+
+	ctx := context.Background()
+	ownerID, strangerID := uuid.New(), uuid.New()
+	for _, user := range []models.User{
+		{ID: ownerID, Name: "Owner", Username: "owner", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: strangerID, Name: "Stranger", Username: "stranger", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	} {
+		if err := users.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+	}
+	list := models.List{ID: uuid.New(), UserID: ownerID, Title: "List", Visibility: models.ListVisibilityPublic, Slug: "trash5678901234567890123456789012", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	kept := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Kept", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	deleted := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Deleted", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, wish := range []models.Wish{kept, deleted} {
+		if err := wishes.CreateWish(ctx, wish); err != nil {
+			t.Fatalf("CreateWish() error = %v", err)
+		}
+	}
+
+	if err := wishes.DeleteWishByID(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteWishByID() error = %v", err)
+	}
+	if got, err := wishes.GetWishesByListID(ctx, list.ID, models.WishFilter{}); err != nil || len(got) != 1 || got[0].ID != kept.ID {
+		t.Fatalf("GetWishesByListID() = %+v, err = %v, want the deleted wish hidden", got, err)
+	}
+	items, err := trash.GetTrashItems(ctx, ownerID)
+	if err != nil || len(items) != 1 || items[0].Type != models.TrashItemWish || items[0].Role != models.ListRoleOwner || *items[0].ListID != list.ID {
+		t.Fatalf("GetTrashItems() = %+v, err = %v, want the deleted wish", items, err)
+	}
+	if items, err = trash.GetTrashItems(ctx, strangerID); err != nil || len(items) != 0 {
+		t.Fatalf("GetTrashItems() of a stranger = %+v, err = %v", items, err)
+	}
+	added := models.Wish{ID: uuid.New(), ListID: list.ID, Title: "Added", Quantity: 1, Status: models.WishStatusOpen, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err = wishes.CreateWish(ctx, added); err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+	if got, err := wishes.GetWishByID(ctx, added.ID); err != nil || got.Position != 1 {
+		t.Fatalf("GetWishByID() = %+v, err = %v, want it after the kept wish only", got, err)
+	}
+
+	// Deleting the list hides the wish it took along, it is restored with the list
+	if err = lists.DeleteListByID(ctx, list.ID); err != nil {
+		t.Fatalf("DeleteListByID() error = %v", err)
+	}
+	if got, err := lists.GetListsByUserID(ctx, ownerID); err != nil || len(got) != 0 {
+		t.Fatalf("GetListsByUserID() = %+v, err = %v, want the deleted list hidden", got, err)
+	}
+	if items, err = trash.GetTrashItems(ctx, ownerID); err != nil || len(items) != 1 || items[0].Type != models.TrashItemList {
+		t.Fatalf("GetTrashItems() = %+v, err = %v, want only the list", items, err)
+	}
+	if _, err = trash.GetTrashItem(ctx, deleted.ID, ownerID); err == nil {
+		t.Fatal("GetTrashItem() of a wish in a deleted list error = nil, want not found")
+	}
+	if err = trash.RestoreListByID(ctx, list.ID); err != nil {
+		t.Fatalf("RestoreListByID() error = %v", err)
+	}
+	if _, err = wishes.GetWishByID(ctx, kept.ID); err != nil {
+		t.Fatalf("GetWishByID() after restoring the list error = %v", err)
+	}
+
+	if err = trash.RestoreWishByID(ctx, deleted.ID); err != nil {
+		t.Fatalf("RestoreWishByID() error = %v", err)
+	}
+	if restored, err := wishes.GetWishByID(ctx, deleted.ID); err != nil || restored.Position != 2 {
+		t.Fatalf("GetWishByID() after restore = %+v, err = %v, want it at the end", restored, err)
+	}
+	if err = trash.RestoreWishByID(ctx, deleted.ID); err == nil {
+		t.Fatal("RestoreWishByID() of a wish not in the trash error = nil, want not found")
+	}
+
+	if err = wishes.DeleteWishByID(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteWishByID() error = %v", err)
+	}
+	if err = lists.DeleteListByID(ctx, list.ID); err != nil {
+		t.Fatalf("DeleteListByID() error = %v", err)
+	}
+	report, err := trash.PurgeTrash(ctx, time.Now().Add(-time.Hour))
+	if err != nil || len(report.Lists) != 0 || len(report.Wishes) != 0 {
+		t.Fatalf("PurgeTrash() before the retention = %+v, err = %v, want nothing", report, err)
+	}
+	report, err = trash.PurgeTrash(ctx, time.Now().Add(time.Hour))
+	if err != nil || len(report.Lists) != 1 || report.Lists[0] != list.ID || len(report.Wishes) != 0 {
+		t.Fatalf("PurgeTrash() = %+v, err = %v, want the list with its wishes", report, err)
+	}
+	if items, err = trash.GetTrashItems(ctx, ownerID); err != nil || len(items) != 0 {
+		t.Fatalf("GetTrashItems() after purge = %+v, err = %v", items, err)
+	}
+}
+
+func TestObjectStorage_ConvertStoredURLs_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	objects := NewObjectStorage(pool)
+
+	ctx := context.Background()
+	base := "http://minio:9000/wishlist"
+	legacyID, convertedID, externalID := uuid.New(), uuid.New(), uuid.New()
+	for i, user := range []struct {
+		id     uuid.UUID
+		avatar string
+	}{
+		{legacyID, base + "/avatars/a/1/original"},
+		{convertedID, "avatars/b/1/original"},
+		{externalID, "https://gravatar.example/c.png"},
+	} {
+		email := fmt.Sprintf("convert%d@example.com", i)
+		if err := users.CreateUser(ctx, models.User{ID: user.id, Avatar: &user.avatar, Name: "User", Username: fmt.Sprintf("convert%d", i), Email: &email, Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+	}
+
+	converted, err := objects.ConvertStoredURLs(ctx, base, func(url string) string { return strings.TrimPrefix(url, base+"/") })
+	if err != nil || converted != 1 {
+		t.Fatalf("ConvertStoredURLs() = %d, err = %v, want one row", converted, err)
+	}
+	for id, want := range map[uuid.UUID]string{legacyID: "avatars/a/1/original", convertedID: "avatars/b/1/original", externalID: "https://gravatar.example/c.png"} {
+		if user, err := users.GetUserByID(ctx, id); err != nil || user.Avatar == nil || *user.Avatar != want {
+			t.Fatalf("avatar = %v, err = %v, want %s", user.Avatar, err, want)
+		}
+	}
+
+	if _, err = pool.Exec(ctx, `UPDATE users SET avatar = $2 WHERE id = $1`, convertedID, base+"/avatars/b/1/original"); err != nil {
+		t.Fatalf("restore legacy avatar: %v", err)
+	}
+	if converted, err = objects.ConvertStoredURLs(ctx, base, func(url string) string { return strings.TrimPrefix(url, base+"/") }); err != nil || converted != 0 {
+		t.Fatalf("second ConvertStoredURLs() = %d, err = %v, want it skipped once it ran", converted, err)
+	}
+	if user, err := users.GetUserByID(ctx, convertedID); err != nil || *user.Avatar != base+"/avatars/b/1/original" {
+		t.Fatalf("avatar = %v, err = %v, want it untouched by the second run", user.Avatar, err)
+	}
+}
+
+func TestWishStorage_InvalidListID_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	wishes := NewWishStorage(pool)
+
+	ctx := context.Background()
+	badListID := uuid.New()
+	price := int64(10)
+	wish := models.Wish{
+		ID:        uuid.New(),
+		ListID:    badListID,
+		Title:     "Invalid",
+		Price:     &price,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := wishes.CreateWish(ctx, wish); err == nil {
+		t.Fatal("expected error on CreateWish with invalid list ID")
+	}
+}
+
+func TestCascadeDelete_Integration(t *testing.T) {
+	pool := mustPostgres(t)
+	resetDB(t, pool)
+	users := NewUserStorage(pool)
+	lists := NewListStorage(pool)
+	wishes := NewWishStorage(pool)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	if err := users.CreateUser(ctx, models.User{ID: userID, Name: "Cascade", Username: "cascade", Password: "hash", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	list := models.List{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Title:      "Cascade list",
+		Visibility: models.ListVisibilityPublic,
+		Slug:       "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := lists.CreateList(ctx, list); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+
+	wish := models.Wish{
+		ID:        uuid.New(),
+		ListID:    list.ID,
+		Title:     "Cascade wish",
+		Quantity:  1,
+		Status:    models.WishStatusOpen,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := wishes.CreateWish(ctx, wish); err != nil {
+		t.Fatalf("CreateWish() error = %v", err)
+	}
+
+	if err := users.DeleteUserByID(ctx, userID); err != nil {
+		t.Fatalf("DeleteUserByID() error = %v", err)
+	}
+	if _, err := lists.GetListByID(ctx, list.ID); err == nil {
+		t.Fatal("expected list deleted on user delete")
+	}
+	if _, err := wishes.GetWishByID(ctx, wish.ID); err == nil {
+		t.Fatal("expected wish deleted on user delete")
+	}
+}
+
+func TestTokenStorage_RedisIntegration(t *testing.T) {
+	client := mustRedis(t)
+	defer func() { _ = client.Close() }()
+
+	viper.Reset()
+	viper.Set(config.PwdResetTokenTTL, "1h")
+	viper.Set(config.EmailVerifyTokenTTL, "1h")
+	viper.Set(config.RefreshTokenTTL, "1h")
+
+	ts := NewTokenStorage(client)
+	ctx := context.Background()
+
+	if err := ts.SaveEmailVerificationToken(ctx, "token1", "user1"); err != nil {
+		t.Fatalf("SaveEmailVerificationToken() error = %v", err)
+	}
+	if val, err := ts.GetEmailVerificationToken(ctx, "token1"); err != nil || val != "user1" {
+		t.Fatalf("GetEmailVerificationToken() error=%v val=%s", err, val)
+	}
+	if err := ts.DeleteEmailVerificationToken(ctx, "token1"); err != nil {
+		t.Fatalf("DeleteEmailVerificationToken() error = %v", err)
+	}
+
+	revoked, err := ts.CheckIfAuthTokenRevoked(ctx, "tokenX")
+	if err != nil || revoked {
+		t.Fatalf("CheckIfAuthTokenRevoked() err=%v revoked=%v", err, revoked)
+	}
+	if err = ts.RevokeAuthTokens(ctx, "tokenX", time.Minute); err != nil {
+		t.Fatalf("RevokeAuthTokens() error = %v", err)
+	}
+	if revoked, err = ts.CheckIfAuthTokenRevoked(ctx, "tokenX"); err != nil || !revoked {
+		t.Fatalf("CheckIfAuthTokenRevoked() err=%v revoked=%v", err, revoked)
+	}
+
+	userID := uuid.NewString()
+	if revokedAt, err := ts.GetUserAuthTokensRevokedAt(ctx, userID); err != nil || !revokedAt.IsZero() {
+		t.Fatalf("GetUserAuthTokensRevokedAt() err=%v revokedAt=%s", err, revokedAt)
+	}
+	issuedBefore := time.Now()
+	if err = ts.RevokeUserAuthTokens(ctx, userID, issuedBefore); err != nil {
+		t.Fatalf("RevokeUserAuthTokens() error = %v", err)
+	}
+	if revokedAt, err := ts.GetUserAuthTokensRevokedAt(ctx, userID); err != nil || revokedAt.Unix() != issuedBefore.Unix() {
+		t.Fatalf("GetUserAuthTokensRevokedAt() err=%v revokedAt=%s, want %s", err, revokedAt, issuedBefore)
+	}
+
+	if err := ts.SavePasswordResetToken(ctx, "token2", "user2"); err != nil {
+		t.Fatalf("SavePasswordResetToken() error = %v", err)
+	}
+	if val, err := ts.GetPasswordResetToken(ctx, "token2"); err != nil || val != "user2" {
+		t.Fatalf("GetPasswordResetToken() error=%v val=%s", err, val)
+	}
+	if err := ts.DeletePasswordResetToken(ctx, "token2"); err != nil {
+		t.Fatalf("DeletePasswordResetToken() error = %v", err)
+	}
+
+	if err := ts.SaveDataExportToken(ctx, "token3", "exports/u/1/export.zip", time.Minute); err != nil {
+		t.Fatalf("SaveDataExportToken() error = %v", err)
+	}
+	if val, err := ts.GetDataExportToken(ctx, "token3"); err != nil || val != "exports/u/1/export.zip" {
+		t.Fatalf("GetDataExportToken() error=%v val=%s", err, val)
+	}
+
+	// A new deletion replaces the link of the earlier one, keeping the account ends both
+	if err := ts.SaveAccountDeletionToken(ctx, "token4", userID, time.Minute); err != nil {
+		t.Fatalf("SaveAccountDeletionToken() error = %v", err)
+	}
+	if err := ts.SaveAccountDeletionToken(ctx, "token5", userID, time.Minute); err != nil {
+		t.Fatalf("SaveAccountDeletionToken() error = %v", err)
+	}
+	if _, err := ts.GetAccountDeletionToken(ctx, "token4"); err == nil {
+		t.Fatal("GetAccountDeletionToken() of the replaced link error = nil, want redis.Nil")
+	}
+	if val, err := ts.GetAccountDeletionToken(ctx, "token5"); err != nil || val != userID {
+		t.Fatalf("GetAccountDeletionToken() error=%v val=%s", err, val)
+	}
+	if err := ts.DeleteAccountDeletionToken(ctx, userID); err != nil {
+		t.Fatalf("DeleteAccountDeletionToken() error = %v", err)
+	}
+	if _, err := ts.GetAccountDeletionToken(ctx, "token5"); err == nil {
+		t.Fatal("GetAccountDeletionToken() after delete error = nil, want redis.Nil")
+	}
+}
+
+func TestRateLimitStorage_RedisIntegration(t *testing.T) {
+	client := mustRedis(t)
+	defer func() { _ = client.Close() }()
+
+	rl := NewRateLimitStorage(client)
+	ctx := context.Background()
+	key := "test:" + uuid.NewString()
+	defer func() { _ = client.Del(ctx, rateLimitPrefix+key).Err() }()
+
+	for i := range 2 {
+		if ok, err := rl.Hit(ctx, key, 2, time.Hour); err != nil || !ok {
+			t.Fatalf("Hit() #%d = %v, %v, want allowed", i+1, ok, err)
+		}
+	}
+	if ok, err := rl.Hit(ctx, key, 2, time.Hour); err != nil || ok {
+		t.Fatalf("Hit() over the limit = %v, %v, want refused", ok, err)
+	}
+	if ttl := client.TTL(ctx, rateLimitPrefix+key).Val(); ttl <= 0 || ttl > time.Hour {
+		t.Fatalf("TTL = %s, want the window started by the first hit", ttl)
+	}
+}
+
+func TestMinioStorage_Integration(t *testing.T) {
+	client, bucket := mustMinio(t)
+	viper.Reset()
+	viper.Set(config.MinioBucketName, bucket)
+
+	svc := NewMinioService(client)
+
+	ctx := context.Background()
+	objectName := fmt.Sprintf("test/%s.txt", uuid.NewString())
+	payload := []byte("hello")
+
+	if err := svc.UploadObject(ctx, objectName, bytesReader(payload), int64(len(payload)), "text/plain"); err != nil {
+		t.Fatalf("UploadObject() error = %v", err)
+	}
+
+	if _, err := client.StatObject(ctx, bucket, objectName, minio.StatObjectOptions{}); err != nil {
+		t.Fatalf("StatObject() error = %v", err)
+	}
+
+	if svc.GetObjectURL(objectName) == "" {
+		t.Fatal("GetObjectURL() empty")
 	}
 	if svc.GetBaseURL() == "" {
 		t.Fatal("GetBaseURL() empty")
 	}
 
+	viper.Set(config.MinioPrivateBucket, true)
+	viper.Set(config.MinioURLTTL, "1m")
+	signed := NewMinioService(client).MediaURL(objectName)
+	if !strings.Contains(signed, "X-Amz-Signature=") {
+		t.Fatalf("MediaURL() = %s, want a presigned URL for a private bucket", signed)
+	}
+	resp, err := http.Get(signed)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET presigned URL = %v, err = %v", resp, err)
+	}
+	_ = resp.Body.Close()
+
+	copyName := fmt.Sprintf("test/%s.txt", uuid.NewString())
+	if err = svc.CopyObject(ctx, objectName, copyName); err != nil {
+		t.Fatalf("CopyObject() error = %v", err)
+	}
+	if info, err := svc.StatObject(ctx, copyName); err != nil || info.Size != int64(len(payload)) {
+		t.Fatalf("StatObject(copy) = %+v, %v", info, err)
+	}
+	if err = svc.CopyObject(ctx, "test/missing.txt", copyName); err == nil {
+		t.Fatal("CopyObject(missing) error = nil")
+	}
+	if err = svc.DeleteObject(ctx, copyName); err != nil {
+		t.Fatalf("DeleteObject(copy) error = %v", err)
+	}
+
 	if err := svc.DeleteObject(ctx, objectName); err != nil {
 		t.Fatalf("DeleteObject() error = %v", err)
 	}
@@ -15,17 +15,27 @@ const (
 	projectPrefix           = "wishlist"
 	emailVerificationPrefix = projectPrefix + ":" + "email_verification_token:"
 	revokedAuthTokensPrefix = projectPrefix + ":" + "revoked_auth_token:"
+	revokedUserTokensPrefix = projectPrefix + ":" + "revoked_user_tokens:"
 	passwordResetPrefix     = projectPrefix + ":" + "password_reset_token:"
+	dataExportPrefix        = projectPrefix + ":" + "data_export_token:"
+	accountDeletionPrefix   = projectPrefix + ":" + "account_deletion_token:"
+	accountDeletionUserKey  = projectPrefix + ":" + "account_deletion_user:" // Token sent for the user's scheduled deletion
 )
 
 type TokenStorageImpl struct {
 	client  *redis.Client
 	pwdTTL  time.Duration // Password Reset Token TTL
 	emVfTTL time.Duration // Email Verification Token TTL
+	authTTL time.Duration // Longest lived auth token, access or refresh
 }
 
 func NewTokenStorage(client *redis.Client) *TokenStorageImpl {
-	return &TokenStorageImpl{client: client, pwdTTL: viper.GetDuration(config.PwdResetTokenTTL), emVfTTL: viper.GetDuration(config.EmailVerifyTokenTTL)}
+	return &TokenStorageImpl{
+		client:  client,
+		pwdTTL:  viper.GetDuration(config.PwdResetTokenTTL),
+		emVfTTL: viper.GetDuration(config.EmailVerifyTokenTTL),
+		authTTL: max(viper.GetDuration(config.AccessTokenTTL), viper.GetDuration(config.RefreshTokenTTL)),
+	}
 }
 
 func (ts *TokenStorageImpl) SaveEmailVerificationToken(ctx context.Context, tokenID, userID string) error {
@@ -54,6 +64,24 @@ func (ts *TokenStorageImpl) RevokeAuthTokens(ctx context.Context, tokenID string
 	return ts.client.Set(ctx, revokedAuthTokensPrefix+tokenID, "ACTIVE, REVOKED", remainingTTL).Err()
 }
 
+// RevokeUserAuthTokens revokes every token of the user issued before the given time, the mark is kept until the
+// last of them would have expired anyway
+func (ts *TokenStorageImpl) RevokeUserAuthTokens(ctx context.Context, userID string, issuedBefore time.Time) error {
+	return ts.client.Set(ctx, revokedUserTokensPrefix+userID, issuedBefore.Unix(), ts.authTTL).Err()
+}
+
+// GetUserAuthTokensRevokedAt returns when all tokens of the user were last revoked, the zero time if they never were
+func (ts *TokenStorageImpl) GetUserAuthTokensRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	seconds, err := ts.client.Get(ctx, revokedUserTokensPrefix+userID).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0), nil
+}
+
 func (ts *TokenStorageImpl) SavePasswordResetToken(ctx context.Context, tokenID string, userID string) error {
 	return ts.client.Set(ctx, passwordResetPrefix+tokenID, userID, ts.pwdTTL).Err()
 }
@@ -65,3 +93,42 @@ func (ts *TokenStorageImpl) GetPasswordResetToken(ctx context.Context, tokenID s
 func (ts *TokenStorageImpl) DeletePasswordResetToken(ctx context.Context, tokenID string) error {
 	return ts.client.Del(ctx, passwordResetPrefix+tokenID).Err()
 }
+
+// SaveDataExportToken remembers which archive the download link points to, the link stops working with the TTL
+func (ts *TokenStorageImpl) SaveDataExportToken(ctx context.Context, tokenID, objectKey string, ttl time.Duration) error {
+	return ts.client.Set(ctx, dataExportPrefix+tokenID, objectKey, ttl).Err()
+}
+
+func (ts *TokenStorageImpl) GetDataExportToken(ctx context.Context, tokenID string) (string, error) {
+	return ts.client.Get(ctx, dataExportPrefix+tokenID).Result()
+}
+
+// SaveAccountDeletionToken keeps the cancel link of a scheduled deletion working until the account is deleted,
+// the link sent for an earlier deletion of the same account stops working
+func (ts *TokenStorageImpl) SaveAccountDeletionToken(ctx context.Context, tokenID, userID string, ttl time.Duration) error {
+	if err := ts.DeleteAccountDeletionToken(ctx, userID); err != nil {
+		return err
+	}
+	_, err := ts.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, accountDeletionPrefix+tokenID, userID, ttl)
+		pipe.Set(ctx, accountDeletionUserKey+userID, tokenID, ttl)
+		return nil
+	})
+	return err
+}
+
+func (ts *TokenStorageImpl) GetAccountDeletionToken(ctx context.Context, tokenID string) (string, error) {
+	return ts.client.Get(ctx, accountDeletionPrefix+tokenID).Result()
+}
+
+// DeleteAccountDeletionToken makes the cancel link sent to the user stop working, whichever way the account was kept
+func (ts *TokenStorageImpl) DeleteAccountDeletionToken(ctx context.Context, userID string) error {
+	tokenID, err := ts.client.Get(ctx, accountDeletionUserKey+userID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		return err
+	}
+	return ts.client.Del(ctx, accountDeletionPrefix+tokenID, accountDeletionUserKey+userID).Err()
+}
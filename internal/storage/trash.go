@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wishlist/internal/models"
+	"wishlist/internal/services/errors"
+)
+
+type TrashStorageImpl struct{ pool *pgxpool.Pool }
+
+func NewTrashStorage(pool *pgxpool.Pool) *TrashStorageImpl { return &TrashStorageImpl{pool: pool} }
+
+// trashQuery selects deleted lists the user manages and deleted wishes of live lists the user edits, $2 narrows it to one item
+const trashQuery = `
+	SELECT 'list' AS type, l.id, NULL::uuid AS list_id, NULL AS list_title, l.image, l.title,
+	       CASE WHEN l.user_id = $1 THEN 'owner' ELSE m.role END AS role, l.deleted_at
+	FROM lists l
+	LEFT JOIN list_members m ON m.list_id = l.id AND m.user_id = $1 AND m.accepted_at IS NOT NULL
+	WHERE l.deleted_at IS NOT NULL AND (l.user_id = $1 OR m.role = 'owner') AND ($2::uuid IS NULL OR l.id = $2)
+	UNION ALL
+	SELECT 'wish', w.id, l.id, l.title, w.image, w.title,
+	       CASE WHEN l.user_id = $1 THEN 'owner' ELSE m.role END, w.deleted_at
+	FROM wishes w
+	JOIN lists l ON l.id = w.list_id
+	LEFT JOIN list_members m ON m.list_id = l.id AND m.user_id = $1 AND m.accepted_at IS NOT NULL
+	WHERE w.deleted_at IS NOT NULL AND l.deleted_at IS NULL AND (l.user_id = $1 OR m.role IN ('owner', 'editor')) AND ($2::uuid IS NULL OR w.id = $2)
+	ORDER BY deleted_at DESC`
+
+// GetTrashItems returns what the user may restore, most recently deleted first
+func (s *TrashStorageImpl) GetTrashItems(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error) {
+	items, err := s.queryTrash(ctx, userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash of user with ID '%s': %w", userID, err)
+	}
+
+	return items, nil
+}
+
+// GetTrashItem returns one deleted list or wish, NotFoundError when it is not in the user's trash
+func (s *TrashStorageImpl) GetTrashItem(ctx context.Context, id, userID uuid.UUID) (models.TrashItem, error) {
+	items, err := s.queryTrash(ctx, userID, &id)
+	if err != nil {
+		return models.TrashItem{}, fmt.Errorf("failed to get trash item with ID '%s': %w", id, err)
+	}
+	if len(items) == 0 {
+		return models.TrashItem{}, svcErr.NotFoundError{Entity: "trash item", Field: "id", Value: id.String()}
+	}
+
+	return items[0], nil
+}
+
+func (s *TrashStorageImpl) queryTrash(ctx context.Context, userID uuid.UUID, id *uuid.UUID) ([]models.TrashItem, error) {
+	rows, err := s.pool.Query(ctx, trashQuery, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.TrashItem
+	for rows.Next() {
+		var item models.TrashItem
+		if err = rows.Scan(&item.Type, &item.ID, &item.ListID, &item.ListTitle, &item.Image, &item.Title, &item.Role, &item.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trash item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// RestoreListByID takes the list out of the trash together with the wishes that were in it
+func (s *TrashStorageImpl) RestoreListByID(ctx context.Context, listID uuid.UUID) error {
+	if result, err := s.pool.Exec(ctx, "UPDATE lists SET deleted_at = NULL, updated_at = now() WHERE id = $1 AND deleted_at IS NOT NULL", listID); err != nil {
+		return fmt.Errorf("failed to restore list with ID '%s': %w", listID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "trash item", Field: "id", Value: listID.String()}
+	}
+
+	return nil
+}
+
+// RestoreWishByID takes the wish out of the trash and puts it at the end of its list
+func (s *TrashStorageImpl) RestoreWishByID(ctx context.Context, wishID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var listID uuid.UUID
+	if err = tx.QueryRow(ctx, `SELECT list_id FROM wishes WHERE id = $1 AND deleted_at IS NOT NULL`, wishID).Scan(&listID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return svcErr.NotFoundError{Entity: "trash item", Field: "id", Value: wishID.String()}
+		}
+		return fmt.Errorf("failed to get wish with ID '%s': %w", wishID, err)
+	}
+
+	// The wish goes back to the end of the list, its old position may have been taken since
+	if err = lockListWishes(ctx, tx, listID); err != nil {
+		return err
+	}
+	if result, err := tx.Exec(ctx, `UPDATE wishes SET deleted_at = NULL, updated_at = now(),
+		position = (SELECT COALESCE(MAX(position) + 1, 0) FROM wishes WHERE list_id = $2 AND deleted_at IS NULL)
+		WHERE id = $1 AND deleted_at IS NOT NULL`, wishID, listID); err != nil {
+		return fmt.Errorf("failed to restore wish with ID '%s': %w", wishID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "trash item", Field: "id", Value: wishID.String()}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit restored wish: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeTrash deletes for good what was moved to the trash before the given time, reservations and contributions
+// go with it. Wishes of purged lists are not reported separately
+func (s *TrashStorageImpl) PurgeTrash(ctx context.Context, deletedBefore time.Time) (models.TrashPurgeReport, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return models.TrashPurgeReport{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var report models.TrashPurgeReport
+	if report.Lists, err = collectIDs(tx.Query(ctx, `DELETE FROM lists WHERE deleted_at < $1 RETURNING id`, deletedBefore)); err != nil {
+		return models.TrashPurgeReport{}, fmt.Errorf("failed to purge lists: %w", err)
+	}
+	if report.Wishes, err = collectIDs(tx.Query(ctx, `DELETE FROM wishes WHERE deleted_at < $1 RETURNING id`, deletedBefore)); err != nil {
+		return models.TrashPurgeReport{}, fmt.Errorf("failed to purge wishes: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return models.TrashPurgeReport{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return report, nil
+}
+
+func collectIDs(rows pgx.Rows, err error) ([]uuid.UUID, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -49,8 +50,8 @@ func (us *UserStorageImpl) SetUserEmailAsVerified(ctx context.Context, id uuid.U
 func (us *UserStorageImpl) GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error) {
 	var user models.User
 
-	if err := us.pool.QueryRow(ctx, `SELECT id, avatar, name, username, email, email_verified, password, created_at, updated_at FROM users WHERE id = $1`, id).Scan(
-		&user.ID, &user.Avatar, &user.Name, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.CreatedAt, &user.UpdatedAt,
+	if err := us.pool.QueryRow(ctx, `SELECT id, avatar, name, username, email, email_verified, password, preferred_currency, delete_after, created_at, updated_at FROM users WHERE id = $1`, id).Scan(
+		&user.ID, &user.Avatar, &user.Name, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.Currency, &user.DeleteAfter, &user.CreatedAt, &user.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.User{}, svcErr.NotFoundError{Entity: "user", Field: "id", Value: id.String()}
@@ -64,8 +65,8 @@ func (us *UserStorageImpl) GetUserByID(ctx context.Context, id uuid.UUID) (model
 func (us *UserStorageImpl) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
 	var user models.User
 
-	if err := us.pool.QueryRow(ctx, `SELECT id, avatar, name, username, email, email_verified, password, created_at, updated_at FROM users WHERE lower(username) = lower($1)`, username).Scan(
-		&user.ID, &user.Avatar, &user.Name, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.CreatedAt, &user.UpdatedAt,
+	if err := us.pool.QueryRow(ctx, `SELECT id, avatar, name, username, email, email_verified, password, preferred_currency, delete_after, created_at, updated_at FROM users WHERE lower(username) = lower($1)`, username).Scan(
+		&user.ID, &user.Avatar, &user.Name, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.Currency, &user.DeleteAfter, &user.CreatedAt, &user.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.User{}, svcErr.NotFoundError{Entity: "user", Field: "username", Value: username}
@@ -86,7 +87,7 @@ func (us *UserStorageImpl) SearchUsersByUsername(ctx context.Context, query stri
 	}
 
 	//noinspection SqlRedundantOrderingDirection
-	rows, err := us.pool.Query(ctx, `SELECT id, avatar, name, username, email, email_verified, password, created_at, updated_at FROM users WHERE lower(username) LIKE $1 ORDER BY CASE WHEN lower(username) LIKE $2 THEN 0 ELSE 1 END, lower(username) ASC LIMIT $3`, "%"+search+"%", search+"%", limit)
+	rows, err := us.pool.Query(ctx, `SELECT id, avatar, name, username, email, email_verified, password, preferred_currency, delete_after, created_at, updated_at FROM users WHERE lower(username) LIKE $1 AND delete_after IS NULL ORDER BY CASE WHEN lower(username) LIKE $2 THEN 0 ELSE 1 END, lower(username) ASC LIMIT $3`, "%"+search+"%", search+"%", limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search users with query '%s': %w", search, err)
 	}
@@ -96,7 +97,7 @@ func (us *UserStorageImpl) SearchUsersByUsername(ctx context.Context, query stri
 	for rows.Next() {
 		var user models.User
 		if err = rows.Scan(
-			&user.ID, &user.Avatar, &user.Name, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.CreatedAt, &user.UpdatedAt,
+			&user.ID, &user.Avatar, &user.Name, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.Currency, &user.DeleteAfter, &user.CreatedAt, &user.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan searched user: %w", err)
 		}
@@ -109,8 +110,8 @@ func (us *UserStorageImpl) SearchUsersByUsername(ctx context.Context, query stri
 func (us *UserStorageImpl) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
 	var user models.User
 
-	if err := us.pool.QueryRow(ctx, `SELECT id, avatar, name, username, email, email_verified, password, created_at, updated_at FROM users WHERE email = $1`, email).Scan(
-		&user.ID, &user.Avatar, &user.Name, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.CreatedAt, &user.UpdatedAt,
+	if err := us.pool.QueryRow(ctx, `SELECT id, avatar, name, username, email, email_verified, password, preferred_currency, delete_after, created_at, updated_at FROM users WHERE email = $1`, email).Scan(
+		&user.ID, &user.Avatar, &user.Name, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.Currency, &user.DeleteAfter, &user.CreatedAt, &user.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.User{}, svcErr.NotFoundError{Entity: "user", Field: "email", Value: email}
@@ -152,6 +153,11 @@ func (us *UserStorageImpl) UpdateUserByID(ctx context.Context, id uuid.UUID, req
 		args = append(args, *req.Password)
 		index++
 	}
+	if req.Currency != nil {
+		clauses = append(clauses, fmt.Sprintf("preferred_currency = NULLIF($%d, '')", index))
+		args = append(args, *req.Currency)
+		index++
+	}
 
 	if len(args) == 0 {
 		return nil
@@ -192,6 +198,38 @@ func (us *UserStorageImpl) DeleteUserByID(ctx context.Context, id uuid.UUID) err
 	return nil
 }
 
+// ScheduleUserDeletion deactivates the account until it is deleted for good at deleteAfter
+func (us *UserStorageImpl) ScheduleUserDeletion(ctx context.Context, id uuid.UUID, deleteAfter time.Time) error {
+	if result, err := us.pool.Exec(ctx, "UPDATE users SET delete_after = $2, updated_at = now() WHERE id = $1", id, deleteAfter); err != nil {
+		return fmt.Errorf("failed to schedule deletion of user with ID '%s': %w", id, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "user", Field: "id", Value: id.String()}
+	}
+
+	return nil
+}
+
+// CancelUserDeletion reactivates the account, it is not an error when no deletion was scheduled
+func (us *UserStorageImpl) CancelUserDeletion(ctx context.Context, id uuid.UUID) error {
+	if result, err := us.pool.Exec(ctx, "UPDATE users SET delete_after = NULL, updated_at = now() WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to cancel deletion of user with ID '%s': %w", id, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.NotFoundError{Entity: "user", Field: "id", Value: id.String()}
+	}
+
+	return nil
+}
+
+// DeleteScheduledUsers deletes the accounts whose grace period ended before the given time and returns their IDs
+func (us *UserStorageImpl) DeleteScheduledUsers(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	ids, err := collectIDs(us.pool.Query(ctx, "DELETE FROM users WHERE delete_after <= $1 RETURNING id", before))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete scheduled users: %w", err)
+	}
+
+	return ids, nil
+}
+
 func mapUserWriteError(err error) error {
 	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.Code == "23505" {
 		switch pgErr.ConstraintName {
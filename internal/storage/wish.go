@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -18,21 +20,86 @@ type WishStorageImpl struct{ pool *pgxpool.Pool }
 
 func NewWishStorage(pool *pgxpool.Pool) *WishStorageImpl { return &WishStorageImpl{pool: pool} }
 
+// insertWishQuery puts new wishes after the last one not in the trash, the column default applies when no priority is set
+const insertWishQuery = `INSERT INTO wishes (id, list_id, image, title, notes, link, price, currency, quantity, status, priority, position, price_alert_below, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE(NULLIF($11, ''), 'nice_to_have'), (SELECT COALESCE(MAX(position) + 1, 0) FROM wishes WHERE list_id = $2 AND deleted_at IS NULL), $12, $13, $14)`
+
 func (s *WishStorageImpl) CreateWish(ctx context.Context, wish models.Wish) error {
-	if _, err := s.pool.Exec(ctx, `INSERT INTO wishes (id, list_id, image, title, notes, link, price, currency, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
-		wish.ID, wish.ListID, wish.Image, wish.Title, wish.Notes, wish.Link, wish.Price, wish.Currency, wish.CreatedAt, wish.UpdatedAt,
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = lockListWishes(ctx, tx, wish.ListID); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, insertWishQuery,
+		wish.ID, wish.ListID, wish.Image, wish.Title, wish.Notes, wish.Link, wish.Price, wish.Currency, wish.Quantity, wish.Status.Stored(), wish.Priority, wish.PriceAlert, wish.CreatedAt, wish.UpdatedAt,
 	); err != nil {
 		return fmt.Errorf("failed to create wish: %w", err)
 	}
 
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit new wish: %w", err)
+	}
+
+	return nil
+}
+
+// lockListWishes locks the list row so wishes of the list are added and reordered one transaction at a time,
+// positions taken from MAX(position) would collide otherwise. Inserting a wish alone doesn't conflict with this lock
+func lockListWishes(ctx context.Context, tx pgx.Tx, listID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM lists WHERE id = $1 FOR NO KEY UPDATE`, listID); err != nil {
+		return fmt.Errorf("failed to lock list with ID '%s': %w", listID, err)
+	}
+
+	return nil
+}
+
+// CreateWishes adds all the wishes in one transaction, in the order given, or none of them
+func (s *WishStorageImpl) CreateWishes(ctx context.Context, wishes []models.Wish) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	listIDs := make([]uuid.UUID, 0, 1)
+	for _, wish := range wishes {
+		if !slices.Contains(listIDs, wish.ListID) {
+			listIDs = append(listIDs, wish.ListID)
+		}
+	}
+	slices.SortFunc(listIDs, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) }) // Same order everywhere, no deadlocks
+	for _, listID := range listIDs {
+		if err = lockListWishes(ctx, tx, listID); err != nil {
+			return err
+		}
+	}
+
+	batch := &pgx.Batch{}
+	for _, wish := range wishes {
+		batch.Queue(insertWishQuery,
+			wish.ID, wish.ListID, wish.Image, wish.Title, wish.Notes, wish.Link, wish.Price, wish.Currency, wish.Quantity, wish.Status.Stored(), wish.Priority, wish.PriceAlert, wish.CreatedAt, wish.UpdatedAt,
+		)
+	}
+	if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to create wishes: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit new wishes: %w", err)
+	}
+
 	return nil
 }
 
 func (s *WishStorageImpl) GetWishByID(ctx context.Context, wishID uuid.UUID) (models.Wish, error) {
 	var wish models.Wish
 
-	if err := s.pool.QueryRow(ctx, `SELECT id, list_id, image, title, notes, link, price, currency, reserved_by, created_at, updated_at FROM wishes WHERE id = $1`, wishID).Scan(
-		&wish.ID, &wish.ListID, &wish.Image, &wish.Title, &wish.Notes, &wish.Link, &wish.Price, &wish.Currency, &wish.ReservedBy, &wish.CreatedAt, &wish.UpdatedAt,
+	if err := s.pool.QueryRow(ctx, `SELECT id, list_id, image, title, notes, link, price, current_price, currency, quantity, status, priority, position, price_alert_below, (SELECT COALESCE(SUM(c.amount), 0) FROM wish_contributions c WHERE c.wish_id = wishes.id), created_at, updated_at FROM wishes WHERE id = $1 AND deleted_at IS NULL`, wishID).Scan(
+		&wish.ID, &wish.ListID, &wish.Image, &wish.Title, &wish.Notes, &wish.Link, &wish.Price, &wish.CurrentPrice, &wish.Currency, &wish.Quantity, &wish.Status, &wish.Priority, &wish.Position, &wish.PriceAlert, &wish.Contributed, &wish.CreatedAt, &wish.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.Wish{}, svcErr.NotFoundError{Entity: "wish", Field: "id", Value: wishID.String()}
@@ -40,12 +107,33 @@ func (s *WishStorageImpl) GetWishByID(ctx context.Context, wishID uuid.UUID) (mo
 		return models.Wish{}, fmt.Errorf("failed to get wish with ID '%s': %w", wishID, err)
 	}
 
-	return wish, nil
+	wishes := []models.Wish{wish}
+	if err := s.loadReservations(ctx, wishes); err != nil {
+		return models.Wish{}, err
+	}
+
+	return wishes[0], nil
 }
 
-func (s *WishStorageImpl) GetWishesByListID(ctx context.Context, listID uuid.UUID) ([]models.Wish, error) {
-	//noinspection SqlRedundantOrderingDirection
-	rows, err := s.pool.Query(ctx, `SELECT id, list_id, image, title, notes, link, price, currency, reserved_by, created_at, updated_at FROM wishes WHERE list_id = $1 ORDER BY created_at ASC`, listID)
+// GetWishesByListID returns wishes of the list in the order of filter.Sort, only the ones in filter.Status when it is set
+func (s *WishStorageImpl) GetWishesByListID(ctx context.Context, listID uuid.UUID, filter models.WishFilter) ([]models.Wish, error) {
+	query := `SELECT id, list_id, image, title, notes, link, price, current_price, currency, quantity, status, priority, position, price_alert_below, (SELECT COALESCE(SUM(c.amount), 0) FROM wish_contributions c WHERE c.wish_id = wishes.id), created_at, updated_at FROM wishes WHERE list_id = $1 AND deleted_at IS NULL`
+	args := []any{listID}
+
+	if filter.Status != nil {
+		const activeReservation = `EXISTS (SELECT 1 FROM wish_reservations r WHERE r.wish_id = wishes.id AND (r.reserved_until IS NULL OR r.reserved_until > now()))`
+		switch *filter.Status {
+		case models.WishStatusOpen:
+			query += ` AND status = 'open' AND NOT ` + activeReservation
+		case models.WishStatusReserved:
+			query += ` AND status = 'open' AND ` + activeReservation
+		default:
+			query += ` AND status = $2`
+			args = append(args, *filter.Status)
+		}
+	}
+
+	rows, err := s.pool.Query(ctx, query+` ORDER BY `+wishOrderBy(filter), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wishes for list with ID '%s': %w", listID, err)
 	}
@@ -54,13 +142,87 @@ func (s *WishStorageImpl) GetWishesByListID(ctx context.Context, listID uuid.UUI
 	var wishes []models.Wish
 	for rows.Next() {
 		var wish models.Wish
-		if err = rows.Scan(&wish.ID, &wish.ListID, &wish.Image, &wish.Title, &wish.Notes, &wish.Link, &wish.Price, &wish.Currency, &wish.ReservedBy, &wish.CreatedAt, &wish.UpdatedAt); err != nil {
+		if err = rows.Scan(&wish.ID, &wish.ListID, &wish.Image, &wish.Title, &wish.Notes, &wish.Link, &wish.Price, &wish.CurrentPrice, &wish.Currency, &wish.Quantity, &wish.Status, &wish.Priority, &wish.Position, &wish.PriceAlert, &wish.Contributed, &wish.CreatedAt, &wish.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan wish: %w", err)
 		}
 		wishes = append(wishes, wish)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = s.loadReservations(ctx, wishes); err != nil {
+		return nil, err
+	}
 
-	return wishes, rows.Err()
+	return wishes, nil
+}
+
+// wishOrderBy builds the ORDER BY clause for the sort of the filter, ties keep the order set by the owner.
+// Prices are compared as stored, which only means something within one currency; the service sorts them again
+// by the price converted to the viewer's currency
+func wishOrderBy(filter models.WishFilter) string {
+	direction := "ASC"
+	if filter.Desc {
+		direction = "DESC"
+	}
+
+	switch filter.Sort {
+	case models.WishSortPrice:
+		return "price " + direction + " NULLS LAST, position ASC, created_at ASC"
+	case models.WishSortPriority:
+		return "CASE priority WHEN 'must_have' THEN 0 ELSE 1 END " + direction + ", position ASC, created_at ASC"
+	case models.WishSortCreatedAt:
+		return "created_at " + direction + ", id ASC"
+	default:
+		return "position " + direction + ", created_at " + direction
+	}
+}
+
+// loadReservations fills Reservations of the given wishes in one query
+func (s *WishStorageImpl) loadReservations(ctx context.Context, wishes []models.Wish) error {
+	if len(wishes) == 0 {
+		return nil
+	}
+
+	index := make(map[uuid.UUID]int, len(wishes))
+	ids := make([]uuid.UUID, len(wishes))
+	for i, wish := range wishes {
+		index[wish.ID] = i
+		ids[i] = wish.ID
+	}
+
+	rows, err := s.pool.Query(ctx, `SELECT wish_id, user_id, units, reserved_until, reminded_at, created_at, updated_at FROM wish_reservations WHERE wish_id = ANY($1) AND (reserved_until IS NULL OR reserved_until > now()) ORDER BY created_at ASC`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get wish reservations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r models.WishReservation
+		var userID *uuid.UUID // Guests have none, who they are stays out of the wish
+		if err = rows.Scan(&r.WishID, &userID, &r.Units, &r.ReservedUntil, &r.RemindedAt, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to scan wish reservation: %w", err)
+		}
+		if userID != nil {
+			r.UserID = *userID
+		} else {
+			r.Guest = true
+		}
+		i := index[r.WishID]
+		wishes[i].Reservations = append(wishes[i].Reservations, r)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range wishes {
+		if wishes[i].Status == models.WishStatusOpen && len(wishes[i].Reservations) > 0 {
+			wishes[i].Status = models.WishStatusReserved
+		}
+	}
+
+	return nil
 }
 
 // noinspection DuplicatedCode
@@ -85,6 +247,7 @@ func (s *WishStorageImpl) UpdateWishByID(ctx context.Context, wishID uuid.UUID,
 		index++
 	}
 	if req.Link != nil {
+		clauses = append(clauses, "current_price = NULL", "price_checked_at = NULL") // Seen on the old link, the new one gets checked first
 		clauses = append(clauses, fmt.Sprintf("link = $%d", index))
 		args = append(args, *req.Link)
 		index++
@@ -95,10 +258,25 @@ func (s *WishStorageImpl) UpdateWishByID(ctx context.Context, wishID uuid.UUID,
 		index++
 	}
 	if req.Currency != nil {
-		clauses = append(clauses, fmt.Sprintf("currency = $%d", index))
+		clauses = append(clauses, fmt.Sprintf("currency = NULLIF($%d, '')", index))
 		args = append(args, *req.Currency)
 		index++
 	}
+	if req.Quantity != nil {
+		clauses = append(clauses, fmt.Sprintf("quantity = $%d", index))
+		args = append(args, *req.Quantity)
+		index++
+	}
+	if req.Priority != nil {
+		clauses = append(clauses, fmt.Sprintf("priority = $%d", index))
+		args = append(args, *req.Priority)
+		index++
+	}
+	if req.PriceAlert != nil {
+		clauses = append(clauses, fmt.Sprintf("price_alert_below = $%d", index))
+		args = append(args, *req.PriceAlert)
+		index++
+	}
 	if len(args) == 0 {
 		return nil
 	}
@@ -106,27 +284,162 @@ func (s *WishStorageImpl) UpdateWishByID(ctx context.Context, wishID uuid.UUID,
 	clauses = append(clauses, "updated_at = now()")
 	args = append(args, wishID)
 
-	if result, err := s.pool.Exec(ctx, fmt.Sprintf("UPDATE wishes SET"+" %s WHERE id = $%d", strings.Join(clauses, ", "), index), args...); err != nil {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if req.Quantity != nil {
+		if err = lockReservedQuantity(ctx, tx, wishID, *req.Quantity); err != nil {
+			return err
+		}
+	}
+
+	if result, err := tx.Exec(ctx, fmt.Sprintf("UPDATE wishes SET"+" %s WHERE id = $%d AND deleted_at IS NULL", strings.Join(clauses, ", "), index), args...); err != nil {
 		return fmt.Errorf("failed to update wish with ID '%s': %w", wishID, err)
 	} else if result.RowsAffected() == 0 {
 		return svcErr.NotFoundError{Entity: "wish", Field: "id", Value: wishID.String()}
 	}
 
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit update of wish with ID '%s': %w", wishID, err)
+	}
+
+	return nil
+}
+
+// lockReservedQuantity locks the wish like lockReservableWish does, so no reservation slips in between the check
+// that the new quantity still covers the reserved units and the update
+func lockReservedQuantity(ctx context.Context, tx pgx.Tx, wishID uuid.UUID, quantity int) error {
+	if err := tx.QueryRow(ctx, `SELECT 1 FROM wishes WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, wishID).Scan(new(int)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return svcErr.NotFoundError{Entity: "wish", Field: "id", Value: wishID.String()}
+		}
+		return fmt.Errorf("failed to lock wish with ID '%s': %w", wishID, err)
+	}
+
+	var reserved int
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(units), 0) FROM wish_reservations WHERE wish_id = $1 AND (reserved_until IS NULL OR reserved_until > now())`, wishID).Scan(&reserved); err != nil {
+		return fmt.Errorf("failed to count reservations for wish with ID '%s': %w", wishID, err)
+	}
+	if quantity < reserved {
+		return svcErr.ValidationError{Message: fmt.Sprintf("quantity cannot be less than %d already reserved units", reserved)}
+	}
+
 	return nil
 }
 
-func (s *WishStorageImpl) ReserveWish(ctx context.Context, wishID, userID uuid.UUID) error {
-	if result, err := s.pool.Exec(ctx, `UPDATE wishes SET reserved_by = $1, updated_at = now() WHERE id = $2 AND reserved_by IS NULL`, userID, wishID); err != nil {
+// ReorderWishes sets positions of the list's wishes to their index in wishIDs, which must hold every wish of the list
+func (s *WishStorageImpl) ReorderWishes(ctx context.Context, listID uuid.UUID, wishIDs []uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// The list lock keeps new wishes out and the row locks keep existing ones from being removed while they are reordered
+	if err = lockListWishes(ctx, tx, listID); err != nil {
+		return err
+	}
+	var count, matched int
+	if err = tx.QueryRow(ctx, `SELECT count(*), count(*) FILTER (WHERE id = ANY($2)) FROM (SELECT id FROM wishes WHERE list_id = $1 AND deleted_at IS NULL FOR UPDATE) w`, listID, wishIDs).Scan(&count, &matched); err != nil {
+		return fmt.Errorf("failed to lock wishes of list with ID '%s': %w", listID, err)
+	}
+	if count != len(wishIDs) || matched != len(wishIDs) {
+		return svcErr.ConflictError{Message: "wishes of the list have changed, reload and try again"}
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE wishes w SET position = o.position - 1 FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, position) WHERE w.id = o.id AND w.list_id = $1 AND w.deleted_at IS NULL`, listID, wishIDs); err != nil {
+		return fmt.Errorf("failed to reorder wishes of list with ID '%s': %w", listID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit order of wishes of list with ID '%s': %w", listID, err)
+	}
+
+	return nil
+}
+
+// ReserveWish adds units to the reservation of the user, the wish row is locked so concurrent requests cannot overbook it
+func (s *WishStorageImpl) ReserveWish(ctx context.Context, reservation models.WishReservation) error {
+	wishID, units := reservation.WishID, reservation.Units
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = lockReservableWish(ctx, tx, wishID, units); err != nil {
+		return err
+	}
+
+	// A new deadline replaces the old one and asks for a fresh reminder. Units held without a deadline must never expire
+	// along with others, so once either side has none the whole reservation is kept without one
+	if _, err = tx.Exec(ctx, `INSERT INTO wish_reservations (wish_id, user_id, units, reserved_until, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (wish_id, user_id) DO UPDATE SET
+			units = wish_reservations.units + EXCLUDED.units,
+			reserved_until = CASE WHEN wish_reservations.reserved_until IS NOT NULL THEN EXCLUDED.reserved_until END,
+			reminded_at = CASE WHEN EXCLUDED.reserved_until IS NULL THEN wish_reservations.reminded_at END,
+			updated_at = now()`,
+		wishID, reservation.UserID, units, reservation.ReservedUntil, reservation.CreatedAt, reservation.UpdatedAt,
+	); err != nil {
 		return fmt.Errorf("failed to reserve wish with ID '%s': %w", wishID, err)
-	} else if result.RowsAffected() == 0 {
-		return fmt.Errorf("failed to reserve wish with ID '%s': already reserved or not found", wishID)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit reservation of wish with ID '%s': %w", wishID, err)
+	}
+
+	return nil
+}
+
+// lockReservableWish locks the wish for the rest of the transaction and checks that the units are still free,
+// reservations of users and guests count alike
+func lockReservableWish(ctx context.Context, tx pgx.Tx, wishID uuid.UUID, units int) error {
+	var quantity, reserved int
+	var status models.WishStatus
+	var chippedIn bool
+	if err := tx.QueryRow(ctx, `SELECT quantity, status FROM wishes WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, wishID).Scan(&quantity, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return svcErr.NotFoundError{Entity: "wish", Field: "id", Value: wishID.String()}
+		}
+		return fmt.Errorf("failed to lock wish with ID '%s': %w", wishID, err)
+	}
+	if status != models.WishStatusOpen {
+		return svcErr.ValidationError{Message: "wish is no longer available"}
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM wish_reservations WHERE wish_id = $1 AND reserved_until <= now()`, wishID); err != nil { // Don't wait for the job to free expired units
+		return fmt.Errorf("failed to release expired reservations of wish with ID '%s': %w", wishID, err)
+	}
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(units), 0), EXISTS (SELECT 1 FROM wish_contributions WHERE wish_id = $1) FROM wish_reservations WHERE wish_id = $1`, wishID).Scan(&reserved, &chippedIn); err != nil {
+		return fmt.Errorf("failed to count reservations for wish with ID '%s': %w", wishID, err)
+	}
+	if chippedIn {
+		return svcErr.ValidationError{Message: "wish is already being chipped in for"}
+	}
+	if reserved >= quantity {
+		return svcErr.ValidationError{Message: "wish is already reserved"}
+	}
+	if reserved+units > quantity {
+		return svcErr.ValidationError{Message: fmt.Sprintf("only %d units left to reserve", quantity-reserved)}
 	}
 
 	return nil
 }
 
-func (s *WishStorageImpl) ReleaseWish(ctx context.Context, wishID, userID uuid.UUID) error {
-	if result, err := s.pool.Exec(ctx, `UPDATE wishes SET reserved_by = NULL, updated_at = now() WHERE id = $1 AND reserved_by = $2`, wishID, userID); err != nil {
+// ReleaseWish gives back units held by the user, zero or more units than held releases the whole reservation
+func (s *WishStorageImpl) ReleaseWish(ctx context.Context, wishID, userID uuid.UUID, units int) error {
+	result, err := s.pool.Exec(ctx, `UPDATE wish_reservations SET units = units - $3, updated_at = now() WHERE wish_id = $1 AND user_id = $2 AND $3 > 0 AND units > $3`, wishID, userID, units)
+	if err != nil {
+		return fmt.Errorf("failed to release wish with ID '%s': %w", wishID, err)
+	}
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	if result, err = s.pool.Exec(ctx, `DELETE FROM wish_reservations WHERE wish_id = $1 AND user_id = $2`, wishID, userID); err != nil {
 		return fmt.Errorf("failed to release wish with ID '%s': %w", wishID, err)
 	} else if result.RowsAffected() == 0 {
 		return fmt.Errorf("failed to release wish with ID '%s': not reserved by you or not found", wishID)
@@ -135,8 +448,106 @@ func (s *WishStorageImpl) ReleaseWish(ctx context.Context, wishID, userID uuid.U
 	return nil
 }
 
+// UpdateWishStatus moves the wish from one status to another, failing with a conflict if someone changed it in between
+func (s *WishStorageImpl) UpdateWishStatus(ctx context.Context, wishID uuid.UUID, from, to models.WishStatus) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if result, err := tx.Exec(ctx, `UPDATE wishes SET status = $3, updated_at = now() WHERE id = $1 AND status = $2 AND deleted_at IS NULL`, wishID, from.Stored(), to.Stored()); err != nil {
+		return fmt.Errorf("failed to update status of wish with ID '%s': %w", wishID, err)
+	} else if result.RowsAffected() == 0 {
+		return svcErr.ConflictError{Message: "wish status has changed, reload and try again"}
+	}
+
+	// A bought gift stays with its reserver, deadlines no longer apply
+	if to != models.WishStatusOpen && to != models.WishStatusReserved {
+		if _, err = tx.Exec(ctx, `UPDATE wish_reservations SET reserved_until = NULL, updated_at = now() WHERE wish_id = $1 AND reserved_until > now()`, wishID); err != nil {
+			return fmt.Errorf("failed to clear reservation deadlines of wish with ID '%s': %w", wishID, err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit status of wish with ID '%s': %w", wishID, err)
+	}
+
+	return nil
+}
+
+// GetReservationsByUserID returns the active reservations the user holds, newest first
+func (s *WishStorageImpl) GetReservationsByUserID(ctx context.Context, userID uuid.UUID) ([]models.HeldReservation, error) {
+	rows, err := s.pool.Query(ctx, `SELECT r.wish_id, r.user_id, r.units, r.reserved_until, r.reminded_at, r.created_at, r.updated_at, w.title, l.id, l.title
+		FROM wish_reservations r
+		JOIN wishes w ON w.id = r.wish_id
+		JOIN lists l ON l.id = w.list_id
+		WHERE r.user_id = $1 AND (r.reserved_until IS NULL OR r.reserved_until > now()) AND w.deleted_at IS NULL AND l.deleted_at IS NULL
+		ORDER BY r.created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservations of user with ID '%s': %w", userID, err)
+	}
+	defer rows.Close()
+
+	var reservations []models.HeldReservation
+	for rows.Next() {
+		var r models.HeldReservation
+		if err = rows.Scan(&r.WishID, &r.UserID, &r.Units, &r.ReservedUntil, &r.RemindedAt, &r.CreatedAt, &r.UpdatedAt, &r.WishTitle, &r.ListID, &r.ListTitle); err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, r)
+	}
+
+	return reservations, rows.Err()
+}
+
+// GetReservationsToRemind returns not yet reminded reservations whose deadline comes before the given time
+func (s *WishStorageImpl) GetReservationsToRemind(ctx context.Context, before time.Time) ([]models.ExpiringReservation, error) {
+	rows, err := s.pool.Query(ctx, `SELECT r.wish_id, r.user_id, r.units, r.reserved_until, r.reminded_at, r.created_at, r.updated_at, u.email, w.title, w.list_id
+		FROM wish_reservations r
+		JOIN wishes w ON w.id = r.wish_id
+		JOIN lists l ON l.id = w.list_id
+		JOIN users u ON u.id = r.user_id
+		WHERE r.reserved_until IS NOT NULL AND r.reserved_until > now() AND r.reserved_until <= $1 AND r.reminded_at IS NULL AND u.email IS NOT NULL
+			AND w.deleted_at IS NULL AND l.deleted_at IS NULL
+		ORDER BY r.reserved_until ASC`, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservations to remind: %w", err)
+	}
+	defer rows.Close()
+
+	var reservations []models.ExpiringReservation
+	for rows.Next() {
+		var r models.ExpiringReservation
+		if err = rows.Scan(&r.WishID, &r.UserID, &r.Units, &r.ReservedUntil, &r.RemindedAt, &r.CreatedAt, &r.UpdatedAt, &r.Email, &r.WishTitle, &r.ListID); err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, r)
+	}
+
+	return reservations, rows.Err()
+}
+
+func (s *WishStorageImpl) MarkReservationReminded(ctx context.Context, wishID, userID uuid.UUID) error {
+	if _, err := s.pool.Exec(ctx, `UPDATE wish_reservations SET reminded_at = now() WHERE wish_id = $1 AND user_id = $2`, wishID, userID); err != nil {
+		return fmt.Errorf("failed to mark reservation of wish with ID '%s' as reminded: %w", wishID, err)
+	}
+
+	return nil
+}
+
+func (s *WishStorageImpl) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	result, err := s.pool.Exec(ctx, `DELETE FROM wish_reservations WHERE reserved_until IS NOT NULL AND reserved_until <= now()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release expired reservations: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// DeleteWishByID moves the wish to the trash, its reservations and contributions are kept for a restore
 func (s *WishStorageImpl) DeleteWishByID(ctx context.Context, wishID uuid.UUID) error {
-	if result, err := s.pool.Exec(ctx, "DELETE FROM wishes WHERE id = $1", wishID); err != nil {
+	if result, err := s.pool.Exec(ctx, "UPDATE wishes SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL", wishID); err != nil {
 		return fmt.Errorf("failed to delete wish with ID '%s': %w", wishID, err)
 	} else if result.RowsAffected() == 0 {
 		return svcErr.NotFoundError{Entity: "wish", Field: "id", Value: wishID.String()}
@@ -0,0 +1,90 @@
+package wishimport
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"wishlist/internal/models"
+)
+
+// parseBookmarks reads a Netscape bookmark file: every <A HREF> is a wish titled by its text, the <DD> after it
+// holds the description. Folders are flattened, only web links are accepted
+func parseBookmarks(data []byte) ([]models.WishImportRow, error) {
+	if !bytes.Contains(bytes.ToLower(data), []byte("<a")) {
+		return nil, fmt.Errorf("%w: no bookmarks found", ErrInvalidFile)
+	}
+
+	var rows []models.WishImportRow
+	var text *strings.Builder // Where text tokens go: the title of an open <A> or the description of a <DD>
+	var inAnchor bool
+
+	z := html.NewTokenizer(bytes.NewReader(data))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		switch tt {
+		case html.StartTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "a":
+				finishNotes(rows, text, inAnchor)
+				var href string
+				for hasAttr {
+					var k, v []byte
+					k, v, hasAttr = z.TagAttr()
+					if string(k) == "href" {
+						href = strings.TrimSpace(string(v))
+					}
+				}
+				row := models.WishImportRow{Row: len(rows) + 1, Request: models.CreateWishRequest{Link: optional(href)}}
+				if u, err := url.Parse(href); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					row.Error = "only http and https links can be imported"
+				}
+				rows = append(rows, row)
+				text, inAnchor = &strings.Builder{}, true
+			case "dd":
+				if len(rows) > 0 && !inAnchor {
+					text = &strings.Builder{}
+				}
+			case "dt", "dl", "h3":
+				finishNotes(rows, text, inAnchor)
+				text = nil
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "a" && inAnchor {
+				title := strings.Join(strings.Fields(text.String()), " ")
+				if title == "" && rows[len(rows)-1].Request.Link != nil {
+					title = *rows[len(rows)-1].Request.Link
+				}
+				rows[len(rows)-1].Request.Title = title
+				text, inAnchor = nil, false
+			}
+		case html.TextToken:
+			if text != nil {
+				text.Write(z.Text())
+			}
+		}
+	}
+	if inAnchor { // File ends inside the last link
+		rows[len(rows)-1].Request.Title = strings.Join(strings.Fields(text.String()), " ")
+	} else {
+		finishNotes(rows, text, false)
+	}
+
+	return rows, nil
+}
+
+// finishNotes stores the collected <DD> text as notes of the last bookmark
+func finishNotes(rows []models.WishImportRow, text *strings.Builder, inAnchor bool) {
+	if text == nil || inAnchor || len(rows) == 0 {
+		return
+	}
+	rows[len(rows)-1].Request.Notes = optional(strings.Join(strings.Fields(text.String()), " "))
+}
@@ -0,0 +1,123 @@
+package wishimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"wishlist/internal/currency"
+	"wishlist/internal/models"
+)
+
+// csvColumns maps header names people use in spreadsheets onto fields of models.CreateWishRequest
+var csvColumns = map[string]string{
+	"title": "title", "name": "title", "wish": "title",
+	"notes": "notes", "note": "notes", "description": "notes", "comment": "notes",
+	"link": "link", "url": "link",
+	"price":    "price",
+	"currency": "currency",
+	"quantity": "quantity", "qty": "quantity",
+	"priority":          "priority",
+	"price_alert_below": "price_alert_below", "price_alert": "price_alert_below",
+}
+
+// parseCSV reads a file with a header row, prices are written the way people do ("19.99", "1 299,00"),
+// not in minor units. Spreadsheets in many locales save with ';', which is picked when the header has no ','
+func parseCSV(data []byte) ([]models.WishImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if header, _, _ := bytes.Cut(data, []byte("\n")); bytes.Contains(header, []byte(";")) && !bytes.Contains(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read CSV header: %v", ErrInvalidFile, err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+		if field, ok := csvColumns[name]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("%w: CSV header must have a 'title' column", ErrInvalidFile)
+	}
+
+	var rows []models.WishImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil { // A broken quote shifts every cell after it, nothing past it can be trusted
+			return nil, fmt.Errorf("%w: failed to read CSV: %v", ErrInvalidFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" { // Spreadsheets keep empty rows with separators
+			continue
+		}
+
+		row := models.WishImportRow{Row: line, Request: models.CreateWishRequest{
+			Title:    cell("title"),
+			Notes:    optional(cell("notes")),
+			Link:     optional(cell("link")),
+			Currency: optional(cell("currency")),
+		}}
+		row.Error = fillCSVNumbers(&row.Request, cell)
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// fillCSVNumbers parses the number columns and the priority, it returns what is wrong with the row or ""
+func fillCSVNumbers(req *models.CreateWishRequest, cell func(field string) string) string {
+	code := ""
+	if req.Currency != nil {
+		code = currency.Normalize(*req.Currency)
+	}
+
+	if raw := cell("price"); raw != "" {
+		value, ok := currency.ParseMajor(raw, code)
+		if !ok {
+			return fmt.Sprintf("price '%s' is not a number", raw)
+		}
+		req.Price = new(currency.FromMajor(value, code))
+	}
+	if raw := cell("price_alert_below"); raw != "" {
+		value, ok := currency.ParseMajor(raw, code)
+		if !ok {
+			return fmt.Sprintf("price alert '%s' is not a number", raw)
+		}
+		req.PriceAlert = new(currency.FromMajor(value, code))
+	}
+	if raw := cell("quantity"); raw != "" {
+		quantity, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Sprintf("quantity '%s' is not a whole number", raw)
+		}
+		req.Quantity = &quantity
+	}
+	if raw := cell("priority"); raw != "" {
+		priority := models.WishPriority(strings.ReplaceAll(strings.ToLower(raw), " ", "_")) // "Must have" reads as must_have
+		req.Priority = &priority
+	}
+
+	return ""
+}
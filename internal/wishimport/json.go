@@ -0,0 +1,41 @@
+package wishimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"wishlist/internal/models"
+)
+
+// parseJSON reads wishes the way the API sends and takes them: prices in minor units, field names of
+// models.CreateWishRequest. The file is either an array of wishes or a list, an object with a "wishes" array
+func parseJSON(data []byte) ([]models.WishImportRow, error) {
+	var items []json.RawMessage
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		var list struct {
+			Wishes []json.RawMessage `json:"wishes"`
+		}
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		items = list.Wishes
+	} else if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	rows := make([]models.WishImportRow, 0, len(items))
+	for i, item := range items {
+		row := models.WishImportRow{Row: i + 1}
+		if err := json.Unmarshal(item, &row.Request); err != nil { // One wrong value only costs its own row
+			row.Error = "invalid wish"
+			if typeErr, ok := errors.AsType[*json.UnmarshalTypeError](err); ok && typeErr.Field != "" {
+				row.Error = fmt.Sprintf("%s has invalid type", typeErr.Field)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,98 @@
+package wishimport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"wishlist/internal/models"
+)
+
+type Format string
+
+const (
+	FormatCSV       Format = "csv"
+	FormatJSON      Format = "json"
+	FormatBookmarks Format = "html" // Netscape bookmark file, what every browser exports
+)
+
+// ErrInvalidFile means the file as a whole can't be read, problems of single rows are reported on the row instead
+var ErrInvalidFile = errors.New("invalid import file")
+
+// DetectFormat takes the format the client named, then the extension of the file name, then a look at the content
+func DetectFormat(requested, filename string, data []byte) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(requested)) {
+	case "":
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatJSON, nil
+	case "html", "htm", "bookmarks":
+		return FormatBookmarks, nil
+	default:
+		return "", fmt.Errorf("%w: unknown format '%s' (csv, json or html)", ErrInvalidFile, requested)
+	}
+
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".html", ".htm":
+		return FormatBookmarks, nil
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")), bytes.HasPrefix(trimmed, []byte("{")):
+		return FormatJSON, nil
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return FormatBookmarks, nil
+	default:
+		return FormatCSV, nil
+	}
+}
+
+// Parse turns the file into rows for models.CreateWishRequest. Images and autofill are never taken from a file,
+// binding rules are left to the caller since they are checked the same way as for a single new wish
+func Parse(format Format, data []byte) ([]models.WishImportRow, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	var rows []models.WishImportRow
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, err = parseCSV(data)
+	case FormatJSON:
+		rows, err = parseJSON(data)
+	case FormatBookmarks:
+		rows, err = parseBookmarks(data)
+	default:
+		return nil, fmt.Errorf("%w: unknown format '%s'", ErrInvalidFile, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no wishes found", ErrInvalidFile)
+	}
+
+	for i := range rows {
+		rows[i].Request.Image, rows[i].Request.Autofill = nil, false
+		rows[i].Request.Title = strings.TrimSpace(rows[i].Request.Title)
+	}
+	return rows, nil
+}
+
+var utf8BOM = []byte("\xef\xbb\xbf") // Excel puts it in front of CSV files saved as UTF-8
+
+// optional returns nil for blank values, so a missing cell is the same as a missing JSON field
+func optional(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,122 @@
+package wishimport
+
+import (
+	"errors"
+	"testing"
+
+	"wishlist/internal/models"
+)
+
+func TestParse_CSV(t *testing.T) {
+	data := "\xef\xbb\xbfName;Description;URL;Price;Currency;Qty;Priority\n" +
+		"Steam Deck;512GB;https://store.steampowered.com/steamdeck;\"1 299,00\";eur;1;Must have\n" +
+		";;;;;;\n" +
+		"Book;;;cheap;;;\n" +
+		"Mug;;;;;two;\n"
+
+	rows, err := Parse(FormatCSV, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("len(rows) = %d, want 3 (blank row skipped)", len(rows))
+	}
+
+	deck := rows[0]
+	if deck.Row != 2 || deck.Error != "" || deck.Request.Title != "Steam Deck" || *deck.Request.Notes != "512GB" {
+		t.Fatalf("rows[0] = %+v", deck)
+	}
+	if *deck.Request.Price != 129900 || *deck.Request.Currency != "eur" || *deck.Request.Quantity != 1 || *deck.Request.Priority != models.WishPriorityMustHave {
+		t.Fatalf("rows[0] numbers = %d %s %d %s", *deck.Request.Price, *deck.Request.Currency, *deck.Request.Quantity, *deck.Request.Priority)
+	}
+	if rows[1].Row != 4 || rows[1].Error == "" {
+		t.Fatalf("rows[1] = %+v, want a price error on line 4", rows[1])
+	}
+	if rows[2].Error == "" {
+		t.Fatalf("rows[2] = %+v, want a quantity error", rows[2])
+	}
+
+	if _, err = Parse(FormatCSV, []byte("link,price\nhttps://a.example,1\n")); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("Parse(no title column) error = %v, want ErrInvalidFile", err)
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	data := `{"title": "Birthday", "wishes": [
+		{"title": "PS5", "price": 49999, "currency": "USD", "image": "wishes/l/w/original", "autofill": true},
+		{"title": "Lego", "price": "a lot"}
+	]}`
+
+	rows, err := Parse(FormatJSON, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].Request.Title != "PS5" || *rows[0].Request.Price != 49999 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if rows[0].Request.Image != nil || rows[0].Request.Autofill {
+		t.Fatal("image and autofill must not be imported")
+	}
+	if rows[1].Row != 2 || rows[1].Error != "price has invalid type" {
+		t.Fatalf("rows[1] = %+v", rows[1])
+	}
+
+	if rows, err = Parse(FormatJSON, []byte(`[{"title": "Kindle"}]`)); err != nil || len(rows) != 1 {
+		t.Fatalf("Parse(array) = %+v, %v", rows, err)
+	}
+	if _, err = Parse(FormatJSON, []byte(`{"wishes": [`)); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("Parse(broken) error = %v, want ErrInvalidFile", err)
+	}
+}
+
+func TestParse_Bookmarks(t *testing.T) {
+	data := `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<TITLE>Bookmarks</TITLE>
+<DL><p>
+	<DT><H3>Gifts</H3>
+	<DL><p>
+		<DT><A HREF="https://shop.example/kettle" ADD_DATE="1700000000">Electric &amp; quiet kettle</A>
+		<DD>The blue one
+		<DT><A HREF="javascript:alert(1)">Bookmarklet</A>
+		<DT><A HREF="https://shop.example/socks"></A>
+	</DL><p>
+</DL>`
+
+	rows, err := Parse(FormatBookmarks, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("len(rows) = %d, want 3", len(rows))
+	}
+	if rows[0].Request.Title != "Electric & quiet kettle" || *rows[0].Request.Link != "https://shop.example/kettle" || rows[0].Request.Notes == nil || *rows[0].Request.Notes != "The blue one" {
+		t.Fatalf("rows[0] = %+v", rows[0])
+	}
+	if rows[1].Error == "" {
+		t.Fatal("javascript: link was accepted")
+	}
+	if rows[2].Request.Title != "https://shop.example/socks" || rows[2].Request.Notes != nil {
+		t.Fatalf("rows[2] = %+v, want the link as title", rows[2])
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		requested, filename, data string
+		want                      Format
+	}{
+		{requested: "CSV", filename: "wishes.json", want: FormatCSV},
+		{filename: "bookmarks.HTML", want: FormatBookmarks},
+		{filename: "export", data: "  [{}]", want: FormatJSON},
+		{filename: "export", data: "<!DOCTYPE NETSCAPE-Bookmark-file-1>", want: FormatBookmarks},
+		{filename: "export", data: "title\nPS5", want: FormatCSV},
+	}
+	for _, tt := range tests {
+		if got, err := DetectFormat(tt.requested, tt.filename, []byte(tt.data)); err != nil || got != tt.want {
+			t.Fatalf("DetectFormat(%q, %q) = %s, %v, want %s", tt.requested, tt.filename, got, err, tt.want)
+		}
+	}
+	if _, err := DetectFormat("xlsx", "", nil); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("DetectFormat(xlsx) error = %v, want ErrInvalidFile", err)
+	}
+}
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE list_members (
+                              list_id UUID NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+                              user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                              role VARCHAR(16) NOT NULL,
+                              invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                              accepted_at TIMESTAMPTZ,
+                              created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                              updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                              PRIMARY KEY (list_id, user_id),
+                              CONSTRAINT list_members_role_check CHECK (role IN ('owner', 'editor', 'viewer'))
+);
+
+CREATE INDEX idx_list_members_user_id ON list_members (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_list_members_user_id;
+DROP TABLE IF EXISTS list_members;
+-- +goose StatementEnd
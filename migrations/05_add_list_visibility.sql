@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE lists ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'public';
+
+-- Non-public lists were always reachable by their shared link, so they keep working as link-only
+UPDATE lists SET visibility = CASE WHEN is_public THEN 'public' ELSE 'link_only' END;
+
+ALTER TABLE lists ADD CONSTRAINT lists_visibility_check CHECK (visibility IN ('private', 'link_only', 'followers', 'public'));
+ALTER TABLE lists DROP COLUMN is_public;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE lists ADD COLUMN is_public BOOLEAN NOT NULL DEFAULT TRUE;
+
+UPDATE lists SET is_public = (visibility = 'public');
+
+ALTER TABLE lists DROP CONSTRAINT IF EXISTS lists_visibility_check;
+ALTER TABLE lists DROP COLUMN visibility;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE wish_contributions (
+                                    wish_id UUID NOT NULL REFERENCES wishes(id) ON DELETE CASCADE,
+                                    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                    amount BIGINT NOT NULL,
+                                    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                    PRIMARY KEY (wish_id, user_id),
+                                    CONSTRAINT wish_contributions_amount_check CHECK (amount > 0)
+);
+
+CREATE INDEX idx_wish_contributions_user_id ON wish_contributions (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_wish_contributions_user_id;
+DROP TABLE IF EXISTS wish_contributions;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wishes ADD COLUMN quantity INT NOT NULL DEFAULT 1;
+ALTER TABLE wishes ADD CONSTRAINT wishes_quantity_positive CHECK (quantity > 0);
+
+CREATE TABLE wish_reservations (
+                                   wish_id UUID NOT NULL REFERENCES wishes(id) ON DELETE CASCADE,
+                                   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                   units INT NOT NULL,
+                                   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                   updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                   PRIMARY KEY (wish_id, user_id),
+                                   CONSTRAINT wish_reservations_units_positive CHECK (units > 0)
+);
+
+CREATE INDEX idx_wish_reservations_user_id ON wish_reservations (user_id);
+
+INSERT INTO wish_reservations (wish_id, user_id, units, created_at, updated_at)
+SELECT id, reserved_by, 1, updated_at, updated_at FROM wishes WHERE reserved_by IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_wishes_reserved_by;
+ALTER TABLE wishes DROP COLUMN reserved_by;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wishes ADD COLUMN reserved_by UUID REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX idx_wishes_reserved_by ON wishes (reserved_by);
+
+-- Only one reserver fits the old column, the earliest one keeps the wish
+UPDATE wishes w SET reserved_by = r.user_id
+FROM (SELECT DISTINCT ON (wish_id) wish_id, user_id FROM wish_reservations ORDER BY wish_id, created_at) r
+WHERE w.id = r.wish_id;
+
+DROP INDEX IF EXISTS idx_wish_reservations_user_id;
+DROP TABLE IF EXISTS wish_reservations;
+
+ALTER TABLE wishes DROP CONSTRAINT IF EXISTS wishes_quantity_positive;
+ALTER TABLE wishes DROP COLUMN quantity;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wish_reservations ADD COLUMN reserved_until TIMESTAMPTZ;
+ALTER TABLE wish_reservations ADD COLUMN reminded_at TIMESTAMPTZ;
+
+CREATE INDEX idx_wish_reservations_reserved_until ON wish_reservations (reserved_until) WHERE reserved_until IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_wish_reservations_reserved_until;
+
+ALTER TABLE wish_reservations DROP COLUMN reminded_at;
+ALTER TABLE wish_reservations DROP COLUMN reserved_until;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- 'reserved' is never stored, an open wish with active reservations is reported as reserved
+ALTER TABLE wishes ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open';
+ALTER TABLE wishes ADD CONSTRAINT wishes_status_check CHECK (status IN ('open', 'purchased', 'received', 'archived'));
+
+CREATE INDEX idx_wishes_list_id_status ON wishes (list_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_wishes_list_id_status;
+
+ALTER TABLE wishes DROP CONSTRAINT IF EXISTS wishes_status_check;
+ALTER TABLE wishes DROP COLUMN status;
+-- +goose StatementEnd
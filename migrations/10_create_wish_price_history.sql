@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wishes ADD COLUMN price_alert_below BIGINT;
+ALTER TABLE wishes ADD COLUMN price_checked_at TIMESTAMPTZ;
+ALTER TABLE wishes ADD CONSTRAINT wishes_price_alert_below_positive CHECK (price_alert_below IS NULL OR price_alert_below > 0);
+
+CREATE TABLE wish_price_history (
+                                    id UUID PRIMARY KEY,
+                                    wish_id UUID NOT NULL REFERENCES wishes(id) ON DELETE CASCADE,
+                                    price BIGINT NOT NULL,
+                                    currency VARCHAR(8),
+                                    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                    CONSTRAINT wish_price_history_price_non_negative CHECK (price >= 0)
+);
+
+CREATE INDEX idx_wish_price_history_wish_id_recorded_at ON wish_price_history (wish_id, recorded_at DESC);
+CREATE INDEX idx_wishes_price_checked_at ON wishes (price_checked_at) WHERE link IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_wishes_price_checked_at;
+DROP INDEX IF EXISTS idx_wish_price_history_wish_id_recorded_at;
+DROP TABLE IF EXISTS wish_price_history;
+
+ALTER TABLE wishes DROP CONSTRAINT IF EXISTS wishes_price_alert_below_positive;
+ALTER TABLE wishes DROP COLUMN price_checked_at;
+ALTER TABLE wishes DROP COLUMN price_alert_below;
+-- +goose StatementEnd
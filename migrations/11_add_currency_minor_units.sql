@@ -0,0 +1,66 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Prices become amounts in minor units of an ISO 4217 currency (cents for EUR, yen for JPY), they used to be whole units
+CREATE OR REPLACE FUNCTION pg_temp.minor_scale(code TEXT) RETURNS BIGINT AS $$
+    SELECT CASE
+               WHEN code IN ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG', 'RWF', 'UGX', 'UYI', 'VND', 'VUV', 'XAF', 'XOF', 'XPF') THEN 1
+               WHEN code IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 1000
+               WHEN code IN ('CLF', 'UYW') THEN 10000
+               ELSE 100
+               END
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION pg_temp.currency_code(value TEXT) RETURNS TEXT AS $$
+    SELECT CASE upper(btrim(value))
+               WHEN '₽' THEN 'RUB'
+               WHEN 'РУБ' THEN 'RUB'
+               WHEN 'RUR' THEN 'RUB'
+               WHEN '$' THEN 'USD'
+               WHEN '€' THEN 'EUR'
+               WHEN '£' THEN 'GBP'
+               ELSE upper(btrim(value))
+               END
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Free-form values were allowed, keep the common spellings and drop what cannot be a code
+UPDATE wishes SET currency = pg_temp.currency_code(currency) WHERE currency IS NOT NULL;
+UPDATE wishes SET currency = NULL WHERE currency !~ '^[A-Z]{3}$';
+UPDATE wish_price_history SET currency = pg_temp.currency_code(currency) WHERE currency IS NOT NULL;
+UPDATE wish_price_history SET currency = NULL WHERE currency !~ '^[A-Z]{3}$';
+
+UPDATE wishes SET price = price * pg_temp.minor_scale(currency), price_alert_below = price_alert_below * pg_temp.minor_scale(currency);
+UPDATE wish_contributions c SET amount = amount * pg_temp.minor_scale(w.currency) FROM wishes w WHERE w.id = c.wish_id;
+UPDATE wish_price_history SET price = price * pg_temp.minor_scale(currency);
+
+ALTER TABLE wishes ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE wishes ADD CONSTRAINT wishes_currency_format CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE wish_price_history ALTER COLUMN currency TYPE VARCHAR(3);
+
+ALTER TABLE users ADD COLUMN preferred_currency VARCHAR(3);
+ALTER TABLE users ADD CONSTRAINT users_preferred_currency_format CHECK (preferred_currency ~ '^[A-Z]{3}$');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION pg_temp.minor_scale(code TEXT) RETURNS BIGINT AS $$
+    SELECT CASE
+               WHEN code IN ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG', 'RWF', 'UGX', 'UYI', 'VND', 'VUV', 'XAF', 'XOF', 'XPF') THEN 1
+               WHEN code IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 1000
+               WHEN code IN ('CLF', 'UYW') THEN 10000
+               ELSE 100
+               END
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_preferred_currency_format;
+ALTER TABLE users DROP COLUMN preferred_currency;
+
+ALTER TABLE wish_price_history ALTER COLUMN currency TYPE VARCHAR(8);
+ALTER TABLE wishes DROP CONSTRAINT IF EXISTS wishes_currency_format;
+ALTER TABLE wishes ALTER COLUMN currency TYPE VARCHAR(8);
+
+-- Whole units again, fractions are rounded; alert prices and contributions stay positive as their checks require
+UPDATE wish_price_history SET price = round(price::NUMERIC / pg_temp.minor_scale(currency));
+UPDATE wish_contributions c SET amount = greatest(round(amount::NUMERIC / pg_temp.minor_scale(w.currency)), 1) FROM wishes w WHERE w.id = c.wish_id;
+UPDATE wishes SET price = round(price::NUMERIC / pg_temp.minor_scale(currency));
+UPDATE wishes SET price_alert_below = greatest(round(price_alert_below::NUMERIC / pg_temp.minor_scale(currency)), 1) WHERE price_alert_below IS NOT NULL;
+-- +goose StatementEnd
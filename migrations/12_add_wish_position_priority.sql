@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wishes ADD COLUMN position INT NOT NULL DEFAULT 0;
+ALTER TABLE wishes ADD COLUMN priority VARCHAR(16) NOT NULL DEFAULT 'nice_to_have';
+ALTER TABLE wishes ADD CONSTRAINT wishes_priority_check CHECK (priority IN ('must_have', 'nice_to_have'));
+
+-- Existing wishes keep the order they were shown in, oldest first
+UPDATE wishes w SET position = o.position
+FROM (SELECT id, row_number() OVER (PARTITION BY list_id ORDER BY created_at, id) - 1 AS position FROM wishes) o
+WHERE w.id = o.id;
+
+CREATE INDEX idx_wishes_list_id_position ON wishes (list_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_wishes_list_id_position;
+
+ALTER TABLE wishes DROP CONSTRAINT IF EXISTS wishes_priority_check;
+ALTER TABLE wishes DROP COLUMN priority;
+ALTER TABLE wishes DROP COLUMN position;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage_objects (
+                                 object_name VARCHAR(512) PRIMARY KEY,
+                                 url TEXT NOT NULL,
+                                 user_id UUID,
+                                 list_id UUID,
+                                 wish_id UUID,
+                                 created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_storage_objects_user_id ON storage_objects (user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX idx_storage_objects_list_id ON storage_objects (list_id) WHERE list_id IS NOT NULL;
+CREATE INDEX idx_storage_objects_wish_id ON storage_objects (wish_id) WHERE wish_id IS NOT NULL;
+CREATE INDEX idx_storage_objects_url ON storage_objects (url);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_storage_objects_url;
+DROP INDEX IF EXISTS idx_storage_objects_wish_id;
+DROP INDEX IF EXISTS idx_storage_objects_list_id;
+DROP INDEX IF EXISTS idx_storage_objects_user_id;
+DROP TABLE IF EXISTS storage_objects;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE lists ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE wishes ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Only the trash and the purge job look for deleted rows
+CREATE INDEX idx_lists_deleted_at ON lists (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_wishes_deleted_at ON wishes (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_wishes_deleted_at;
+DROP INDEX IF EXISTS idx_lists_deleted_at;
+
+-- Whatever was in the trash would come back, it is removed for good instead
+DELETE FROM wishes WHERE deleted_at IS NOT NULL;
+DELETE FROM lists WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE wishes DROP COLUMN deleted_at;
+ALTER TABLE lists DROP COLUMN deleted_at;
+-- +goose StatementEnd
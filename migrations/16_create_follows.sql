@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE follows (
+                         follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                         followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                         accepted_at TIMESTAMPTZ,
+                         created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                         PRIMARY KEY (follower_id, followee_id),
+                         CONSTRAINT follows_not_self_check CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX idx_follows_followee_id ON follows (followee_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_follows_followee_id;
+DROP TABLE IF EXISTS follows;
+-- +goose StatementEnd
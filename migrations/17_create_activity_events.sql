@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE activity_events (
+                                 id UUID PRIMARY KEY,
+                                 actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                 list_id UUID NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+                                 wish_id UUID REFERENCES wishes(id) ON DELETE CASCADE,
+                                 kind VARCHAR(32) NOT NULL,
+                                 title TEXT NOT NULL,
+                                 created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The feed pages through events newest first and filters them by list
+CREATE INDEX idx_activity_events_created_at_id ON activity_events (created_at DESC, id DESC);
+CREATE INDEX idx_activity_events_list_id ON activity_events (list_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_activity_events_list_id;
+DROP INDEX IF EXISTS idx_activity_events_created_at_id;
+DROP TABLE IF EXISTS activity_events;
+-- +goose StatementEnd
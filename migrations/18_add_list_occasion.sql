@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE lists ADD COLUMN occasion_date DATE;
+ALTER TABLE lists ADD COLUMN occasion_recurs_yearly BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE lists ADD COLUMN occasion_reminded_for DATE; -- Occurrence the last reminder was sent for, so each one is reminded once
+
+-- Only the reminder job and the upcoming view look for lists with an occasion
+CREATE INDEX idx_lists_occasion_date ON lists (occasion_date) WHERE occasion_date IS NOT NULL AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lists_occasion_date;
+
+ALTER TABLE lists DROP COLUMN occasion_reminded_for;
+ALTER TABLE lists DROP COLUMN occasion_recurs_yearly;
+ALTER TABLE lists DROP COLUMN occasion_date;
+-- +goose StatementEnd
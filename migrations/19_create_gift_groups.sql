@@ -0,0 +1,66 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE gift_groups (
+                             id UUID PRIMARY KEY,
+                             organizer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                             title TEXT NOT NULL,
+                             notes TEXT,
+                             created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                             updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE gift_group_members (
+                                    group_id UUID NOT NULL REFERENCES gift_groups(id) ON DELETE CASCADE,
+                                    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                    list_id UUID REFERENCES lists(id) ON DELETE SET NULL, -- The list the member wants gifts from
+                                    invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                    accepted_at TIMESTAMPTZ,
+                                    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX idx_gift_group_members_user_id ON gift_group_members (user_id);
+
+-- Givers that must not draw the receiver, e.g. partners. Leaving the group removes the rules about the member
+CREATE TABLE gift_group_exclusions (
+                                       group_id UUID NOT NULL,
+                                       giver_id UUID NOT NULL,
+                                       receiver_id UUID NOT NULL,
+                                       created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                       PRIMARY KEY (group_id, giver_id, receiver_id),
+                                       FOREIGN KEY (group_id, giver_id) REFERENCES gift_group_members(group_id, user_id) ON DELETE CASCADE,
+                                       FOREIGN KEY (group_id, receiver_id) REFERENCES gift_group_members(group_id, user_id) ON DELETE CASCADE,
+                                       CONSTRAINT gift_group_exclusions_not_self_check CHECK (giver_id <> receiver_id)
+);
+
+-- Every draw is kept for the audit trail, participants see the assignments of the latest one
+CREATE TABLE gift_group_draws (
+                                  id UUID PRIMARY KEY,
+                                  group_id UUID NOT NULL REFERENCES gift_groups(id) ON DELETE CASCADE,
+                                  drawn_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                                  seed BIGINT NOT NULL,
+                                  participants INT NOT NULL,
+                                  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_gift_group_draws_group_id_created_at ON gift_group_draws (group_id, created_at DESC);
+
+CREATE TABLE gift_group_assignments (
+                                        draw_id UUID NOT NULL REFERENCES gift_group_draws(id) ON DELETE CASCADE,
+                                        giver_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                        receiver_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                        PRIMARY KEY (draw_id, giver_id),
+                                        UNIQUE (draw_id, receiver_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS gift_group_assignments;
+DROP INDEX IF EXISTS idx_gift_group_draws_group_id_created_at;
+DROP TABLE IF EXISTS gift_group_draws;
+DROP TABLE IF EXISTS gift_group_exclusions;
+DROP INDEX IF EXISTS idx_gift_group_members_user_id;
+DROP TABLE IF EXISTS gift_group_members;
+DROP TABLE IF EXISTS gift_groups;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Guests reserve without an account, so a reservation is held by a user or by a guest with a management token
+ALTER TABLE wish_reservations DROP CONSTRAINT wish_reservations_pkey;
+ALTER TABLE wish_reservations ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE wish_reservations ADD CONSTRAINT wish_reservations_wish_id_user_id_key UNIQUE (wish_id, user_id);
+
+ALTER TABLE wish_reservations ADD COLUMN guest_name TEXT;
+ALTER TABLE wish_reservations ADD COLUMN guest_email TEXT;
+ALTER TABLE wish_reservations ADD COLUMN guest_token VARCHAR(64);
+ALTER TABLE wish_reservations ADD COLUMN confirmed_at TIMESTAMPTZ;
+ALTER TABLE wish_reservations ADD CONSTRAINT wish_reservations_holder_check CHECK (
+    (user_id IS NOT NULL AND guest_token IS NULL AND guest_name IS NULL AND guest_email IS NULL) OR
+    (user_id IS NULL AND guest_token IS NOT NULL AND guest_name IS NOT NULL AND guest_email IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX idx_wish_reservations_guest_token ON wish_reservations (guest_token) WHERE guest_token IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM wish_reservations WHERE user_id IS NULL;
+
+DROP INDEX IF EXISTS idx_wish_reservations_guest_token;
+ALTER TABLE wish_reservations DROP CONSTRAINT IF EXISTS wish_reservations_holder_check;
+ALTER TABLE wish_reservations DROP COLUMN confirmed_at;
+ALTER TABLE wish_reservations DROP COLUMN guest_token;
+ALTER TABLE wish_reservations DROP COLUMN guest_email;
+ALTER TABLE wish_reservations DROP COLUMN guest_name;
+
+ALTER TABLE wish_reservations DROP CONSTRAINT IF EXISTS wish_reservations_wish_id_user_id_key;
+ALTER TABLE wish_reservations ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE wish_reservations ADD PRIMARY KEY (wish_id, user_id);
+-- +goose StatementEnd
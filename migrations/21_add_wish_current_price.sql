@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The price tracking job used to overwrite the price set by the owner, the shop's price gets a column of its own
+ALTER TABLE wishes ADD COLUMN current_price BIGINT;
+ALTER TABLE wishes ADD CONSTRAINT wishes_current_price_non_negative CHECK (current_price IS NULL OR current_price >= 0);
+
+UPDATE wishes w SET current_price = h.price
+FROM (SELECT DISTINCT ON (wish_id) wish_id, price FROM wish_price_history ORDER BY wish_id, recorded_at DESC) h
+WHERE w.id = h.wish_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wishes DROP CONSTRAINT IF EXISTS wishes_current_price_non_negative;
+ALTER TABLE wishes DROP COLUMN current_price;
+-- +goose StatementEnd
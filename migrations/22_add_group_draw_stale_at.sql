@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A member leaving after the draw takes their giver and receiver along, the draw is stale until the organizer draws again
+ALTER TABLE gift_group_draws ADD COLUMN stale_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE gift_group_draws DROP COLUMN stale_at;
+-- +goose StatementEnd
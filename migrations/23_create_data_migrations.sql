@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Data migrations that need the app config run from the app on start, each one records itself here to run only once
+CREATE TABLE data_migrations (
+    name VARCHAR(64) PRIMARY KEY,
+    ran_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE data_migrations;
+-- +goose StatementEnd
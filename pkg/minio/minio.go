@@ -16,6 +16,7 @@ type Config struct {
 	SecretAccessKey string
 	UseSSL          bool
 	BucketName      string
+	Private         bool // Objects are only readable with presigned URLs
 }
 
 func NewClient(ctx context.Context, cfg Config) (*minio.Client, error) {
@@ -41,6 +42,15 @@ func NewClient(ctx context.Context, cfg Config) (*minio.Client, error) {
 		}
 	}
 
+	if cfg.Private {
+		if err = client.SetBucketPolicy(ctx, cfg.BucketName, ""); err != nil { // Drops the public read policy of earlier versions
+			fmt.Println()
+			return nil, fmt.Errorf("MinIO: failed to remove policy of bucket '%s': %w", cfg.BucketName, err)
+		}
+		fmt.Println(colors.Green(" Done."))
+		return client, nil
+	}
+
 	allowPublicReadPolicy := fmt.Sprintf(`{
   "Version": "2012-10-17",
   "Statement": [
@@ -119,6 +119,32 @@ async function searchUsers(query) {
     return await apiRequest(`/users/search?query=${encodeURIComponent(query)}`);
 }
 
+// Get follow state between current user and selected one
+async function getFollowState(userId) {
+    return await apiRequest(`/users/${encodeURIComponent(userId)}/follow`);
+}
+
+// Send follow request
+async function followUser(userId) {
+    return await apiRequest(`/users/${encodeURIComponent(userId)}/follow`, {
+        method: 'POST'
+    });
+}
+
+// Unfollow or withdraw follow request
+async function unfollowUser(userId) {
+    return await apiRequest(`/users/${encodeURIComponent(userId)}/follow`, {
+        method: 'DELETE'
+    });
+}
+
+// Accept follow request of selected user
+async function acceptFollower(userId) {
+    return await apiRequest(`/users/me/followers/${encodeURIComponent(userId)}/accept`, {
+        method: 'POST'
+    });
+}
+
 // Update password
 async function updatePassword(oldPassword, newPassword) {
     return await apiRequest('/users/me/update-password', {
@@ -130,8 +156,38 @@ async function updatePassword(oldPassword, newPassword) {
     });
 }
 
+// Upload a file straight to S3 with a presigned URL and let the API attach it.
+// Returns null when S3 can't be reached from the browser (e.g. no CORS on the bucket), so the caller can send it through the API instead
+async function uploadDirect(basePath, file) {
+    const upload = await apiRequest(`${basePath}/upload-url`, {
+        method: 'POST',
+        body: JSON.stringify({ content_type: file.type, size: file.size })
+    });
+    if (!upload) return null;
+
+    try {
+        const response = await fetch(upload.upload_url, {
+            method: 'PUT',
+            headers: upload.headers,
+            body: file
+        });
+        if (!response.ok) return null;
+    } catch (error) {
+        console.warn('Direct upload failed, sending through the API:', error);
+        return null;
+    }
+
+    return await apiRequest(`${basePath}/confirm`, {
+        method: 'POST',
+        body: JSON.stringify({ object_key: upload.object_key })
+    });
+}
+
 // Upload avatar
 async function uploadAvatar(file) {
+    const uploaded = await uploadDirect('/users/me/avatar', file);
+    if (uploaded) return uploaded;
+
     const formData = new FormData();
     formData.append('avatar', file);
 
@@ -176,8 +232,19 @@ async function setNewPassword(token, newPassword) {
     });
 }
 
+// Keep the account scheduled for deletion with the token from the email
+async function cancelAccountDeletion(token) {
+    return await apiRequest('/auth/cancel-deletion', {
+        method: 'POST',
+        body: JSON.stringify({ token })
+    });
+}
+
 // Upload wish image
 async function uploadWishImage(listId, wishId, file) {
+    const uploaded = await uploadDirect(`/lists/${listId}/wishes/${wishId}/image`, file);
+    if (uploaded) return uploaded;
+
     const formData = new FormData();
     formData.append('image', file);
 
@@ -222,11 +289,11 @@ async function getListBySlug(slug) {
     return await apiRequest(`/lists/shared/${slug}`);
 }
 
-// Create list
-async function createList(title) {
+// Create list, extra may carry occasion_date and occasion_recurs_yearly
+async function createList(title, extra = {}) {
     return await apiRequest('/lists', {
         method: 'POST',
-        body: JSON.stringify({ title })
+        body: JSON.stringify({ title, ...extra })
     });
 }
 
@@ -245,6 +312,14 @@ async function rotateSharedLink(listId) {
     });
 }
 
+// Duplicate list
+async function duplicateList(listId, data = {}) {
+    return await apiRequest(`/lists/${listId}/duplicate`, {
+        method: 'POST',
+        body: JSON.stringify(data)
+    });
+}
+
 // Delete list
 async function deleteList(listId) {
     return await apiRequest(`/lists/${listId}`, {
@@ -272,6 +347,14 @@ async function updateWish(listId, wishId, data) {
     });
 }
 
+// Reorder wishes, wishIds holds every wish of the list in the new order
+async function reorderWishes(listId, wishIds) {
+    return await apiRequest(`/lists/${listId}/wishes/order`, {
+        method: 'PUT',
+        body: JSON.stringify({ wish_ids: wishIds })
+    });
+}
+
 // Delete wish
 async function deleteWish(listId, wishId) {
     return await apiRequest(`/lists/${listId}/wishes/${wishId}`, {
@@ -292,3 +375,30 @@ async function releaseWish(listId, wishId) {
         method: 'DELETE'
     });
 }
+
+// Reserve wish of a shared list without an account, the link to confirm it is emailed
+async function guestReserveWish(slug, wishId, name, email) {
+    return await apiRequest(`/lists/shared/${slug}/wishes/${wishId}/guest-reserve`, {
+        method: 'POST',
+        body: JSON.stringify({ name, email })
+    });
+}
+
+// Get guest reservation by the emailed token
+async function getGuestReservation(token) {
+    return await apiRequest(`/guest-reservations/${encodeURIComponent(token)}`);
+}
+
+// Confirm guest reservation
+async function confirmGuestReservation(token) {
+    return await apiRequest(`/guest-reservations/${encodeURIComponent(token)}/confirm`, {
+        method: 'POST'
+    });
+}
+
+// Release guest reservation
+async function releaseGuestReservation(token) {
+    return await apiRequest(`/guest-reservations/${encodeURIComponent(token)}`, {
+        method: 'DELETE'
+    });
+}
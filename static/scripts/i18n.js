@@ -81,6 +81,17 @@ const translations = {
         'users.find.searchChoose': 'Выберите пользователя из списка',
         'users.publicLists.back': 'К моим вишлистам',
         'users.publicLists.subtitle': 'Здесь показаны только публичные списки',
+        'users.follow.follow': 'Подписаться',
+        'users.follow.requested': 'Заявка отправлена',
+        'users.follow.following': 'Вы подписаны',
+        'users.follow.accept': 'Принять заявку',
+        'users.follow.accepted': 'Заявка принята',
+        'users.follow.requestSent': 'Заявка на подписку отправлена',
+        'users.follow.followsYou': 'Подписан на вас',
+        'users.follow.followersListsHint': 'Вам видны списки для подписчиков',
+        'users.follow.unfollowTitle': 'Отписаться?',
+        'users.follow.unfollowConfirm': 'Списки только для подписчиков перестанут быть видны',
+        'users.follow.failed': 'Не удалось обновить подписку',
         'users.publicLists.emptyTitle': 'Тут пусто',
         'users.publicLists.emptySubtitle': 'Похоже, у этого пользователя ещё нет вишлистов',
 
@@ -89,6 +100,9 @@ const translations = {
         'list.generateLink': 'Сгенерировать новую ссылку',
         'list.makePrivate': 'Сделать приватным',
         'list.makePublic': 'Сделать публичным',
+        'list.duplicate': 'Дублировать список',
+        'list.duplicated': 'Копия списка создана',
+        'list.duplicateFailed': 'Не удалось дублировать список',
         'list.delete': 'Удалить список',
         'list.addWish': 'Добавить желание',
         'list.empty': 'В этом списке пока нет желаний',
@@ -97,6 +111,11 @@ const translations = {
         'list.createFailed': 'Не удалось создать список',
         'list.createdAt': 'Создан',
         'list.updatedAt': 'Обновлён',
+        'list.occasion.date': 'Дата события',
+        'list.occasion.yearly': 'Повторять каждый год',
+        'list.occasion.today': 'Событие сегодня',
+        'list.occasion.tomorrow': 'Событие завтра',
+        'list.occasion.inDays': 'До события: {days} дн.',
         'list.privateNotice': 'Доступ к этому списку предоставляется только по ссылке, не делитесь ей с посторонними!',
         'list.status.public': 'Публичный',
         'list.status.private': 'Приватный',
@@ -121,6 +140,7 @@ const translations = {
         'wish.title': 'Название',
         'wish.note': 'Описание',
         'wish.price': 'Стоимость',
+        'wish.priceNow': 'Сейчас в магазине:',
         'wish.link': 'Ссылка',
         'wish.image': 'Изображение',
         'wish.selectImage': 'Нажмите для выбора изображения',
@@ -130,6 +150,8 @@ const translations = {
         'wish.modal.title': 'Детали желания',
         'wish.createdAt': 'Создано',
         'wish.updatedAt': 'Изменено',
+        'wish.sort.manual': 'Как расставил владелец',
+        'wish.sort.priority': 'Сначала самые желанные',
         'wish.sort.newFirst': 'Сначала новые',
         'wish.sort.oldFirst': 'Сначала старые',
         'wish.sort.nameAsc': 'По имени (А-Я)',
@@ -144,7 +166,9 @@ const translations = {
         'wish.filter.withNotes': 'С заметками',
         'wish.filter.withoutNotes': 'Без заметок',
         'wish.validation.titleRequired': 'Название обязательно',
-        'wish.validation.priceIntegerNonNegative': 'Цена должна быть целым неотрицательным числом',
+        'wish.validation.priceNonNegative': 'Цена должна быть неотрицательным числом',
+        'wish.priority.mustHave': 'Очень хочу',
+        'wish.reorderFailed': 'Не удалось сохранить порядок',
         'wish.updated': 'Желание обновлено',
         'wish.updateFieldFailed': 'Не удалось обновить поле',
         'wish.loadFailed': 'Не удалось загрузить желание',
@@ -161,6 +185,20 @@ const translations = {
         'wish.reserve.released': 'Бронь снята',
         'wish.reserve.failed': 'Не удалось забронировать желание',
         'wish.reserve.releaseFailed': 'Не удалось снять бронь',
+        'wish.reserve.guestTitle': 'Бронь без аккаунта',
+        'wish.reserve.guestName': 'Ваше имя',
+        'wish.reserve.guestEmail': 'Ваш email, на него придёт ссылка для подтверждения брони',
+        'wish.reserve.guestSent': 'Проверьте почту и подтвердите бронь по ссылке из письма',
+        'guestReservation.title': 'Ваша бронь',
+        'guestReservation.pending': 'Бронь ждёт подтверждения до',
+        'guestReservation.confirmedHint': 'Бронь подтверждена, желание закреплено за вами',
+        'guestReservation.confirm': 'Подтвердить бронь',
+        'guestReservation.release': 'Снять бронь',
+        'guestReservation.confirmed': 'Бронь подтверждена',
+        'guestReservation.released': 'Бронь снята',
+        'guestReservation.openList': 'Открыть список',
+        'guestReservation.failed': 'Не удалось изменить бронь',
+        'guestReservation.invalidToken': 'Ссылка недействительна или бронь уже снята',
         'wish.image.updated': 'Изображение обновлено',
         'wish.image.updateFailed': 'Не удалось загрузить изображение',
         'wish.image.deleteTitle': 'Удаление изображения',
@@ -206,6 +244,13 @@ const translations = {
         'auth.loginRequestFailed': 'Ошибка при входе',
         'auth.registerError': 'Ошибка регистрации',
         'auth.registerRequestFailed': 'Ошибка при регистрации',
+        'auth.reactivateTitle': 'Аккаунт будет удалён',
+        'auth.reactivateMessage': 'Восстановить аккаунт? Если ничего не делать, он будет удалён',
+        'auth.cancelDeletionHint': 'Аккаунт будет удалён. Нажмите кнопку, чтобы сохранить его',
+        'auth.cancelDeletionSubmit': 'Сохранить аккаунт',
+        'auth.cancelDeletionSuccess': 'Удаление аккаунта отменено',
+        'auth.cancelDeletionError': 'Не удалось отменить удаление аккаунта',
+        'auth.cancelDeletionInvalidToken': 'Ссылка недействительна или устарела',
 
         // API errors
         'api.invalidRequestPayload': 'Некорректный запрос',
@@ -344,6 +389,17 @@ const translations = {
         'users.find.searchChoose': 'Choose a user from the list',
         'users.publicLists.back': 'Back to my wishlists',
         'users.publicLists.subtitle': 'Only public wishlists are shown here',
+        'users.follow.follow': 'Follow',
+        'users.follow.requested': 'Request sent',
+        'users.follow.following': 'Following',
+        'users.follow.accept': 'Accept request',
+        'users.follow.accepted': 'Request accepted',
+        'users.follow.requestSent': 'Follow request sent',
+        'users.follow.followsYou': 'Follows you',
+        'users.follow.followersListsHint': 'You can see their followers-only wishlists',
+        'users.follow.unfollowTitle': 'Unfollow?',
+        'users.follow.unfollowConfirm': 'Followers-only wishlists will no longer be visible',
+        'users.follow.failed': 'Failed to update follow',
         'users.publicLists.emptyTitle': 'Nothing here',
         'users.publicLists.emptySubtitle': 'Looks like this user has no wishlists yet',
 
@@ -352,6 +408,9 @@ const translations = {
         'list.generateLink': 'Generate new link',
         'list.makePrivate': 'Make private',
         'list.makePublic': 'Make public',
+        'list.duplicate': 'Duplicate list',
+        'list.duplicated': 'List copy created',
+        'list.duplicateFailed': 'Failed to duplicate list',
         'list.delete': 'Delete list',
         'list.addWish': 'Add wish',
         'list.empty': 'This list has no wishes yet',
@@ -360,6 +419,11 @@ const translations = {
         'list.createFailed': 'Failed to create list',
         'list.createdAt': 'Created',
         'list.updatedAt': 'Updated',
+        'list.occasion.date': 'Occasion date',
+        'list.occasion.yearly': 'Repeat every year',
+        'list.occasion.today': 'Occasion is today',
+        'list.occasion.tomorrow': 'Occasion is tomorrow',
+        'list.occasion.inDays': '{days} days until the occasion',
         'list.privateNotice': 'Access to this wishlist is granted only by link, do not share it with strangers!',
         'list.status.public': 'Public',
         'list.status.private': 'Private',
@@ -384,6 +448,7 @@ const translations = {
         'wish.title': 'Title',
         'wish.note': 'Note',
         'wish.price': 'Price',
+        'wish.priceNow': 'Now in the shop:',
         'wish.link': 'Link',
         'wish.image': 'Image',
         'wish.selectImage': 'Click to select an image',
@@ -393,6 +458,8 @@ const translations = {
         'wish.modal.title': 'Wish details',
         'wish.createdAt': 'Created',
         'wish.updatedAt': 'Updated',
+        'wish.sort.manual': 'Owner\'s order',
+        'wish.sort.priority': 'Must-haves first',
         'wish.sort.newFirst': 'Newest first',
         'wish.sort.oldFirst': 'Oldest first',
         'wish.sort.nameAsc': 'Name (A-Z)',
@@ -407,7 +474,9 @@ const translations = {
         'wish.filter.withNotes': 'With notes',
         'wish.filter.withoutNotes': 'Without notes',
         'wish.validation.titleRequired': 'Title is required',
-        'wish.validation.priceIntegerNonNegative': 'Price must be a non-negative integer',
+        'wish.validation.priceNonNegative': 'Price must be a non-negative number',
+        'wish.priority.mustHave': 'Must-have',
+        'wish.reorderFailed': 'Failed to save the order',
         'wish.updated': 'Wish updated',
         'wish.updateFieldFailed': 'Failed to update field',
         'wish.loadFailed': 'Failed to load wish',
@@ -424,6 +493,20 @@ const translations = {
         'wish.reserve.released': 'Reservation removed',
         'wish.reserve.failed': 'Failed to reserve wish',
         'wish.reserve.releaseFailed': 'Failed to release reservation',
+        'wish.reserve.guestTitle': 'Reserve without an account',
+        'wish.reserve.guestName': 'Your name',
+        'wish.reserve.guestEmail': 'Your email, we send a link there to confirm the reservation',
+        'wish.reserve.guestSent': 'Check your email and confirm the reservation with the link',
+        'guestReservation.title': 'Your reservation',
+        'guestReservation.pending': 'Waiting for your confirmation until',
+        'guestReservation.confirmedHint': 'Reservation confirmed, the wish is yours',
+        'guestReservation.confirm': 'Confirm reservation',
+        'guestReservation.release': 'Release reservation',
+        'guestReservation.confirmed': 'Reservation confirmed',
+        'guestReservation.released': 'Reservation released',
+        'guestReservation.openList': 'Open the list',
+        'guestReservation.failed': 'Failed to update the reservation',
+        'guestReservation.invalidToken': 'The link is invalid or the reservation was released',
         'wish.image.updated': 'Image updated',
         'wish.image.updateFailed': 'Failed to upload image',
         'wish.image.deleteTitle': 'Delete image',
@@ -469,6 +552,13 @@ const translations = {
         'auth.loginRequestFailed': 'Login failed',
         'auth.registerError': 'Registration error',
         'auth.registerRequestFailed': 'Registration failed',
+        'auth.reactivateTitle': 'Account scheduled for deletion',
+        'auth.reactivateMessage': 'Keep your account? Otherwise it will be deleted on',
+        'auth.cancelDeletionHint': 'Your account is scheduled for deletion. Press the button to keep it',
+        'auth.cancelDeletionSubmit': 'Keep my account',
+        'auth.cancelDeletionSuccess': 'Account deletion cancelled',
+        'auth.cancelDeletionError': 'Failed to cancel the account deletion',
+        'auth.cancelDeletionInvalidToken': 'The link is invalid or has expired',
 
         // API errors
         'api.invalidRequestPayload': 'Invalid request payload',
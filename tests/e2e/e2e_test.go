@@ -126,6 +126,12 @@ func Test_E2E_FullFlow(t *testing.T) {
 	// Delete list
 	doNoBody(t, client, http.MethodDelete, baseURL+"/lists/"+list.ID, user1.AccessToken)
 
+	// Trash: restore the list and delete it again
+	doNoBody(t, client, http.MethodGet, baseURL+"/trash", user1.AccessToken)
+	doNoBody(t, client, http.MethodPost, baseURL+"/trash/"+list.ID+"/restore", user1.AccessToken)
+	doNoBody(t, client, http.MethodGet, baseURL+"/lists/"+list.ID, user1.AccessToken)
+	doNoBody(t, client, http.MethodDelete, baseURL+"/lists/"+list.ID, user1.AccessToken)
+
 	// Logout
 	doJSON(t, client, http.MethodPost, baseURL+"/auth/logout", user1.AccessToken, map[string]any{"refresh_token": user1.RefreshToken}, nil)
 	doJSON(t, client, http.MethodPost, baseURL+"/auth/logout", user2.AccessToken, map[string]any{"refresh_token": user2.RefreshToken}, nil)